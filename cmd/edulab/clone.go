@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/wizard"
+)
+
+// clone copies an experiment into a new one, without its participants.
+func clone(args []string) error {
+	flags := flag.NewFlagSet("clone", flag.ExitOnError)
+	publicID := flags.String("id", "", "Public ID of the new experiment (random if empty)")
+	name := flags.String("name", "", "Name of the new experiment (the same as the original if empty)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: edulab clone [flags] <experiment public id>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("missing the public ID of the experiment to clone")
+	}
+
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+
+	source, err := db.FindExperiment(flags.Arg(0))
+	if err != nil {
+		return errors.Wrapf(err, "could not find experiment %s", flags.Arg(0))
+	}
+
+	if *publicID != "" {
+		if _, err := db.FindExperiment(*publicID); err == nil {
+			return errors.Errorf("experiment %s already exists", *publicID)
+		}
+	}
+
+	target := edulab.Experiment{
+		PublicID:    *publicID,
+		Name:        *name,
+		Description: source.Description,
+	}
+
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	err = wizard.Clone(db, source, &target, random)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Cloned experiment %s into %s (%s)\n", source.PublicID, target.PublicID, target.Name)
+	return nil
+}
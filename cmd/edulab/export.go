@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/wizard"
+)
+
+// export writes an experiment in the YAML format read by the importer, or
+// one of its assessments as a question bank for learning management systems.
+func export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	output := flags.String("o", "", "File to write to (standard output if empty)")
+	assessmentID := flags.String("assessment", "", "Public ID of an assessment to export as a question bank")
+	format := flags.String("format", "qti", "Format of the question bank: qti (QTI 2.1 package) or moodle (Moodle XML)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: edulab export [flags] <experiment public id>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("missing the public ID of the experiment to export")
+	}
+
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if *assessmentID == "" {
+		err = wizard.ExportYAML(db, flags.Arg(0), &buf)
+	} else {
+		err = exportAssessment(db, flags.Arg(0), *assessmentID, *format, &buf)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+
+	return errors.Wrap(os.WriteFile(*output, buf.Bytes(), 0644), "could not write output file")
+}
+
+// exportAssessment writes an assessment of an experiment as a question bank.
+func exportAssessment(db edulab.Database, experimentID, assessmentID, format string, w io.Writer) error {
+	experiment, err := db.FindExperiment(experimentID)
+	if err != nil {
+		return errors.Wrapf(err, "could not find experiment %s", experimentID)
+	}
+
+	assessment, err := db.FindAssessment(experiment.ID, assessmentID)
+	if err != nil {
+		return errors.Wrapf(err, "could not find assessment %s", assessmentID)
+	}
+
+	switch format {
+	case "qti":
+		return wizard.ExportQTI(db, experiment, assessment, w)
+	case "moodle":
+		return wizard.ExportMoodleXML(db, experiment, assessment, w)
+	default:
+		return errors.Errorf("unknown question bank format %q", format)
+	}
+}
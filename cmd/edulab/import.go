@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/wizard"
+)
+
+// importQuestions creates an assessment in an experiment from a question bank
+// exported by a learning management system.
+func importQuestions(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	experimentID := flags.String("experiment", "", "Public ID of the experiment")
+	kind := flags.String("type", string(edulab.AssessmentTypePre), "Type of the new assessment (pre, mid, post or delayed)")
+	description := flags.String("description", "", "Description of the new assessment")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: edulab import [flags] <question bank>")
+		fmt.Fprintln(flags.Output(), "Question banks are read from QTI 2.1 packages (.zip), Moodle XML (.xml) and GIFT (.gift, .txt) files.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 || *experimentID == "" {
+		flags.Usage()
+		return errors.New("missing the experiment or the question bank to import")
+	}
+
+	path := flags.Arg(0)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "could not read question bank")
+	}
+
+	questions, skipped, err := wizard.ParseQuestionBank(path, content)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range skipped {
+		fmt.Fprintf(os.Stderr, "%s: skipped %s\n", path, s)
+	}
+
+	if len(questions) == 0 {
+		return errors.New("none of the items could be converted")
+	}
+
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+
+	experiment, err := db.FindExperiment(*experimentID)
+	if err != nil {
+		return errors.Wrapf(err, "could not find experiment %s", *experimentID)
+	}
+
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	assessment := edulab.Assessment{
+		ExperimentID: experiment.ID,
+		PublicID:     wizard.PublicID(random, 2),
+		Type:         edulab.AssessmentType(*kind),
+		Description:  *description,
+	}
+
+	err = wizard.ImportQuestions(db, &assessment, questions)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created assessment %s in %s with %d questions (%d skipped)\n",
+		assessment.PublicID, experiment.PublicID, len(questions), len(skipped))
+	return nil
+}
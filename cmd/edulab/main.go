@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/db/postgres"
+	"github.com/louisbranch/edulab/db/sqlite"
+)
+
+const usage = `Usage: edulab <command> [arguments]
+
+Commands:
+  clone    Copy an experiment into a new one, without its participants
+  export   Write an experiment in the YAML format of the experiments folder,
+           or an assessment as a question bank (QTI or Moodle XML)
+  import   Create an assessment from a question bank (QTI, Moodle XML or GIFT)
+  responses
+           Import responses collected elsewhere from a CSV file
+  simulate Estimate the power of an experiment design from repeated simulations
+  validate Check experiment YAML files, reporting problems by line
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "clone":
+		err = clone(os.Args[2:])
+	case "export":
+		err = export(os.Args[2:])
+	case "import":
+		err = importQuestions(os.Args[2:])
+	case "responses":
+		err = importResponses(os.Args[2:])
+	case "simulate":
+		err = simulateDesign(os.Args[2:])
+	case "validate":
+		err = validate(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "edulab: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// openDB connects to the same database as the server, configured through the
+// environment.
+func openDB() (edulab.Database, error) {
+	dburl := os.Getenv("DATABASE_URL")
+	dbuser := os.Getenv("POSTGRES_USER")
+
+	if dburl != "" {
+		return postgres.New(dburl)
+	}
+
+	if dbuser == "" {
+		return sqlite.New("edulab.db")
+	}
+
+	pswd := os.Getenv("POSTGRES_PASSWORD")
+	host := os.Getenv("POSTGRES_HOSTNAME")
+	dbname := os.Getenv("POSTGRES_DB")
+
+	sslmode := "verify-full"
+	if os.Getenv("APP_ENV") != "production" {
+		sslmode = "disable"
+	}
+
+	connection := fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=%s",
+		dbuser, pswd, host, dbname, sslmode)
+	return postgres.New(connection)
+}
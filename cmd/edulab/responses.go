@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab/wizard"
+)
+
+// importResponses imports a CSV file of responses collected outside of edulab
+// into an assessment, guessing the columns from their headers unless they are
+// given.
+func importResponses(args []string) error {
+	flags := flag.NewFlagSet("responses", flag.ExitOnError)
+	experimentID := flags.String("experiment", "", "Public ID of the experiment")
+	assessmentID := flags.String("assessment", "", "Public ID of the assessment")
+	student := flags.String("student", "", "Column identifying the students (guessed if empty)")
+	cohort := flags.String("cohort", "", "Column with the public ID or name of the cohorts (guessed if empty)")
+	questions := flags.String("questions", "", "Columns of the questions, e.g. \"Q1=1,Q2=2\" (guessed if empty)")
+	dryRun := flags.Bool("dry-run", false, "Report what would be imported and the unmatched values, without importing")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: edulab responses [flags] <responses.csv>")
+		fmt.Fprintln(flags.Output(), "Choices are matched by text, number or letter, separated by semicolons for multiple choice questions.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 || *experimentID == "" || *assessmentID == "" {
+		flags.Usage()
+		return errors.New("missing the experiment, the assessment or the responses to import")
+	}
+
+	path := flags.Arg(0)
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "could not open responses")
+	}
+	defer f.Close()
+
+	file, err := wizard.ParseResponses(f)
+	if err != nil {
+		return errors.Wrap(err, path)
+	}
+
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+
+	experiment, err := db.FindExperiment(*experimentID)
+	if err != nil {
+		return errors.Wrapf(err, "could not find experiment %s", *experimentID)
+	}
+
+	assessment, err := db.FindAssessment(experiment.ID, *assessmentID)
+	if err != nil {
+		return errors.Wrapf(err, "could not find assessment %s", *assessmentID)
+	}
+
+	found, err := db.FindQuestions(assessment.ID)
+	if err != nil {
+		return errors.Wrap(err, "could not find questions")
+	}
+
+	columns := file.GuessColumns(found)
+	if *student != "" {
+		columns.Student = *student
+	}
+	if *cohort != "" {
+		columns.Cohort = *cohort
+	}
+	if *questions != "" {
+		columns.Questions = make(map[string]int)
+		for _, mapping := range strings.Split(*questions, ",") {
+			name, number, ok := strings.Cut(mapping, "=")
+			n, err := strconv.Atoi(strings.TrimSpace(number))
+			if !ok || err != nil {
+				return errors.Errorf("invalid question column %q, expected column=number", mapping)
+			}
+			columns.Questions[strings.TrimSpace(name)] = n
+		}
+	}
+
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	report, err := wizard.ImportResponses(db, experiment, assessment, file, columns, random, *dryRun)
+	for _, u := range report.Unmatched {
+		fmt.Fprintf(os.Stderr, "%s:%d: %s %q %s\n", path, u.Line, u.Column, u.Value, u.Reason)
+	}
+	if err != nil {
+		return err
+	}
+
+	verb := "Imported"
+	if *dryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d responses into %s of %s (%d new participants, %d replaced)\n",
+		verb, report.Responses, assessment.PublicID, experiment.PublicID, report.Participants, report.Replaced)
+	if len(report.Unmatched) > 0 {
+		return errors.Errorf("%d values couldn't be matched", len(report.Unmatched))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab/simulate"
+)
+
+// simulateDesign estimates the empirical power of an experiment design from
+// repeated simulations described in a YAML file.
+func simulateDesign(args []string) error {
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	seed := flags.Int64("seed", 0, "Seed of the simulations (the configured seed if 0)")
+	repetitions := flags.Int("repetitions", 0, "Number of simulated experiments (the configured number if 0)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: edulab simulate [flags] <simulation.yaml>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("missing the simulation to run")
+	}
+
+	path := flags.Arg(0)
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "could not open simulation")
+	}
+	defer f.Close()
+
+	config, err := simulate.Parse(f)
+	if err != nil {
+		return errors.Wrap(err, path)
+	}
+
+	if *seed != 0 {
+		config.Seed = *seed
+	}
+	if *repetitions != 0 {
+		config.Repetitions = *repetitions
+		if err := config.Validate(); err != nil {
+			return err
+		}
+	}
+
+	report := simulate.Run(config)
+
+	model := "ordinary least squares, as each arm has a single cohort"
+	if report.Mixed {
+		model = "a random intercept per cohort"
+	}
+	fmt.Printf("Simulated %d experiments (seed %d), comparing the gains of each arm with %s.\n",
+		config.Repetitions, config.Seed, model)
+	if report.Failures > 0 {
+		fmt.Printf("%d simulations couldn't be analyzed.\n", report.Failures)
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Arm\tEffect size\tStudents\tGain difference\tCohen's d\tPower\tPower (OLS)\tPlanned power")
+	for _, a := range report.Arms {
+		fmt.Fprintf(w, "%s\t%.2f\t%.0f / %.0f\t%.3f\t%.2f\t%.3f\t%.3f\t%.3f\n", a.Name, a.EffectSize,
+			a.Control, a.Intervention, a.MeanEstimate, a.MeanD, a.Power, a.OLSPower, a.PlannedPower)
+	}
+	w.Flush()
+
+	fmt.Printf("\nPower is the share of simulations with a p-value below %g. Students are the average in the control and the arm who took both assessments.\n",
+		config.Alpha)
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab/wizard"
+)
+
+// validate checks experiment files strictly, printing each problem as
+// file:line: message. Files with only warnings are valid.
+func validate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: edulab validate <file.yaml>...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("missing the experiment files to validate")
+	}
+
+	invalid := 0
+	for _, path := range flags.Args() {
+		problems, err := validateFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range problems {
+			message := p.Message
+			if p.Warning {
+				message = "warning: " + message
+			}
+			if p.Line > 0 {
+				fmt.Printf("%s:%d: %s\n", path, p.Line, message)
+			} else {
+				fmt.Printf("%s: %s\n", path, message)
+			}
+		}
+
+		if len(problems.Errors()) > 0 {
+			invalid++
+		}
+	}
+
+	if invalid > 0 {
+		return errors.Errorf("%d of %d files are invalid", invalid, flags.NArg())
+	}
+
+	return nil
+}
+
+// validateFile returns the problems found in an experiment file.
+func validateFile(path string) (wizard.Problems, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open experiment file")
+	}
+	defer file.Close()
+
+	return wizard.Check(file)
+}
@@ -65,12 +65,20 @@ func main() {
 		log.Fatal(err)
 	}
 
+	err = res.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Arms are compared in order, starting with the control arm
+	arms, _ := res.ComparisonPairs()
+
 	aq := []result.AssessmentQuestions{
 		{AssessmentID: "1", QuestionID: "2"},
 		{AssessmentID: "2", QuestionID: "7"},
 	}
 
-	cmp, err := result.NewComparison(res, aq, []string{"1", "2"})
+	cmp, err := result.NewComparison(res, aq, arms)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -15,7 +15,7 @@ func (db *DB) CreateAssessment(a *edulab.Assessment) error {
 	var id int64
 	err := db.QueryRow(query, a.ExperimentID, a.PublicID, a.Description, a.Type).Scan(&id)
 	if err != nil {
-		return errors.Wrap(err, "cannot create assessment")
+		return createError(err, "cannot create assessment")
 	}
 
 	a.ID = strconv.FormatInt(id, 10)
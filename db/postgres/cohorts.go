@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"database/sql"
 	"strconv"
 
 	"github.com/pkg/errors"
@@ -9,13 +10,15 @@ import (
 )
 
 func (db *DB) CreateCohort(c *edulab.Cohort) error {
-	query := `INSERT INTO cohorts (experiment_id, public_id, name, description)
-		VALUES ($1, $2, $3, $4) RETURNING id`
+	query := `INSERT INTO cohorts (experiment_id, arm_id, public_id, name, description)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`
+
+	armID := sql.NullString{String: c.ArmID, Valid: c.ArmID != ""}
 
 	var id int64
-	err := db.QueryRow(query, c.ExperimentID, c.PublicID, c.Name, c.Description).Scan(&id)
+	err := db.QueryRow(query, c.ExperimentID, armID, c.PublicID, c.Name, c.Description).Scan(&id)
 	if err != nil {
-		return errors.Wrap(err, "could not create cohort")
+		return createError(err, "could not create cohort")
 	}
 
 	c.ID = strconv.FormatInt(id, 10)
@@ -25,10 +28,12 @@ func (db *DB) CreateCohort(c *edulab.Cohort) error {
 
 func (db *DB) UpdateCohort(experimentID string, c edulab.Cohort) error {
 	query := `UPDATE cohorts
-		SET name = $1, description = $2
-		WHERE experiment_id = $3 AND public_id = $4`
+		SET arm_id = $1, name = $2, description = $3
+		WHERE experiment_id = $4 AND public_id = $5`
+
+	armID := sql.NullString{String: c.ArmID, Valid: c.ArmID != ""}
 
-	_, err := db.Exec(query, c.Name, c.Description, experimentID, c.PublicID)
+	_, err := db.Exec(query, armID, c.Name, c.Description, experimentID, c.PublicID)
 	if err != nil {
 		return errors.Wrap(err, "could not update cohort")
 	}
@@ -41,21 +46,24 @@ func (db *DB) FindCohort(experimentID string, publicID string) (edulab.Cohort, e
 		PublicID:     publicID,
 	}
 
-	query := `SELECT id, name, description
+	query := `SELECT id, arm_id, name, description
 		FROM cohorts
 		WHERE experiment_id = $1 AND public_id = $2`
 
-	err := db.QueryRow(query, experimentID, publicID).Scan(&cohort.ID, &cohort.Name, &cohort.Description)
+	var armID sql.NullString
+	err := db.QueryRow(query, experimentID, publicID).Scan(&cohort.ID, &armID,
+		&cohort.Name, &cohort.Description)
 	if err != nil {
 		return cohort, errors.Wrap(err, "could not find cohort")
 	}
+	cohort.ArmID = armID.String
 	return cohort, nil
 }
 
 func (db *DB) FindCohorts(experimentID string) ([]edulab.Cohort, error) {
 	var cohorts []edulab.Cohort
 
-	query := `SELECT id, public_id, name, description
+	query := `SELECT id, arm_id, public_id, name, description
 		FROM cohorts
 		WHERE experiment_id = $1
 		ORDER BY created_at ASC`
@@ -70,12 +78,71 @@ func (db *DB) FindCohorts(experimentID string) ([]edulab.Cohort, error) {
 		cohort := edulab.Cohort{
 			ExperimentID: experimentID,
 		}
-		err := rows.Scan(&cohort.ID, &cohort.PublicID, &cohort.Name, &cohort.Description)
+		var armID sql.NullString
+		err := rows.Scan(&cohort.ID, &armID, &cohort.PublicID, &cohort.Name, &cohort.Description)
 		if err != nil {
 			return cohorts, errors.Wrap(err, "could not scan cohort")
 		}
+		cohort.ArmID = armID.String
 		cohorts = append(cohorts, cohort)
 	}
 
 	return cohorts, nil
 }
+
+// UpdateCohortPeriod sets the arm of a cohort from an assessment onwards.
+// An empty arm removes the period.
+func (db *DB) UpdateCohortPeriod(p edulab.CohortPeriod) error {
+	if p.ArmID == "" {
+		query := `DELETE FROM cohort_periods WHERE cohort_id = $1 AND assessment_id = $2`
+
+		_, err := db.Exec(query, p.CohortID, p.AssessmentID)
+		if err != nil {
+			return errors.Wrap(err, "could not delete cohort period")
+		}
+		return nil
+	}
+
+	query := `INSERT INTO cohort_periods (experiment_id, cohort_id, assessment_id, arm_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (cohort_id, assessment_id) DO UPDATE SET arm_id = EXCLUDED.arm_id`
+
+	_, err := db.Exec(query, p.ExperimentID, p.CohortID, p.AssessmentID, p.ArmID)
+	if err != nil {
+		return errors.Wrap(err, "could not update cohort period")
+	}
+
+	return nil
+}
+
+func (db *DB) FindCohortPeriods(experimentID string) ([]edulab.CohortPeriod, error) {
+	var periods []edulab.CohortPeriod
+
+	query := `SELECT cohort_id, assessment_id, arm_id
+		FROM cohort_periods
+		WHERE experiment_id = $1
+		ORDER BY cohort_id, assessment_id`
+
+	rows, err := db.Query(query, experimentID)
+	if err != nil {
+		return periods, errors.Wrap(err, "could not find cohort periods")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := edulab.CohortPeriod{
+			ExperimentID: experimentID,
+		}
+		err := rows.Scan(&p.CohortID, &p.AssessmentID, &p.ArmID)
+		if err != nil {
+			return periods, errors.Wrap(err, "could not scan cohort period")
+		}
+		periods = append(periods, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return periods, errors.Wrap(err, "could not iterate cohort periods")
+	}
+
+	return periods, nil
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+)
+
+func (db *DB) CreateParticipantEvent(e *edulab.ParticipantEvent) error {
+	q := `INSERT INTO participant_events (experiment_id, cohort_id, assessment_id, participant_id, type)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`
+
+	var id int64
+	err := db.QueryRow(q, e.ExperimentID, e.CohortID, e.AssessmentID, e.ParticipantID, e.Type).Scan(&id)
+	if err != nil {
+		return errors.Wrap(err, "create participant event")
+	}
+
+	e.ID = strconv.FormatInt(id, 10)
+
+	return nil
+}
+
+func (db *DB) FindParticipantEvents(experimentID string) ([]edulab.ParticipantEvent, error) {
+	var events []edulab.ParticipantEvent
+
+	query := `SELECT id, experiment_id, cohort_id, assessment_id, participant_id, type, created_at
+		FROM participant_events WHERE experiment_id = $1
+		ORDER BY id`
+
+	rows, err := db.Query(query, experimentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "query participant events")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e := edulab.ParticipantEvent{}
+		err = rows.Scan(&e.ID, &e.ExperimentID, &e.CohortID, &e.AssessmentID,
+			&e.ParticipantID, &e.Type, &e.CreatedAt)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan participant events")
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
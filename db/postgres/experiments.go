@@ -14,7 +14,7 @@ func (db *DB) CreateExperiment(e *edulab.Experiment) error {
 	var id int64
 	err := db.QueryRow(q, e.PublicID, e.Name, e.Description).Scan(&id)
 	if err != nil {
-		return errors.Wrap(err, "create experiment")
+		return createError(err, "create experiment")
 	}
 
 	e.ID = strconv.FormatInt(id, 10)
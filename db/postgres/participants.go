@@ -9,13 +9,13 @@ import (
 )
 
 func (db *DB) CreateParticipant(p *edulab.Participant) error {
-	q := `INSERT INTO participants (public_id, experiment_id, cohort_id, access_token)
-		VALUES ($1, $2, $3, $4) RETURNING id`
+	q := `INSERT INTO participants (public_id, experiment_id, cohort_id, access_token, external_id)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`
 
 	var id int64
-	err := db.QueryRow(q, p.PublicID, p.ExperimentID, p.CohortID, p.AccessToken).Scan(&id)
+	err := db.QueryRow(q, p.PublicID, p.ExperimentID, p.CohortID, p.AccessToken, p.ExternalID).Scan(&id)
 	if err != nil {
-		return errors.Wrap(err, "create participant")
+		return createError(err, "create participant")
 	}
 
 	p.ID = strconv.FormatInt(id, 10)
@@ -26,11 +26,11 @@ func (db *DB) CreateParticipant(p *edulab.Participant) error {
 func (db *DB) FindParticipant(experimentID string, accessToken string) (edulab.Participant, error) {
 	var p edulab.Participant
 
-	query := `SELECT id, public_id, experiment_id, cohort_id, access_token
+	query := `SELECT id, public_id, experiment_id, cohort_id, access_token, COALESCE(external_id, '')
 		FROM participants WHERE experiment_id = $1 AND access_token = $2`
 
 	err := db.QueryRow(query, experimentID, accessToken).
-		Scan(&p.ID, &p.PublicID, &p.ExperimentID, &p.CohortID, &p.AccessToken)
+		Scan(&p.ID, &p.PublicID, &p.ExperimentID, &p.CohortID, &p.AccessToken, &p.ExternalID)
 	if err != nil {
 		return p, errors.Wrap(err, "query participant")
 	}
@@ -41,7 +41,7 @@ func (db *DB) FindParticipant(experimentID string, accessToken string) (edulab.P
 func (db *DB) FindParticipants(experimentID string) ([]edulab.Participant, error) {
 	var participants []edulab.Participant
 
-	query := `SELECT id, public_id, experiment_id, cohort_id, access_token
+	query := `SELECT id, public_id, experiment_id, cohort_id, access_token, COALESCE(external_id, '')
 		FROM participants WHERE experiment_id = $1
 		ORDER BY id`
 
@@ -53,7 +53,7 @@ func (db *DB) FindParticipants(experimentID string) ([]edulab.Participant, error
 
 	for rows.Next() {
 		p := edulab.Participant{}
-		err = rows.Scan(&p.ID, &p.PublicID, &p.ExperimentID, &p.CohortID, &p.AccessToken)
+		err = rows.Scan(&p.ID, &p.PublicID, &p.ExperimentID, &p.CohortID, &p.AccessToken, &p.ExternalID)
 		if err != nil {
 			return nil, errors.Wrap(err, "scan participants")
 		}
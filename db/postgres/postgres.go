@@ -2,12 +2,73 @@ package postgres
 
 import (
 	"database/sql"
+	"strings"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
 )
 
 type DB struct {
 	*sql.DB
+	tx *sql.Tx // Set while running a transaction
+}
+
+// Exec runs a statement inside the current transaction, if any.
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	if db.tx != nil {
+		return db.tx.Exec(query, args...)
+	}
+	return db.DB.Exec(query, args...)
+}
+
+// Query runs a query inside the current transaction, if any.
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	if db.tx != nil {
+		return db.tx.Query(query, args...)
+	}
+	return db.DB.Query(query, args...)
+}
+
+// QueryRow runs a single row query inside the current transaction, if any.
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	if db.tx != nil {
+		return db.tx.QueryRow(query, args...)
+	}
+	return db.DB.QueryRow(query, args...)
+}
+
+// Transaction runs fn on a database whose changes are committed together
+// when fn succeeds, and rolled back when it returns an error. Nested
+// transactions join the outer one.
+func (db *DB) Transaction(fn func(edulab.Database) error) error {
+	if db.tx != nil {
+		return fn(db)
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+	defer tx.Rollback()
+
+	err = fn(&DB{DB: db.DB, tx: tx})
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(tx.Commit(), "could not commit transaction")
+}
+
+// createError wraps the error of an insert, reporting unique public ID
+// violations as edulab.ErrPublicIDTaken.
+func createError(err error, message string) error {
+	var pe *pq.Error
+	if errors.As(err, &pe) && pe.Code == "23505" && strings.Contains(pe.Constraint, "public_id") {
+		return errors.Wrap(edulab.ErrPublicIDTaken, message)
+	}
+	return errors.Wrap(err, message)
 }
 
 func New(connection string) (*DB, error) {
@@ -34,7 +95,7 @@ func New(connection string) (*DB, error) {
 			id SERIAL PRIMARY KEY,
 			experiment_id INTEGER NOT NULL,
 			public_id TEXT NOT NULL UNIQUE CHECK(public_id <> ''),
-			type TEXT CHECK(type IN ('pre', 'post')),
+			type TEXT,
 			description TEXT,
 			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE
@@ -63,17 +124,41 @@ func New(connection string) (*DB, error) {
 		);
 		`,
 		`
-		CREATE TABLE IF NOT EXISTS cohorts (
+		CREATE TABLE IF NOT EXISTS translations (
+			question_id INTEGER NOT NULL,
+			choice_id TEXT NOT NULL DEFAULT '',
+			lang TEXT NOT NULL CHECK(lang <> ''),
+			text TEXT NOT NULL CHECK(text <> ''),
+			PRIMARY KEY (question_id, choice_id, lang),
+			FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS arms (
 			id SERIAL PRIMARY KEY,
 			experiment_id INTEGER NOT NULL,
 			public_id TEXT NOT NULL UNIQUE CHECK(public_id <> ''),
 			name TEXT NOT NULL CHECK(name <> ''),
 			description TEXT,
+			is_control BOOLEAN NOT NULL DEFAULT FALSE,
 			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE
 		);
 		`,
 		`
+		CREATE TABLE IF NOT EXISTS cohorts (
+			id SERIAL PRIMARY KEY,
+			experiment_id INTEGER NOT NULL,
+			public_id TEXT NOT NULL UNIQUE CHECK(public_id <> ''),
+			name TEXT NOT NULL CHECK(name <> ''),
+			description TEXT,
+			arm_id INTEGER,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE,
+			FOREIGN KEY (arm_id) REFERENCES arms(id) ON DELETE SET NULL
+		);
+		`,
+		`
 		CREATE UNIQUE INDEX IF NOT EXISTS cohorts_public_id ON cohorts(public_id);
 		`,
 		`
@@ -82,6 +167,7 @@ func New(connection string) (*DB, error) {
 			experiment_id INTEGER NOT NULL,
 			text TEXT NOT NULL CHECK(text <> ''),
 			type TEXT CHECK(type IN ('multiple', 'single', 'text')),
+			position INTEGER NOT NULL DEFAULT 0,
 			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE
 		);
@@ -91,6 +177,7 @@ func New(connection string) (*DB, error) {
 			id SERIAL PRIMARY KEY,
 			demographic_id INTEGER NOT NULL,
 			text TEXT NOT NULL CHECK(text <> ''),
+			position INTEGER NOT NULL DEFAULT 0,
 			FOREIGN KEY (demographic_id) REFERENCES demographics(id) ON DELETE CASCADE
 		);
 		`,
@@ -118,6 +205,87 @@ func New(connection string) (*DB, error) {
 			FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE
 		);
 		`,
+		`
+		CREATE TABLE IF NOT EXISTS participant_events (
+			id SERIAL PRIMARY KEY,
+			experiment_id INTEGER NOT NULL,
+			cohort_id INTEGER NOT NULL,
+			assessment_id INTEGER NOT NULL,
+			participant_id INTEGER NOT NULL,
+			type TEXT NOT NULL CHECK(type <> ''),
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE,
+			FOREIGN KEY (cohort_id) REFERENCES cohorts(id) ON DELETE CASCADE,
+			FOREIGN KEY (assessment_id) REFERENCES assessments(id) ON DELETE CASCADE,
+			FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS randomizations (
+			experiment_id INTEGER PRIMARY KEY,
+			method TEXT NOT NULL CHECK(method IN ('', 'simple', 'block', 'stratified')),
+			block_size INTEGER NOT NULL DEFAULT 0,
+			demographic_id INTEGER,
+			seed BIGINT NOT NULL,
+			FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE,
+			FOREIGN KEY (demographic_id) REFERENCES demographics(id) ON DELETE SET NULL
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS allocations (
+			id SERIAL PRIMARY KEY,
+			experiment_id INTEGER NOT NULL,
+			participant_id INTEGER NOT NULL UNIQUE,
+			cohort_id INTEGER NOT NULL,
+			method TEXT NOT NULL,
+			stratum TEXT NOT NULL DEFAULT '',
+			sequence INTEGER NOT NULL,
+			seed BIGINT NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE,
+			FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE,
+			FOREIGN KEY (cohort_id) REFERENCES cohorts(id) ON DELETE CASCADE
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);
+		`,
+		`
+		CREATE TABLE IF NOT EXISTS cohort_periods (
+			experiment_id INTEGER NOT NULL,
+			cohort_id INTEGER NOT NULL,
+			assessment_id INTEGER NOT NULL,
+			arm_id INTEGER NOT NULL,
+			PRIMARY KEY (cohort_id, assessment_id),
+			FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE,
+			FOREIGN KEY (cohort_id) REFERENCES cohorts(id) ON DELETE CASCADE,
+			FOREIGN KEY (assessment_id) REFERENCES assessments(id) ON DELETE CASCADE,
+			FOREIGN KEY (arm_id) REFERENCES arms(id) ON DELETE CASCADE
+		);
+		`,
+		// Columns added after the tables were first created
+		`
+		ALTER TABLE cohorts ADD COLUMN IF NOT EXISTS arm_id INTEGER REFERENCES arms(id) ON DELETE SET NULL;
+		`,
+		`
+		ALTER TABLE questions ADD COLUMN IF NOT EXISTS anchor TEXT;
+		`,
+		`
+		ALTER TABLE demographics ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+		`,
+		`
+		ALTER TABLE demographic_options ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+		`,
+		`
+		ALTER TABLE participants ADD COLUMN IF NOT EXISTS external_id TEXT;
+		`,
+		// Assessments are no longer restricted to pre and post
+		`
+		ALTER TABLE assessments DROP CONSTRAINT IF EXISTS assessments_type_check;
+		`,
 	}
 
 	for _, q := range queries {
@@ -127,5 +295,129 @@ func New(connection string) (*DB, error) {
 		}
 	}
 
-	return &DB{db}, nil
+	// Data migrations run once, in order
+	migrations := []struct {
+		version string
+		run     func(tx *sql.Tx) error
+	}{
+		{"arms", migrateArms},
+		{"cohort arm labels", dropArmLabels},
+	}
+
+	for _, m := range migrations {
+		err = migrate(db, m.version, m.run)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &DB{DB: db}, nil
+}
+
+// migrate runs a data migration unless it was already applied, recording it
+// in the same transaction. The lock keeps servers starting together from
+// running it twice.
+func migrate(db *sql.DB, version string, run func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`LOCK TABLE schema_migrations IN EXCLUSIVE MODE`)
+	if err != nil {
+		return err
+	}
+
+	var applied int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM schema_migrations WHERE version = $1`, version).Scan(&applied)
+	if err != nil {
+		return err
+	}
+	if applied > 0 {
+		return nil
+	}
+
+	if err := run(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO schema_migrations (version) VALUES ($1)`, version)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrateArms gives experiments created before arms existed one arm per arm
+// label of their cohorts, or per cohort without a label, with the arm of the
+// first cohort as control.
+func migrateArms(tx *sql.Tx) error {
+	label := "''"
+	if ok, err := hasColumn(tx, "cohorts", "arm"); err != nil {
+		return err
+	} else if ok {
+		label = "c.arm"
+	}
+
+	rows, err := tx.Query(`SELECT c.id, c.experiment_id, c.public_id, c.name,
+			COALESCE(c.description, ''), ` + label + `
+		FROM cohorts c
+		WHERE c.arm_id IS NULL
+		AND NOT EXISTS (SELECT 1 FROM arms a WHERE a.experiment_id = c.experiment_id)
+		ORDER BY c.experiment_id, c.created_at, c.id`)
+	if err != nil {
+		return err
+	}
+
+	var cohorts []legacyCohort
+	for rows.Next() {
+		var c legacyCohort
+		err = rows.Scan(&c.id, &c.experimentID, &c.publicID, &c.name, &c.description, &c.arm)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		cohorts = append(cohorts, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, a := range legacyArms(cohorts) {
+		var id int64
+		err := tx.QueryRow(`INSERT INTO arms (experiment_id, public_id, name, description, is_control)
+			VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			a.experimentID, a.publicID, a.name, a.description, a.control).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		for _, cohortID := range a.cohortIDs {
+			_, err = tx.Exec(`UPDATE cohorts SET arm_id = $1 WHERE id = $2`, id, cohortID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// dropArmLabels drops the arm labels cohorts had before arms existed, once
+// migrateArms has turned them into arms.
+func dropArmLabels(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE cohorts DROP COLUMN IF EXISTS arm`)
+	return err
+}
+
+// hasColumn returns whether a table has a column.
+func hasColumn(tx *sql.Tx, table, column string) (bool, error) {
+	var n int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1 AND column_name = $2`,
+		table, column).Scan(&n)
+	return n > 0, err
 }
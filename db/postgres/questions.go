@@ -8,11 +8,11 @@ import (
 )
 
 func (db *DB) CreateQuestion(q *edulab.Question) error {
-	query := `INSERT INTO questions (assessment_id, text, type)
-		VALUES ($1, $2, $3) RETURNING id`
+	query := `INSERT INTO questions (assessment_id, text, type, anchor)
+		VALUES ($1, $2, $3, $4) RETURNING id`
 
 	var id int64
-	err := db.QueryRow(query, q.AssessmentID, q.Text, q.Type).Scan(&id)
+	err := db.QueryRow(query, q.AssessmentID, q.Text, q.Type, q.Anchor).Scan(&id)
 	if err != nil {
 		return errors.Wrap(err, "could not create question")
 	}
@@ -22,12 +22,43 @@ func (db *DB) CreateQuestion(q *edulab.Question) error {
 	return nil
 }
 
+func (db *DB) UpdateQuestion(q edulab.Question) error {
+	query := `UPDATE questions
+		SET text = $1, type = $2, anchor = $3
+		WHERE assessment_id = $4 AND id = $5`
+
+	_, err := db.Exec(query, q.Text, q.Type, q.Anchor, q.AssessmentID, q.ID)
+	if err != nil {
+		return errors.Wrap(err, "could not update question")
+	}
+	return nil
+}
+
 func (db *DB) CreateQuestionChoice(qc *edulab.QuestionChoice) error {
 	query := `INSERT INTO question_choices (question_id, text, is_correct)
-		VALUES ($1, $2, $3)`
+		VALUES ($1, $2, $3) RETURNING id`
+
+	var id int64
+	err := db.QueryRow(query, qc.QuestionID, qc.Text, qc.IsCorrect).Scan(&id)
+	if err != nil {
+		return errors.Wrap(err, "could not create question choice")
+	}
+
+	qc.ID = strconv.FormatInt(id, 10)
+
+	return nil
+}
+
+func (db *DB) UpdateQuestionChoice(qc edulab.QuestionChoice) error {
+	query := `UPDATE question_choices
+		SET text = $1, is_correct = $2
+		WHERE question_id = $3 AND id = $4`
 
-	_, err := db.Exec(query, qc.QuestionID, qc.Text, qc.IsCorrect)
-	return errors.Wrap(err, "could not create question choice")
+	_, err := db.Exec(query, qc.Text, qc.IsCorrect, qc.QuestionID, qc.ID)
+	if err != nil {
+		return errors.Wrap(err, "could not update question choice")
+	}
+	return nil
 }
 
 func (db *DB) FindQuestion(assessmentID string, pid string) (edulab.Question, error) {
@@ -35,11 +66,12 @@ func (db *DB) FindQuestion(assessmentID string, pid string) (edulab.Question, er
 		AssessmentID: assessmentID,
 	}
 
-	query := `SELECT id, text, type
+	query := `SELECT id, text, type, COALESCE(anchor, '')
 		FROM questions
 		WHERE assessment_id = $1 AND id = $2`
 
-	err := db.QueryRow(query, assessmentID, pid).Scan(&question.ID, &question.Text, &question.Type)
+	err := db.QueryRow(query, assessmentID, pid).Scan(&question.ID, &question.Text,
+		&question.Type, &question.Anchor)
 	if err != nil {
 		return question, errors.Wrap(err, "could not find question")
 	}
@@ -48,7 +80,7 @@ func (db *DB) FindQuestion(assessmentID string, pid string) (edulab.Question, er
 
 func (db *DB) FindQuestions(assessmentID string) ([]edulab.Question, error) {
 
-	query := `SELECT id, text, type
+	query := `SELECT id, text, type, COALESCE(anchor, '')
 		FROM questions
 		WHERE assessment_id = $1
 		ORDER BY created_at ASC`
@@ -64,7 +96,7 @@ func (db *DB) FindQuestions(assessmentID string) ([]edulab.Question, error) {
 		q := edulab.Question{
 			AssessmentID: assessmentID,
 		}
-		err = rows.Scan(&q.ID, &q.Text, &q.Type)
+		err = rows.Scan(&q.ID, &q.Text, &q.Type, &q.Anchor)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not find questions")
 		}
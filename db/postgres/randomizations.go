@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+)
+
+// UpdateRandomization creates or replaces the randomization of an experiment.
+func (db *DB) UpdateRandomization(r edulab.Randomization) error {
+	query := `INSERT INTO randomizations (experiment_id, method, block_size, demographic_id, seed)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (experiment_id) DO UPDATE SET method = EXCLUDED.method,
+		block_size = EXCLUDED.block_size, demographic_id = EXCLUDED.demographic_id,
+		seed = EXCLUDED.seed`
+
+	demographicID := sql.NullString{String: r.DemographicID, Valid: r.DemographicID != ""}
+
+	_, err := db.Exec(query, r.ExperimentID, r.Method, r.BlockSize, demographicID, r.Seed)
+	if err != nil {
+		return errors.Wrap(err, "could not update randomization")
+	}
+
+	return nil
+}
+
+func (db *DB) FindRandomization(experimentID string) (edulab.Randomization, error) {
+	r := edulab.Randomization{
+		ExperimentID: experimentID,
+	}
+
+	query := `SELECT method, block_size, demographic_id, seed
+		FROM randomizations
+		WHERE experiment_id = $1`
+
+	var demographicID sql.NullString
+	err := db.QueryRow(query, experimentID).Scan(&r.Method, &r.BlockSize, &demographicID, &r.Seed)
+	if err != nil {
+		return r, errors.Wrap(err, "could not find randomization")
+	}
+	r.DemographicID = demographicID.String
+
+	return r, nil
+}
+
+// AllocateParticipant creates a participant in the cohort drawn from the
+// experiment's previous allocations and records the allocation. The
+// randomization row stays locked until the transaction, joining the current
+// one if any, commits, so concurrent participants are allocated one at a time.
+func (db *DB) AllocateParticipant(p *edulab.Participant,
+	draw func([]edulab.Allocation) (edulab.Allocation, error)) (edulab.Allocation, error) {
+
+	var a edulab.Allocation
+
+	err := db.Transaction(func(d edulab.Database) error {
+		tx := d.(*DB)
+
+		var experimentID string
+		err := tx.QueryRow(`SELECT experiment_id FROM randomizations
+			WHERE experiment_id = $1 FOR UPDATE`, p.ExperimentID).Scan(&experimentID)
+		if err != nil {
+			return errors.Wrap(err, "could not lock randomization")
+		}
+
+		allocations, err := tx.FindAllocations(p.ExperimentID)
+		if err != nil {
+			return err
+		}
+
+		a, err = draw(allocations)
+		if err != nil {
+			return err
+		}
+
+		p.CohortID = a.CohortID
+
+		query := `INSERT INTO participants (public_id, experiment_id, cohort_id, access_token)
+			VALUES ($1, $2, $3, $4) RETURNING id`
+
+		var id int64
+		err = tx.QueryRow(query, p.PublicID, p.ExperimentID, p.CohortID, p.AccessToken).Scan(&id)
+		if err != nil {
+			return errors.Wrap(err, "could not create participant")
+		}
+
+		p.ID = strconv.FormatInt(id, 10)
+		a.ParticipantID = p.ID
+
+		query = `INSERT INTO allocations (experiment_id, participant_id, cohort_id, method, stratum, sequence, seed)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+		err = tx.QueryRow(query, a.ExperimentID, a.ParticipantID, a.CohortID, a.Method,
+			a.Stratum, a.Sequence, a.Seed).Scan(&id)
+		if err != nil {
+			return errors.Wrap(err, "could not create allocation")
+		}
+
+		a.ID = strconv.FormatInt(id, 10)
+
+		return nil
+	})
+
+	return a, err
+}
+
+// FindAllocations returns the allocations of an experiment in the order they
+// were made.
+func (db *DB) FindAllocations(experimentID string) ([]edulab.Allocation, error) {
+	var allocations []edulab.Allocation
+
+	query := `SELECT id, participant_id, cohort_id, method, stratum, sequence, seed, created_at
+		FROM allocations
+		WHERE experiment_id = $1
+		ORDER BY id ASC`
+
+	rows, err := db.Query(query, experimentID)
+	if err != nil {
+		return allocations, errors.Wrap(err, "could not find allocations")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a := edulab.Allocation{
+			ExperimentID: experimentID,
+		}
+		err := rows.Scan(&a.ID, &a.ParticipantID, &a.CohortID, &a.Method, &a.Stratum,
+			&a.Sequence, &a.Seed, &a.CreatedAt)
+		if err != nil {
+			return allocations, errors.Wrap(err, "could not scan allocation")
+		}
+		allocations = append(allocations, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return allocations, errors.Wrap(err, "could not iterate allocations")
+	}
+
+	return allocations, nil
+}
+
+// FindAllocation returns the allocation of a participant.
+func (db *DB) FindAllocation(experimentID, participantID string) (edulab.Allocation, error) {
+	query := `SELECT id, cohort_id, method, stratum, sequence, seed, created_at
+		FROM allocations
+		WHERE experiment_id = $1 AND participant_id = $2`
+
+	a := edulab.Allocation{
+		ExperimentID:  experimentID,
+		ParticipantID: participantID,
+	}
+
+	err := db.QueryRow(query, experimentID, participantID).Scan(&a.ID, &a.CohortID, &a.Method,
+		&a.Stratum, &a.Sequence, &a.Seed, &a.CreatedAt)
+	if err != nil {
+		return a, errors.Wrap(err, "could not find allocation")
+	}
+
+	return a, nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+)
+
+// UpdateTranslation sets the text of a question or choice in a language.
+// An empty text removes the translation.
+func (db *DB) UpdateTranslation(t edulab.Translation) error {
+	if t.Text == "" {
+		query := `DELETE FROM translations
+			WHERE question_id = $1 AND choice_id = $2 AND lang = $3`
+
+		_, err := db.Exec(query, t.QuestionID, t.ChoiceID, t.Lang)
+		if err != nil {
+			return errors.Wrap(err, "could not delete translation")
+		}
+		return nil
+	}
+
+	query := `INSERT INTO translations (question_id, choice_id, lang, text)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (question_id, choice_id, lang) DO UPDATE SET text = EXCLUDED.text`
+
+	_, err := db.Exec(query, t.QuestionID, t.ChoiceID, t.Lang, t.Text)
+	if err != nil {
+		return errors.Wrap(err, "could not update translation")
+	}
+
+	return nil
+}
+
+func (db *DB) FindTranslations(assessmentID string) ([]edulab.Translation, error) {
+	query := `SELECT t.question_id, t.choice_id, t.lang, t.text
+		FROM translations AS t
+		JOIN questions AS q ON t.question_id = q.id
+		WHERE q.assessment_id = $1
+		ORDER BY t.question_id, t.choice_id, t.lang`
+
+	rows, err := db.Query(query, assessmentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find translations")
+	}
+
+	defer rows.Close()
+
+	var translations []edulab.Translation
+	for rows.Next() {
+		t := edulab.Translation{}
+		err = rows.Scan(&t.QuestionID, &t.ChoiceID, &t.Lang, &t.Text)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find translations")
+		}
+
+		translations = append(translations, t)
+	}
+
+	return translations, nil
+}
@@ -0,0 +1,134 @@
+package sqlite
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+)
+
+func (db *DB) CreateArm(a *edulab.Arm) error {
+	query := `INSERT INTO arms (experiment_id, public_id, name, description, is_control)
+	VALUES (?, ?, ?, ?, ?)`
+
+	res, err := db.Exec(query, a.ExperimentID, a.PublicID, a.Name, a.Description, a.Control)
+	if err != nil {
+		return createError(err, "could not create arm")
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.Wrap(err, "retrieve last arm id")
+	}
+
+	a.ID = strconv.FormatInt(id, 10)
+
+	return nil
+}
+
+func (db *DB) UpdateArm(experimentID string, a edulab.Arm) error {
+	query := `UPDATE arms
+	SET name = ?, description = ?, is_control = ?
+	WHERE experiment_id = ? AND public_id = ?`
+
+	_, err := db.Exec(query, a.Name, a.Description, a.Control, experimentID, a.PublicID)
+	if err != nil {
+		return errors.Wrap(err, "could not update arm")
+	}
+	return nil
+}
+
+func (db *DB) FindArm(experimentID string, publicID string) (edulab.Arm, error) {
+	arm := edulab.Arm{
+		ExperimentID: experimentID,
+		PublicID:     publicID,
+	}
+
+	query := `SELECT id, name, description, is_control
+	FROM arms
+	WHERE experiment_id = ? AND public_id = ?`
+
+	err := db.QueryRow(query, experimentID, publicID).Scan(&arm.ID, &arm.Name,
+		&arm.Description, &arm.Control)
+	if err != nil {
+		return arm, errors.Wrap(err, "could not find arm")
+	}
+	return arm, nil
+}
+
+// FindArms returns the arms of an experiment, with the control arm first.
+func (db *DB) FindArms(experimentID string) ([]edulab.Arm, error) {
+	var arms []edulab.Arm
+
+	query := `SELECT id, public_id, name, description, is_control
+	FROM arms
+	WHERE experiment_id = ?
+	ORDER BY is_control DESC, id ASC`
+
+	rows, err := db.Query(query, experimentID)
+	if err != nil {
+		return arms, errors.Wrap(err, "could not find arms")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		arm := edulab.Arm{
+			ExperimentID: experimentID,
+		}
+		err := rows.Scan(&arm.ID, &arm.PublicID, &arm.Name, &arm.Description, &arm.Control)
+		if err != nil {
+			return arms, errors.Wrap(err, "could not scan arm")
+		}
+		arms = append(arms, arm)
+	}
+
+	return arms, nil
+}
+
+// legacyCohort is a cohort created before arms existed, with the arm label
+// it may have been given then.
+type legacyCohort struct {
+	id, experimentID, publicID, name, description, arm string
+}
+
+// legacyArm is an arm to create for legacy cohorts.
+type legacyArm struct {
+	experimentID, publicID, name, description string
+	control                                   bool
+	cohortIDs                                 []string
+}
+
+// legacyArms groups the cohorts of each experiment by their arm label, in
+// order. Cohorts without a label get an arm of their own, named after them.
+// The first arm of each experiment is the control.
+func legacyArms(cohorts []legacyCohort) []legacyArm {
+	var arms []legacyArm
+	index := make(map[string]int)
+
+	for _, c := range cohorts {
+		key := c.experimentID + "/cohort/" + c.id
+		arm := legacyArm{
+			experimentID: c.experimentID,
+			publicID:     c.publicID,
+			name:         c.name,
+			description:  c.description,
+		}
+		if c.arm != "" {
+			key = c.experimentID + "/arm/" + c.arm
+			arm.name = c.arm
+			arm.description = ""
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(arms)
+			index[key] = i
+			arm.control = i == 0 || arms[i-1].experimentID != c.experimentID
+			arms = append(arms, arm)
+		}
+		arms[i].cohortIDs = append(arms[i].cohortIDs, c.id)
+	}
+
+	return arms
+}
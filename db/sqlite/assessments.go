@@ -14,7 +14,7 @@ func (db *DB) CreateAssessment(a *edulab.Assessment) error {
 
 	res, err := db.Exec(query, a.ExperimentID, a.PublicID, a.Description, a.Type)
 	if err != nil {
-		return errors.Wrap(err, "cannot create assessment")
+		return createError(err, "cannot create assessment")
 	}
 
 	id, err := res.LastInsertId()
@@ -1,6 +1,7 @@
 package sqlite
 
 import (
+	"database/sql"
 	"strconv"
 
 	"github.com/pkg/errors"
@@ -9,12 +10,14 @@ import (
 )
 
 func (db *DB) CreateCohort(c *edulab.Cohort) error {
-	query := `INSERT INTO cohorts (experiment_id, public_id, name, description)
-	VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO cohorts (experiment_id, arm_id, public_id, name, description)
+	VALUES (?, ?, ?, ?, ?)`
 
-	res, err := db.Exec(query, c.ExperimentID, c.PublicID, c.Name, c.Description)
+	armID := sql.NullString{String: c.ArmID, Valid: c.ArmID != ""}
+
+	res, err := db.Exec(query, c.ExperimentID, armID, c.PublicID, c.Name, c.Description)
 	if err != nil {
-		return errors.Wrap(err, "could not create cohort")
+		return createError(err, "could not create cohort")
 	}
 
 	id, err := res.LastInsertId()
@@ -29,10 +32,12 @@ func (db *DB) CreateCohort(c *edulab.Cohort) error {
 
 func (db *DB) UpdateCohort(experimentID string, c edulab.Cohort) error {
 	query := `UPDATE cohorts
-	SET name = ?, description = ?
+	SET arm_id = ?, name = ?, description = ?
 	WHERE experiment_id = ? AND public_id = ?`
 
-	_, err := db.Exec(query, c.Name, c.Description, experimentID, c.PublicID)
+	armID := sql.NullString{String: c.ArmID, Valid: c.ArmID != ""}
+
+	_, err := db.Exec(query, armID, c.Name, c.Description, experimentID, c.PublicID)
 	if err != nil {
 		return errors.Wrap(err, "could not update cohort")
 	}
@@ -45,21 +50,24 @@ func (db *DB) FindCohort(experimentID string, publicID string) (edulab.Cohort, e
 		PublicID:     publicID,
 	}
 
-	query := `SELECT id, name, description
+	query := `SELECT id, arm_id, name, description
 	FROM cohorts
 	WHERE experiment_id = ? AND public_id = ?`
 
-	err := db.QueryRow(query, experimentID, publicID).Scan(&cohort.ID, &cohort.Name, &cohort.Description)
+	var armID sql.NullString
+	err := db.QueryRow(query, experimentID, publicID).Scan(&cohort.ID, &armID,
+		&cohort.Name, &cohort.Description)
 	if err != nil {
 		return cohort, errors.Wrap(err, "could not find cohort")
 	}
+	cohort.ArmID = armID.String
 	return cohort, nil
 }
 
 func (db *DB) FindCohorts(experimentID string) ([]edulab.Cohort, error) {
 	var cohorts []edulab.Cohort
 
-	query := `SELECT id, public_id, name, description
+	query := `SELECT id, arm_id, public_id, name, description
 	FROM cohorts
 	WHERE experiment_id = ?
 	ORDER BY created_at ASC`
@@ -74,12 +82,70 @@ func (db *DB) FindCohorts(experimentID string) ([]edulab.Cohort, error) {
 		cohort := edulab.Cohort{
 			ExperimentID: experimentID,
 		}
-		err := rows.Scan(&cohort.ID, &cohort.PublicID, &cohort.Name, &cohort.Description)
+		var armID sql.NullString
+		err := rows.Scan(&cohort.ID, &armID, &cohort.PublicID, &cohort.Name, &cohort.Description)
 		if err != nil {
 			return cohorts, errors.Wrap(err, "could not scan cohort")
 		}
+		cohort.ArmID = armID.String
 		cohorts = append(cohorts, cohort)
 	}
 
 	return cohorts, nil
 }
+
+// UpdateCohortPeriod sets the arm of a cohort from an assessment onwards.
+// An empty arm removes the period.
+func (db *DB) UpdateCohortPeriod(p edulab.CohortPeriod) error {
+	if p.ArmID == "" {
+		q := `DELETE FROM cohort_periods WHERE cohort_id = ? AND assessment_id = ?`
+
+		_, err := db.Exec(q, p.CohortID, p.AssessmentID)
+		if err != nil {
+			return errors.Wrap(err, "delete cohort period")
+		}
+		return nil
+	}
+
+	q := `INSERT INTO cohort_periods (experiment_id, cohort_id, assessment_id, arm_id)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT (cohort_id, assessment_id) DO UPDATE SET arm_id = excluded.arm_id;`
+
+	_, err := db.Exec(q, p.ExperimentID, p.CohortID, p.AssessmentID, p.ArmID)
+	if err != nil {
+		return errors.Wrap(err, "update cohort period")
+	}
+
+	return nil
+}
+
+func (db *DB) FindCohortPeriods(experimentID string) ([]edulab.CohortPeriod, error) {
+	var periods []edulab.CohortPeriod
+
+	query := `SELECT cohort_id, assessment_id, arm_id
+	FROM cohort_periods WHERE experiment_id = ?
+	ORDER BY cohort_id, assessment_id`
+
+	rows, err := db.Query(query, experimentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "query cohort periods")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := edulab.CohortPeriod{
+			ExperimentID: experimentID,
+		}
+		err = rows.Scan(&p.CohortID, &p.AssessmentID, &p.ArmID)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan cohort periods")
+		}
+		periods = append(periods, p)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, errors.Wrap(err, "find cohort periods")
+	}
+
+	return periods, nil
+}
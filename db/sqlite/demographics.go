@@ -9,10 +9,10 @@ import (
 )
 
 func (db *DB) CreateDemographic(d *edulab.Demographic) error {
-	query := `INSERT INTO demographics (experiment_id, text, type)
-	VALUES (?, ?, ?)`
+	query := `INSERT INTO demographics (experiment_id, text, type, position)
+	VALUES (?, ?, ?, ?)`
 
-	res, err := db.Exec(query, d.ExperimentID, d.Text, d.Type)
+	res, err := db.Exec(query, d.ExperimentID, d.Text, d.Type, d.Position)
 	if err != nil {
 		return errors.Wrap(err, "could not create demographic")
 	}
@@ -27,12 +27,53 @@ func (db *DB) CreateDemographic(d *edulab.Demographic) error {
 	return nil
 }
 
+func (db *DB) UpdateDemographic(d edulab.Demographic) error {
+	query := `UPDATE demographics
+	SET text = ?, type = ?, position = ?
+	WHERE experiment_id = ? AND id = ?`
+
+	_, err := db.Exec(query, d.Text, d.Type, d.Position, d.ExperimentID, d.ID)
+	if err != nil {
+		return errors.Wrap(err, "could not update demographic")
+	}
+	return nil
+}
+
+// DeleteDemographic deletes a demographic with its options. The answers
+// participants already gave are kept in their participations.
+func (db *DB) DeleteDemographic(experimentID string, id string) error {
+	query := `DELETE FROM demographics WHERE experiment_id = ? AND id = ?`
+
+	_, err := db.Exec(query, experimentID, id)
+	if err != nil {
+		return errors.Wrap(err, "could not delete demographic")
+	}
+	return nil
+}
+
+func (db *DB) FindDemographic(experimentID string, id string) (edulab.Demographic, error) {
+	d := edulab.Demographic{
+		ExperimentID: experimentID,
+	}
+
+	query := `SELECT id, text, type, position
+	FROM demographics
+	WHERE experiment_id = ? AND id = ?`
+
+	err := db.QueryRow(query, experimentID, id).Scan(&d.ID, &d.Text, &d.Type, &d.Position)
+	if err != nil {
+		return d, errors.Wrap(err, "could not find demographic")
+	}
+	return d, nil
+}
+
 func (db *DB) FindDemographics(experimentID string) ([]edulab.Demographic, error) {
 	var demographics []edulab.Demographic
 
-	query := `SELECT id, text, type
+	query := `SELECT id, text, type, position
 	FROM demographics
-	WHERE experiment_id = ?`
+	WHERE experiment_id = ?
+	ORDER BY position ASC, id ASC`
 
 	rows, err := db.Query(query, experimentID)
 	if err != nil {
@@ -42,7 +83,7 @@ func (db *DB) FindDemographics(experimentID string) ([]edulab.Demographic, error
 
 	for rows.Next() {
 		var d edulab.Demographic
-		err := rows.Scan(&d.ID, &d.Text, &d.Type)
+		err := rows.Scan(&d.ID, &d.Text, &d.Type, &d.Position)
 		if err != nil {
 			return demographics, errors.Wrap(err, "could not scan demographic")
 		}
@@ -55,9 +96,9 @@ func (db *DB) FindDemographics(experimentID string) ([]edulab.Demographic, error
 }
 
 func (db *DB) CreateDemographicOption(o *edulab.DemographicOption) error {
-	query := `INSERT INTO demographic_options (demographic_id, text) VALUES (?, ?)`
+	query := `INSERT INTO demographic_options (demographic_id, text, position) VALUES (?, ?, ?)`
 
-	res, err := db.Exec(query, o.DemographicID, o.Text)
+	res, err := db.Exec(query, o.DemographicID, o.Text, o.Position)
 	if err != nil {
 		return errors.Wrap(err, "could not create demographic option")
 	}
@@ -72,14 +113,36 @@ func (db *DB) CreateDemographicOption(o *edulab.DemographicOption) error {
 	return nil
 }
 
+func (db *DB) UpdateDemographicOption(o edulab.DemographicOption) error {
+	query := `UPDATE demographic_options
+	SET text = ?, position = ?
+	WHERE demographic_id = ? AND id = ?`
+
+	_, err := db.Exec(query, o.Text, o.Position, o.DemographicID, o.ID)
+	if err != nil {
+		return errors.Wrap(err, "could not update demographic option")
+	}
+	return nil
+}
+
+func (db *DB) DeleteDemographicOption(demographicID string, id string) error {
+	query := `DELETE FROM demographic_options WHERE demographic_id = ? AND id = ?`
+
+	_, err := db.Exec(query, demographicID, id)
+	if err != nil {
+		return errors.Wrap(err, "could not delete demographic option")
+	}
+	return nil
+}
+
 func (db *DB) FindDemographicOptions(experimentID string) ([]edulab.DemographicOption, error) {
 	var options []edulab.DemographicOption
 
-	query := `SELECT o.id, o.demographic_id, o.text
+	query := `SELECT o.id, o.demographic_id, o.text, o.position
 	FROM demographic_options AS o
 	JOIN demographics AS d ON o.demographic_id = d.id
 	WHERE d.experiment_id = ?
-	ORDER BY o.id ASC`
+	ORDER BY o.position ASC, o.id ASC`
 
 	rows, err := db.Query(query, experimentID)
 	if err != nil {
@@ -89,7 +152,7 @@ func (db *DB) FindDemographicOptions(experimentID string) ([]edulab.DemographicO
 
 	for rows.Next() {
 		var o edulab.DemographicOption
-		err := rows.Scan(&o.ID, &o.DemographicID, &o.Text)
+		err := rows.Scan(&o.ID, &o.DemographicID, &o.Text, &o.Position)
 		if err != nil {
 			return options, errors.Wrap(err, "could not scan demographic option")
 		}
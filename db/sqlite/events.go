@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+)
+
+func (db *DB) CreateParticipantEvent(e *edulab.ParticipantEvent) error {
+	q := `INSERT into participant_events (experiment_id, cohort_id, assessment_id, participant_id, type)
+	values (?, ?, ?, ?, ?);`
+
+	res, err := db.Exec(q, e.ExperimentID, e.CohortID, e.AssessmentID, e.ParticipantID, e.Type)
+	if err != nil {
+		return errors.Wrap(err, "create participant event")
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.Wrap(err, "retrieve last participant event id")
+	}
+
+	e.ID = strconv.FormatInt(id, 10)
+
+	return nil
+}
+
+func (db *DB) FindParticipantEvents(experimentID string) ([]edulab.ParticipantEvent, error) {
+	var events []edulab.ParticipantEvent
+
+	query := `SELECT id, experiment_id, cohort_id, assessment_id, participant_id, type, created_at
+	FROM participant_events WHERE experiment_id = ?
+	ORDER BY id`
+
+	rows, err := db.Query(query, experimentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "query participant events")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e := edulab.ParticipantEvent{}
+		err = rows.Scan(&e.ID, &e.ExperimentID, &e.CohortID, &e.AssessmentID,
+			&e.ParticipantID, &e.Type, &e.CreatedAt)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan participant events")
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
@@ -13,7 +13,7 @@ func (db *DB) CreateExperiment(e *edulab.Experiment) error {
 
 	res, err := db.Exec(q, e.PublicID, e.Name, e.Description)
 	if err != nil {
-		return errors.Wrap(err, "create experiment")
+		return createError(err, "create experiment")
 	}
 
 	id, err := res.LastInsertId()
@@ -9,12 +9,12 @@ import (
 )
 
 func (db *DB) CreateParticipant(p *edulab.Participant) error {
-	q := `INSERT into participants (public_id, experiment_id, cohort_id, access_token)
-	values (?, ?, ?, ?);`
+	q := `INSERT into participants (public_id, experiment_id, cohort_id, access_token, external_id)
+	values (?, ?, ?, ?, ?);`
 
-	res, err := db.Exec(q, p.PublicID, p.ExperimentID, p.CohortID, p.AccessToken)
+	res, err := db.Exec(q, p.PublicID, p.ExperimentID, p.CohortID, p.AccessToken, p.ExternalID)
 	if err != nil {
-		return errors.Wrap(err, "create participant")
+		return createError(err, "create participant")
 	}
 
 	id, err := res.LastInsertId()
@@ -30,11 +30,11 @@ func (db *DB) CreateParticipant(p *edulab.Participant) error {
 func (db *DB) FindParticipant(experimentID string, accessToken string) (edulab.Participant, error) {
 	var p edulab.Participant
 
-	query := `SELECT id, public_id, experiment_id, cohort_id, access_token
+	query := `SELECT id, public_id, experiment_id, cohort_id, access_token, COALESCE(external_id, '')
 	FROM participants WHERE experiment_id = ? AND access_token = ?`
 
 	err := db.QueryRow(query, experimentID, accessToken).
-		Scan(&p.ID, &p.PublicID, &p.ExperimentID, &p.CohortID, &p.AccessToken)
+		Scan(&p.ID, &p.PublicID, &p.ExperimentID, &p.CohortID, &p.AccessToken, &p.ExternalID)
 	if err != nil {
 		return p, errors.Wrap(err, "query participant")
 	}
@@ -45,7 +45,7 @@ func (db *DB) FindParticipant(experimentID string, accessToken string) (edulab.P
 func (db *DB) FindParticipants(experimentID string) ([]edulab.Participant, error) {
 	var participants []edulab.Participant
 
-	query := `SELECT id, public_id, experiment_id, cohort_id, access_token
+	query := `SELECT id, public_id, experiment_id, cohort_id, access_token, COALESCE(external_id, '')
 	FROM participants WHERE experiment_id = ?
 	ORDER BY id`
 
@@ -57,7 +57,7 @@ func (db *DB) FindParticipants(experimentID string) ([]edulab.Participant, error
 
 	for rows.Next() {
 		p := edulab.Participant{}
-		err = rows.Scan(&p.ID, &p.PublicID, &p.ExperimentID, &p.CohortID, &p.AccessToken)
+		err = rows.Scan(&p.ID, &p.PublicID, &p.ExperimentID, &p.CohortID, &p.AccessToken, &p.ExternalID)
 		if err != nil {
 			return nil, errors.Wrap(err, "scan participants")
 		}
@@ -8,10 +8,10 @@ import (
 )
 
 func (db *DB) CreateQuestion(q *edulab.Question) error {
-	query := `INSERT INTO questions (assessment_id, text, type)
-	VALUES (?, ?, ?)`
+	query := `INSERT INTO questions (assessment_id, text, type, anchor)
+	VALUES (?, ?, ?, ?)`
 
-	res, err := db.Exec(query, q.AssessmentID, q.Text, q.Type)
+	res, err := db.Exec(query, q.AssessmentID, q.Text, q.Type, q.Anchor)
 	if err != nil {
 		return errors.Wrap(err, "could not create question")
 	}
@@ -26,13 +26,47 @@ func (db *DB) CreateQuestion(q *edulab.Question) error {
 	return nil
 }
 
+func (db *DB) UpdateQuestion(q edulab.Question) error {
+	query := `UPDATE questions
+	SET text = ?, type = ?, anchor = ?
+	WHERE assessment_id = ? AND id = ?`
+
+	_, err := db.Exec(query, q.Text, q.Type, q.Anchor, q.AssessmentID, q.ID)
+	if err != nil {
+		return errors.Wrap(err, "could not update question")
+	}
+	return nil
+}
+
 func (db *DB) CreateQuestionChoice(qc *edulab.QuestionChoice) error {
 	query := `INSERT INTO question_choices (question_id, text, is_correct)
 	VALUES (?, ?, ?)`
 
-	_, err := db.Exec(query, qc.QuestionID, qc.Text, qc.IsCorrect)
+	res, err := db.Exec(query, qc.QuestionID, qc.Text, qc.IsCorrect)
+	if err != nil {
+		return errors.Wrap(err, "could not create question choice")
+	}
 
-	return errors.Wrap(err, "could not create question choice")
+	id, err := res.LastInsertId()
+	if err != nil {
+		return errors.Wrap(err, "retrieve last question choice id")
+	}
+
+	qc.ID = strconv.FormatInt(id, 10)
+
+	return nil
+}
+
+func (db *DB) UpdateQuestionChoice(qc edulab.QuestionChoice) error {
+	query := `UPDATE question_choices
+	SET text = ?, is_correct = ?
+	WHERE question_id = ? AND id = ?`
+
+	_, err := db.Exec(query, qc.Text, qc.IsCorrect, qc.QuestionID, qc.ID)
+	if err != nil {
+		return errors.Wrap(err, "could not update question choice")
+	}
+	return nil
 }
 
 func (db *DB) FindQuestion(assessmentID string, pid string) (edulab.Question, error) {
@@ -40,11 +74,12 @@ func (db *DB) FindQuestion(assessmentID string, pid string) (edulab.Question, er
 		AssessmentID: assessmentID,
 	}
 
-	query := `SELECT id, text, type
+	query := `SELECT id, text, type, COALESCE(anchor, '')
 	FROM questions
-	WHERE assessment_id AND id = ?`
+	WHERE assessment_id = ? AND id = ?`
 
-	err := db.QueryRow(query, pid).Scan(&question.ID, &question.Text, &question.Type)
+	err := db.QueryRow(query, assessmentID, pid).Scan(&question.ID, &question.Text,
+		&question.Type, &question.Anchor)
 	if err != nil {
 		return question, errors.Wrap(err, "could not find question")
 	}
@@ -53,7 +88,7 @@ func (db *DB) FindQuestion(assessmentID string, pid string) (edulab.Question, er
 
 func (db *DB) FindQuestions(assessmentID string) ([]edulab.Question, error) {
 
-	query := `SELECT id, text, type
+	query := `SELECT id, text, type, COALESCE(anchor, '')
 	FROM questions
 	WHERE assessment_id = ?
 	ORDER BY created_at ASC`
@@ -70,7 +105,7 @@ func (db *DB) FindQuestions(assessmentID string) ([]edulab.Question, error) {
 		q := edulab.Question{
 			AssessmentID: assessmentID,
 		}
-		err = rows.Scan(&q.ID, &q.Text, &q.Type)
+		err = rows.Scan(&q.ID, &q.Text, &q.Type, &q.Anchor)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not find questions")
 		}
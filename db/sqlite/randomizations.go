@@ -0,0 +1,167 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+)
+
+// UpdateRandomization creates or replaces the randomization of an experiment.
+func (db *DB) UpdateRandomization(r edulab.Randomization) error {
+	q := `INSERT INTO randomizations (experiment_id, method, block_size, demographic_id, seed)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (experiment_id) DO UPDATE SET method = excluded.method,
+	block_size = excluded.block_size, demographic_id = excluded.demographic_id,
+	seed = excluded.seed;`
+
+	demographicID := sql.NullString{String: r.DemographicID, Valid: r.DemographicID != ""}
+
+	_, err := db.Exec(q, r.ExperimentID, r.Method, r.BlockSize, demographicID, r.Seed)
+	if err != nil {
+		return errors.Wrap(err, "update randomization")
+	}
+
+	return nil
+}
+
+func (db *DB) FindRandomization(experimentID string) (edulab.Randomization, error) {
+	q := `SELECT method, block_size, demographic_id, seed
+	FROM randomizations WHERE experiment_id = ?`
+
+	r := edulab.Randomization{
+		ExperimentID: experimentID,
+	}
+
+	var demographicID sql.NullString
+	err := db.QueryRow(q, experimentID).Scan(&r.Method, &r.BlockSize, &demographicID, &r.Seed)
+	if err != nil {
+		return r, errors.Wrap(err, "find randomization")
+	}
+	r.DemographicID = demographicID.String
+
+	return r, nil
+}
+
+// AllocateParticipant creates a participant in the cohort drawn from the
+// experiment's previous allocations and records the allocation. The
+// transaction, joining the current one if any, starts by writing to the
+// randomization, taking the database write lock before the allocations are
+// read.
+func (db *DB) AllocateParticipant(p *edulab.Participant,
+	draw func([]edulab.Allocation) (edulab.Allocation, error)) (edulab.Allocation, error) {
+
+	var a edulab.Allocation
+
+	err := db.Transaction(func(d edulab.Database) error {
+		tx := d.(*DB)
+
+		_, err := tx.Exec(`UPDATE randomizations SET seed = seed WHERE experiment_id = ?`, p.ExperimentID)
+		if err != nil {
+			return errors.Wrap(err, "lock randomization")
+		}
+
+		allocations, err := tx.FindAllocations(p.ExperimentID)
+		if err != nil {
+			return err
+		}
+
+		a, err = draw(allocations)
+		if err != nil {
+			return err
+		}
+
+		p.CohortID = a.CohortID
+
+		q := `INSERT into participants (public_id, experiment_id, cohort_id, access_token)
+		values (?, ?, ?, ?);`
+
+		res, err := tx.Exec(q, p.PublicID, p.ExperimentID, p.CohortID, p.AccessToken)
+		if err != nil {
+			return errors.Wrap(err, "create participant")
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return errors.Wrap(err, "retrieve last participant id")
+		}
+
+		p.ID = strconv.FormatInt(id, 10)
+		a.ParticipantID = p.ID
+
+		q = `INSERT INTO allocations (experiment_id, participant_id, cohort_id, method, stratum, sequence, seed)
+		VALUES (?, ?, ?, ?, ?, ?, ?);`
+
+		res, err = tx.Exec(q, a.ExperimentID, a.ParticipantID, a.CohortID, a.Method,
+			a.Stratum, a.Sequence, a.Seed)
+		if err != nil {
+			return errors.Wrap(err, "create allocation")
+		}
+
+		id, err = res.LastInsertId()
+		if err != nil {
+			return errors.Wrap(err, "retrieve last allocation id")
+		}
+
+		a.ID = strconv.FormatInt(id, 10)
+
+		return nil
+	})
+
+	return a, err
+}
+
+// FindAllocations returns the allocations of an experiment in the order they
+// were made.
+func (db *DB) FindAllocations(experimentID string) ([]edulab.Allocation, error) {
+	var allocations []edulab.Allocation
+
+	query := `SELECT id, participant_id, cohort_id, method, stratum, sequence, seed, created_at
+	FROM allocations WHERE experiment_id = ?
+	ORDER BY id`
+
+	rows, err := db.Query(query, experimentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "query allocations")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a := edulab.Allocation{
+			ExperimentID: experimentID,
+		}
+		err = rows.Scan(&a.ID, &a.ParticipantID, &a.CohortID, &a.Method, &a.Stratum,
+			&a.Sequence, &a.Seed, &a.CreatedAt)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan allocations")
+		}
+		allocations = append(allocations, a)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, errors.Wrap(err, "find allocations")
+	}
+
+	return allocations, nil
+}
+
+// FindAllocation returns the allocation of a participant.
+func (db *DB) FindAllocation(experimentID, participantID string) (edulab.Allocation, error) {
+	q := `SELECT id, cohort_id, method, stratum, sequence, seed, created_at
+	FROM allocations WHERE experiment_id = ? AND participant_id = ?`
+
+	a := edulab.Allocation{
+		ExperimentID:  experimentID,
+		ParticipantID: participantID,
+	}
+
+	err := db.QueryRow(q, experimentID, participantID).Scan(&a.ID, &a.CohortID, &a.Method,
+		&a.Stratum, &a.Sequence, &a.Seed, &a.CreatedAt)
+	if err != nil {
+		return a, errors.Wrap(err, "find allocation")
+	}
+
+	return a, nil
+}
@@ -1,13 +1,76 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
+	"strings"
 
 	sqlite3 "github.com/mattn/go-sqlite3"
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
 )
 
 type DB struct {
 	*sql.DB
+	tx *sql.Tx // Set while running a transaction
+}
+
+// Exec runs a statement inside the current transaction, if any.
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	if db.tx != nil {
+		return db.tx.Exec(query, args...)
+	}
+	return db.DB.Exec(query, args...)
+}
+
+// Query runs a query inside the current transaction, if any.
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	if db.tx != nil {
+		return db.tx.Query(query, args...)
+	}
+	return db.DB.Query(query, args...)
+}
+
+// QueryRow runs a single row query inside the current transaction, if any.
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	if db.tx != nil {
+		return db.tx.QueryRow(query, args...)
+	}
+	return db.DB.QueryRow(query, args...)
+}
+
+// Transaction runs fn on a database whose changes are committed together
+// when fn succeeds, and rolled back when it returns an error. Nested
+// transactions join the outer one.
+func (db *DB) Transaction(fn func(edulab.Database) error) error {
+	if db.tx != nil {
+		return fn(db)
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return errors.Wrap(err, "begin transaction")
+	}
+	defer tx.Rollback()
+
+	err = fn(&DB{DB: db.DB, tx: tx})
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(tx.Commit(), "commit transaction")
+}
+
+// createError wraps the error of an insert, reporting unique public ID
+// violations as edulab.ErrPublicIDTaken.
+func createError(err error, message string) error {
+	var se sqlite3.Error
+	if errors.As(err, &se) && se.ExtendedCode == sqlite3.ErrConstraintUnique &&
+		strings.HasSuffix(se.Error(), ".public_id") {
+		return errors.Wrap(edulab.ErrPublicIDTaken, message)
+	}
+	return errors.Wrap(err, message)
 }
 
 func init() {
@@ -26,6 +89,11 @@ func New(path string) (*DB, error) {
 		return nil, err
 	}
 
+	err = relaxAssessmentType(db)
+	if err != nil {
+		return nil, err
+	}
+
 	queries := []string{
 		`
     CREATE TABLE IF NOT EXISTS experiments(
@@ -45,7 +113,7 @@ func New(path string) (*DB, error) {
 		id INTEGER PRIMARY KEY,
 		experiment_id INTEGER NOT NULL,
         public_id TEXT NOT NULL UNIQUE CHECK(public_id <> ''),
-		type TEXT CHECK(type IN ('pre', 'post')),
+		type TEXT,
 		description TEXT,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE
@@ -73,17 +141,40 @@ func New(path string) (*DB, error) {
 		FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE
 	);`,
 		`
-	CREATE TABLE IF NOT EXISTS cohorts (
+	CREATE TABLE IF NOT EXISTS translations (
+		question_id INTEGER NOT NULL,
+		choice_id TEXT NOT NULL DEFAULT '',
+		lang TEXT NOT NULL CHECK(lang <> ''),
+		text TEXT NOT NULL CHECK(text <> ''),
+		PRIMARY KEY (question_id, choice_id, lang),
+		FOREIGN KEY (question_id) REFERENCES questions(id) ON DELETE CASCADE
+	);`,
+		`
+	CREATE TABLE IF NOT EXISTS arms (
 		id INTEGER PRIMARY KEY,
 		experiment_id INTEGER NOT NULL,
         public_id TEXT NOT NULL UNIQUE CHECK(public_id <> ''),
 		name TEXT NOT NULL CHECK(name <> ''),
 		description TEXT,
+		is_control BOOLEAN NOT NULL DEFAULT 0,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE
 	);
 	`,
 		`
+	CREATE TABLE IF NOT EXISTS cohorts (
+		id INTEGER PRIMARY KEY,
+		experiment_id INTEGER NOT NULL,
+        public_id TEXT NOT NULL UNIQUE CHECK(public_id <> ''),
+		name TEXT NOT NULL CHECK(name <> ''),
+		description TEXT,
+		arm_id INTEGER,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE,
+		FOREIGN KEY (arm_id) REFERENCES arms(id) ON DELETE SET NULL
+	);
+	`,
+		`
     CREATE UNIQUE INDEX IF NOT EXISTS cohorts_public_id ON
         cohorts(public_id);
     `,
@@ -93,6 +184,7 @@ func New(path string) (*DB, error) {
 		experiment_id INTEGER NOT NULL,
 		text TEXT NOT NULL CHECK(text <> ''),
 		type TEXT CHECK(type IN ('multiple', 'single', 'text')),
+		position INTEGER NOT NULL DEFAULT 0,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE
 	);`,
@@ -101,6 +193,7 @@ func New(path string) (*DB, error) {
 		id INTEGER PRIMARY KEY,
 		demographic_id INTEGER NOT NULL,
 		text TEXT NOT NULL CHECK(text <> ''),
+		position INTEGER NOT NULL DEFAULT 0,
 		FOREIGN KEY (demographic_id) REFERENCES demographics(id) ON DELETE CASCADE
 	);`,
 		`
@@ -124,6 +217,62 @@ func New(path string) (*DB, error) {
 		FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE,
 		FOREIGN KEY (assessment_id) REFERENCES assessments(id) ON DELETE CASCADE,
 		FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE
+	);`,
+		`
+	CREATE TABLE IF NOT EXISTS participant_events (
+		id INTEGER PRIMARY KEY,
+		experiment_id INTEGER NOT NULL,
+		cohort_id INTEGER NOT NULL,
+		assessment_id INTEGER NOT NULL,
+		participant_id INTEGER NOT NULL,
+		type TEXT NOT NULL CHECK(type <> ''),
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE,
+		FOREIGN KEY (cohort_id) REFERENCES cohorts(id) ON DELETE CASCADE,
+		FOREIGN KEY (assessment_id) REFERENCES assessments(id) ON DELETE CASCADE,
+		FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE
+	);`,
+		`
+	CREATE TABLE IF NOT EXISTS randomizations (
+		experiment_id INTEGER PRIMARY KEY,
+		method TEXT NOT NULL CHECK(method IN ('', 'simple', 'block', 'stratified')),
+		block_size INTEGER NOT NULL DEFAULT 0,
+		demographic_id INTEGER,
+		seed INTEGER NOT NULL,
+		FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE,
+		FOREIGN KEY (demographic_id) REFERENCES demographics(id) ON DELETE SET NULL
+	);`,
+		`
+	CREATE TABLE IF NOT EXISTS allocations (
+		id INTEGER PRIMARY KEY,
+		experiment_id INTEGER NOT NULL,
+		participant_id INTEGER NOT NULL UNIQUE,
+		cohort_id INTEGER NOT NULL,
+		method TEXT NOT NULL,
+		stratum TEXT NOT NULL DEFAULT '',
+		sequence INTEGER NOT NULL,
+		seed INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE,
+		FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE,
+		FOREIGN KEY (cohort_id) REFERENCES cohorts(id) ON DELETE CASCADE
+	);`,
+		`
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version TEXT PRIMARY KEY,
+		applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`,
+		`
+	CREATE TABLE IF NOT EXISTS cohort_periods (
+		experiment_id INTEGER NOT NULL,
+		cohort_id INTEGER NOT NULL,
+		assessment_id INTEGER NOT NULL,
+		arm_id INTEGER NOT NULL,
+		PRIMARY KEY (cohort_id, assessment_id),
+		FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE,
+		FOREIGN KEY (cohort_id) REFERENCES cohorts(id) ON DELETE CASCADE,
+		FOREIGN KEY (assessment_id) REFERENCES assessments(id) ON DELETE CASCADE,
+		FOREIGN KEY (arm_id) REFERENCES arms(id) ON DELETE CASCADE
 	);`,
 	}
 
@@ -135,5 +284,234 @@ func New(path string) (*DB, error) {
 		}
 	}
 
-	return &DB{db}, nil
+	// Columns added after the tables were first created
+	columns := []struct {
+		table, name, definition string
+	}{
+		{"cohorts", "arm_id", "INTEGER REFERENCES arms(id) ON DELETE SET NULL"},
+		{"questions", "anchor", "TEXT"},
+		{"demographics", "position", "INTEGER NOT NULL DEFAULT 0"},
+		{"demographic_options", "position", "INTEGER NOT NULL DEFAULT 0"},
+		{"participants", "external_id", "TEXT"},
+	}
+
+	for _, c := range columns {
+		err = addColumn(db, c.table, c.name, c.definition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Data migrations run once, in order
+	migrations := []struct {
+		version string
+		run     func(tx *sql.Tx) error
+	}{
+		{"arms", migrateArms},
+		{"cohort arm labels", dropArmLabels},
+	}
+
+	for _, m := range migrations {
+		err = migrate(db, m.version, m.run)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &DB{DB: db}, nil
+}
+
+// relaxAssessmentType rebuilds the assessments table of databases created
+// when the type was restricted to pre and post, since SQLite cannot drop a
+// CHECK constraint.
+func relaxAssessmentType(db *sql.DB) error {
+	var schema string
+	err := db.QueryRow(`SELECT sql FROM sqlite_master
+	WHERE type = 'table' AND name = 'assessments'`).Scan(&schema)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(schema, "CHECK(type IN") {
+		return nil
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Dropping the old table must not cascade to questions and participations
+	_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
+	if err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	queries := []string{
+		`
+	CREATE TABLE assessments_relaxed (
+		id INTEGER PRIMARY KEY,
+		experiment_id INTEGER NOT NULL,
+		public_id TEXT NOT NULL UNIQUE CHECK(public_id <> ''),
+		type TEXT,
+		description TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE
+	);`,
+		`INSERT INTO assessments_relaxed (id, experiment_id, public_id, type, description, created_at)
+	SELECT id, experiment_id, public_id, type, description, created_at FROM assessments;`,
+		`DROP TABLE assessments;`,
+		`ALTER TABLE assessments_relaxed RENAME TO assessments;`,
+	}
+
+	for _, q := range queries {
+		_, err = tx.ExecContext(ctx, q)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// migrate runs a data migration unless it was already applied, recording it
+// in the same transaction.
+func migrate(db *sql.DB, version string, run func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var applied int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`, version).Scan(&applied)
+	if err != nil {
+		return err
+	}
+	if applied > 0 {
+		return nil
+	}
+
+	if err := run(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, version)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrateArms gives experiments created before arms existed one arm per arm
+// label of their cohorts, or per cohort without a label, with the arm of the
+// first cohort as control.
+func migrateArms(tx *sql.Tx) error {
+	label := "''"
+	if ok, err := hasColumn(tx, "cohorts", "arm"); err != nil {
+		return err
+	} else if ok {
+		label = "arm"
+	}
+
+	rows, err := tx.Query(`SELECT c.id, c.experiment_id, c.public_id, c.name,
+		COALESCE(c.description, ''), ` + label + `
+	FROM cohorts c
+	WHERE c.arm_id IS NULL
+	AND NOT EXISTS (SELECT 1 FROM arms a WHERE a.experiment_id = c.experiment_id)
+	ORDER BY c.experiment_id, c.created_at, c.id`)
+	if err != nil {
+		return err
+	}
+
+	var cohorts []legacyCohort
+	for rows.Next() {
+		var c legacyCohort
+		err = rows.Scan(&c.id, &c.experimentID, &c.publicID, &c.name, &c.description, &c.arm)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		cohorts = append(cohorts, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, a := range legacyArms(cohorts) {
+		res, err := tx.Exec(`INSERT INTO arms (experiment_id, public_id, name, description, is_control)
+		VALUES (?, ?, ?, ?, ?)`, a.experimentID, a.publicID, a.name, a.description, a.control)
+		if err != nil {
+			return err
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		for _, cohortID := range a.cohortIDs {
+			_, err = tx.Exec(`UPDATE cohorts SET arm_id = ? WHERE id = ?`, id, cohortID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// dropArmLabels drops the arm labels cohorts had before arms existed, once
+// migrateArms has turned them into arms.
+func dropArmLabels(tx *sql.Tx) error {
+	ok, err := hasColumn(tx, "cohorts", "arm")
+	if err != nil || !ok {
+		return err
+	}
+
+	_, err = tx.Exec(`ALTER TABLE cohorts DROP COLUMN arm`)
+	return err
+}
+
+// hasColumn returns whether a table has a column.
+func hasColumn(tx *sql.Tx, table, column string) (bool, error) {
+	var n int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&n)
+	return n > 0, err
+}
+
+// addColumn adds a column to an existing table unless it already exists.
+func addColumn(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+
+	_, err = db.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition)
+	return err
 }
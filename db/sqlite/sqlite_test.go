@@ -1,6 +1,11 @@
 package sqlite
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/louisbranch/edulab"
@@ -10,3 +15,405 @@ func TestDBInterface(t *testing.T) {
 	var _ edulab.Database = &DB{}
 
 }
+
+func TestMigrateArms(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "edulab.db")
+
+	// Cohorts created before arms existed, grouped by an arm label
+	raw, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v, want nil", err)
+	}
+	for _, q := range []string{
+		`CREATE TABLE experiments (
+			id INTEGER PRIMARY KEY,
+			public_id TEXT NOT NULL UNIQUE CHECK(public_id <> ''),
+			name TEXT NOT NULL CHECK(name <> ''),
+			description TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE cohorts (
+			id INTEGER PRIMARY KEY,
+			experiment_id INTEGER NOT NULL,
+			public_id TEXT NOT NULL UNIQUE CHECK(public_id <> ''),
+			name TEXT NOT NULL CHECK(name <> ''),
+			description TEXT,
+			arm TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE
+		)`,
+		`INSERT INTO experiments (id, public_id, name) VALUES (1, 'E1', 'Labels'), (2, 'E2', 'Cohorts')`,
+		`INSERT INTO cohorts (id, experiment_id, public_id, name, description, arm) VALUES
+			(1, 1, 'C1', 'Section 1', '', 'Lecture'),
+			(2, 1, 'C2', 'Section 2', '', 'Workshop'),
+			(3, 1, 'C3', 'Section 3', '', 'Lecture'),
+			(4, 1, 'C4', 'Section 4', '', ''),
+			(5, 2, 'C5', 'Section 5', '', ''),
+			(6, 2, 'C6', 'Section 6', '', '')`,
+	} {
+		if _, err := raw.Exec(q); err != nil {
+			t.Fatalf("Exec(%q) error = %v, want nil", q, err)
+		}
+	}
+	raw.Close()
+
+	// Migrating twice must not create more arms
+	var db *DB
+	for i := 0; i < 2; i++ {
+		db, err = New(path)
+		if err != nil {
+			t.Fatalf("New() error = %v, want nil", err)
+		}
+		defer db.Close()
+	}
+
+	tests := []struct {
+		experimentID string
+		arms         []string
+		cohorts      []string // Arm name of each cohort
+	}{
+		{
+			experimentID: "1",
+			arms:         []string{"Lecture", "Workshop", "Section 4"},
+			cohorts:      []string{"Lecture", "Workshop", "Lecture", "Section 4"},
+		},
+		{
+			experimentID: "2",
+			arms:         []string{"Section 5", "Section 6"},
+			cohorts:      []string{"Section 5", "Section 6"},
+		},
+	}
+
+	for _, tt := range tests {
+		arms, err := db.FindArms(tt.experimentID)
+		if err != nil {
+			t.Fatalf("FindArms() error = %v, want nil", err)
+		}
+
+		names := make(map[string]string)
+		for i, a := range arms {
+			names[a.ID] = a.Name
+			if a.Name != tt.arms[i] || a.Control != (i == 0) {
+				t.Errorf("FindArms(%s)[%d] = %v, want %s", tt.experimentID, i, a, tt.arms[i])
+			}
+		}
+		if len(arms) != len(tt.arms) {
+			t.Fatalf("FindArms(%s) = %v, want %v", tt.experimentID, arms, tt.arms)
+		}
+
+		cohorts, err := db.FindCohorts(tt.experimentID)
+		if err != nil {
+			t.Fatalf("FindCohorts() error = %v, want nil", err)
+		}
+
+		for i, c := range cohorts {
+			if names[c.ArmID] != tt.cohorts[i] {
+				t.Errorf("FindCohorts(%s)[%d] arm = %q, want %q", tt.experimentID, i, names[c.ArmID], tt.cohorts[i])
+			}
+		}
+	}
+
+	// The labels are dropped once migrated
+	var labels int
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('cohorts') WHERE name = 'arm'`).Scan(&labels)
+	if err != nil || labels != 0 {
+		t.Errorf("cohorts.arm columns = %d, %v, want 0", labels, err)
+	}
+
+	// Cohorts created afterwards are given their arm when created
+	cohort := &edulab.Cohort{ExperimentID: "2", PublicID: "C7", Name: "Section 7"}
+	if err := db.CreateCohort(cohort); err != nil {
+		t.Fatalf("CreateCohort() error = %v, want nil", err)
+	}
+	db.Close()
+
+	db, err = New(path)
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer db.Close()
+
+	arms, _ := db.FindArms("2")
+	if len(arms) != 2 {
+		t.Errorf("FindArms() = %v, want no arm for the new cohort", arms)
+	}
+}
+
+func TestRelaxAssessmentType(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "edulab.db")
+
+	db, err := New(path)
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+
+	experiment := &edulab.Experiment{PublicID: "E1", Name: "Experiment"}
+	if err := db.CreateExperiment(experiment); err != nil {
+		t.Fatalf("CreateExperiment() error = %v, want nil", err)
+	}
+
+	assessment := &edulab.Assessment{ExperimentID: experiment.ID, PublicID: "A1",
+		Type: edulab.AssessmentTypePre}
+	if err := db.CreateAssessment(assessment); err != nil {
+		t.Fatalf("CreateAssessment() error = %v, want nil", err)
+	}
+
+	question := &edulab.Question{AssessmentID: assessment.ID, Text: "Why?", Type: edulab.InputText}
+	if err := db.CreateQuestion(question); err != nil {
+		t.Fatalf("CreateQuestion() error = %v, want nil", err)
+	}
+	db.Close()
+
+	// Restore the assessments table of databases restricted to pre and post
+	raw, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v, want nil", err)
+	}
+	for _, q := range []string{
+		`CREATE TABLE assessments_old (
+			id INTEGER PRIMARY KEY,
+			experiment_id INTEGER NOT NULL,
+			public_id TEXT NOT NULL UNIQUE CHECK(public_id <> ''),
+			type TEXT CHECK(type IN ('pre', 'post')),
+			description TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE
+		)`,
+		`INSERT INTO assessments_old SELECT * FROM assessments`,
+		`DROP TABLE assessments`,
+		`ALTER TABLE assessments_old RENAME TO assessments`,
+	} {
+		if _, err := raw.Exec(q); err != nil {
+			t.Fatalf("Exec(%q) error = %v, want nil", q, err)
+		}
+	}
+	raw.Close()
+
+	db, err = New(path)
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer db.Close()
+
+	questions, err := db.FindQuestions(assessment.ID)
+	if err != nil || len(questions) != 1 {
+		t.Fatalf("FindQuestions() = %v, %v, want the question kept", questions, err)
+	}
+
+	delayed := &edulab.Assessment{ExperimentID: experiment.ID, PublicID: "A2",
+		Type: edulab.AssessmentTypeDelayed}
+	if err := db.CreateAssessment(delayed); err != nil {
+		t.Errorf("CreateAssessment(delayed) error = %v, want nil", err)
+	}
+}
+
+func TestTranslations(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "edulab.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer db.Close()
+
+	experiment := &edulab.Experiment{PublicID: "E1", Name: "Experiment"}
+	if err := db.CreateExperiment(experiment); err != nil {
+		t.Fatalf("CreateExperiment() error = %v, want nil", err)
+	}
+
+	assessment := &edulab.Assessment{ExperimentID: experiment.ID, PublicID: "A1", Type: edulab.AssessmentTypePre}
+	if err := db.CreateAssessment(assessment); err != nil {
+		t.Fatalf("CreateAssessment() error = %v, want nil", err)
+	}
+
+	question := &edulab.Question{AssessmentID: assessment.ID, Text: "What causes seasons?", Type: edulab.InputSingle}
+	if err := db.CreateQuestion(question); err != nil {
+		t.Fatalf("CreateQuestion() error = %v, want nil", err)
+	}
+
+	choice := &edulab.QuestionChoice{QuestionID: question.ID, Text: "The tilt of Earth's axis", IsCorrect: true}
+	if err := db.CreateQuestionChoice(choice); err != nil {
+		t.Fatalf("CreateQuestionChoice() error = %v, want nil", err)
+	}
+	if choice.ID == "" {
+		t.Fatal("CreateQuestionChoice() ID = \"\", want an ID")
+	}
+
+	translations := []edulab.Translation{
+		{QuestionID: question.ID, Lang: "pt-BR", Text: "O que causa as estações?"},
+		{QuestionID: question.ID, ChoiceID: choice.ID, Lang: "pt-BR", Text: "A distância do Sol"},
+		{QuestionID: question.ID, ChoiceID: choice.ID, Lang: "pt-BR", Text: "A inclinação do eixo da Terra"},
+	}
+	for _, tr := range translations {
+		if err := db.UpdateTranslation(tr); err != nil {
+			t.Fatalf("UpdateTranslation() error = %v, want nil", err)
+		}
+	}
+
+	found, err := db.FindTranslations(assessment.ID)
+	if err != nil {
+		t.Fatalf("FindTranslations() error = %v, want nil", err)
+	}
+
+	want := []edulab.Translation{translations[0], translations[2]}
+	if len(found) != len(want) || found[0] != want[0] || found[1] != want[1] {
+		t.Fatalf("FindTranslations() = %v, want %v", found, want)
+	}
+
+	// An empty text removes the translation
+	if err := db.UpdateTranslation(edulab.Translation{QuestionID: question.ID, Lang: "pt-BR"}); err != nil {
+		t.Fatalf("UpdateTranslation() error = %v, want nil", err)
+	}
+
+	found, err = db.FindTranslations(assessment.ID)
+	if err != nil {
+		t.Fatalf("FindTranslations() error = %v, want nil", err)
+	}
+
+	if len(found) != 1 || found[0] != want[1] {
+		t.Errorf("FindTranslations() = %v, want %v", found, want[1:])
+	}
+}
+
+func TestAllocateParticipant(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "edulab.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer db.Close()
+
+	experiment := &edulab.Experiment{PublicID: "E1", Name: "Experiment"}
+	if err := db.CreateExperiment(experiment); err != nil {
+		t.Fatalf("CreateExperiment() error = %v, want nil", err)
+	}
+
+	cohort := &edulab.Cohort{ExperimentID: experiment.ID, PublicID: "C1", Name: "Section 1"}
+	if err := db.CreateCohort(cohort); err != nil {
+		t.Fatalf("CreateCohort() error = %v, want nil", err)
+	}
+
+	err = db.UpdateRandomization(edulab.Randomization{ExperimentID: experiment.ID,
+		Method: edulab.RandomizationSimple})
+	if err != nil {
+		t.Fatalf("UpdateRandomization() error = %v, want nil", err)
+	}
+
+	// Concurrent allocations each see the ones made before them
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p := &edulab.Participant{PublicID: fmt.Sprintf("P%d", i), ExperimentID: experiment.ID,
+				AccessToken: fmt.Sprintf("token%d", i)}
+			_, err := db.AllocateParticipant(p, func(previous []edulab.Allocation) (edulab.Allocation, error) {
+				return edulab.Allocation{ExperimentID: experiment.ID, CohortID: cohort.ID,
+					Method: edulab.RandomizationSimple, Sequence: len(previous) + 1}, nil
+			})
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("AllocateParticipant() error = %v, want nil", err)
+		}
+	}
+
+	allocations, err := db.FindAllocations(experiment.ID)
+	if err != nil {
+		t.Fatalf("FindAllocations() error = %v, want nil", err)
+	}
+
+	for i, a := range allocations {
+		if a.Sequence != i+1 || a.ParticipantID == "" {
+			t.Errorf("FindAllocations()[%d] = %v, want sequence %d", i, a, i+1)
+		}
+	}
+	if len(allocations) != 10 {
+		t.Errorf("FindAllocations() = %d allocations, want 10", len(allocations))
+	}
+
+	// Allocations inside a transaction roll back with it
+	failure := fmt.Errorf("failure")
+	err = db.Transaction(func(tx edulab.Database) error {
+		p := &edulab.Participant{PublicID: "P10", ExperimentID: experiment.ID, AccessToken: "token10"}
+		_, err := tx.AllocateParticipant(p, func(previous []edulab.Allocation) (edulab.Allocation, error) {
+			return edulab.Allocation{ExperimentID: experiment.ID, CohortID: cohort.ID,
+				Method: edulab.RandomizationSimple, Sequence: len(previous) + 1}, nil
+		})
+		if err != nil {
+			return err
+		}
+		return failure
+	})
+	if err != failure {
+		t.Fatalf("Transaction() error = %v, want %v", err, failure)
+	}
+
+	allocations, err = db.FindAllocations(experiment.ID)
+	if err != nil {
+		t.Fatalf("FindAllocations() error = %v, want nil", err)
+	}
+	if len(allocations) != 10 {
+		t.Errorf("FindAllocations() = %d allocations after a rollback, want 10", len(allocations))
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "edulab.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer db.Close()
+
+	failure := fmt.Errorf("failure")
+
+	err = db.Transaction(func(tx edulab.Database) error {
+		if err := tx.CreateExperiment(&edulab.Experiment{PublicID: "E1", Name: "Rolled back"}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if err != failure {
+		t.Fatalf("Transaction() error = %v, want %v", err, failure)
+	}
+
+	err = db.Transaction(func(tx edulab.Database) error {
+		return tx.CreateExperiment(&edulab.Experiment{PublicID: "E2", Name: "Committed"})
+	})
+	if err != nil {
+		t.Fatalf("Transaction() error = %v, want nil", err)
+	}
+
+	experiments, err := db.FindExperiments()
+	if err != nil {
+		t.Fatalf("FindExperiments() error = %v, want nil", err)
+	}
+	if len(experiments) != 1 || experiments[0].PublicID != "E2" {
+		t.Errorf("FindExperiments() = %v, want only E2", experiments)
+	}
+}
+
+func TestPublicIDTaken(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "edulab.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+	defer db.Close()
+
+	if err := db.CreateExperiment(&edulab.Experiment{PublicID: "E1", Name: "First"}); err != nil {
+		t.Fatalf("CreateExperiment() error = %v, want nil", err)
+	}
+
+	err = db.CreateExperiment(&edulab.Experiment{PublicID: "E1", Name: "Second"})
+	if !errors.Is(err, edulab.ErrPublicIDTaken) {
+		t.Errorf("CreateExperiment() error = %v, want %v", err, edulab.ErrPublicIDTaken)
+	}
+
+	err = db.CreateExperiment(&edulab.Experiment{Name: "No public ID"})
+	if err == nil || errors.Is(err, edulab.ErrPublicIDTaken) {
+		t.Errorf("CreateExperiment() error = %v, want a check constraint error", err)
+	}
+}
@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+)
+
+// UpdateTranslation sets the text of a question or choice in a language.
+// An empty text removes the translation.
+func (db *DB) UpdateTranslation(t edulab.Translation) error {
+	if t.Text == "" {
+		q := `DELETE FROM translations
+		WHERE question_id = ? AND choice_id = ? AND lang = ?`
+
+		_, err := db.Exec(q, t.QuestionID, t.ChoiceID, t.Lang)
+		if err != nil {
+			return errors.Wrap(err, "delete translation")
+		}
+		return nil
+	}
+
+	q := `INSERT INTO translations (question_id, choice_id, lang, text)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT (question_id, choice_id, lang) DO UPDATE SET text = excluded.text;`
+
+	_, err := db.Exec(q, t.QuestionID, t.ChoiceID, t.Lang, t.Text)
+	if err != nil {
+		return errors.Wrap(err, "update translation")
+	}
+
+	return nil
+}
+
+func (db *DB) FindTranslations(assessmentID string) ([]edulab.Translation, error) {
+	query := `SELECT t.question_id, t.choice_id, t.lang, t.text
+	FROM translations AS t
+	JOIN questions AS q ON t.question_id = q.id
+	WHERE q.assessment_id = ?
+	ORDER BY t.question_id, t.choice_id, t.lang`
+
+	rows, err := db.Query(query, assessmentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find translations")
+	}
+
+	defer rows.Close()
+
+	var translations []edulab.Translation
+	for rows.Next() {
+		t := edulab.Translation{}
+		err = rows.Scan(&t.QuestionID, &t.ChoiceID, &t.Lang, &t.Text)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find translations")
+		}
+
+		translations = append(translations, t)
+	}
+
+	return translations, nil
+}
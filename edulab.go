@@ -2,6 +2,7 @@ package edulab
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 )
 
@@ -17,10 +18,31 @@ type Experiment struct {
 type AssessmentType string
 
 const (
-	AssessmentTypePre  AssessmentType = "pre"
-	AssessmentTypePost AssessmentType = "post"
+	AssessmentTypePre     AssessmentType = "pre"
+	AssessmentTypeMid     AssessmentType = "mid"
+	AssessmentTypePost    AssessmentType = "post"
+	AssessmentTypeDelayed AssessmentType = "delayed"
 )
 
+// AssessmentTypes lists the assessment timepoints in chronological order.
+// Assessments of the same type follow the order they were created in.
+var AssessmentTypes = []AssessmentType{
+	AssessmentTypePre,
+	AssessmentTypeMid,
+	AssessmentTypePost,
+	AssessmentTypeDelayed,
+}
+
+// Valid reports whether the type is a known timepoint.
+func (t AssessmentType) Valid() bool {
+	for _, at := range AssessmentTypes {
+		if t == at {
+			return true
+		}
+	}
+	return false
+}
+
 type Assessment struct {
 	ID             string
 	ExperimentID   string
@@ -38,11 +60,14 @@ const (
 	InputText     InputType = "text"
 )
 
+// Question is an item of an assessment. Questions sharing an anchor are the
+// same item across assessments, even when their texts differ.
 type Question struct {
 	ID           string
 	AssessmentID string
 	Text         string
 	Type         InputType
+	Anchor       string
 }
 
 type QuestionChoice struct {
@@ -52,25 +77,65 @@ type QuestionChoice struct {
 	IsCorrect  bool   `json:"is_correct"`
 }
 
+// Translation is the text of a question, or of one of its choices, in
+// another language. Participants answer the same question and choices
+// whatever language they read them in, so results count them together.
+type Translation struct {
+	QuestionID string
+	ChoiceID   string // Empty for the text of the question
+	Lang       string
+	Text       string
+}
+
+// Arm is a treatment condition of an experiment, such as control or an
+// intervention. Cohorts assigned to the same arm receive the same treatment
+// and every other arm is compared against the control arm.
+type Arm struct {
+	ID           string
+	ExperimentID string
+	PublicID     string
+	Name         string
+	Description  string
+	Control      bool
+}
+
 type Cohort struct {
 	ID           string
 	ExperimentID string
+	ArmID        string
 	PublicID     string
 	Name         string
 	Description  string
 }
 
+// CohortPeriod assigns a cohort to an arm from an assessment onwards, so
+// cohorts can swap arms between periods in crossover designs. Cohorts use
+// their own arm until their first period.
+type CohortPeriod struct {
+	ExperimentID string
+	CohortID     string
+	AssessmentID string
+	ArmID        string
+}
+
+// Demographic is a question asked to participants before their first
+// assessment. Demographics are shown in the order of their position, then of
+// creation.
 type Demographic struct {
 	ID           string
 	ExperimentID string
 	Text         string
 	Type         InputType
+	Position     int
 }
 
+// DemographicOption is an answer to a single or multiple choice demographic,
+// ordered the same way as demographics.
 type DemographicOption struct {
 	ID            string
 	DemographicID string
 	Text          string
+	Position      int
 }
 
 type Participant struct {
@@ -79,6 +144,7 @@ type Participant struct {
 	ExperimentID string
 	CohortID     string
 	AccessToken  string
+	ExternalID   string // hashed student ID of imported participants
 }
 
 type Participation struct {
@@ -90,7 +156,75 @@ type Participation struct {
 	Demographics  json.RawMessage `json:"demographics"`
 }
 
+type EventType string
+
+const (
+	EventLinkOpened            EventType = "link_opened"
+	EventDemographicsSubmitted EventType = "demographics_submitted"
+	EventAssessmentStarted     EventType = "assessment_started"
+	EventAssessmentSubmitted   EventType = "assessment_submitted"
+)
+
+// EventTypes lists the participation events in funnel order.
+var EventTypes = []EventType{
+	EventLinkOpened,
+	EventDemographicsSubmitted,
+	EventAssessmentStarted,
+	EventAssessmentSubmitted,
+}
+
+type ParticipantEvent struct {
+	ID            string
+	ExperimentID  string
+	CohortID      string
+	AssessmentID  string
+	ParticipantID string
+	Type          EventType
+	CreatedAt     time.Time
+}
+
+type RandomizationMethod string
+
+const (
+	RandomizationSimple     RandomizationMethod = "simple"
+	RandomizationBlock      RandomizationMethod = "block"
+	RandomizationStratified RandomizationMethod = "stratified"
+)
+
+// Randomization configures the experiment-level link that assigns each new
+// participant to a cohort. Stratified randomization uses permuted blocks
+// within each answer of the stratification demographic.
+type Randomization struct {
+	ExperimentID  string
+	Method        RandomizationMethod
+	BlockSize     int
+	DemographicID string
+	Seed          int64
+}
+
+// Allocation records the cohort assigned to a participant by randomization.
+// Seed is the seed of the random draw, so the allocation can be reproduced.
+type Allocation struct {
+	ID            string
+	ExperimentID  string
+	ParticipantID string
+	CohortID      string
+	Method        RandomizationMethod
+	Stratum       string
+	Sequence      int
+	Seed          int64
+	CreatedAt     time.Time
+}
+
+// ErrPublicIDTaken is returned when a record is created with a public ID that
+// another record already has.
+var ErrPublicIDTaken = errors.New("public ID already taken")
+
 type Database interface {
+	// Transaction runs fn on a database whose changes are committed together
+	// when fn succeeds, and discarded when it returns an error.
+	Transaction(fn func(Database) error) error
+
 	CreateExperiment(*Experiment) error
 	UpdateExperiment(Experiment) error
 	FindExperiments() ([]Experiment, error)
@@ -104,19 +238,37 @@ type Database interface {
 	CreateQuestion(*Question) error
 	FindQuestion(assessmentID string, id string) (Question, error)
 	FindQuestions(assessmentID string) ([]Question, error)
+	UpdateQuestion(Question) error
 
 	CreateQuestionChoice(*QuestionChoice) error
+	UpdateQuestionChoice(QuestionChoice) error
 	FindQuestionChoices(assessmentID string) ([]QuestionChoice, error)
 
+	UpdateTranslation(Translation) error
+	FindTranslations(assessmentID string) ([]Translation, error)
+
+	CreateArm(*Arm) error
+	UpdateArm(experimentID string, a Arm) error
+	FindArm(experimentID string, publicID string) (Arm, error)
+	FindArms(experimentID string) ([]Arm, error)
+
 	CreateCohort(*Cohort) error
 	UpdateCohort(experimentID string, c Cohort) error
 	FindCohort(experimentID string, publicID string) (Cohort, error)
 	FindCohorts(experimentID string) ([]Cohort, error)
 
+	UpdateCohortPeriod(CohortPeriod) error
+	FindCohortPeriods(experimentID string) ([]CohortPeriod, error)
+
 	CreateDemographic(*Demographic) error
+	UpdateDemographic(Demographic) error
+	DeleteDemographic(experimentID string, id string) error
+	FindDemographic(experimentID string, id string) (Demographic, error)
 	FindDemographics(experimentID string) ([]Demographic, error)
 
 	CreateDemographicOption(*DemographicOption) error
+	UpdateDemographicOption(DemographicOption) error
+	DeleteDemographicOption(demographicID string, id string) error
 	FindDemographicOptions(experimentID string) ([]DemographicOption, error)
 
 	CreateParticipant(*Participant) error
@@ -129,4 +281,17 @@ type Database interface {
 	FindParticipations(experimentID string) ([]Participation, error)
 	FindParticipationsByParticipant(experimentID string, participantID string) ([]Participation, error)
 	FindParticipationsByAssessment(experimentID string, assessmentID string) ([]Participation, error)
+
+	CreateParticipantEvent(*ParticipantEvent) error
+	FindParticipantEvents(experimentID string) ([]ParticipantEvent, error)
+
+	UpdateRandomization(Randomization) error
+	FindRandomization(experimentID string) (Randomization, error)
+
+	// AllocateParticipant creates a participant in the cohort drawn from the
+	// experiment's previous allocations and records the allocation, in one
+	// transaction that locks the experiment's randomization.
+	AllocateParticipant(p *Participant, draw func([]Allocation) (Allocation, error)) (Allocation, error)
+	FindAllocations(experimentID string) ([]Allocation, error)
+	FindAllocation(experimentID, participantID string) (Allocation, error)
 }
@@ -15,4 +15,4 @@ require (
 	gopkg.in/yaml.v3 v3.0.1
 )
 
-require golang.org/x/exp v0.0.0-20231110203233-9a3e6036ecaa // indirect
+require golang.org/x/exp v0.0.0-20231110203233-9a3e6036ecaa
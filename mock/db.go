@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 
 	"gopkg.in/yaml.v3"
 
@@ -15,11 +17,17 @@ type DB struct {
 	assessments        []edulab.Assessment
 	questions          []edulab.Question
 	questionChoices    []edulab.QuestionChoice
+	translations       []edulab.Translation
+	arms               []edulab.Arm
 	cohorts            []edulab.Cohort
+	cohortPeriods      []edulab.CohortPeriod
 	demographics       []edulab.Demographic
 	demographicOptions []edulab.DemographicOption
 	participants       []edulab.Participant
 	participations     []edulab.Participation
+	events             []edulab.ParticipantEvent
+	randomizations     []edulab.Randomization
+	allocations        []edulab.Allocation
 }
 
 func NewDB() *DB {
@@ -46,6 +54,10 @@ func (db *DB) LoadFixtures(fixturesDir string) error {
 	if err := loadYAML(filepath.Join(fixturesDir, "question_choices.yaml"), &db.questionChoices); err != nil {
 		return err
 	}
+	// Load arms
+	if err := loadYAML(filepath.Join(fixturesDir, "arms.yaml"), &db.arms); err != nil {
+		return err
+	}
 	// Load cohorts
 	if err := loadYAML(filepath.Join(fixturesDir, "cohorts.yaml"), &db.cohorts); err != nil {
 		return err
@@ -78,8 +90,27 @@ func loadYAML(filePath string, out interface{}) error {
 	return yaml.Unmarshal(data, out)
 }
 
+// freeID returns the first ID after n that isn't used, as deleting records
+// leaves the count behind the IDs already assigned.
+func freeID(n int, used func(id string) bool) string {
+	id := strconv.Itoa(n + 1)
+	for used(id) {
+		n++
+		id = strconv.Itoa(n + 1)
+	}
+	return id
+}
+
+// Transaction runs fn on the same database. Changes are not rolled back.
+func (db *DB) Transaction(fn func(edulab.Database) error) error {
+	return fn(db)
+}
+
 // CreateExperiment creates a new experiment
 func (db *DB) CreateExperiment(e *edulab.Experiment) error {
+	if e.ID == "" {
+		e.ID = strconv.Itoa(len(db.experiments) + 1)
+	}
 	db.experiments = append(db.experiments, *e)
 	return nil
 }
@@ -123,6 +154,9 @@ func (db *DB) DeleteExperiment(publicID string) error {
 
 // CreateAssessment creates a new assessment
 func (db *DB) CreateAssessment(a *edulab.Assessment) error {
+	if a.ID == "" {
+		a.ID = strconv.Itoa(len(db.assessments) + 1)
+	}
 	db.assessments = append(db.assessments, *a)
 	return nil
 }
@@ -150,6 +184,9 @@ func (db *DB) FindAssessments(experimentID string) ([]edulab.Assessment, error)
 
 // CreateQuestion creates a new question
 func (db *DB) CreateQuestion(q *edulab.Question) error {
+	if q.ID == "" {
+		q.ID = strconv.Itoa(len(db.questions) + 1)
+	}
 	db.questions = append(db.questions, *q)
 	return nil
 }
@@ -176,12 +213,37 @@ func (db *DB) FindQuestions(assessmentID string) ([]edulab.Question, error) {
 	return result, nil
 }
 
+// UpdateQuestion updates a question
+func (db *DB) UpdateQuestion(q edulab.Question) error {
+	for i, question := range db.questions {
+		if question.AssessmentID == q.AssessmentID && question.ID == q.ID {
+			db.questions[i] = q
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
 // CreateQuestionChoice creates a new question choice
 func (db *DB) CreateQuestionChoice(qc *edulab.QuestionChoice) error {
+	if qc.ID == "" {
+		qc.ID = strconv.Itoa(len(db.questionChoices) + 1)
+	}
 	db.questionChoices = append(db.questionChoices, *qc)
 	return nil
 }
 
+// UpdateQuestionChoice updates a question choice
+func (db *DB) UpdateQuestionChoice(qc edulab.QuestionChoice) error {
+	for i, choice := range db.questionChoices {
+		if choice.QuestionID == qc.QuestionID && choice.ID == qc.ID {
+			db.questionChoices[i] = qc
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
 // FindQuestionChoices fetches question choices by question ID
 func (db *DB) FindQuestionChoices(assessmentID string) ([]edulab.QuestionChoice, error) {
 	questions := make(map[string]string)
@@ -198,8 +260,91 @@ func (db *DB) FindQuestionChoices(assessmentID string) ([]edulab.QuestionChoice,
 	return result, nil
 }
 
+// UpdateTranslation sets the text of a question or choice in a language
+func (db *DB) UpdateTranslation(t edulab.Translation) error {
+	for i, existing := range db.translations {
+		if existing.QuestionID == t.QuestionID && existing.ChoiceID == t.ChoiceID && existing.Lang == t.Lang {
+			if t.Text == "" {
+				db.translations = append(db.translations[:i], db.translations[i+1:]...)
+			} else {
+				db.translations[i] = t
+			}
+			return nil
+		}
+	}
+	if t.Text != "" {
+		db.translations = append(db.translations, t)
+	}
+	return nil
+}
+
+// FindTranslations fetches the translations of an assessment's questions
+func (db *DB) FindTranslations(assessmentID string) ([]edulab.Translation, error) {
+	questions := make(map[string]string)
+	for _, q := range db.questions {
+		questions[q.ID] = q.AssessmentID
+	}
+
+	var result []edulab.Translation
+	for _, t := range db.translations {
+		if questions[t.QuestionID] == assessmentID {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// CreateArm creates a new arm
+func (db *DB) CreateArm(a *edulab.Arm) error {
+	if a.ID == "" {
+		a.ID = strconv.Itoa(len(db.arms) + 1)
+	}
+	db.arms = append(db.arms, *a)
+	return nil
+}
+
+// UpdateArm updates an existing arm
+func (db *DB) UpdateArm(experimentID string, a edulab.Arm) error {
+	for i, arm := range db.arms {
+		if arm.ExperimentID == experimentID && arm.PublicID == a.PublicID {
+			a.ID = arm.ID
+			a.ExperimentID = experimentID
+			db.arms[i] = a
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+// FindArm fetches an arm by public ID
+func (db *DB) FindArm(experimentID, publicID string) (edulab.Arm, error) {
+	for _, a := range db.arms {
+		if a.ExperimentID == experimentID && a.PublicID == publicID {
+			return a, nil
+		}
+	}
+	return edulab.Arm{}, sql.ErrNoRows
+}
+
+// FindArms fetches arms by experiment ID, with the control arm first
+func (db *DB) FindArms(experimentID string) ([]edulab.Arm, error) {
+	var result []edulab.Arm
+	for _, a := range db.arms {
+		if a.ExperimentID == experimentID {
+			result = append(result, a)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Control && !result[j].Control
+	})
+	return result, nil
+}
+
 // CreateCohort creates a new cohort
 func (db *DB) CreateCohort(c *edulab.Cohort) error {
+	if c.ID == "" {
+		c.ID = strconv.Itoa(len(db.cohorts) + 1)
+	}
 	db.cohorts = append(db.cohorts, *c)
 	return nil
 }
@@ -236,19 +381,130 @@ func (db *DB) FindCohorts(experimentID string) ([]edulab.Cohort, error) {
 	return result, nil
 }
 
+// UpdateCohortPeriod sets the arm of a cohort from an assessment onwards
+func (db *DB) UpdateCohortPeriod(p edulab.CohortPeriod) error {
+	for i, existing := range db.cohortPeriods {
+		if existing.CohortID == p.CohortID && existing.AssessmentID == p.AssessmentID {
+			if p.ArmID == "" {
+				db.cohortPeriods = append(db.cohortPeriods[:i], db.cohortPeriods[i+1:]...)
+			} else {
+				db.cohortPeriods[i] = p
+			}
+			return nil
+		}
+	}
+	if p.ArmID != "" {
+		db.cohortPeriods = append(db.cohortPeriods, p)
+	}
+	return nil
+}
+
+// FindCohortPeriods fetches cohort periods by experiment ID
+func (db *DB) FindCohortPeriods(experimentID string) ([]edulab.CohortPeriod, error) {
+	var result []edulab.CohortPeriod
+	for _, p := range db.cohortPeriods {
+		if p.ExperimentID == experimentID {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
 // CreateDemographic creates a new demographic
 func (db *DB) CreateDemographic(d *edulab.Demographic) error {
+	if d.ID == "" {
+		d.ID = freeID(len(db.demographics), func(id string) bool {
+			for _, demographic := range db.demographics {
+				if demographic.ID == id {
+					return true
+				}
+			}
+			return false
+		})
+	}
 	db.demographics = append(db.demographics, *d)
 	return nil
 }
 
+// UpdateDemographic updates a demographic
+func (db *DB) UpdateDemographic(d edulab.Demographic) error {
+	for i, demographic := range db.demographics {
+		if demographic.ExperimentID == d.ExperimentID && demographic.ID == d.ID {
+			db.demographics[i] = d
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+// DeleteDemographic deletes a demographic with its options
+func (db *DB) DeleteDemographic(experimentID, id string) error {
+	for i, d := range db.demographics {
+		if d.ExperimentID == experimentID && d.ID == id {
+			db.demographics = append(db.demographics[:i], db.demographics[i+1:]...)
+
+			var options []edulab.DemographicOption
+			for _, o := range db.demographicOptions {
+				if o.DemographicID != id {
+					options = append(options, o)
+				}
+			}
+			db.demographicOptions = options
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+// FindDemographic fetches a demographic by ID
+func (db *DB) FindDemographic(experimentID, id string) (edulab.Demographic, error) {
+	for _, d := range db.demographics {
+		if d.ExperimentID == experimentID && d.ID == id {
+			return d, nil
+		}
+	}
+	return edulab.Demographic{}, sql.ErrNoRows
+}
+
 // CreateDemographicOption creates a new demographic option
 func (db *DB) CreateDemographicOption(d *edulab.DemographicOption) error {
+	if d.ID == "" {
+		d.ID = freeID(len(db.demographicOptions), func(id string) bool {
+			for _, o := range db.demographicOptions {
+				if o.ID == id {
+					return true
+				}
+			}
+			return false
+		})
+	}
 	db.demographicOptions = append(db.demographicOptions, *d)
 	return nil
 }
 
-// FindDemographics fetches demographics by experiment ID
+// UpdateDemographicOption updates a demographic option
+func (db *DB) UpdateDemographicOption(o edulab.DemographicOption) error {
+	for i, option := range db.demographicOptions {
+		if option.DemographicID == o.DemographicID && option.ID == o.ID {
+			db.demographicOptions[i] = o
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+// DeleteDemographicOption deletes a demographic option
+func (db *DB) DeleteDemographicOption(demographicID, id string) error {
+	for i, o := range db.demographicOptions {
+		if o.DemographicID == demographicID && o.ID == id {
+			db.demographicOptions = append(db.demographicOptions[:i], db.demographicOptions[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+// FindDemographics fetches demographics by experiment ID, in order
 func (db *DB) FindDemographics(experimentID string) ([]edulab.Demographic, error) {
 	var result []edulab.Demographic
 	for _, d := range db.demographics {
@@ -256,22 +512,37 @@ func (db *DB) FindDemographics(experimentID string) ([]edulab.Demographic, error
 			result = append(result, d)
 		}
 	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Position < result[j].Position
+	})
 	return result, nil
 }
 
-// FindDemographicOptions fetches demographic options by demographic ID
-func (db *DB) FindDemographicOptions(demographicID string) ([]edulab.DemographicOption, error) {
+// FindDemographicOptions fetches the demographic options of an experiment, in
+// order
+func (db *DB) FindDemographicOptions(experimentID string) ([]edulab.DemographicOption, error) {
+	demographics := make(map[string]string)
+	for _, d := range db.demographics {
+		demographics[d.ID] = d.ExperimentID
+	}
+
 	var result []edulab.DemographicOption
-	for _, d := range db.demographicOptions {
-		if d.DemographicID == demographicID {
-			result = append(result, d)
+	for _, o := range db.demographicOptions {
+		if demographics[o.DemographicID] == experimentID {
+			result = append(result, o)
 		}
 	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Position < result[j].Position
+	})
 	return result, nil
 }
 
 // CreateParticipant creates a new participant
 func (db *DB) CreateParticipant(p *edulab.Participant) error {
+	if p.ID == "" {
+		p.ID = strconv.Itoa(len(db.participants) + 1)
+	}
 	db.participants = append(db.participants, *p)
 	return nil
 }
@@ -306,7 +577,7 @@ func (db *DB) CreateParticipation(p *edulab.Participation) error {
 // UpdateParticipation updates an existing participation
 func (db *DB) UpdateParticipation(p edulab.Participation) error {
 	for i, pa := range db.participations {
-		if pa.ExperimentID == p.ExperimentID && pa.CohortID == p.CohortID && pa.ParticipantID == p.ParticipantID {
+		if pa.ExperimentID == p.ExperimentID && pa.AssessmentID == p.AssessmentID && pa.ParticipantID == p.ParticipantID {
 			db.participations[i] = p
 			return nil
 		}
@@ -356,3 +627,87 @@ func (db *DB) FindParticipationsByParticipant(experimentID, participantID string
 	}
 	return result, nil
 }
+
+// CreateParticipantEvent records a new participant event
+func (db *DB) CreateParticipantEvent(e *edulab.ParticipantEvent) error {
+	db.events = append(db.events, *e)
+	return nil
+}
+
+// FindParticipantEvents fetches participant events by experiment ID
+func (db *DB) FindParticipantEvents(experimentID string) ([]edulab.ParticipantEvent, error) {
+	var result []edulab.ParticipantEvent
+	for _, e := range db.events {
+		if e.ExperimentID == experimentID {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+// UpdateRandomization creates or replaces the randomization of an experiment
+func (db *DB) UpdateRandomization(r edulab.Randomization) error {
+	for i, existing := range db.randomizations {
+		if existing.ExperimentID == r.ExperimentID {
+			db.randomizations[i] = r
+			return nil
+		}
+	}
+	db.randomizations = append(db.randomizations, r)
+	return nil
+}
+
+// FindRandomization fetches the randomization of an experiment
+func (db *DB) FindRandomization(experimentID string) (edulab.Randomization, error) {
+	for _, r := range db.randomizations {
+		if r.ExperimentID == experimentID {
+			return r, nil
+		}
+	}
+	return edulab.Randomization{}, sql.ErrNoRows
+}
+
+// AllocateParticipant creates a participant in the drawn cohort and records
+// the allocation
+func (db *DB) AllocateParticipant(p *edulab.Participant,
+	draw func([]edulab.Allocation) (edulab.Allocation, error)) (edulab.Allocation, error) {
+
+	allocations, _ := db.FindAllocations(p.ExperimentID)
+
+	a, err := draw(allocations)
+	if err != nil {
+		return a, err
+	}
+
+	p.CohortID = a.CohortID
+	db.CreateParticipant(p)
+
+	a.ParticipantID = p.ID
+	if a.ID == "" {
+		a.ID = strconv.Itoa(len(db.allocations) + 1)
+	}
+	db.allocations = append(db.allocations, a)
+
+	return a, nil
+}
+
+// FindAllocation fetches the allocation of a participant
+func (db *DB) FindAllocation(experimentID, participantID string) (edulab.Allocation, error) {
+	for _, a := range db.allocations {
+		if a.ExperimentID == experimentID && a.ParticipantID == participantID {
+			return a, nil
+		}
+	}
+	return edulab.Allocation{}, sql.ErrNoRows
+}
+
+// FindAllocations fetches allocations by experiment ID
+func (db *DB) FindAllocations(experimentID string) ([]edulab.Allocation, error) {
+	var result []edulab.Allocation
+	for _, a := range db.allocations {
+		if a.ExperimentID == experimentID {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,96 @@
+// Package randomize assigns participants to cohorts for experiments that
+// share a single randomization link instead of one link per cohort.
+package randomize
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+)
+
+// Allocate assigns the next participant of a stratum to one of the cohorts.
+// Each draw is seeded from the randomization seed, the stratum and the
+// position in the sequence, so the whole allocation list can be reproduced
+// from the recorded seeds. Strata only apply to stratified randomization.
+func Allocate(r edulab.Randomization, cohorts []edulab.Cohort,
+	previous []edulab.Allocation, stratum string) (edulab.Allocation, error) {
+
+	if len(cohorts) == 0 {
+		return edulab.Allocation{}, errors.New("randomization needs at least one cohort")
+	}
+
+	if r.Method != edulab.RandomizationStratified {
+		stratum = ""
+	}
+
+	sequence := 0
+	for _, a := range previous {
+		if a.Stratum == stratum {
+			sequence++
+		}
+	}
+
+	a := edulab.Allocation{
+		ExperimentID: r.ExperimentID,
+		Method:       r.Method,
+		Stratum:      stratum,
+		Sequence:     sequence,
+	}
+
+	switch r.Method {
+	case edulab.RandomizationSimple:
+		a.Seed = drawSeed(r.Seed, stratum, sequence)
+		rng := rand.New(rand.NewSource(a.Seed))
+		a.CohortID = cohorts[rng.Intn(len(cohorts))].ID
+	case edulab.RandomizationBlock, edulab.RandomizationStratified:
+		size := BlockSize(r.BlockSize, len(cohorts))
+		a.Seed = drawSeed(r.Seed, stratum, sequence/size)
+		block := permutedBlock(a.Seed, size, len(cohorts))
+		a.CohortID = cohorts[block[sequence%size]].ID
+	default:
+		return a, errors.Errorf("unknown randomization method %q", r.Method)
+	}
+
+	return a, nil
+}
+
+// BlockSize returns the block size used for permuted blocks, rounded up to a
+// multiple of the number of cohorts so every block is balanced. Without a
+// configured size, blocks hold two participants per cohort.
+func BlockSize(size, cohorts int) int {
+	if cohorts < 1 {
+		return 0
+	}
+	if size < cohorts {
+		size = 2 * cohorts
+	}
+	return (size + cohorts - 1) / cohorts * cohorts
+}
+
+// permutedBlock returns a shuffled block with the same number of slots for
+// each cohort index.
+func permutedBlock(seed int64, size, cohorts int) []int {
+	block := make([]int, size)
+	for i := range block {
+		block[i] = i % cohorts
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(block), func(i, j int) {
+		block[i], block[j] = block[j], block[i]
+	})
+
+	return block
+}
+
+// drawSeed derives the seed of a single draw (or block) from the
+// randomization seed.
+func drawSeed(seed int64, stratum string, n int) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%s:%d", seed, stratum, n)
+	return int64(h.Sum64() >> 1)
+}
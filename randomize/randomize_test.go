@@ -0,0 +1,118 @@
+package randomize
+
+import (
+	"testing"
+
+	"github.com/louisbranch/edulab"
+)
+
+var cohorts = []edulab.Cohort{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+
+func allocateN(t *testing.T, r edulab.Randomization, strata []string) []edulab.Allocation {
+	t.Helper()
+
+	var allocations []edulab.Allocation
+	for _, stratum := range strata {
+		a, err := Allocate(r, cohorts, allocations, stratum)
+		if err != nil {
+			t.Fatalf("Allocate() error = %v, want nil", err)
+		}
+		allocations = append(allocations, a)
+	}
+	return allocations
+}
+
+func TestAllocateSimple(t *testing.T) {
+	r := edulab.Randomization{Method: edulab.RandomizationSimple, Seed: 42}
+
+	first := allocateN(t, r, make([]string, 30))
+	second := allocateN(t, r, make([]string, 30))
+
+	seen := make(map[string]bool)
+	for i := range first {
+		if first[i].CohortID != second[i].CohortID || first[i].Seed != second[i].Seed {
+			t.Fatalf("Allocate() is not reproducible at %d: %v != %v", i, first[i], second[i])
+		}
+		if first[i].Sequence != i {
+			t.Errorf("Allocate()[%d] sequence = %d, want %d", i, first[i].Sequence, i)
+		}
+		seen[first[i].CohortID] = true
+	}
+
+	if len(seen) != len(cohorts) {
+		t.Errorf("Allocate() used cohorts %v, want all %d", seen, len(cohorts))
+	}
+}
+
+func TestAllocateBlock(t *testing.T) {
+	r := edulab.Randomization{Method: edulab.RandomizationBlock, BlockSize: 6, Seed: 7}
+
+	allocations := allocateN(t, r, make([]string, 12))
+
+	for b := 0; b < 2; b++ {
+		counts := make(map[string]int)
+		for _, a := range allocations[b*6 : (b+1)*6] {
+			counts[a.CohortID]++
+		}
+		for _, c := range cohorts {
+			if counts[c.ID] != 2 {
+				t.Errorf("block %d counts = %v, want 2 per cohort", b, counts)
+			}
+		}
+	}
+}
+
+func TestAllocateStratified(t *testing.T) {
+	r := edulab.Randomization{Method: edulab.RandomizationStratified, BlockSize: 3, Seed: 1}
+
+	strata := []string{"a", "b", "a", "a", "b", "b"}
+	allocations := allocateN(t, r, strata)
+
+	counts := make(map[string]map[string]int)
+	for _, a := range allocations {
+		if counts[a.Stratum] == nil {
+			counts[a.Stratum] = make(map[string]int)
+		}
+		counts[a.Stratum][a.CohortID]++
+	}
+
+	for _, stratum := range []string{"a", "b"} {
+		if len(counts[stratum]) != len(cohorts) {
+			t.Errorf("stratum %s counts = %v, want one per cohort", stratum, counts[stratum])
+		}
+	}
+
+	if allocations[3].Sequence != 2 {
+		t.Errorf("Allocate()[3] sequence = %d, want 2", allocations[3].Sequence)
+	}
+}
+
+func TestAllocateErrors(t *testing.T) {
+	_, err := Allocate(edulab.Randomization{Method: edulab.RandomizationSimple}, nil, nil, "")
+	if err == nil {
+		t.Error("Allocate() without cohorts error = nil, want error")
+	}
+
+	_, err = Allocate(edulab.Randomization{Method: "coin"}, cohorts, nil, "")
+	if err == nil {
+		t.Error("Allocate() with unknown method error = nil, want error")
+	}
+}
+
+func TestBlockSize(t *testing.T) {
+	tests := []struct {
+		size, cohorts, want int
+	}{
+		{0, 2, 4},
+		{4, 2, 4},
+		{5, 2, 6},
+		{1, 3, 6},
+		{0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := BlockSize(tt.size, tt.cohorts); got != tt.want {
+			t.Errorf("BlockSize(%d, %d) = %d, want %d", tt.size, tt.cohorts, got, tt.want)
+		}
+	}
+}
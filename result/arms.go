@@ -0,0 +1,114 @@
+package result
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/stats"
+	"gonum.org/v1/gonum/stat"
+)
+
+// ArmSummary holds the cohorts of an arm and their learning gains.
+type ArmSummary struct {
+	Arm       edulab.Arm
+	CohortIDs []string
+	N         int
+	MeanGain  float64
+}
+
+// ArmAnalysis holds the effect of each arm on learning gains compared to the
+// first arm (control), accounting for participants nested in cohorts.
+type ArmAnalysis struct {
+	Arms  []ArmSummary
+	Mixed stats.MixedModel
+	OLS   stats.Regression // Ignores the nesting, for comparison
+}
+
+// Arms returns the given arms, in order, with the cohorts assigned to them.
+func (r *Result) Arms(armIDs []string) []ArmSummary {
+	cohortIDs := make([]string, 0, len(r.cohorts))
+	for id := range r.cohorts {
+		cohortIDs = append(cohortIDs, id)
+	}
+
+	sort.Slice(cohortIDs, func(i, j int) bool {
+		c1, _ := strconv.Atoi(cohortIDs[i])
+		c2, _ := strconv.Atoi(cohortIDs[j])
+		return c1 < c2
+	})
+
+	var arms []ArmSummary
+	for _, armID := range armIDs {
+		arm := ArmSummary{Arm: r.arms[armID]}
+		for _, id := range cohortIDs {
+			if r.cohorts[id].ArmID == armID {
+				arm.CohortIDs = append(arm.CohortIDs, id)
+			}
+		}
+		arms = append(arms, arm)
+	}
+
+	return arms
+}
+
+// ArmEffect fits the participant gains on arm indicators with a random
+// intercept per cohort.
+func (r *Result) ArmEffect(gains []Gain, armIDs []string) (ArmAnalysis, error) {
+	arms := r.Arms(armIDs)
+
+	armIndex := make(map[string]int)
+	cohortIndex := make(map[string]int)
+	for i, a := range arms {
+		armIndex[a.Arm.ID] = i
+		for _, id := range a.CohortIDs {
+			cohortIndex[id] = len(cohortIndex)
+		}
+	}
+
+	values := make([][]float64, len(arms))
+
+	var y []float64
+	var x [][]float64
+	var groups []int
+	for _, g := range gains {
+		a, ok := armIndex[g.ArmID]
+		if !ok {
+			continue
+		}
+
+		values[a] = append(values[a], g.Value())
+
+		row := make([]float64, len(arms)-1)
+		if a > 0 {
+			row[a-1] = 1
+		}
+
+		y = append(y, g.Value())
+		x = append(x, row)
+		groups = append(groups, cohortIndex[g.CohortID])
+	}
+
+	for i := range arms {
+		arms[i].N = len(values[i])
+		if len(values[i]) > 0 {
+			arms[i].MeanGain = stat.Mean(values[i], nil)
+		}
+	}
+
+	analysis := ArmAnalysis{Arms: arms}
+
+	mixed, err := stats.RandomIntercept(y, x, groups)
+	if err != nil {
+		return analysis, err
+	}
+	analysis.Mixed = mixed
+
+	ols, err := stats.OLS(y, x)
+	if err != nil {
+		return analysis, err
+	}
+	analysis.OLS = ols
+
+	return analysis, nil
+}
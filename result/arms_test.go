@@ -0,0 +1,66 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/louisbranch/edulab"
+)
+
+func TestArms(t *testing.T) {
+	r := &Result{
+		arms: map[string]edulab.Arm{
+			"1": {ID: "1", Name: "Lecture", Control: true},
+			"2": {ID: "2", Name: "Active"},
+			"3": {ID: "3", Name: "Unused"},
+		},
+		cohorts: map[string]edulab.Cohort{
+			"1":  {ID: "1", ArmID: "1", Name: "Section A"},
+			"2":  {ID: "2", ArmID: "2", Name: "Section B"},
+			"10": {ID: "10", ArmID: "1", Name: "Section C"},
+			"4":  {ID: "4", Name: "Section D"},
+		},
+	}
+
+	arms := r.Arms([]string{"1", "2", "3"})
+
+	expected := [][]string{{"1", "10"}, {"2"}, nil}
+
+	if len(arms) != len(expected) {
+		t.Fatalf("Arms() = %v, want %d arms", arms, len(expected))
+	}
+
+	for i := range expected {
+		if len(arms[i].CohortIDs) != len(expected[i]) {
+			t.Fatalf("Arms()[%d] cohorts = %v, want %v", i, arms[i].CohortIDs, expected[i])
+		}
+		for j := range expected[i] {
+			if arms[i].CohortIDs[j] != expected[i][j] {
+				t.Errorf("Arms()[%d] cohorts = %v, want %v", i, arms[i].CohortIDs, expected[i])
+			}
+		}
+	}
+
+	gains := []Gain{
+		{ParticipantID: "1", ArmID: "1", CohortID: "1", Post: 0.1},
+		{ParticipantID: "2", ArmID: "1", CohortID: "1", Post: 0.2},
+		{ParticipantID: "3", ArmID: "1", CohortID: "10", Post: 0.3},
+		{ParticipantID: "4", ArmID: "1", CohortID: "10", Post: 0.2},
+		{ParticipantID: "5", ArmID: "2", CohortID: "2", Post: 0.6},
+		{ParticipantID: "6", ArmID: "2", CohortID: "2", Post: 0.5},
+		{ParticipantID: "7", ArmID: "2", CohortID: "2", Post: 0.7},
+		{ParticipantID: "8", CohortID: "4", Post: 0.9}, // cohort without arm
+	}
+
+	analysis, err := r.ArmEffect(gains, []string{"1", "2"})
+	if err != nil {
+		t.Fatalf("ArmEffect() error = %v, want nil", err)
+	}
+
+	if analysis.Arms[0].N != 4 || analysis.Arms[1].N != 3 {
+		t.Errorf("ArmEffect() arm sizes = %d, %d, want 4, 3", analysis.Arms[0].N, analysis.Arms[1].N)
+	}
+
+	if analysis.Mixed.Groups != 3 {
+		t.Errorf("ArmEffect() groups = %d, want 3", analysis.Mixed.Groups)
+	}
+}
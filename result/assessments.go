@@ -6,26 +6,154 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/stats"
 )
 
-func CountChoicesByCohorts(db edulab.Database, experiment edulab.Experiment) ([][][]int, error) {
+// QuestionChoices holds how many times each choice of a question was selected
+// per arm and whether the choices are distributed differently.
+type QuestionChoices struct {
+	QuestionID string             `json:"question_id"`
+	Counts     [][]int            `json:"counts"` // [arm][choice]
+	Arms       stats.Contingency  `json:"arms"`
+	PrePost    *stats.Contingency `json:"pre_post,omitempty"`
+
+	question   edulab.Question
+	choices    []edulab.QuestionChoice
+	assessment edulab.AssessmentType
+}
+
+func CountChoicesByArms(db edulab.Database, experiment edulab.Experiment) ([][][]int, error) {
+	qcs, err := countChoices(db, experiment)
+	if err != nil {
+		return nil, err
+	}
+
+	var total [][][]int
+	for _, qc := range qcs {
+		total = append(total, qc.Counts)
+	}
+
+	return total, nil
+}
+
+// ChoiceTests counts the choices of each answered question per arm and
+// tests whether arms choose differently. Questions with the same text in
+// the pre- and post-assessments are also tested for differences between
+// assessments, matching their choices by text. Each selection of a
+// multiple-answer question counts as one observation, so their tests are
+// only approximate.
+func ChoiceTests(db edulab.Database, experiment edulab.Experiment) ([]QuestionChoices, error) {
+	qcs, err := countChoices(db, experiment)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range qcs {
+		qcs[i].Arms = stats.ContingencyTest(qcs[i].Counts)
+	}
+
+	for i := range qcs {
+		if qcs[i].assessment != edulab.AssessmentTypePre {
+			continue
+		}
+
+		for j := range qcs {
+			if qcs[j].assessment != edulab.AssessmentTypePost ||
+				qcs[j].question.Text != qcs[i].question.Text {
+				continue
+			}
+
+			table, ok := prePostTable(qcs[i], qcs[j])
+			if !ok {
+				continue
+			}
+
+			c := stats.ContingencyTest(table)
+			qcs[i].PrePost = &c
+			qcs[j].PrePost = &c
+		}
+	}
+
+	return qcs, nil
+}
+
+// prePostTable builds a table of the choices selected in the pre- and
+// post-assessments across all arms. Choices are matched by text, or by
+// position when the texts differ.
+func prePostTable(pre, post QuestionChoices) ([][]int, bool) {
+	if len(pre.choices) != len(post.choices) {
+		return nil, false
+	}
+
+	order := make([]int, len(post.choices))
+	for k, c := range post.choices {
+		order[k] = -1
+		for l, pc := range pre.choices {
+			if pc.Text == c.Text {
+				order[k] = l
+			}
+		}
+	}
+
+	for k := range order {
+		if order[k] == -1 {
+			for l := range order {
+				order[l] = l
+			}
+			break
+		}
+	}
+
+	table := [][]int{
+		make([]int, len(post.choices)),
+		make([]int, len(post.choices)),
+	}
+
+	for _, counts := range pre.Counts {
+		for k, l := range order {
+			table[0][k] += counts[l]
+		}
+	}
+
+	for _, counts := range post.Counts {
+		for k, v := range counts {
+			table[1][k] += v
+		}
+	}
+
+	return table, true
+}
+
+// countChoices counts how many times each choice was selected per arm,
+// skipping questions without answers.
+func countChoices(db edulab.Database, experiment edulab.Experiment) ([]QuestionChoices, error) {
 
 	participants, err := db.FindParticipants(experiment.ID)
 	if err != nil {
 		return nil, err
 	}
 
+	cohorts, err := db.FindCohorts(experiment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	arms, err := db.FindArms(experiment.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	participantCohorts := make(map[string]string)
 	for _, p := range participants {
 		participantCohorts[p.ID] = p.CohortID
 	}
 
-	participations, err := db.FindParticipations(experiment.ID)
-	if err != nil {
-		return nil, err
+	cohortArms := make(map[string]string)
+	for _, c := range cohorts {
+		cohortArms[c.ID] = c.ArmID
 	}
 
-	cohorts, err := db.FindCohorts(experiment.ID)
+	participations, err := db.FindParticipations(experiment.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -41,6 +169,7 @@ func CountChoicesByCohorts(db edulab.Database, experiment edulab.Experiment) ([]
 		if p.CohortID == "" {
 			p.CohortID = participantCohorts[p.ParticipantID]
 		}
+		armID := cohortArms[p.CohortID]
 
 		var answers map[string][]string
 		if err := json.Unmarshal(p.Answers, &answers); err != nil {
@@ -52,12 +181,12 @@ func CountChoicesByCohorts(db edulab.Database, experiment edulab.Experiment) ([]
 				questionsMap[questionID] = make(map[string]map[string]int)
 			}
 
-			if _, ok := questionsMap[questionID][p.CohortID]; !ok {
-				questionsMap[questionID][p.CohortID] = make(map[string]int)
+			if _, ok := questionsMap[questionID][armID]; !ok {
+				questionsMap[questionID][armID] = make(map[string]int)
 			}
 
 			for _, choiceID := range choiceIDs {
-				questionsMap[questionID][p.CohortID][choiceID]++
+				questionsMap[questionID][armID][choiceID]++
 			}
 		}
 	}
@@ -67,7 +196,7 @@ func CountChoicesByCohorts(db edulab.Database, experiment edulab.Experiment) ([]
 		return nil, err
 	}
 
-	var total [][][]int
+	var total []QuestionChoices
 
 	for _, assessment := range assessments {
 
@@ -83,19 +212,22 @@ func CountChoicesByCohorts(db edulab.Database, experiment edulab.Experiment) ([]
 
 		for _, question := range questions {
 
-			counts := make([][]int, len(cohorts))
+			counts := make([][]int, len(arms))
+			var qchoices []edulab.QuestionChoice
 
 			for _, choice := range choices {
 				if choice.QuestionID != question.ID {
 					continue
 				}
 
+				qchoices = append(qchoices, choice)
+
 				if _, ok := questionsMap[choice.QuestionID]; !ok {
 					continue
 				}
 
-				for i, cohort := range cohorts {
-					count := questionsMap[choice.QuestionID][cohort.ID][choice.ID]
+				for i, arm := range arms {
+					count := questionsMap[choice.QuestionID][arm.ID][choice.ID]
 					counts[i] = append(counts[i], count)
 				}
 
@@ -112,7 +244,13 @@ func CountChoicesByCohorts(db edulab.Database, experiment edulab.Experiment) ([]
 			}
 
 			if add {
-				total = append(total, counts)
+				total = append(total, QuestionChoices{
+					QuestionID: question.ID,
+					Counts:     counts,
+					question:   question,
+					choices:    qchoices,
+					assessment: assessment.Type,
+				})
 			}
 		}
 	}
@@ -8,7 +8,7 @@ import (
 	"github.com/louisbranch/edulab/mock"
 )
 
-func TestCountChoicesByCohorts(t *testing.T) {
+func TestCountChoicesByArms(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		db := mock.NewDB()
 
@@ -40,9 +40,9 @@ func TestCountChoicesByCohorts(t *testing.T) {
 			ID: "1",
 		}
 
-		actual, err := CountChoicesByCohorts(db, experiment)
+		actual, err := CountChoicesByArms(db, experiment)
 		if err != nil {
-			t.Errorf("CountChoicesByCohorts() error = %v, want nil", err)
+			t.Errorf("CountChoicesByArms() error = %v, want nil", err)
 			return
 		}
 
@@ -55,8 +55,48 @@ func TestCountChoicesByCohorts(t *testing.T) {
 
 		// Compare using reflect.DeepEqual
 		if !reflect.DeepEqual(actual, expected) {
-			t.Errorf("CountChoicesByCohorts() = %v, want %v", actual, expected)
+			t.Errorf("CountChoicesByArms() = %v, want %v", actual, expected)
 		}
 
 	})
 }
+
+func TestChoiceTests(t *testing.T) {
+	db := mock.NewDB()
+
+	participations := []edulab.Participation{
+		{AssessmentID: "1", CohortID: "1", ParticipantID: "1", Answers: []byte(`{"1":["1"]}`)},
+		{AssessmentID: "1", CohortID: "2", ParticipantID: "2", Answers: []byte(`{"1":["2"]}`)},
+		{AssessmentID: "2", CohortID: "1", ParticipantID: "1", Answers: []byte(`{"4":["8"]}`)},
+		{AssessmentID: "2", CohortID: "2", ParticipantID: "2", Answers: []byte(`{"4":["8"]}`)},
+	}
+
+	for _, p := range participations {
+		p := p
+		p.ExperimentID = "1"
+		if err := db.CreateParticipation(&p); err != nil {
+			t.Fatalf("CreateParticipation() error = %v, want nil", err)
+		}
+	}
+
+	qcs, err := ChoiceTests(db, edulab.Experiment{ID: "1"})
+	if err != nil {
+		t.Fatalf("ChoiceTests() error = %v, want nil", err)
+	}
+
+	if len(qcs) != 2 {
+		t.Fatalf("ChoiceTests() = %v, want 2 questions", qcs)
+	}
+
+	if qcs[0].QuestionID != "1" || !qcs[0].Arms.Exact {
+		t.Errorf("ChoiceTests()[0] = %+v, want exact test for question 1", qcs[0])
+	}
+
+	if qcs[0].PrePost == nil || qcs[0].PrePost != qcs[1].PrePost {
+		t.Fatalf("ChoiceTests() pre-post test not shared between paired questions")
+	}
+
+	if qcs[0].PrePost.N != 4 {
+		t.Errorf("ChoiceTests() pre-post N = %d, want 4", qcs[0].PrePost.N)
+	}
+}
@@ -0,0 +1,275 @@
+package result
+
+import (
+	"github.com/louisbranch/edulab"
+)
+
+// FunnelStep holds how many participants reached a participation event.
+type FunnelStep struct {
+	Type         edulab.EventType
+	Participants int
+	Rate         float64 // Relative to the step reached by most participants
+}
+
+// Funnel holds the participation steps of a cohort, or of all cohorts of an
+// arm, in an assessment.
+type Funnel struct {
+	CohortID     string // Empty when the funnel rolls up an arm
+	ArmID        string
+	AssessmentID string
+	Steps        []FunnelStep
+}
+
+// Attrition holds the participants of a cohort, or of all cohorts of an arm,
+// who submitted the pre-assessment but not the post-assessment.
+type Attrition struct {
+	CohortID     string // Empty when the attrition rolls up an arm
+	ArmID        string
+	Pre          int
+	Post         int
+	Lost         int
+	Rate         float64
+	Differential float64 // Rate difference against the first cohort or arm (control)
+}
+
+// NewFunnels counts the distinct participants that reached each event for
+// every cohort and assessment. Submissions recorded before events were tracked
+// are counted from the participations, so earlier steps may be missing for
+// older experiments.
+func NewFunnels(cohorts []edulab.Cohort, assessments []edulab.Assessment,
+	participants []edulab.Participant, participations []edulab.Participation,
+	events []edulab.ParticipantEvent) []Funnel {
+
+	cohortOf := make(map[string]string)
+	for _, p := range participants {
+		cohortOf[p.ID] = p.CohortID
+	}
+
+	reached := reachedEvents(cohortOf, participations, events)
+
+	var funnels []Funnel
+	for _, c := range cohorts {
+		for _, a := range assessments {
+			funnels = append(funnels, Funnel{
+				CohortID:     c.ID,
+				ArmID:        c.ArmID,
+				AssessmentID: a.ID,
+				Steps:        funnelSteps(reached[c.ID][a.ID]),
+			})
+		}
+	}
+
+	return funnels
+}
+
+// NewArmFunnels rolls up the funnels of NewFunnels by arm, counting the
+// participants of all cohorts of each arm.
+func NewArmFunnels(arms []edulab.Arm, cohorts []edulab.Cohort,
+	assessments []edulab.Assessment, participants []edulab.Participant,
+	participations []edulab.Participation, events []edulab.ParticipantEvent) []Funnel {
+
+	reached := reachedEvents(participantArms(cohorts, participants), participations, events)
+
+	var funnels []Funnel
+	for _, arm := range arms {
+		for _, a := range assessments {
+			funnels = append(funnels, Funnel{
+				ArmID:        arm.ID,
+				AssessmentID: a.ID,
+				Steps:        funnelSteps(reached[arm.ID][a.ID]),
+			})
+		}
+	}
+
+	return funnels
+}
+
+// reachedEvents groups the participants that reached each event by the
+// group (cohort or arm) they belong to and by assessment.
+func reachedEvents(groupOf map[string]string, participations []edulab.Participation,
+	events []edulab.ParticipantEvent) map[string]map[string]map[edulab.EventType]map[string]bool {
+
+	// group ID -> assessment ID -> event type -> participant IDs
+	reached := make(map[string]map[string]map[edulab.EventType]map[string]bool)
+	mark := func(groupID, assessmentID, participantID string, t edulab.EventType) {
+		if _, ok := reached[groupID]; !ok {
+			reached[groupID] = make(map[string]map[edulab.EventType]map[string]bool)
+		}
+		if _, ok := reached[groupID][assessmentID]; !ok {
+			reached[groupID][assessmentID] = make(map[edulab.EventType]map[string]bool)
+		}
+		if _, ok := reached[groupID][assessmentID][t]; !ok {
+			reached[groupID][assessmentID][t] = make(map[string]bool)
+		}
+		reached[groupID][assessmentID][t][participantID] = true
+	}
+
+	for _, e := range events {
+		mark(groupOf[e.ParticipantID], e.AssessmentID, e.ParticipantID, e.Type)
+	}
+
+	for _, p := range participations {
+		groupID := groupOf[p.ParticipantID]
+		if len(p.Demographics) > 0 {
+			mark(groupID, p.AssessmentID, p.ParticipantID, edulab.EventDemographicsSubmitted)
+		}
+		if len(p.Answers) > 0 {
+			mark(groupID, p.AssessmentID, p.ParticipantID, edulab.EventAssessmentSubmitted)
+		}
+	}
+
+	return reached
+}
+
+// funnelSteps counts the participants of every event type, relative to the
+// step reached by most participants.
+func funnelSteps(reached map[edulab.EventType]map[string]bool) []FunnelStep {
+	var steps []FunnelStep
+
+	top := 0
+	for _, t := range edulab.EventTypes {
+		step := FunnelStep{
+			Type:         t,
+			Participants: len(reached[t]),
+		}
+		if step.Participants > top {
+			top = step.Participants
+		}
+		steps = append(steps, step)
+	}
+
+	for i := range steps {
+		if top > 0 {
+			steps[i].Rate = float64(steps[i].Participants) / float64(top)
+		}
+	}
+
+	return steps
+}
+
+// NewAttrition calculates the attrition between pre- and post-assessments of
+// each cohort and the overall attrition rate. The first cohort is the
+// reference for differential attrition.
+func NewAttrition(cohorts []edulab.Cohort, assessments []edulab.Assessment,
+	participants []edulab.Participant,
+	participations []edulab.Participation) ([]Attrition, float64) {
+
+	ids := make([]string, len(cohorts))
+	for i, c := range cohorts {
+		ids[i] = c.ID
+	}
+
+	cohortOf := make(map[string]string)
+	for _, p := range participants {
+		cohortOf[p.ID] = p.CohortID
+	}
+
+	attrition, overall := groupAttrition(ids, cohortOf, assessments, participants, participations)
+	for i, c := range cohorts {
+		attrition[i].CohortID = c.ID
+		attrition[i].ArmID = c.ArmID
+	}
+
+	return attrition, overall
+}
+
+// NewArmAttrition rolls up the attrition of NewAttrition by arm. The first
+// arm (control) is the reference for differential attrition.
+func NewArmAttrition(arms []edulab.Arm, cohorts []edulab.Cohort,
+	assessments []edulab.Assessment, participants []edulab.Participant,
+	participations []edulab.Participation) ([]Attrition, float64) {
+
+	ids := make([]string, len(arms))
+	for i, a := range arms {
+		ids[i] = a.ID
+	}
+
+	attrition, overall := groupAttrition(ids, participantArms(cohorts, participants),
+		assessments, participants, participations)
+	for i, a := range arms {
+		attrition[i].ArmID = a.ID
+	}
+
+	return attrition, overall
+}
+
+// groupAttrition calculates the attrition of each group ID, in order, using
+// groupOf to find the group of a participant.
+func groupAttrition(ids []string, groupOf map[string]string,
+	assessments []edulab.Assessment, participants []edulab.Participant,
+	participations []edulab.Participation) ([]Attrition, float64) {
+
+	types := make(map[string]edulab.AssessmentType)
+	for _, a := range assessments {
+		types[a.ID] = a.Type
+	}
+
+	pre := make(map[string]bool)
+	post := make(map[string]bool)
+	for _, p := range participations {
+		if len(p.Answers) == 0 {
+			continue
+		}
+		switch types[p.AssessmentID] {
+		case edulab.AssessmentTypePre:
+			pre[p.ParticipantID] = true
+		case edulab.AssessmentTypePost:
+			post[p.ParticipantID] = true
+		}
+	}
+
+	index := make(map[string]int)
+	for i, id := range ids {
+		index[id] = i
+	}
+	attrition := make([]Attrition, len(ids))
+
+	for _, p := range participants {
+		i, ok := index[groupOf[p.ID]]
+		if !ok || !pre[p.ID] {
+			continue
+		}
+		attrition[i].Pre++
+		if post[p.ID] {
+			attrition[i].Post++
+		} else {
+			attrition[i].Lost++
+		}
+	}
+
+	var total, lost int
+	for i := range attrition {
+		a := &attrition[i]
+		total += a.Pre
+		lost += a.Lost
+		if a.Pre > 0 {
+			a.Rate = float64(a.Lost) / float64(a.Pre)
+		}
+		if i > 0 {
+			a.Differential = a.Rate - attrition[0].Rate
+		}
+	}
+
+	overall := 0.0
+	if total > 0 {
+		overall = float64(lost) / float64(total)
+	}
+
+	return attrition, overall
+}
+
+// participantArms maps each participant ID to the arm of their cohort.
+func participantArms(cohorts []edulab.Cohort,
+	participants []edulab.Participant) map[string]string {
+
+	arms := make(map[string]string)
+	for _, c := range cohorts {
+		arms[c.ID] = c.ArmID
+	}
+
+	armOf := make(map[string]string)
+	for _, p := range participants {
+		armOf[p.ID] = arms[p.CohortID]
+	}
+	return armOf
+}
@@ -0,0 +1,146 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/louisbranch/edulab"
+)
+
+func TestNewFunnels(t *testing.T) {
+	cohorts := []edulab.Cohort{{ID: "1", ArmID: "1"}}
+	assessments := []edulab.Assessment{{ID: "1", Type: edulab.AssessmentTypePre}}
+	participants := []edulab.Participant{
+		{ID: "1", CohortID: "1"},
+		{ID: "2", CohortID: "1"},
+	}
+	participations := []edulab.Participation{
+		{AssessmentID: "1", ParticipantID: "1", Answers: []byte(`{"1":["1"]}`)},
+	}
+	events := []edulab.ParticipantEvent{
+		{CohortID: "1", AssessmentID: "1", ParticipantID: "1", Type: edulab.EventLinkOpened},
+		{CohortID: "1", AssessmentID: "1", ParticipantID: "2", Type: edulab.EventLinkOpened},
+		{CohortID: "1", AssessmentID: "1", ParticipantID: "2", Type: edulab.EventLinkOpened},
+		{CohortID: "1", AssessmentID: "1", ParticipantID: "1", Type: edulab.EventAssessmentStarted},
+		{CohortID: "1", AssessmentID: "1", ParticipantID: "2", Type: edulab.EventAssessmentStarted},
+	}
+
+	funnels := NewFunnels(cohorts, assessments, participants, participations, events)
+	if len(funnels) != 1 {
+		t.Fatalf("NewFunnels() = %v, want 1 funnel", funnels)
+	}
+
+	if funnels[0].CohortID != "1" || funnels[0].ArmID != "1" {
+		t.Errorf("NewFunnels() cohort, arm = %q, %q, want 1, 1", funnels[0].CohortID, funnels[0].ArmID)
+	}
+
+	expected := []int{2, 0, 2, 1}
+	for i, step := range funnels[0].Steps {
+		if step.Participants != expected[i] {
+			t.Errorf("NewFunnels() step %s = %d, want %d", step.Type, step.Participants, expected[i])
+		}
+	}
+
+	if funnels[0].Steps[3].Rate != 0.5 {
+		t.Errorf("NewFunnels() submitted rate = %f, want 0.5", funnels[0].Steps[3].Rate)
+	}
+}
+
+func TestNewArmFunnels(t *testing.T) {
+	arms := []edulab.Arm{{ID: "1", Control: true}, {ID: "2"}}
+	cohorts := []edulab.Cohort{{ID: "1", ArmID: "1"}, {ID: "2", ArmID: "1"}, {ID: "3", ArmID: "2"}}
+	assessments := []edulab.Assessment{{ID: "1", Type: edulab.AssessmentTypePre}}
+	participants := []edulab.Participant{
+		{ID: "1", CohortID: "1"},
+		{ID: "2", CohortID: "2"},
+		{ID: "3", CohortID: "3"},
+	}
+	events := []edulab.ParticipantEvent{
+		{CohortID: "1", AssessmentID: "1", ParticipantID: "1", Type: edulab.EventLinkOpened},
+		{CohortID: "2", AssessmentID: "1", ParticipantID: "2", Type: edulab.EventLinkOpened},
+		{CohortID: "3", AssessmentID: "1", ParticipantID: "3", Type: edulab.EventLinkOpened},
+	}
+
+	funnels := NewArmFunnels(arms, cohorts, assessments, participants, nil, events)
+	if len(funnels) != 2 {
+		t.Fatalf("NewArmFunnels() = %v, want 2 funnels", funnels)
+	}
+
+	for i, want := range []int{2, 1} {
+		f := funnels[i]
+		if f.ArmID != arms[i].ID || f.CohortID != "" {
+			t.Errorf("NewArmFunnels() arm, cohort = %q, %q, want %q, empty", f.ArmID, f.CohortID, arms[i].ID)
+		}
+		if got := f.Steps[0].Participants; got != want {
+			t.Errorf("NewArmFunnels() arm %s opened = %d, want %d", f.ArmID, got, want)
+		}
+	}
+}
+
+func TestNewAttrition(t *testing.T) {
+	arms := []edulab.Arm{{ID: "1", Control: true}, {ID: "2"}}
+	cohorts := []edulab.Cohort{{ID: "1", ArmID: "1"}, {ID: "2", ArmID: "2"}, {ID: "3", ArmID: "2"}}
+	assessments := []edulab.Assessment{
+		{ID: "1", Type: edulab.AssessmentTypePre},
+		{ID: "2", Type: edulab.AssessmentTypePost},
+	}
+	participants := []edulab.Participant{
+		{ID: "1", CohortID: "1"},
+		{ID: "2", CohortID: "1"},
+		{ID: "3", CohortID: "2"},
+		{ID: "4", CohortID: "2"},
+		{ID: "5", CohortID: "3"},
+		{ID: "6", CohortID: "3"},
+	}
+	participations := []edulab.Participation{
+		{AssessmentID: "1", ParticipantID: "1", Answers: []byte(`{}`)},
+		{AssessmentID: "2", ParticipantID: "1", Answers: []byte(`{}`)},
+		{AssessmentID: "1", ParticipantID: "2", Answers: []byte(`{}`)},
+		{AssessmentID: "2", ParticipantID: "2", Answers: []byte(`{}`)},
+		{AssessmentID: "1", ParticipantID: "3", Answers: []byte(`{}`)},
+		{AssessmentID: "2", ParticipantID: "3", Answers: []byte(`{}`)},
+		{AssessmentID: "1", ParticipantID: "4", Answers: []byte(`{}`)},
+		{AssessmentID: "2", ParticipantID: "4", Demographics: []byte(`{}`)}, // not submitted
+		{AssessmentID: "1", ParticipantID: "5", Answers: []byte(`{}`)},
+		{AssessmentID: "2", ParticipantID: "5", Answers: []byte(`{}`)},
+		{AssessmentID: "1", ParticipantID: "6", Answers: []byte(`{}`)},
+		{AssessmentID: "2", ParticipantID: "6", Answers: []byte(`{}`)},
+	}
+
+	attrition, overall := NewAttrition(cohorts, assessments, participants, participations)
+
+	if len(attrition) != 3 || attrition[1].CohortID != "2" || attrition[1].ArmID != "2" {
+		t.Fatalf("NewAttrition() = %v, want one row per cohort", attrition)
+	}
+
+	if attrition[0].Lost != 0 || attrition[1].Lost != 1 || attrition[2].Lost != 0 {
+		t.Errorf("NewAttrition() lost = %d, %d, %d, want 0, 1, 0",
+			attrition[0].Lost, attrition[1].Lost, attrition[2].Lost)
+	}
+
+	if attrition[1].Differential != 0.5 {
+		t.Errorf("NewAttrition() differential = %f, want 0.5", attrition[1].Differential)
+	}
+
+	want := 1.0 / 6
+	if overall != want {
+		t.Errorf("NewAttrition() overall = %f, want %f", overall, want)
+	}
+
+	attrition, overall = NewArmAttrition(arms, cohorts, assessments, participants, participations)
+
+	if len(attrition) != 2 || attrition[1].ArmID != "2" || attrition[1].CohortID != "" {
+		t.Fatalf("NewArmAttrition() = %v, want one row per arm", attrition)
+	}
+
+	if attrition[1].Pre != 4 || attrition[1].Lost != 1 {
+		t.Errorf("NewArmAttrition() pre, lost = %d, %d, want 4, 1", attrition[1].Pre, attrition[1].Lost)
+	}
+
+	if attrition[1].Differential != 0.25 {
+		t.Errorf("NewArmAttrition() differential = %f, want 0.25", attrition[1].Differential)
+	}
+
+	if overall != want {
+		t.Errorf("NewArmAttrition() overall = %f, want %f", overall, want)
+	}
+}
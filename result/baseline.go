@@ -0,0 +1,136 @@
+package result
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/stats"
+	"gonum.org/v1/gonum/stat"
+)
+
+// BaselineGroup summarizes the pre-assessment scores of an arm.
+type BaselineGroup struct {
+	ArmID string
+	N     int
+	Mean  float64
+	SD    float64
+}
+
+// BaselineComparison compares the pre-assessment scores of an intervention
+// arm against the control arm.
+type BaselineComparison struct {
+	Control      BaselineGroup
+	Intervention BaselineGroup
+	T            float64 // Welch's t-statistic
+	DF           float64
+	PValueT      float64
+	U            float64 // Mann-Whitney U statistic
+	PValueU      float64
+	HedgesG      float64
+	Equivalence  stats.Equivalence
+}
+
+// PreScores returns the average pre-assessment score of each participant,
+// grouped by arm ID. Text questions are not scored.
+func (r *Result) PreScores() (map[string][]float64, error) {
+	participantIDs := make([]string, 0, len(r.participation))
+	for id := range r.participation {
+		participantIDs = append(participantIDs, id)
+	}
+
+	sort.Slice(participantIDs, func(i, j int) bool {
+		p1, _ := strconv.Atoi(participantIDs[i])
+		p2, _ := strconv.Atoi(participantIDs[j])
+		return p1 < p2
+	})
+
+	scores := make(map[string][]float64)
+
+	for _, participantID := range participantIDs {
+		total := 0.0
+		n := 0
+
+		for _, p := range r.participation[participantID] {
+			if r.assessments[p.AssessmentID].Type != edulab.AssessmentTypePre || len(p.Answers) == 0 {
+				continue
+			}
+
+			var answers map[string][]string
+			if err := json.Unmarshal(p.Answers, &answers); err != nil {
+				return nil, err
+			}
+
+			for questionID, answerIDs := range answers {
+				question, ok := r.questions[questionID]
+				if !ok || question.Type == edulab.InputText {
+					continue
+				}
+				total += r.scoreAnswer(question, answerIDs)
+				n++
+			}
+		}
+
+		armID := r.armOf(participantID)
+		if n == 0 || armID == "" {
+			continue
+		}
+
+		scores[armID] = append(scores[armID], total/float64(n))
+	}
+
+	return scores, nil
+}
+
+// Baseline compares the pre-assessment scores of each arm against the first
+// arm (control).
+func (r *Result) Baseline(armIDs []string) ([]BaselineComparison, error) {
+	if len(armIDs) < 2 {
+		return nil, nil
+	}
+
+	scores, err := r.PreScores()
+	if err != nil {
+		return nil, err
+	}
+
+	control := scores[armIDs[0]]
+
+	var comparisons []BaselineComparison
+	for _, id := range armIDs[1:] {
+		intervention := scores[id]
+
+		t, df, pt := stats.WelchTTest(intervention, control)
+		u, pu := stats.MannWhitneyU(intervention, control)
+		g := stats.HedgesG(control, intervention)
+
+		comparisons = append(comparisons, BaselineComparison{
+			Control:      baselineGroup(armIDs[0], control),
+			Intervention: baselineGroup(id, intervention),
+			T:            t,
+			DF:           df,
+			PValueT:      pt,
+			U:            u,
+			PValueU:      pu,
+			HedgesG:      g,
+			Equivalence:  stats.WWCEquivalence(g),
+		})
+	}
+
+	return comparisons, nil
+}
+
+func baselineGroup(armID string, scores []float64) BaselineGroup {
+	bg := BaselineGroup{
+		ArmID: armID,
+		N:     len(scores),
+	}
+	if len(scores) > 0 {
+		bg.Mean = stat.Mean(scores, nil)
+	}
+	if len(scores) > 1 {
+		bg.SD = stat.StdDev(scores, nil)
+	}
+	return bg
+}
@@ -0,0 +1,75 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/mock"
+)
+
+func TestPreScores(t *testing.T) {
+	db := mock.NewDB()
+	markCorrect(t, db, "1", "8")
+
+	participants := []edulab.Participant{
+		{ID: "1", ExperimentID: "1", CohortID: "1"},
+		{ID: "2", ExperimentID: "1", CohortID: "2"},
+	}
+
+	participations := []edulab.Participation{
+		{AssessmentID: "1", ParticipantID: "1", Answers: []byte(`{"1":["2"],"3":["text"]}`)},
+		{AssessmentID: "2", ParticipantID: "1", Answers: []byte(`{"4":["8"]}`)},
+		{AssessmentID: "1", ParticipantID: "2", Answers: []byte(`{"1":["1"]}`)},
+	}
+
+	for _, p := range participants {
+		p := p
+		if err := db.CreateParticipant(&p); err != nil {
+			t.Fatalf("CreateParticipant() error = %v, want nil", err)
+		}
+	}
+
+	for _, p := range participations {
+		p := p
+		p.ExperimentID = "1"
+		if err := db.CreateParticipation(&p); err != nil {
+			t.Fatalf("CreateParticipation() error = %v, want nil", err)
+		}
+	}
+
+	res, err := New(db, "1")
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+
+	if err := res.Load(); err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+
+	scores, err := res.PreScores()
+	if err != nil {
+		t.Fatalf("PreScores() error = %v, want nil", err)
+	}
+
+	if len(scores["1"]) != 1 || scores["1"][0] != 0 {
+		t.Errorf("PreScores()[1] = %v, want [0]", scores["1"])
+	}
+
+	if len(scores["2"]) != 1 || scores["2"][0] != 1 {
+		t.Errorf("PreScores()[2] = %v, want [1]", scores["2"])
+	}
+
+	comparisons, err := res.Baseline([]string{"1", "2"})
+	if err != nil {
+		t.Fatalf("Baseline() error = %v, want nil", err)
+	}
+
+	if len(comparisons) != 1 {
+		t.Fatalf("Baseline() = %v, want 1 comparison", comparisons)
+	}
+
+	if comparisons[0].Control.N != 1 || comparisons[0].Intervention.N != 1 {
+		t.Errorf("Baseline() groups = %v, %v, want 1 participant each",
+			comparisons[0].Control, comparisons[0].Intervention)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/louisbranch/edulab"
 	"github.com/louisbranch/edulab/stats"
 )
 
@@ -22,28 +23,45 @@ type AssessmentQuestions struct {
 	QuestionID   string
 }
 
-// NewComparison initializes a Comparison struct with scores across specified cohorts and assessments.
+// NewComparison initializes a Comparison struct with scores across specified
+// arms and assessments. When comparing two arms, the first is the control.
+// Assessments of a type already compared are numbered, such as post2_control
+// for the second post-assessment.
 func NewComparison(r *Result, assessmentQuestions []AssessmentQuestions,
-	cohorts []string) (*Comparison, error) {
+	arms []string) (*Comparison, error) {
 	c := &Comparison{
 		headers: []string{},
 		data:    make(map[string][]float64),
 	}
 
-	cohortLabels := []string{"control", "intervention"}
-	if len(cohorts) != 2 {
-		cohortLabels = []string{}
-		for _, cohortID := range cohorts {
-			cohort := r.cohorts[cohortID]
-			cohortLabels = append(cohortLabels, strings.ToLower(cohort.Name))
+	armLabels := []string{"control", "intervention"}
+	if len(arms) != 2 {
+		armLabels = []string{}
+		for _, armID := range arms {
+			arm := r.arms[armID]
+			armLabels = append(armLabels, strings.ToLower(arm.Name))
 		}
 	}
 
+	// Number the assessments of each type in the order they are compared
+	labels := make(map[string]string)
+	counts := make(map[edulab.AssessmentType]int)
+
 	// Populate score for each assignment question
 	for _, val := range assessmentQuestions {
 
 		assessement := r.assessments[val.AssessmentID]
 
+		label, ok := labels[assessement.ID]
+		if !ok {
+			counts[assessement.Type]++
+			label = string(assessement.Type)
+			if n := counts[assessement.Type]; n > 1 {
+				label += strconv.Itoa(n)
+			}
+			labels[assessement.ID] = label
+		}
+
 		questionID := val.QuestionID
 		question := r.questions[questionID]
 
@@ -55,13 +73,11 @@ func NewComparison(r *Result, assessmentQuestions []AssessmentQuestions,
 			continue
 		}
 
-		for i, cohortID := range cohorts {
-			label := cohortLabels[i]
-
-			header := fmt.Sprintf("%s_%s", assessement.Type, label)
+		for i, armID := range arms {
+			header := fmt.Sprintf("%s_%s", label, armLabels[i])
 			c.headers = append(c.headers, header)
 
-			score := scores[cohortID]
+			score := scores[armID]
 			if _, ok := c.data[header]; ok {
 				return nil, fmt.Errorf("%s already exists", header)
 			}
@@ -112,6 +128,12 @@ func (c *Comparison) ToCSV(filePath string) error {
 	return nil
 }
 
+// Scores returns every score of a column, such as post_control, including
+// the rows ToStatsData drops to pair the columns.
+func (c *Comparison) Scores(header string) []float64 {
+	return c.data[header]
+}
+
 func (c *Comparison) ToStatsData() []stats.Data {
 	var data []stats.Data
 	for i := 0; i < c.rows; i++ {
@@ -2,7 +2,10 @@ package result
 
 import (
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/louisbranch/edulab"
 )
 
 func TestToCSV(t *testing.T) {
@@ -46,3 +49,35 @@ func TestToCSV(t *testing.T) {
 	}
 
 }
+
+func TestNewComparisonRepeatedType(t *testing.T) {
+	r := &Result{
+		assessments: map[string]edulab.Assessment{
+			"1": {ID: "1", Type: edulab.AssessmentTypePre},
+			"2": {ID: "2", Type: edulab.AssessmentTypePost},
+			"3": {ID: "3", Type: edulab.AssessmentTypePost},
+		},
+		questions: map[string]edulab.Question{
+			"1": {ID: "1", AssessmentID: "1"},
+			"2": {ID: "2", AssessmentID: "2"},
+			"3": {ID: "3", AssessmentID: "3"},
+		},
+	}
+
+	items := []AssessmentQuestions{
+		{AssessmentID: "1", QuestionID: "1"},
+		{AssessmentID: "2", QuestionID: "2"},
+		{AssessmentID: "3", QuestionID: "3"},
+	}
+
+	c, err := NewComparison(r, items, []string{"1", "2"})
+	if err != nil {
+		t.Fatalf("NewComparison() error = %v, want nil", err)
+	}
+
+	expected := []string{"pre_control", "pre_intervention", "post_control",
+		"post_intervention", "post2_control", "post2_intervention"}
+	if strings.Join(c.headers, ",") != strings.Join(expected, ",") {
+		t.Errorf("NewComparison() headers = %v, want %v", c.headers, expected)
+	}
+}
@@ -0,0 +1,205 @@
+package result
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/louisbranch/edulab"
+)
+
+// Gain holds a participant's pre- and post-assessment scores.
+type Gain struct {
+	ParticipantID string
+	ArmID         string
+	CohortID      string
+	Pre           float64
+	Post          float64
+}
+
+// Value returns the learning gain (post - pre).
+func (g Gain) Value() float64 {
+	return g.Post - g.Pre
+}
+
+// ParticipantGains calculates the average pre- and post-assessment scores of
+// each participant across the comparison pairs. Only participants who answered
+// at least one question of a pair in both assessments are included. In
+// crossover designs, the gain belongs to the arm of the period that ends at the
+// post-assessment.
+func (r *Result) ParticipantGains(items [][]AssessmentQuestions) ([]Gain, error) {
+	type sums struct {
+		pre, post float64
+		n         int
+		armID     string
+	}
+
+	totals := make(map[string]*sums)
+
+	for _, item := range items {
+		var pre, post []string
+		var postAssessmentID string
+		for _, aq := range item {
+			switch r.assessments[aq.AssessmentID].Type {
+			case edulab.AssessmentTypePre:
+				pre = append(pre, aq.QuestionID)
+			case edulab.AssessmentTypePost:
+				if len(post) == 0 {
+					postAssessmentID = aq.AssessmentID
+				}
+				post = append(post, aq.QuestionID)
+			}
+		}
+
+		if len(pre) == 0 || len(post) == 0 {
+			continue
+		}
+
+		for participantID := range r.participation {
+			preScore, okPre, err := r.participantScore(participantID, pre[0])
+			if err != nil {
+				return nil, err
+			}
+			postScore, okPost, err := r.participantScore(participantID, post[0])
+			if err != nil {
+				return nil, err
+			}
+			if !okPre || !okPost {
+				continue
+			}
+
+			t, ok := totals[participantID]
+			if !ok {
+				t = &sums{armID: r.armUntil(participantID, postAssessmentID)}
+				totals[participantID] = t
+			}
+			t.pre += preScore
+			t.post += postScore
+			t.n++
+		}
+	}
+
+	var gains []Gain
+	for participantID, t := range totals {
+		gains = append(gains, Gain{
+			ParticipantID: participantID,
+			ArmID:         t.armID,
+			CohortID:      r.participants[participantID].CohortID,
+			Pre:           t.pre / float64(t.n),
+			Post:          t.post / float64(t.n),
+		})
+	}
+
+	sort.Slice(gains, func(i, j int) bool {
+		p1, _ := strconv.Atoi(gains[i].ParticipantID)
+		p2, _ := strconv.Atoi(gains[j].ParticipantID)
+		return p1 < p2
+	})
+
+	return gains, nil
+}
+
+// GainsByArm returns the learning gains of the participants of each arm on
+// the comparison items, keyed by arm ID. Each gain is the difference between
+// the scores of the same participant.
+func (r *Result) GainsByArm(items [][]AssessmentQuestions) (map[string][]float64, error) {
+	gains, err := r.ParticipantGains(items)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string][]float64)
+	for _, g := range gains {
+		if g.ArmID == "" {
+			continue
+		}
+		values[g.ArmID] = append(values[g.ArmID], g.Value())
+	}
+
+	return values, nil
+}
+
+// participantScore returns the score of a participant for a question and
+// whether the question was answered.
+func (r *Result) participantScore(participantID string, questionID string) (float64, bool, error) {
+	question, ok := r.questions[questionID]
+	if !ok || question.Type == edulab.InputText {
+		return 0, false, nil
+	}
+
+	for _, p := range r.participation[participantID] {
+		if p.AssessmentID != question.AssessmentID || len(p.Answers) == 0 {
+			continue
+		}
+
+		var answers map[string][]string
+		if err := json.Unmarshal(p.Answers, &answers); err != nil {
+			return 0, false, err
+		}
+
+		answerIDs, answered := answers[questionID]
+		if !answered {
+			return 0, false, nil
+		}
+
+		return r.scoreAnswer(question, answerIDs), true, nil
+	}
+
+	return 0, false, nil
+}
+
+// ParticipantDemographics returns the demographic options selected by a
+// participant, keyed by demographic ID.
+func (r *Result) ParticipantDemographics(participantID string) map[string][]string {
+	for _, p := range r.participation[participantID] {
+		if len(p.Demographics) == 0 {
+			continue
+		}
+		return parseDemographics(p.Demographics)
+	}
+	return map[string][]string{}
+}
+
+// FilterByDemographic keeps only the participants who selected the given
+// demographic option.
+func (r *Result) FilterByDemographic(optionID string) {
+	for participantID := range r.participation {
+		selected := false
+		for _, options := range r.ParticipantDemographics(participantID) {
+			for _, o := range options {
+				if o == optionID {
+					selected = true
+				}
+			}
+		}
+
+		if !selected {
+			delete(r.participation, participantID)
+		}
+	}
+}
+
+// parseDemographics decodes demographic answers stored either as a single
+// option ID or as a list of option IDs.
+func parseDemographics(raw json.RawMessage) map[string][]string {
+	var values map[string]interface{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return map[string][]string{}
+	}
+
+	demographics := make(map[string][]string)
+	for id, v := range values {
+		switch val := v.(type) {
+		case string:
+			demographics[id] = []string{val}
+		case []interface{}:
+			for _, item := range val {
+				if str, ok := item.(string); ok {
+					demographics[id] = append(demographics[id], str)
+				}
+			}
+		}
+	}
+
+	return demographics
+}
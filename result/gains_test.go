@@ -0,0 +1,120 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/mock"
+)
+
+// markCorrect marks fixture choices as correct. The fixtures don't load
+// which choices are correct.
+func markCorrect(t *testing.T, db *mock.DB, ids ...string) {
+	for _, assessmentID := range []string{"1", "2"} {
+		choices, err := db.FindQuestionChoices(assessmentID)
+		if err != nil {
+			t.Fatalf("FindQuestionChoices() error = %v, want nil", err)
+		}
+
+		for _, c := range choices {
+			for _, id := range ids {
+				if c.ID == id {
+					c.IsCorrect = true
+					db.UpdateQuestionChoice(c)
+				}
+			}
+		}
+	}
+}
+
+func TestParticipantGains(t *testing.T) {
+	db := mock.NewDB()
+	markCorrect(t, db, "1", "8")
+
+	participants := []edulab.Participant{
+		{ID: "1", ExperimentID: "1", CohortID: "1"},
+		{ID: "2", ExperimentID: "1", CohortID: "2"},
+		{ID: "3", ExperimentID: "1", CohortID: "2"},
+	}
+
+	participations := []edulab.Participation{
+		{AssessmentID: "1", ParticipantID: "1", Answers: []byte(`{"1":["2"]}`), Demographics: []byte(`{"1":["1"]}`)},
+		{AssessmentID: "2", ParticipantID: "1", Answers: []byte(`{"4":["8"]}`)},
+		{AssessmentID: "1", ParticipantID: "2", Answers: []byte(`{"1":["1"]}`), Demographics: []byte(`{"1":"2"}`)},
+		{AssessmentID: "2", ParticipantID: "2", Answers: []byte(`{"4":["8"]}`)},
+		{AssessmentID: "1", ParticipantID: "3", Answers: []byte(`{"1":["3"]}`)}, // no post-assessment
+	}
+
+	for _, p := range participants {
+		p := p
+		if err := db.CreateParticipant(&p); err != nil {
+			t.Fatalf("CreateParticipant() error = %v, want nil", err)
+		}
+	}
+
+	for _, p := range participations {
+		p := p
+		p.ExperimentID = "1"
+		if err := db.CreateParticipation(&p); err != nil {
+			t.Fatalf("CreateParticipation() error = %v, want nil", err)
+		}
+	}
+
+	res, err := New(db, "1")
+	if err != nil {
+		t.Fatalf("New() error = %v, want nil", err)
+	}
+
+	if err := res.Load(); err != nil {
+		t.Fatalf("Load() error = %v, want nil", err)
+	}
+
+	items := [][]AssessmentQuestions{
+		{{AssessmentID: "1", QuestionID: "1"}, {AssessmentID: "2", QuestionID: "4"}},
+	}
+
+	gains, err := res.ParticipantGains(items)
+	if err != nil {
+		t.Fatalf("ParticipantGains() error = %v, want nil", err)
+	}
+
+	expected := []Gain{
+		{ParticipantID: "1", ArmID: "1", CohortID: "1", Pre: 0, Post: 1},
+		{ParticipantID: "2", ArmID: "2", CohortID: "2", Pre: 1, Post: 1},
+	}
+
+	if len(gains) != len(expected) {
+		t.Fatalf("ParticipantGains() = %v, want %v", gains, expected)
+	}
+
+	for i := range expected {
+		if gains[i] != expected[i] {
+			t.Errorf("ParticipantGains()[%d] = %v, want %v", i, gains[i], expected[i])
+		}
+	}
+
+	byArm, err := res.GainsByArm(items)
+	if err != nil {
+		t.Fatalf("GainsByArm() error = %v, want nil", err)
+	}
+
+	if len(byArm) != 2 || len(byArm["1"]) != 1 || byArm["1"][0] != 1 || len(byArm["2"]) != 1 || byArm["2"][0] != 0 {
+		t.Errorf("GainsByArm() = %v, want map[1:[1] 2:[0]]", byArm)
+	}
+
+	demographics := res.ParticipantDemographics("2")
+	if len(demographics["1"]) != 1 || demographics["1"][0] != "2" {
+		t.Errorf("ParticipantDemographics() = %v, want map[1:[2]]", demographics)
+	}
+
+	res.FilterByDemographic("1")
+
+	gains, err = res.ParticipantGains(items)
+	if err != nil {
+		t.Fatalf("ParticipantGains() error = %v, want nil", err)
+	}
+
+	if len(gains) != 1 || gains[0].ParticipantID != "1" {
+		t.Errorf("ParticipantGains() after filter = %v, want participant 1 only", gains)
+	}
+}
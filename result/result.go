@@ -12,7 +12,11 @@ type Result struct {
 	experimentID   string
 	participants   map[string]edulab.Participant
 	assessments    map[string]edulab.Assessment
+	arms           map[string]edulab.Arm
+	armIDs         []string // Control arm first
 	cohorts        map[string]edulab.Cohort
+	periods        map[string]map[string]string // Arm of each cohort from an assessment onwards
+	timepoints     []string                     // Assessment IDs in chronological order
 	questions      map[string]edulab.Question
 	choices        map[string][]edulab.QuestionChoice
 	participation  map[string][]edulab.Participation // Map from participantID to their Participation records
@@ -27,28 +31,56 @@ func (r *Result) Participations() int {
 	return len(r.participations)
 }
 
+// ComparisonPairs returns the arm IDs to compare, starting with the control
+// arm, and the questions asked in more than one assessment. Questions are
+// matched by anchor first, then questions without an anchor are matched by
+// text with any other question, anchored or not.
 func (r *Result) ComparisonPairs() ([]string, [][]AssessmentQuestions) {
+	armIDs := append([]string{}, r.armIDs...)
 
-	cohortIDs := make([]string, 0, len(r.cohorts))
-	for id := range r.cohorts {
-		cohortIDs = append(cohortIDs, id)
+	ids := make([]string, 0, len(r.questions))
+	for id := range r.questions {
+		ids = append(ids, id)
 	}
-
-	sort.Slice(cohortIDs, func(i, j int) bool {
-		return cohortIDs[i] < cohortIDs[j]
+	sort.Slice(ids, func(i, j int) bool {
+		q1, _ := strconv.Atoi(ids[i])
+		q2, _ := strconv.Atoi(ids[j])
+		return q1 < q2
 	})
 
 	questions := make(map[string][]AssessmentQuestions)
-
-	for _, q := range r.questions {
-		mq := questions[q.Text]
+	add := func(key string, q edulab.Question) {
 		a := AssessmentQuestions{
 			AssessmentID: q.AssessmentID,
 			QuestionID:   q.ID,
 		}
+		questions[key] = append(questions[key], a)
+	}
 
-		mq = append(mq, a)
-		questions[q.Text] = mq
+	// Text of anchored questions -> the first anchor with that text
+	anchors := make(map[string]string)
+	for _, id := range ids {
+		q := r.questions[id]
+		if q.Anchor == "" {
+			continue
+		}
+		key := "anchor:" + q.Anchor
+		add(key, q)
+		if _, ok := anchors[q.Text]; !ok {
+			anchors[q.Text] = key
+		}
+	}
+
+	for _, id := range ids {
+		q := r.questions[id]
+		if q.Anchor != "" {
+			continue
+		}
+		key, ok := anchors[q.Text]
+		if !ok {
+			key = "text:" + q.Text
+		}
+		add(key, q)
 	}
 
 	var items [][]AssessmentQuestions
@@ -59,7 +91,7 @@ func (r *Result) ComparisonPairs() ([]string, [][]AssessmentQuestions) {
 
 		sort.Slice(mq, func(i, j int) bool {
 			a1, _ := strconv.Atoi(mq[i].AssessmentID)
-			a2, _ := strconv.Atoi(mq[i].AssessmentID)
+			a2, _ := strconv.Atoi(mq[j].AssessmentID)
 
 			if a1 != a2 {
 				return a1 < a2
@@ -80,7 +112,14 @@ func (r *Result) ComparisonPairs() ([]string, [][]AssessmentQuestions) {
 		return a1 < a2 || (a1 == a2 && q1 < q2)
 	})
 
-	return cohortIDs, items
+	return armIDs, items
+}
+
+// armOf returns the arm ID of a participant's cohort. Participants in cohorts
+// without an arm are not compared.
+func (r *Result) armOf(participantID string) string {
+	participant := r.participants[participantID]
+	return r.cohorts[participant.CohortID].ArmID
 }
 
 // New initializes a new Result instance, loading data into memory.
@@ -90,7 +129,9 @@ func New(db edulab.Database, experimentID string) (*Result, error) {
 		experimentID:   experimentID,
 		participants:   make(map[string]edulab.Participant),
 		assessments:    make(map[string]edulab.Assessment),
+		arms:           make(map[string]edulab.Arm),
 		cohorts:        make(map[string]edulab.Cohort),
+		periods:        make(map[string]map[string]string),
 		questions:      make(map[string]edulab.Question),
 		choices:        make(map[string][]edulab.QuestionChoice),
 		participation:  make(map[string][]edulab.Participation),
@@ -121,6 +162,16 @@ func (r *Result) Load() error {
 		r.participants[p.ID] = p
 	}
 
+	// Load arms
+	arms, err := db.FindArms(experimentID)
+	if err != nil {
+		return err
+	}
+	for _, a := range arms {
+		r.arms[a.ID] = a
+		r.armIDs = append(r.armIDs, a.ID)
+	}
+
 	// Load cohorts
 	cohorts, err := db.FindCohorts(experimentID)
 	if err != nil {
@@ -130,11 +181,26 @@ func (r *Result) Load() error {
 		r.cohorts[c.ID] = c
 	}
 
+	// Load crossover periods
+	periods, err := db.FindCohortPeriods(experimentID)
+	if err != nil {
+		return err
+	}
+	for _, p := range periods {
+		if r.periods[p.CohortID] == nil {
+			r.periods[p.CohortID] = make(map[string]string)
+		}
+		r.periods[p.CohortID][p.AssessmentID] = p.ArmID
+	}
+
 	// Load questions
 	assessments, err := db.FindAssessments(experimentID)
 	if err != nil {
 		return err
 	}
+	for _, a := range SortTimepoints(assessments) {
+		r.timepoints = append(r.timepoints, a.ID)
+	}
 	for _, a := range assessments {
 		r.assessments[a.ID] = a
 
@@ -0,0 +1,83 @@
+package result
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/louisbranch/edulab"
+)
+
+func TestComparisonPairs(t *testing.T) {
+	questions := []edulab.Question{
+		{ID: "1", AssessmentID: "1", Text: "What causes the seasons?", Anchor: "seasons"},
+		{ID: "2", AssessmentID: "1", Text: "How long is a year?"},
+		{ID: "3", AssessmentID: "1", Text: "Why is the sky blue?"},
+		{ID: "4", AssessmentID: "2", Text: "What causes teh seasons?", Anchor: "seasons"},
+		{ID: "5", AssessmentID: "2", Text: "How long is a year?"},
+		{ID: "6", AssessmentID: "2", Text: "Why is the sky blue?", Anchor: "sky"},
+		{ID: "7", AssessmentID: "10", Text: "What makes the seasons change?", Anchor: "seasons"},
+	}
+
+	r := &Result{
+		armIDs:    []string{"1", "2"},
+		questions: make(map[string]edulab.Question),
+	}
+	for _, q := range questions {
+		r.questions[q.ID] = q
+	}
+
+	armIDs, items := r.ComparisonPairs()
+
+	if !reflect.DeepEqual(armIDs, []string{"1", "2"}) {
+		t.Errorf("expected arms [1 2], got %v", armIDs)
+	}
+
+	want := [][]AssessmentQuestions{
+		{
+			{AssessmentID: "1", QuestionID: "1"},
+			{AssessmentID: "2", QuestionID: "4"},
+			{AssessmentID: "10", QuestionID: "7"},
+		},
+		{
+			{AssessmentID: "1", QuestionID: "2"},
+			{AssessmentID: "2", QuestionID: "5"},
+		},
+		{
+			{AssessmentID: "1", QuestionID: "3"},
+			{AssessmentID: "2", QuestionID: "6"},
+		},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("expected %v, got %v", want, items)
+	}
+}
+
+func TestComparisonPairsOneSidedAnchor(t *testing.T) {
+	questions := []edulab.Question{
+		{ID: "1", AssessmentID: "1", Text: "What causes the seasons?", Anchor: "seasons"},
+		{ID: "2", AssessmentID: "1", Text: "Why is the sky blue?"},
+		{ID: "3", AssessmentID: "2", Text: "What causes the seasons?"},
+		{ID: "4", AssessmentID: "2", Text: "Why is the sky blue?", Anchor: "sky"},
+	}
+
+	r := &Result{questions: make(map[string]edulab.Question)}
+	for _, q := range questions {
+		r.questions[q.ID] = q
+	}
+
+	_, items := r.ComparisonPairs()
+
+	want := [][]AssessmentQuestions{
+		{
+			{AssessmentID: "1", QuestionID: "1"},
+			{AssessmentID: "2", QuestionID: "3"},
+		},
+		{
+			{AssessmentID: "1", QuestionID: "2"},
+			{AssessmentID: "2", QuestionID: "4"},
+		},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("expected %v, got %v", want, items)
+	}
+}
@@ -9,14 +9,15 @@ import (
 )
 
 // QuestionScore calculates the score for each participation for a given question
-// and returns a map from cohortID to score.
+// and returns a map from arm ID to score. In crossover designs, participants
+// count towards the arm of the period that ends at the question's assessment.
 func (r *Result) QuestionScore(questionID string) (map[string][]float64, error) {
 	question, exists := r.questions[questionID]
 	if !exists {
 		return nil, errors.New("question not found")
 	}
 
-	// Map to store scores by cohort ID
+	// Map to store scores by arm ID
 	scores := make(map[string][]float64)
 	for participantID, participations := range r.participation {
 		for _, participation := range participations {
@@ -35,24 +36,19 @@ func (r *Result) QuestionScore(questionID string) (map[string][]float64, error)
 				continue // Skip if participant didn't answer this question
 			}
 
-			participant := r.participants[participantID]
-			cohortID := participant.CohortID
-
-			// Score the answer based on question type
-			var score float64
-			switch question.Type {
-			case edulab.InputSingle:
-				score = r.scoreSingleAnswer(questionID, answerIDs)
-			case edulab.InputMultiple:
-				score = r.scoreMultipleAnswer(questionID, answerIDs)
+			armID := r.armUntil(participantID, question.AssessmentID)
+			if armID == "" {
+				continue
 			}
 
-			// Append score to cohort's list of scores
-			if _, exists := scores[cohortID]; !exists {
-				scores[cohortID] = []float64{}
+			score := r.scoreAnswer(question, answerIDs)
+
+			// Append score to arm's list of scores
+			if _, exists := scores[armID]; !exists {
+				scores[armID] = []float64{}
 			}
 
-			scores[cohortID] = append(scores[cohortID], score)
+			scores[armID] = append(scores[armID], score)
 		}
 	}
 
@@ -64,6 +60,18 @@ func (r *Result) QuestionScore(questionID string) (map[string][]float64, error)
 	return scores, nil
 }
 
+// scoreAnswer scores an answer based on the question type.
+func (r *Result) scoreAnswer(question edulab.Question, answerIDs []string) float64 {
+	switch question.Type {
+	case edulab.InputSingle:
+		return r.scoreSingleAnswer(question.ID, answerIDs)
+	case edulab.InputMultiple:
+		return r.scoreMultipleAnswer(question.ID, answerIDs)
+	default:
+		return 0.0
+	}
+}
+
 // scoreSingleAnswer scores a single-answer question as 0 or 1.
 func (r *Result) scoreSingleAnswer(questionID string, answerIDs []string) float64 {
 	if len(answerIDs) != 1 {
@@ -0,0 +1,195 @@
+package result
+
+import (
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/stats"
+	"gonum.org/v1/gonum/stat"
+)
+
+// SubgroupArm holds the scores of a demographic subgroup within an arm.
+type SubgroupArm struct {
+	N        int
+	MeanPre  float64
+	MeanPost float64
+	MeanGain float64
+}
+
+// Subgroup holds the scores of participants who selected a demographic option.
+type Subgroup struct {
+	Option edulab.DemographicOption
+	Arms   []SubgroupArm
+}
+
+// Equity holds the achievement gap between the best and worst performing
+// subgroups of each arm, before and after the intervention.
+type Equity struct {
+	PreGaps  []float64
+	PostGaps []float64
+}
+
+// Change returns how much the gap of an arm changed (post - pre).
+// Negative values mean the gap closed.
+func (e Equity) Change(arm int) float64 {
+	return e.PostGaps[arm] - e.PreGaps[arm]
+}
+
+// SubgroupAnalysis holds the learning gains stratified by a demographic.
+type SubgroupAnalysis struct {
+	Demographic edulab.Demographic
+	Subgroups   []Subgroup
+	Interaction stats.Interaction
+	Equity      Equity
+}
+
+// Subgroups stratifies the participant gains by the options of a demographic
+// across the given arms. Arms are compared in order, with the first
+// being the reference (control).
+func (r *Result) Subgroups(gains []Gain, demographic edulab.Demographic,
+	options []edulab.DemographicOption, armIDs []string) SubgroupAnalysis {
+
+	sa := SubgroupAnalysis{
+		Demographic: demographic,
+	}
+
+	armIndex := make(map[string]int)
+	for i, id := range armIDs {
+		armIndex[id] = i
+	}
+
+	optionIndex := make(map[string]int)
+	for i, o := range options {
+		optionIndex[o.ID] = i
+	}
+
+	// [option][arm] -> scores
+	pre := make([][][]float64, len(options))
+	post := make([][][]float64, len(options))
+	for i := range options {
+		pre[i] = make([][]float64, len(armIDs))
+		post[i] = make([][]float64, len(armIDs))
+	}
+
+	var ys []float64
+	var cs, ss []int
+
+	for _, g := range gains {
+		c, ok := armIndex[g.ArmID]
+		if !ok {
+			continue
+		}
+
+		selected := r.ParticipantDemographics(g.ParticipantID)[demographic.ID]
+		if len(selected) != 1 {
+			continue // skip unanswered and multiple selections
+		}
+
+		o, ok := optionIndex[selected[0]]
+		if !ok {
+			continue
+		}
+
+		pre[o][c] = append(pre[o][c], g.Pre)
+		post[o][c] = append(post[o][c], g.Post)
+
+		ys = append(ys, g.Value())
+		cs = append(cs, c)
+		ss = append(ss, o)
+	}
+
+	sa.Equity = Equity{
+		PreGaps:  make([]float64, len(armIDs)),
+		PostGaps: make([]float64, len(armIDs)),
+	}
+
+	for i, o := range options {
+		sg := Subgroup{
+			Option: o,
+			Arms:   make([]SubgroupArm, len(armIDs)),
+		}
+
+		for c := range armIDs {
+			n := len(pre[i][c])
+			if n == 0 {
+				continue
+			}
+
+			mpre := stat.Mean(pre[i][c], nil)
+			mpost := stat.Mean(post[i][c], nil)
+
+			sg.Arms[c] = SubgroupArm{
+				N:        n,
+				MeanPre:  mpre,
+				MeanPost: mpost,
+				MeanGain: mpost - mpre,
+			}
+		}
+
+		sa.Subgroups = append(sa.Subgroups, sg)
+	}
+
+	for c := range armIDs {
+		sa.Equity.PreGaps[c] = gap(sa.Subgroups, c, func(sc SubgroupArm) float64 { return sc.MeanPre })
+		sa.Equity.PostGaps[c] = gap(sa.Subgroups, c, func(sc SubgroupArm) float64 { return sc.MeanPost })
+	}
+
+	// Only levels with observations enter the regression
+	cs, numArms := compact(cs)
+	ss, numSubgroups := compact(ss)
+	sa.Interaction = stats.InteractionTest(ys, cs, ss, numArms, numSubgroups)
+
+	return sa
+}
+
+// gap returns the difference between the highest and lowest subgroup means
+// of an arm, ignoring empty subgroups.
+func gap(subgroups []Subgroup, arm int, value func(SubgroupArm) float64) float64 {
+	first := true
+	var min, max float64
+	for _, sg := range subgroups {
+		sc := sg.Arms[arm]
+		if sc.N == 0 {
+			continue
+		}
+
+		v := value(sc)
+		if first || v < min {
+			min = v
+		}
+		if first || v > max {
+			max = v
+		}
+		first = false
+	}
+	return max - min
+}
+
+// compact renumbers levels so that only observed ones are kept, preserving
+// their order. It returns the renumbered levels and how many there are.
+func compact(levels []int) ([]int, int) {
+	max := -1
+	for _, l := range levels {
+		if l > max {
+			max = l
+		}
+	}
+
+	observed := make([]bool, max+1)
+	for _, l := range levels {
+		observed[l] = true
+	}
+
+	n := 0
+	index := make([]int, max+1)
+	for i, ok := range observed {
+		if ok {
+			index[i] = n
+			n++
+		}
+	}
+
+	compacted := make([]int, len(levels))
+	for i, l := range levels {
+		compacted[i] = index[l]
+	}
+	return compacted, n
+}
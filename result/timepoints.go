@@ -0,0 +1,271 @@
+package result
+
+import (
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/gonum/stat"
+
+	"github.com/louisbranch/edulab"
+)
+
+// TimepointScore summarizes the scores of a group at one timepoint.
+type TimepointScore struct {
+	AssessmentID string
+	N            int
+	Mean         float64
+	SD           float64
+}
+
+// Trajectory holds the scores of a cohort at each timepoint.
+type Trajectory struct {
+	CohortID string
+	Scores   []TimepointScore
+}
+
+// ArmGain summarizes the gains of the participants in an arm.
+type ArmGain struct {
+	ArmID string
+	N     int
+	Mean  float64
+	SD    float64
+}
+
+// PeriodGain holds the gains of each arm between two consecutive timepoints.
+// In crossover designs a cohort can belong to a different arm in each period.
+type PeriodGain struct {
+	From string // Assessment ID
+	To   string // Assessment ID
+	Arms []ArmGain
+}
+
+// SortTimepoints orders assessments chronologically by type, keeping the
+// creation order of assessments of the same type.
+func SortTimepoints(assessments []edulab.Assessment) []edulab.Assessment {
+	rank := make(map[edulab.AssessmentType]int)
+	for i, t := range edulab.AssessmentTypes {
+		rank[t] = i
+	}
+
+	sorted := append([]edulab.Assessment{}, assessments...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		r1, r2 := rank[sorted[i].Type], rank[sorted[j].Type]
+		if r1 != r2 {
+			return r1 < r2
+		}
+		a1, _ := strconv.Atoi(sorted[i].ID)
+		a2, _ := strconv.Atoi(sorted[j].ID)
+		return a1 < a2
+	})
+
+	return sorted
+}
+
+// Timepoints returns the assessment IDs in chronological order.
+func (r *Result) Timepoints() []string {
+	return append([]string{}, r.timepoints...)
+}
+
+// armAt returns the arm of a participant during the period that starts at an
+// assessment, following the crossover periods of their cohort.
+func (r *Result) armAt(participantID, assessmentID string) string {
+	cohortID := r.participants[participantID].CohortID
+	armID := r.cohorts[cohortID].ArmID
+
+	for _, id := range r.timepoints {
+		if a, ok := r.periods[cohortID][id]; ok {
+			armID = a
+		}
+		if id == assessmentID {
+			break
+		}
+	}
+
+	return armID
+}
+
+// armUntil returns the arm of a participant during the period that ends at an
+// assessment, so scores are compared by the treatment that preceded them. The
+// first assessment takes the arm of the period it starts.
+func (r *Result) armUntil(participantID, assessmentID string) string {
+	for i, id := range r.timepoints {
+		if id == assessmentID && i > 0 {
+			return r.armAt(participantID, r.timepoints[i-1])
+		}
+	}
+	return r.armAt(participantID, assessmentID)
+}
+
+// TimepointScores returns the average score of each participant at each
+// timepoint, keyed by participant and assessment ID, over the questions of the
+// comparison items. Text questions are not scored.
+func (r *Result) TimepointScores(items [][]AssessmentQuestions) (map[string]map[string]float64, error) {
+	type sums struct {
+		total float64
+		n     int
+	}
+
+	totals := make(map[string]map[string]*sums)
+
+	for _, item := range items {
+		for _, aq := range item {
+			for participantID := range r.participation {
+				score, ok, err := r.participantScore(participantID, aq.QuestionID)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+
+				if totals[participantID] == nil {
+					totals[participantID] = make(map[string]*sums)
+				}
+				t, ok := totals[participantID][aq.AssessmentID]
+				if !ok {
+					t = &sums{}
+					totals[participantID][aq.AssessmentID] = t
+				}
+				t.total += score
+				t.n++
+			}
+		}
+	}
+
+	scores := make(map[string]map[string]float64)
+	for participantID, assessments := range totals {
+		scores[participantID] = make(map[string]float64)
+		for assessmentID, t := range assessments {
+			scores[participantID][assessmentID] = t.total / float64(t.n)
+		}
+	}
+
+	return scores, nil
+}
+
+// Trajectories returns the mean score of each cohort at each timepoint.
+func (r *Result) Trajectories(scores map[string]map[string]float64) []Trajectory {
+	cohortIDs := make([]string, 0, len(r.cohorts))
+	for id := range r.cohorts {
+		cohortIDs = append(cohortIDs, id)
+	}
+	sort.Slice(cohortIDs, func(i, j int) bool {
+		c1, _ := strconv.Atoi(cohortIDs[i])
+		c2, _ := strconv.Atoi(cohortIDs[j])
+		return c1 < c2
+	})
+
+	participantIDs := sortedParticipants(scores)
+
+	trajectories := make([]Trajectory, len(cohortIDs))
+	for i, cohortID := range cohortIDs {
+		trajectories[i].CohortID = cohortID
+
+		for _, assessmentID := range r.timepoints {
+			var values []float64
+			for _, participantID := range participantIDs {
+				if r.participants[participantID].CohortID != cohortID {
+					continue
+				}
+				if score, ok := scores[participantID][assessmentID]; ok {
+					values = append(values, score)
+				}
+			}
+
+			ts := TimepointScore{AssessmentID: assessmentID, N: len(values)}
+			if len(values) > 0 {
+				ts.Mean, ts.SD = stat.MeanStdDev(values, nil)
+			}
+			trajectories[i].Scores = append(trajectories[i].Scores, ts)
+		}
+	}
+
+	return trajectories
+}
+
+// PeriodGains returns the gains of each arm between consecutive timepoints.
+// Participants count towards the arm their cohort belonged to during the
+// period, so crossover cohorts contribute to a different arm in each period.
+func (r *Result) PeriodGains(scores map[string]map[string]float64) []PeriodGain {
+	var periods []PeriodGain
+
+	for i := 1; i < len(r.timepoints); i++ {
+		from, to := r.timepoints[i-1], r.timepoints[i]
+		periods = append(periods, PeriodGain{
+			From: from,
+			To:   to,
+			Arms: r.armGains(scores, from, to),
+		})
+	}
+
+	return periods
+}
+
+// Retention returns the retention gain (delayed - post) of each arm, using
+// the last post-assessment and the first delayed assessment. It returns nil
+// when the experiment has no post and delayed assessments.
+func (r *Result) Retention(scores map[string]map[string]float64) []ArmGain {
+	var post, delayed string
+	for _, id := range r.timepoints {
+		switch r.assessments[id].Type {
+		case edulab.AssessmentTypePost:
+			post = id
+		case edulab.AssessmentTypeDelayed:
+			if delayed == "" {
+				delayed = id
+			}
+		}
+	}
+
+	if post == "" || delayed == "" {
+		return nil
+	}
+
+	return r.armGains(scores, post, delayed)
+}
+
+// armGains summarizes the gains between two timepoints by the arm of each
+// participant at the start of the period, with the control arm first.
+func (r *Result) armGains(scores map[string]map[string]float64, from, to string) []ArmGain {
+	values := make(map[string][]float64)
+
+	for _, participantID := range sortedParticipants(scores) {
+		s := scores[participantID]
+		pre, ok1 := s[from]
+		post, ok2 := s[to]
+		if !ok1 || !ok2 {
+			continue
+		}
+
+		armID := r.armAt(participantID, from)
+		if armID == "" {
+			continue
+		}
+		values[armID] = append(values[armID], post-pre)
+	}
+
+	gains := make([]ArmGain, len(r.armIDs))
+	for i, armID := range r.armIDs {
+		gains[i] = ArmGain{ArmID: armID, N: len(values[armID])}
+		if len(values[armID]) > 0 {
+			gains[i].Mean, gains[i].SD = stat.MeanStdDev(values[armID], nil)
+		}
+	}
+
+	return gains
+}
+
+// sortedParticipants returns the participant IDs of the scores in numeric
+// order, so floating point sums are deterministic.
+func sortedParticipants(scores map[string]map[string]float64) []string {
+	ids := make([]string, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		p1, _ := strconv.Atoi(ids[i])
+		p2, _ := strconv.Atoi(ids[j])
+		return p1 < p2
+	})
+	return ids
+}
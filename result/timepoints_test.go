@@ -0,0 +1,143 @@
+package result
+
+import (
+	"math"
+	"testing"
+
+	"github.com/louisbranch/edulab"
+)
+
+func TestSortTimepoints(t *testing.T) {
+	assessments := []edulab.Assessment{
+		{ID: "1", Type: edulab.AssessmentTypePost},
+		{ID: "2", Type: edulab.AssessmentTypeDelayed},
+		{ID: "10", Type: edulab.AssessmentTypeMid},
+		{ID: "3", Type: edulab.AssessmentTypePre},
+		{ID: "4", Type: edulab.AssessmentTypeMid},
+	}
+
+	sorted := SortTimepoints(assessments)
+
+	expected := []string{"3", "4", "10", "1", "2"}
+	for i, a := range sorted {
+		if a.ID != expected[i] {
+			t.Fatalf("SortTimepoints() = %v, want IDs %v", sorted, expected)
+		}
+	}
+}
+
+func crossoverResult() *Result {
+	return &Result{
+		armIDs: []string{"1", "2"},
+		assessments: map[string]edulab.Assessment{
+			"1": {ID: "1", Type: edulab.AssessmentTypePre},
+			"2": {ID: "2", Type: edulab.AssessmentTypeMid},
+			"3": {ID: "3", Type: edulab.AssessmentTypePost},
+			"4": {ID: "4", Type: edulab.AssessmentTypeDelayed},
+		},
+		timepoints: []string{"1", "2", "3", "4"},
+		cohorts: map[string]edulab.Cohort{
+			"1": {ID: "1", ArmID: "1"},
+			"2": {ID: "2", ArmID: "2"},
+		},
+		// The cohorts swap arms after the mid-assessment
+		periods: map[string]map[string]string{
+			"1": {"2": "2"},
+			"2": {"2": "1"},
+		},
+		participants: map[string]edulab.Participant{
+			"1": {ID: "1", CohortID: "1"},
+			"2": {ID: "2", CohortID: "1"},
+			"3": {ID: "3", CohortID: "2"},
+		},
+	}
+}
+
+func TestPeriodGains(t *testing.T) {
+	r := crossoverResult()
+
+	scores := map[string]map[string]float64{
+		"1": {"1": 0.2, "2": 0.3, "3": 0.8, "4": 0.7},
+		"2": {"1": 0.4, "2": 0.5, "3": 1.0},
+		"3": {"1": 0.1, "2": 0.6, "3": 0.7, "4": 0.4},
+	}
+
+	periods := r.PeriodGains(scores)
+	if len(periods) != 3 {
+		t.Fatalf("PeriodGains() = %v, want 3 periods", periods)
+	}
+
+	// Pre to mid: cohort 1 in arm 1, cohort 2 in arm 2
+	first := periods[0]
+	if first.Arms[0].N != 2 || math.Abs(first.Arms[0].Mean-0.1) > 1e-9 {
+		t.Errorf("PeriodGains()[0] control = %+v, want n=2 mean=0.1", first.Arms[0])
+	}
+	if first.Arms[1].N != 1 || math.Abs(first.Arms[1].Mean-0.5) > 1e-9 {
+		t.Errorf("PeriodGains()[0] intervention = %+v, want n=1 mean=0.5", first.Arms[1])
+	}
+
+	// Mid to post: the cohorts swap arms
+	second := periods[1]
+	if second.Arms[0].N != 1 || math.Abs(second.Arms[0].Mean-0.1) > 1e-9 {
+		t.Errorf("PeriodGains()[1] control = %+v, want n=1 mean=0.1", second.Arms[0])
+	}
+	if second.Arms[1].N != 2 || math.Abs(second.Arms[1].Mean-0.5) > 1e-9 {
+		t.Errorf("PeriodGains()[1] intervention = %+v, want n=2 mean=0.5", second.Arms[1])
+	}
+
+	retention := r.Retention(scores)
+	if len(retention) != 2 {
+		t.Fatalf("Retention() = %v, want 2 arms", retention)
+	}
+	if retention[0].N != 1 || math.Abs(retention[0].Mean+0.3) > 1e-9 {
+		t.Errorf("Retention() control = %+v, want n=1 mean=-0.3", retention[0])
+	}
+	if retention[1].N != 1 || math.Abs(retention[1].Mean+0.1) > 1e-9 {
+		t.Errorf("Retention() intervention = %+v, want n=1 mean=-0.1", retention[1])
+	}
+
+	trajectories := r.Trajectories(scores)
+	if len(trajectories) != 2 || len(trajectories[0].Scores) != 4 {
+		t.Fatalf("Trajectories() = %v, want 2 cohorts with 4 timepoints", trajectories)
+	}
+	if trajectories[0].Scores[3].N != 1 || trajectories[0].Scores[2].N != 2 {
+		t.Errorf("Trajectories()[0] = %+v, want 2 participants at post and 1 delayed", trajectories[0])
+	}
+}
+
+func TestRetentionWithoutDelayed(t *testing.T) {
+	r := crossoverResult()
+	r.timepoints = []string{"1", "2", "3"}
+
+	if retention := r.Retention(nil); retention != nil {
+		t.Errorf("Retention() = %v, want nil", retention)
+	}
+}
+
+func TestQuestionScoreCrossover(t *testing.T) {
+	r := crossoverResult()
+	r.questions = map[string]edulab.Question{
+		"1": {ID: "1", AssessmentID: "3", Type: edulab.InputSingle},
+	}
+	r.choices = map[string][]edulab.QuestionChoice{
+		"1": {{ID: "1", QuestionID: "1", IsCorrect: true}, {ID: "2", QuestionID: "1"}},
+	}
+	r.participation = map[string][]edulab.Participation{
+		"1": {{AssessmentID: "3", Answers: []byte(`{"1":["1"]}`)}},
+		"2": {{AssessmentID: "3", Answers: []byte(`{"1":["1"]}`)}},
+		"3": {{AssessmentID: "3", Answers: []byte(`{"1":["2"]}`)}},
+	}
+
+	scores, err := r.QuestionScore("1")
+	if err != nil {
+		t.Fatalf("QuestionScore() error = %v, want nil", err)
+	}
+
+	// The post-assessment ends the period after the swap
+	if len(scores["1"]) != 1 || scores["1"][0] != 0 {
+		t.Errorf("QuestionScore() control = %v, want [0]", scores["1"])
+	}
+	if len(scores["2"]) != 2 || scores["2"][0] != 1 || scores["2"][1] != 1 {
+		t.Errorf("QuestionScore() intervention = %v, want [1 1]", scores["2"])
+	}
+}
@@ -0,0 +1,176 @@
+package simulate
+
+import (
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/stat"
+
+	"github.com/louisbranch/edulab/stats"
+)
+
+// Report holds the outcome of repeated simulations of an experiment.
+type Report struct {
+	Config   Config
+	Mixed    bool // Arms were compared with a random intercept per cohort
+	Failures int  // Simulations whose gains couldn't be analyzed
+	Arms     []ArmPower
+}
+
+// ArmPower holds how often an intervention arm was found to differ from the
+// control across the simulations.
+type ArmPower struct {
+	Name         string
+	EffectSize   float64 // True effect on the ability
+	Power        float64 // Share of simulations with a p-value below alpha
+	OLSPower     float64 // Same, ignoring that students are nested in cohorts
+	MeanEstimate float64 // Average estimated difference in gains
+	MeanD        float64 // Average standardized difference in gains (Cohen's d)
+	PlannedPower float64 // Power of a t-test for the average d and students, as the planner computes it
+	Control      float64 // Average students of the control who took both assessments
+	Intervention float64 // Average students of the arm who took both assessments
+}
+
+// Run simulates an experiment repeatedly and analyzes the learning gains of
+// each simulation as the arm results do: the gains of the students who took
+// both assessments are fitted on the arm, with a random intercept per cohort
+// when any arm has more than one cohort, which leaves the mixed model degrees
+// of freedom, and by ordinary least squares otherwise. Each simulation draws
+// from its own seed, taken in turn from the configured seed, so the report is
+// the same for the same configuration.
+func Run(config Config) Report {
+	report := Report{
+		Config: config,
+		Mixed:  cohorts(config) > len(config.Arms),
+	}
+
+	interventions := len(config.Arms) - 1
+	significant := make([]int, interventions)
+	independent := make([]int, interventions)
+	estimates := make([][]float64, interventions)
+	ds := make([][]float64, interventions)
+	sizes := make([][2][]float64, interventions)
+
+	seeds := rand.New(rand.NewSource(config.Seed))
+
+	for r := 0; r < config.Repetitions; r++ {
+		random := rand.New(rand.NewSource(seeds.Int63()))
+		students := config.Sample(random)
+
+		model, ok := analyze(config, students)
+		if !ok {
+			report.Failures++
+			continue
+		}
+
+		gains := make([][]float64, len(config.Arms))
+		for _, s := range students {
+			if s.Completed {
+				gains[s.Arm] = append(gains[s.Arm], s.Post-s.Pre)
+			}
+		}
+
+		for i := 0; i < interventions; i++ {
+			if model.PValues[i+1] < config.Alpha {
+				significant[i]++
+			}
+			if model.OLS.PValues[i+1] < config.Alpha {
+				independent[i]++
+			}
+			estimates[i] = append(estimates[i], model.Coefficients[i+1])
+			if d := stats.CohensD(gains[0], gains[i+1]); !math.IsNaN(d) {
+				ds[i] = append(ds[i], d)
+			}
+			sizes[i][0] = append(sizes[i][0], float64(len(gains[0])))
+			sizes[i][1] = append(sizes[i][1], float64(len(gains[i+1])))
+		}
+	}
+
+	analyzed := config.Repetitions - report.Failures
+
+	for i, arm := range config.Arms[1:] {
+		a := ArmPower{
+			Name:       arm.label(i + 1),
+			EffectSize: arm.EffectSize,
+		}
+
+		if analyzed > 0 {
+			a.Power = float64(significant[i]) / float64(analyzed)
+			a.OLSPower = float64(independent[i]) / float64(analyzed)
+			a.MeanEstimate = stat.Mean(estimates[i], nil)
+			a.Control = stat.Mean(sizes[i][0], nil)
+			a.Intervention = stat.Mean(sizes[i][1], nil)
+		}
+		if len(ds[i]) > 0 {
+			a.MeanD = stat.Mean(ds[i], nil)
+			a.PlannedPower = stats.TwoSampleTPower(a.MeanD, int(math.Round(a.Control)),
+				int(math.Round(a.Intervention)), config.Alpha)
+		}
+
+		report.Arms = append(report.Arms, a)
+	}
+
+	return report
+}
+
+// fit holds the arm effects of a simulation, with the p-values of the model
+// the arm results rely on and of ordinary least squares.
+type fit struct {
+	Coefficients []float64
+	PValues      []float64
+	OLS          stats.Regression
+}
+
+// analyze fits the gains of the students who took both assessments on arm
+// indicators. The first coefficient is the intercept.
+func analyze(config Config, students []Student) (fit, bool) {
+	var f fit
+	var y []float64
+	var x [][]float64
+	var groups []int
+
+	for _, s := range students {
+		if !s.Completed {
+			continue
+		}
+
+		row := make([]float64, len(config.Arms)-1)
+		if s.Arm > 0 {
+			row[s.Arm-1] = 1
+		}
+
+		y = append(y, s.Post-s.Pre)
+		x = append(x, row)
+		groups = append(groups, s.Cohort)
+	}
+
+	if len(y) == 0 {
+		return f, false
+	}
+
+	ols, err := stats.OLS(y, x)
+	if err != nil {
+		return f, false
+	}
+	f.OLS = ols
+	f.Coefficients, f.PValues = ols.Coefficients, ols.PValues
+
+	if cohorts(config) > len(config.Arms) {
+		mixed, err := stats.RandomIntercept(y, x, groups)
+		if err != nil {
+			return f, false
+		}
+		f.Coefficients, f.PValues = mixed.Coefficients, mixed.PValues
+	}
+
+	return f, true
+}
+
+// cohorts counts the cohorts of every arm.
+func cohorts(config Config) int {
+	n := 0
+	for _, arm := range config.Arms {
+		n += len(arm.Cohorts)
+	}
+	return n
+}
@@ -0,0 +1,210 @@
+// Package simulate rehearses the design of an experiment before running it.
+// Students with a latent ability answer the same items before and after the
+// intervention, following an item response model, and the gains of repeated
+// simulations are analyzed like the results of a real experiment to estimate
+// the empirical power of the design.
+package simulate
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Defaults of the settings left out of a configuration.
+const (
+	DefaultRepetitions = 1000
+	DefaultAlpha       = 0.05
+)
+
+// Config describes a simulated experiment. Abilities and effects are in
+// standard deviations of the student ability before the intervention.
+type Config struct {
+	Seed        int64   `yaml:"seed"`                 // Seeds every simulation, for reproducible reports
+	Repetitions int     `yaml:"repetitions"`          // Number of simulated experiments
+	Alpha       float64 `yaml:"alpha"`                // Significance level (two-tailed)
+	Growth      float64 `yaml:"growth"`               // Ability gained by every student, e.g. from the lecture itself
+	Correlation float64 `yaml:"pre_post_correlation"` // Correlation of a student's ability before and after
+	CohortSD    float64 `yaml:"cohort_sd"`            // Variation of the ability between cohorts
+	Attrition   float64 `yaml:"attrition"`            // Probability of missing the post-assessment
+	Arms        []Arm   `yaml:"arms"`                 // The first arm is the control
+	Items       []Item  `yaml:"items"`                // Asked before and after the intervention
+}
+
+// Arm is a treatment condition with the number of students in each of its
+// cohorts.
+type Arm struct {
+	Name       string  `yaml:"name"`
+	EffectSize float64 `yaml:"effect_size"` // True effect on the ability gained, compared to the control
+	Cohorts    []int   `yaml:"cohorts"`
+}
+
+// Item is a question scored right or wrong, following the three-parameter
+// logistic model: a student of ability theta answers it correctly with
+// probability guessing + (1 - guessing) / (1 + exp(-discrimination * (theta - difficulty))).
+type Item struct {
+	Difficulty     float64 `yaml:"difficulty"`
+	Discrimination float64 `yaml:"discrimination"` // 1 if left out
+	Guessing       float64 `yaml:"guessing"`       // e.g. 0.25 for four choices
+}
+
+// Student is a simulated participant with their scores, the share of items
+// answered correctly.
+type Student struct {
+	Arm       int
+	Cohort    int // Index of the cohort across all arms
+	Pre       float64
+	Post      float64
+	Completed bool // Took the post-assessment
+}
+
+// Parse reads a configuration, rejecting unknown fields, and fills in the
+// defaults.
+func Parse(r io.Reader) (Config, error) {
+	var config Config
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return config, errors.Wrap(err, "could not read configuration")
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil {
+		if err == io.EOF {
+			return config, errors.New("the configuration is empty")
+		}
+		return config, errors.Wrap(err, "could not decode configuration")
+	}
+
+	config = config.withDefaults()
+	return config, config.Validate()
+}
+
+// withDefaults fills in the settings left out.
+func (c Config) withDefaults() Config {
+	if c.Repetitions == 0 {
+		c.Repetitions = DefaultRepetitions
+	}
+	if c.Alpha == 0 {
+		c.Alpha = DefaultAlpha
+	}
+
+	items := make([]Item, len(c.Items))
+	for i, item := range c.Items {
+		if item.Discrimination == 0 {
+			item.Discrimination = 1
+		}
+		items[i] = item
+	}
+	c.Items = items
+
+	return c
+}
+
+// Validate checks that a configuration describes an experiment that can be
+// simulated and analyzed.
+func (c Config) Validate() error {
+	switch {
+	case c.Repetitions < 1:
+		return errors.New("repetitions must be at least 1")
+	case c.Alpha <= 0 || c.Alpha >= 1:
+		return errors.New("alpha must be between 0 and 1")
+	case c.Correlation < -1 || c.Correlation > 1:
+		return errors.New("pre_post_correlation must be between -1 and 1")
+	case c.CohortSD < 0:
+		return errors.New("cohort_sd can't be negative")
+	case c.Attrition < 0 || c.Attrition >= 1:
+		return errors.New("attrition must be at least 0 and less than 1")
+	case len(c.Arms) < 2:
+		return errors.New("arms must have a control and at least one intervention")
+	case c.Arms[0].EffectSize != 0:
+		return errors.Errorf("arm %s is the control and can't have an effect size", c.Arms[0].label(0))
+	case len(c.Items) == 0:
+		return errors.New("items must have at least one item")
+	}
+
+	for i, arm := range c.Arms {
+		if len(arm.Cohorts) == 0 {
+			return errors.Errorf("arm %s has no cohorts", arm.label(i))
+		}
+		for j, n := range arm.Cohorts {
+			if n < 1 {
+				return errors.Errorf("cohort %d of arm %s must have at least 1 student", j+1, arm.label(i))
+			}
+		}
+	}
+
+	for i, item := range c.Items {
+		switch {
+		case item.Discrimination < 0:
+			return errors.Errorf("item %d can't have a negative discrimination", i+1)
+		case item.Guessing < 0 || item.Guessing >= 1:
+			return errors.Errorf("item %d must have a guessing of at least 0 and less than 1", i+1)
+		}
+	}
+
+	return nil
+}
+
+// label names an arm in errors and reports.
+func (a Arm) label(i int) string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return strconv.Itoa(i + 1)
+}
+
+// Sample simulates the students of one experiment. Each cohort shifts the
+// ability of its students; after the intervention, their ability keeps the
+// configured correlation with the one before, plus the growth and the effect
+// of their arm.
+func (c Config) Sample(random *rand.Rand) []Student {
+	var students []Student
+
+	residual := math.Sqrt(1 - c.Correlation*c.Correlation)
+	cohort := 0
+
+	for a, arm := range c.Arms {
+		for _, size := range arm.Cohorts {
+			shift := c.CohortSD * random.NormFloat64()
+
+			for i := 0; i < size; i++ {
+				before := random.NormFloat64()
+				after := c.Correlation*before + residual*random.NormFloat64()
+
+				student := Student{
+					Arm:       a,
+					Cohort:    cohort,
+					Pre:       c.score(shift+before, random),
+					Post:      c.score(shift+after+c.Growth+arm.EffectSize, random),
+					Completed: random.Float64() >= c.Attrition,
+				}
+
+				students = append(students, student)
+			}
+
+			cohort++
+		}
+	}
+
+	return students
+}
+
+// score answers every item with an ability, returning the share of correct
+// answers.
+func (c Config) score(theta float64, random *rand.Rand) float64 {
+	correct := 0
+	for _, item := range c.Items {
+		p := item.Guessing + (1-item.Guessing)/(1+math.Exp(-item.Discrimination*(theta-item.Difficulty)))
+		if random.Float64() < p {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(c.Items))
+}
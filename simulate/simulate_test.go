@@ -0,0 +1,168 @@
+package simulate
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+const config = `
+seed: 7
+repetitions: 200
+growth: 0.3
+pre_post_correlation: 0.6
+attrition: 0.2
+arms:
+  - name: Control
+    cohorts: [100]
+  - name: Peer Instruction
+    effect_size: 0.8
+    cohorts: [100]
+items:
+  - {difficulty: -1, guessing: 0.25}
+  - {difficulty: -0.5}
+  - {difficulty: 0}
+  - {difficulty: 0.5, discrimination: 1.5}
+  - {difficulty: 1}
+`
+
+func TestParse(t *testing.T) {
+	c, err := Parse(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("failed to parse configuration: %v", err)
+	}
+
+	if c.Alpha != DefaultAlpha || c.Repetitions != 200 {
+		t.Errorf("expected alpha %v and 200 repetitions, got %v and %d", DefaultAlpha, c.Alpha, c.Repetitions)
+	}
+
+	want := []Item{
+		{Difficulty: -1, Discrimination: 1, Guessing: 0.25},
+		{Difficulty: -0.5, Discrimination: 1},
+		{Difficulty: 0, Discrimination: 1},
+		{Difficulty: 0.5, Discrimination: 1.5},
+		{Difficulty: 1, Discrimination: 1},
+	}
+	if !reflect.DeepEqual(c.Items, want) {
+		t.Errorf("expected items %+v, got %+v", want, c.Items)
+	}
+
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{"empty", "", "the configuration is empty"},
+		{"unknown field", "seed: 1\nsamples: 10\n", "field samples not found"},
+		{"one arm", "arms: [{cohorts: [10]}]\nitems: [{difficulty: 0}]\n", "arms must have a control"},
+		{"control effect", "arms: [{name: A, effect_size: 1, cohorts: [10]}, {cohorts: [10]}]\nitems: [{difficulty: 0}]\n",
+			"arm A is the control and can't have an effect size"},
+		{"no cohorts", "arms: [{cohorts: [10]}, {}]\nitems: [{difficulty: 0}]\n", "arm 2 has no cohorts"},
+		{"no items", "arms: [{cohorts: [10]}, {cohorts: [10]}]\n", "items must have at least one item"},
+		{"attrition", "attrition: 1\narms: [{cohorts: [10]}, {cohorts: [10]}]\nitems: [{difficulty: 0}]\n",
+			"attrition must be at least 0 and less than 1"},
+		{"guessing", "arms: [{cohorts: [10]}, {cohorts: [10]}]\nitems: [{difficulty: 0, guessing: 1}]\n",
+			"item 1 must have a guessing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.config))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestSample(t *testing.T) {
+	c, err := Parse(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("failed to parse configuration: %v", err)
+	}
+	c.Arms[0].Cohorts = []int{300, 300}
+	c.Arms[1].Cohorts = []int{300}
+
+	students := c.Sample(rand.New(rand.NewSource(1)))
+	if len(students) != 900 {
+		t.Fatalf("expected 900 students, got %d", len(students))
+	}
+
+	if s := students[599]; s.Arm != 0 || s.Cohort != 1 {
+		t.Errorf("expected student 600 in cohort 1 of the control, got %+v", s)
+	}
+	if s := students[600]; s.Arm != 1 || s.Cohort != 2 {
+		t.Errorf("expected student 601 in cohort 2 of the intervention, got %+v", s)
+	}
+
+	var gains [2][]float64
+	completed := 0
+	for _, s := range students {
+		gains[s.Arm] = append(gains[s.Arm], s.Post-s.Pre)
+		if s.Completed {
+			completed++
+		}
+	}
+
+	if rate := 1 - float64(completed)/900; math.Abs(rate-c.Attrition) > 0.05 {
+		t.Errorf("expected an attrition near %v, got %v", c.Attrition, rate)
+	}
+
+	control, intervention := stat.Mean(gains[0], nil), stat.Mean(gains[1], nil)
+	if control <= 0 || intervention <= control {
+		t.Errorf("expected both arms to gain and the intervention more, got %v and %v", control, intervention)
+	}
+
+	if again := c.Sample(rand.New(rand.NewSource(1))); !reflect.DeepEqual(again, students) {
+		t.Error("expected the same students from the same seed")
+	}
+}
+
+func TestRun(t *testing.T) {
+	c, err := Parse(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("failed to parse configuration: %v", err)
+	}
+
+	report := Run(c)
+	if report.Mixed || report.Failures != 0 || len(report.Arms) != 1 {
+		t.Fatalf("expected one arm compared by OLS, got %+v", report)
+	}
+
+	arm := report.Arms[0]
+	if arm.Name != "Peer Instruction" || arm.Power < 0.7 || arm.Power != arm.OLSPower {
+		t.Errorf("expected a large effect to be found most of the time, got %+v", arm)
+	}
+	if math.Abs(arm.Power-arm.PlannedPower) > 0.1 {
+		t.Errorf("expected the power to be close to the planned power, got %v and %v", arm.Power, arm.PlannedPower)
+	}
+	if arm.Control < 75 || arm.Control > 85 {
+		t.Errorf("expected about 80 students of the control after attrition, got %v", arm.Control)
+	}
+
+	if again := Run(c); !reflect.DeepEqual(again, report) {
+		t.Error("expected the same report from the same seed")
+	}
+
+	c.Arms[1].EffectSize = 0
+	null := Run(c).Arms[0]
+	if null.Power > 0.1 {
+		t.Errorf("expected significant results near alpha without an effect, got %v", null.Power)
+	}
+
+	c.Arms[0].Cohorts = []int{20, 20}
+	c.Arms[1].Cohorts = []int{20, 20}
+	c.Repetitions = 20
+	if report := Run(c); !report.Mixed {
+		t.Error("expected arms with many cohorts to be compared with a random intercept")
+	}
+
+	c.Arms[1].Cohorts = []int{40}
+	if report := Run(c); !report.Mixed {
+		t.Error("expected a random intercept when any arm has many cohorts")
+	}
+}
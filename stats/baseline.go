@@ -0,0 +1,224 @@
+package stats
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Equivalence classifies the baseline difference between two groups
+// following the What Works Clearinghouse standards.
+type Equivalence int
+
+const (
+	// EquivalenceUnknown means there is not enough data to compare the groups.
+	EquivalenceUnknown Equivalence = iota
+	// EquivalenceSatisfied means the groups differ by at most 0.05 SD.
+	EquivalenceSatisfied
+	// EquivalenceAdjustment means the groups differ by more than 0.05 SD and
+	// at most 0.25 SD. Gains must be adjusted for the baseline difference.
+	EquivalenceAdjustment
+	// EquivalenceNotSatisfied means the groups differ by more than 0.25 SD.
+	EquivalenceNotSatisfied
+)
+
+// WWCEquivalence classifies an absolute standardized baseline difference.
+func WWCEquivalence(g float64) Equivalence {
+	g = math.Abs(g)
+	switch {
+	case math.IsNaN(g):
+		return EquivalenceUnknown
+	case g <= 0.05:
+		return EquivalenceSatisfied
+	case g <= 0.25:
+		return EquivalenceAdjustment
+	default:
+		return EquivalenceNotSatisfied
+	}
+}
+
+// HedgesG calculates the standardized mean difference between two groups
+// with the small sample correction used by the What Works Clearinghouse.
+func HedgesG(control, intervention []float64) float64 {
+	n := float64(len(control) + len(intervention))
+	d := CohensD(control, intervention)
+	return d * (1 - 3/(4*n-9))
+}
+
+// WelchTTest compares the means of two groups without assuming equal
+// variances, returning the t-statistic, degrees of freedom and two-tailed p-value.
+func WelchTTest(a, b []float64) (t, df, pValue float64) {
+	n1, n2 := float64(len(a)), float64(len(b))
+	if n1 < 2 || n2 < 2 {
+		return math.NaN(), math.NaN(), 1.0
+	}
+
+	m1, v1 := stat.MeanVariance(a, nil)
+	m2, v2 := stat.MeanVariance(b, nil)
+
+	se1, se2 := v1/n1, v2/n2
+	if se1+se2 == 0 {
+		return 0, n1 + n2 - 2, 1.0
+	}
+
+	t = (m1 - m2) / math.Sqrt(se1+se2)
+	df = (se1 + se2) * (se1 + se2) / (se1*se1/(n1-1) + se2*se2/(n2-1))
+
+	tDist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}
+	pValue = 2 * (1 - tDist.CDF(math.Abs(t)))
+
+	return t, df, pValue
+}
+
+// MannWhitneyU compares the distributions of two groups without assuming
+// normality. It returns the U statistic of the first group and the two-tailed
+// p-value from the normal approximation with tie correction.
+func MannWhitneyU(a, b []float64) (u, pValue float64) {
+	n1, n2 := len(a), len(b)
+	if n1 == 0 || n2 == 0 {
+		return math.NaN(), 1.0
+	}
+
+	type value struct {
+		v     float64
+		first bool
+	}
+
+	values := make([]value, 0, n1+n2)
+	for _, v := range a {
+		values = append(values, value{v, true})
+	}
+	for _, v := range b {
+		values = append(values, value{v, false})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].v < values[j].v
+	})
+
+	// Assign average ranks to ties
+	n := len(values)
+	ranks := make([]float64, n)
+	ties := 0.0
+	for i := 0; i < n; {
+		j := i
+		for j < n && values[j].v == values[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			ranks[k] = rank
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	r1 := 0.0
+	for i, v := range values {
+		if v.first {
+			r1 += ranks[i]
+		}
+	}
+
+	fn1, fn2, fn := float64(n1), float64(n2), float64(n)
+	u = r1 - fn1*(fn1+1)/2
+
+	mean := fn1 * fn2 / 2
+	variance := fn1 * fn2 / 12 * ((fn + 1) - ties/(fn*(fn-1)))
+	if variance <= 0 {
+		return u, 1.0
+	}
+
+	z := (u - mean) / math.Sqrt(variance)
+	pValue = 2 * (1 - distuv.UnitNormal.CDF(math.Abs(z)))
+
+	return u, pValue
+}
+
+// ChiSquareTest tests the independence of a contingency table, such as
+// option counts per cohort. Rows or columns without observations are ignored.
+// It returns the chi-square statistic, its degrees of freedom and p-value.
+func ChiSquareTest(table [][]int) (chi2 float64, df int, pValue float64) {
+	table = trimTable(table)
+
+	rows := len(table)
+	if rows < 2 || len(table[0]) < 2 {
+		return math.NaN(), 0, 1.0
+	}
+	cols := len(table[0])
+
+	rowTotals := make([]float64, rows)
+	colTotals := make([]float64, cols)
+	total := 0.0
+	for i, row := range table {
+		for j, v := range row {
+			rowTotals[i] += float64(v)
+			colTotals[j] += float64(v)
+			total += float64(v)
+		}
+	}
+
+	for i, row := range table {
+		for j, v := range row {
+			expected := rowTotals[i] * colTotals[j] / total
+			diff := float64(v) - expected
+			chi2 += diff * diff / expected
+		}
+	}
+
+	df = (rows - 1) * (cols - 1)
+	dist := distuv.ChiSquared{K: float64(df)}
+	pValue = 1 - dist.CDF(chi2)
+
+	return chi2, df, pValue
+}
+
+// trimTable removes the rows and columns of a contingency table without observations.
+func trimTable(table [][]int) [][]int {
+	if len(table) == 0 {
+		return nil
+	}
+
+	cols := 0
+	for _, row := range table {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+
+	keep := make([]bool, cols)
+	var rows [][]int
+	for _, row := range table {
+		sum := 0
+		for j, v := range row {
+			sum += v
+			if v > 0 {
+				keep[j] = true
+			}
+		}
+		if sum > 0 {
+			rows = append(rows, row)
+		}
+	}
+
+	var trimmed [][]int
+	for _, row := range rows {
+		var r []int
+		for j := 0; j < cols; j++ {
+			if !keep[j] {
+				continue
+			}
+			if j < len(row) {
+				r = append(r, row[j])
+			} else {
+				r = append(r, 0)
+			}
+		}
+		trimmed = append(trimmed, r)
+	}
+
+	return trimmed
+}
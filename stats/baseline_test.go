@@ -0,0 +1,69 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWelchTTest(t *testing.T) {
+	a := []float64{1, 2, 3, 4, 5}
+	b := []float64{2, 4, 6, 8, 10}
+
+	tStat, df, p := WelchTTest(a, b)
+	if math.Abs(tStat+1.8974) > 0.001 {
+		t.Errorf("WelchTTest() t = %f; want -1.8974", tStat)
+	}
+	if math.Abs(df-5.882) > 0.001 {
+		t.Errorf("WelchTTest() df = %f; want 5.882", df)
+	}
+	if math.Abs(p-0.1075) > 0.005 {
+		t.Errorf("WelchTTest() p = %f; want ~0.1075", p)
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	u, p := MannWhitneyU([]float64{1, 2, 3}, []float64{4, 5, 6})
+	if u != 0 {
+		t.Errorf("MannWhitneyU() u = %f; want 0", u)
+	}
+	if math.Abs(p-0.0495) > 0.001 {
+		t.Errorf("MannWhitneyU() p = %f; want ~0.0495", p)
+	}
+
+	_, p = MannWhitneyU([]float64{1, 1, 1}, []float64{1, 1, 1})
+	if p != 1.0 {
+		t.Errorf("MannWhitneyU() with all ties p = %f; want 1", p)
+	}
+}
+
+func TestChiSquareTest(t *testing.T) {
+	chi2, df, p := ChiSquareTest([][]int{{10, 20, 0}, {20, 10, 0}, {0, 0, 0}})
+	if math.Abs(chi2-6.667) > 0.001 {
+		t.Errorf("ChiSquareTest() chi2 = %f; want 6.667", chi2)
+	}
+	if df != 1 {
+		t.Errorf("ChiSquareTest() df = %d; want 1", df)
+	}
+	if math.Abs(p-0.0098) > 0.0005 {
+		t.Errorf("ChiSquareTest() p = %f; want ~0.0098", p)
+	}
+}
+
+func TestWWCEquivalence(t *testing.T) {
+	tests := []struct {
+		g    float64
+		want Equivalence
+	}{
+		{g: 0.03, want: EquivalenceSatisfied},
+		{g: -0.05, want: EquivalenceSatisfied},
+		{g: 0.1, want: EquivalenceAdjustment},
+		{g: -0.3, want: EquivalenceNotSatisfied},
+		{g: math.NaN(), want: EquivalenceUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := WWCEquivalence(tt.g); got != tt.want {
+			t.Errorf("WWCEquivalence(%f) = %d; want %d", tt.g, got, tt.want)
+		}
+	}
+}
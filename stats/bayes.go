@@ -0,0 +1,114 @@
+package stats
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// BayesDraws is the number of posterior samples drawn by the Bayesian estimates.
+const BayesDraws = 20000
+
+// BayesSeed seeds the posterior sampler so estimates are reproducible.
+const BayesSeed = 2024
+
+// Posterior summarizes the posterior distribution of the difference between
+// the intervention and the control (intervention - control).
+type Posterior struct {
+	Mean              float64
+	Lower             float64 // 2.5% quantile
+	Upper             float64 // 97.5% quantile
+	ProbabilityBetter float64 // Probability that the intervention outperforms control
+	Invalid           bool
+}
+
+// BetaBinomial estimates the difference in the proportion of correct answers
+// between cohorts. Each proportion has a uniform Beta(1, 1) prior, updated by
+// the number of correct answers (successes) out of all answers (trials).
+func BetaBinomial(successesControl, trialsControl, successesIntervention,
+	trialsIntervention int, seed uint64) Posterior {
+
+	if trialsControl == 0 || trialsIntervention == 0 {
+		return Posterior{Invalid: true}
+	}
+
+	src := rand.NewSource(seed)
+
+	control := distuv.Beta{
+		Alpha: float64(1 + successesControl),
+		Beta:  float64(1 + trialsControl - successesControl),
+		Src:   src,
+	}
+
+	intervention := distuv.Beta{
+		Alpha: float64(1 + successesIntervention),
+		Beta:  float64(1 + trialsIntervention - successesIntervention),
+		Src:   src,
+	}
+
+	diffs := make([]float64, BayesDraws)
+	for i := range diffs {
+		diffs[i] = intervention.Rand() - control.Rand()
+	}
+
+	return summarize(diffs)
+}
+
+// NormalGains estimates the difference in mean learning gains between cohorts.
+// Gains are modelled as normal with a non-informative prior, so the posterior
+// of each mean is a Student's t centred on the sample mean.
+func NormalGains(control, intervention []float64, seed uint64) Posterior {
+	if len(control) < 2 || len(intervention) < 2 {
+		return Posterior{Invalid: true}
+	}
+
+	src := rand.NewSource(seed)
+
+	c := meanPosterior(control, src)
+	i := meanPosterior(intervention, src)
+
+	diffs := make([]float64, BayesDraws)
+	for k := range diffs {
+		diffs[k] = i.Rand() - c.Rand()
+	}
+
+	return summarize(diffs)
+}
+
+// meanPosterior returns the posterior of the mean of normal data.
+func meanPosterior(data []float64, src rand.Source) distuv.StudentsT {
+	n := float64(len(data))
+	mean, sd := stat.MeanStdDev(data, nil)
+
+	// Avoid a degenerate distribution when all values are equal
+	sigma := math.Max(sd/math.Sqrt(n), 1e-9)
+
+	return distuv.StudentsT{
+		Mu:    mean,
+		Sigma: sigma,
+		Nu:    n - 1,
+		Src:   src,
+	}
+}
+
+// summarize describes posterior samples of a difference.
+func summarize(diffs []float64) Posterior {
+	sort.Float64s(diffs)
+
+	better := 0
+	for _, d := range diffs {
+		if d > 0 {
+			better++
+		}
+	}
+
+	return Posterior{
+		Mean:              stat.Mean(diffs, nil),
+		Lower:             stat.Quantile(0.025, stat.Empirical, diffs, nil),
+		Upper:             stat.Quantile(0.975, stat.Empirical, diffs, nil),
+		ProbabilityBetter: float64(better) / float64(len(diffs)),
+	}
+}
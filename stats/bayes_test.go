@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBetaBinomial(t *testing.T) {
+	p := BetaBinomial(30, 100, 50, 100, BayesSeed)
+	if p.Invalid {
+		t.Fatalf("BetaBinomial() invalid = true; want false")
+	}
+	if math.Abs(p.Mean-0.196) > 0.01 {
+		t.Errorf("BetaBinomial() mean = %.3f; want ~0.196", p.Mean)
+	}
+	if p.ProbabilityBetter < 0.99 {
+		t.Errorf("BetaBinomial() P(better) = %.3f; want > 0.99", p.ProbabilityBetter)
+	}
+	if p.Lower > p.Mean || p.Upper < p.Mean {
+		t.Errorf("BetaBinomial() interval [%.3f, %.3f] does not contain mean %.3f", p.Lower, p.Upper, p.Mean)
+	}
+
+	again := BetaBinomial(30, 100, 50, 100, BayesSeed)
+	if again != p {
+		t.Errorf("BetaBinomial() = %v; want reproducible %v", again, p)
+	}
+
+	if !BetaBinomial(0, 0, 1, 1, BayesSeed).Invalid {
+		t.Errorf("BetaBinomial() without trials invalid = false; want true")
+	}
+}
+
+func TestNormalGains(t *testing.T) {
+	control := []float64{0, 0.1, 0.2, 0.1, 0, 0.2, 0.1, 0.3}
+	intervention := []float64{0.1, 0.2, 0.3, 0.2, 0.1, 0.3, 0.2, 0.4}
+
+	p := NormalGains(control, intervention, BayesSeed)
+	if math.Abs(p.Mean-0.1) > 0.01 {
+		t.Errorf("NormalGains() mean = %.3f; want ~0.1", p.Mean)
+	}
+	if p.ProbabilityBetter < 0.9 {
+		t.Errorf("NormalGains() P(better) = %.3f; want > 0.9", p.ProbabilityBetter)
+	}
+
+	p = NormalGains(control, control, BayesSeed)
+	if math.Abs(p.ProbabilityBetter-0.5) > 0.02 {
+		t.Errorf("NormalGains() same data P(better) = %.3f; want ~0.5", p.ProbabilityBetter)
+	}
+}
@@ -0,0 +1,172 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// maxFisherTables limits how many tables Fisher's exact test enumerates
+// before giving up in favour of the chi-square approximation.
+const maxFisherTables = 1000000
+
+// Contingency holds the test of independence of a contingency table.
+type Contingency struct {
+	N         int     `json:"n"`
+	ChiSquare float64 `json:"chi_square"`
+	DF        int     `json:"df"`
+	PValue    float64 `json:"p_value"`
+	CramersV  float64 `json:"cramers_v"`
+	Exact     bool    `json:"exact"` // Whether the p-value comes from Fisher's exact test
+	Invalid   bool    `json:"invalid"`
+}
+
+// ContingencyTest tests whether the rows of a table (e.g. cohorts) are
+// distributed differently across its columns (e.g. choices). The chi-square
+// test is used unless an expected count is below 5, in which case Fisher's
+// exact test is used when the table is small enough to enumerate.
+func ContingencyTest(table [][]int) Contingency {
+	table = trimTable(table)
+
+	chi2, df, p := ChiSquareTest(table)
+	if math.IsNaN(chi2) {
+		return Contingency{PValue: 1.0, Invalid: true}
+	}
+
+	rows, cols := len(table), len(table[0])
+
+	c := Contingency{
+		ChiSquare: chi2,
+		DF:        df,
+		PValue:    p,
+	}
+
+	rowTotals, colTotals, n := margins(table)
+	c.N = n
+	c.CramersV = math.Sqrt(chi2 / (float64(n) * float64(min(rows, cols)-1)))
+
+	small := false
+	for _, r := range rowTotals {
+		for _, col := range colTotals {
+			if float64(r)*float64(col)/float64(n) < 5 {
+				small = true
+			}
+		}
+	}
+
+	if small {
+		if exact, err := FisherExact(table); err == nil {
+			c.PValue = exact
+			c.Exact = true
+		}
+	}
+
+	return c
+}
+
+// FisherExact calculates the two-sided p-value of the Fisher-Freeman-Halton
+// exact test by enumerating every table with the same margins. It returns an
+// error if the table is too large to enumerate.
+func FisherExact(table [][]int) (float64, error) {
+	table = trimTable(table)
+	if len(table) < 2 || len(table[0]) < 2 {
+		return 1.0, nil
+	}
+
+	rowTotals, colTotals, n := margins(table)
+
+	// log of the probability constant: prod(R!) prod(C!) / N!
+	constant := -logFactorial(n)
+	for _, r := range rowTotals {
+		constant += logFactorial(r)
+	}
+	for _, c := range colTotals {
+		constant += logFactorial(c)
+	}
+
+	observed := constant
+	for _, row := range table {
+		for _, v := range row {
+			observed -= logFactorial(v)
+		}
+	}
+
+	rows, cols := len(table), len(table[0])
+	rowRem := append([]int{}, rowTotals...)
+	colRem := append([]int{}, colTotals...)
+
+	// Tolerance for ties between table probabilities
+	const epsilon = 1e-7
+
+	pValue := 0.0
+	count := 0
+
+	var fill func(i, j int, logp float64) bool
+	fill = func(i, j int, logp float64) bool {
+		if i == rows-1 {
+			// The last row is determined by the remaining column totals
+			for _, c := range colRem {
+				logp -= logFactorial(c)
+			}
+			count++
+			if logp <= observed+epsilon {
+				pValue += math.Exp(logp)
+			}
+			return count < maxFisherTables
+		}
+
+		if j == cols-1 {
+			// The last column is determined by the remaining row total
+			v := rowRem[i]
+			colRem[j] -= v
+			ok := fill(i+1, 0, logp-logFactorial(v))
+			colRem[j] += v
+			return ok
+		}
+
+		rest := 0
+		for k := j + 1; k < cols; k++ {
+			rest += colRem[k]
+		}
+
+		lo := max(0, rowRem[i]-rest)
+		hi := min(rowRem[i], colRem[j])
+
+		for v := lo; v <= hi; v++ {
+			rowRem[i] -= v
+			colRem[j] -= v
+			ok := fill(i, j+1, logp-logFactorial(v))
+			rowRem[i] += v
+			colRem[j] += v
+			if !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	if !fill(0, 0, constant) {
+		return math.NaN(), errors.New("table too large for exact test")
+	}
+
+	return math.Min(pValue, 1.0), nil
+}
+
+// margins returns the row and column totals of a table and its grand total.
+func margins(table [][]int) ([]int, []int, int) {
+	rowTotals := make([]int, len(table))
+	colTotals := make([]int, len(table[0]))
+	n := 0
+	for i, row := range table {
+		for j, v := range row {
+			rowTotals[i] += v
+			colTotals[j] += v
+			n += v
+		}
+	}
+	return rowTotals, colTotals, n
+}
+
+func logFactorial(n int) float64 {
+	v, _ := math.Lgamma(float64(n + 1))
+	return v
+}
@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFisherExact(t *testing.T) {
+	tests := []struct {
+		table [][]int
+		want  float64
+	}{
+		{table: [][]int{{3, 1}, {1, 3}}, want: 0.4857},
+		{table: [][]int{{2, 0}, {0, 2}}, want: 0.3333},
+		{table: [][]int{{1, 9}, {11, 3}}, want: 0.0028},
+		{table: [][]int{{5, 0}, {0, 0}}, want: 1.0},
+	}
+
+	for _, tt := range tests {
+		p, err := FisherExact(tt.table)
+		if err != nil {
+			t.Fatalf("FisherExact(%v) error = %v, want nil", tt.table, err)
+		}
+		if math.Abs(p-tt.want) > 0.0001 {
+			t.Errorf("FisherExact(%v) = %.4f; want %.4f", tt.table, p, tt.want)
+		}
+	}
+}
+
+func TestContingencyTest(t *testing.T) {
+	c := ContingencyTest([][]int{{10, 20}, {20, 10}})
+	if c.Exact {
+		t.Errorf("ContingencyTest() exact = true; want chi-square for large counts")
+	}
+	if math.Abs(c.CramersV-0.3333) > 0.0001 {
+		t.Errorf("ContingencyTest() Cramér's V = %.4f; want 0.3333", c.CramersV)
+	}
+
+	c = ContingencyTest([][]int{{3, 1}, {1, 3}})
+	if !c.Exact {
+		t.Errorf("ContingencyTest() exact = false; want Fisher for small counts")
+	}
+
+	c = ContingencyTest([][]int{{3, 1}})
+	if !c.Invalid {
+		t.Errorf("ContingencyTest() invalid = false; want true for a single row")
+	}
+}
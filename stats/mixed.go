@@ -0,0 +1,215 @@
+package stats
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// MixedModel holds the results of a linear mixed model with a random
+// intercept per group, such as students nested in lab sections.
+// The first coefficient is always the intercept.
+type MixedModel struct {
+	Coefficients   []float64
+	StandardErrors []float64
+	PValues        []float64
+	GroupVariance  float64 // Variance of the random intercepts
+	Variance       float64 // Residual variance
+	ICC            float64 // Intraclass correlation
+	DF             int     // Degrees of freedom of the fixed effects (groups - coefficients)
+	Groups         int
+}
+
+// RandomIntercept fits y = b0 + b1*x1 + ... + bk*xk + u[group] + e by
+// restricted maximum likelihood (REML). Each row of x holds the predictors of
+// one observation; the intercept is added. Fixed effects are tested against
+// the number of groups rather than observations, since predictors such as the
+// treatment arm vary between groups only.
+func RandomIntercept(y []float64, x [][]float64, groups []int) (MixedModel, error) {
+	n := len(y)
+	if n == 0 || len(x) != n || len(groups) != n {
+		return MixedModel{}, errors.New("mismatched number of observations")
+	}
+
+	p := len(x[0]) + 1
+	if n <= p {
+		return MixedModel{}, errors.New("not enough observations")
+	}
+
+	// Split observations by group
+	index := make(map[int]int)
+	var members [][]int
+	for i, g := range groups {
+		j, ok := index[g]
+		if !ok {
+			j = len(members)
+			index[g] = j
+			members = append(members, nil)
+		}
+		members[j] = append(members[j], i)
+	}
+
+	design := make([][]float64, n)
+	for i, row := range x {
+		design[i] = append([]float64{1}, row...)
+	}
+
+	m := mixedFit{y: y, x: design, groups: members, p: p}
+
+	// Find the ratio between group and residual variances that maximizes the
+	// restricted likelihood, searching on a log scale
+	best, bestLL := 0.0, m.reml(0)
+	if math.IsNaN(bestLL) {
+		return MixedModel{}, errors.New("predictors are collinear")
+	}
+
+	lo, hi := -12.0, 8.0
+	const phi = 0.6180339887498949
+	a := hi - phi*(hi-lo)
+	b := lo + phi*(hi-lo)
+	fa, fb := m.reml(math.Exp(a)), m.reml(math.Exp(b))
+	for hi-lo > 1e-6 {
+		if fa > fb {
+			hi, b, fb = b, a, fa
+			a = hi - phi*(hi-lo)
+			fa = m.reml(math.Exp(a))
+		} else {
+			lo, a, fa = a, b, fb
+			b = lo + phi*(hi-lo)
+			fb = m.reml(math.Exp(b))
+		}
+	}
+
+	if ll := m.reml(math.Exp(lo)); ll > bestLL {
+		best = math.Exp(lo)
+	}
+
+	beta, cov, q, err := m.gls(best)
+	if err != nil {
+		return MixedModel{}, err
+	}
+
+	variance := q / float64(n-p)
+
+	mm := MixedModel{
+		Coefficients:   make([]float64, p),
+		StandardErrors: make([]float64, p),
+		PValues:        make([]float64, p),
+		Variance:       variance,
+		GroupVariance:  best * variance,
+		Groups:         len(members),
+		DF:             len(members) - p,
+	}
+
+	if total := mm.GroupVariance + mm.Variance; total > 0 {
+		mm.ICC = mm.GroupVariance / total
+	}
+
+	for j := 0; j < p; j++ {
+		mm.Coefficients[j] = beta.AtVec(j)
+		mm.StandardErrors[j] = math.Sqrt(variance * cov.At(j, j))
+
+		mm.PValues[j] = 1.0
+		if mm.DF > 0 && mm.StandardErrors[j] > 0 {
+			t := mm.Coefficients[j] / mm.StandardErrors[j]
+			tDist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(mm.DF)}
+			mm.PValues[j] = 2 * (1 - tDist.CDF(math.Abs(t)))
+		}
+	}
+
+	return mm, nil
+}
+
+// mixedFit holds the data of a random intercept model. Observations of a group
+// are correlated through V = I + lambda*J, scaled by the residual variance.
+type mixedFit struct {
+	y      []float64
+	x      [][]float64
+	groups [][]int
+	p      int
+}
+
+// gls estimates the fixed effects by generalized least squares for a ratio of
+// group to residual variance. It returns the coefficients, the inverse of
+// X'V^-1X and the weighted residual sum of squares.
+func (m mixedFit) gls(lambda float64) (*mat.VecDense, *mat.Dense, float64, error) {
+	p := m.p
+	xtx := mat.NewDense(p, p, nil)
+	xty := mat.NewVecDense(p, nil)
+
+	for _, members := range m.groups {
+		// V^-1 = I - c*J for a group of size k
+		c := lambda / (1 + float64(len(members))*lambda)
+
+		sumX := make([]float64, p)
+		sumY := 0.0
+		for _, i := range members {
+			sumY += m.y[i]
+			for a := 0; a < p; a++ {
+				sumX[a] += m.x[i][a]
+				xty.SetVec(a, xty.AtVec(a)+m.x[i][a]*m.y[i])
+				for b := 0; b < p; b++ {
+					xtx.Set(a, b, xtx.At(a, b)+m.x[i][a]*m.x[i][b])
+				}
+			}
+		}
+
+		for a := 0; a < p; a++ {
+			xty.SetVec(a, xty.AtVec(a)-c*sumX[a]*sumY)
+			for b := 0; b < p; b++ {
+				xtx.Set(a, b, xtx.At(a, b)-c*sumX[a]*sumX[b])
+			}
+		}
+	}
+
+	var inv mat.Dense
+	if err := inv.Inverse(xtx); err != nil {
+		return nil, nil, 0, errors.New("predictors are collinear")
+	}
+
+	var beta mat.VecDense
+	beta.MulVec(&inv, xty)
+
+	q := 0.0
+	for _, members := range m.groups {
+		c := lambda / (1 + float64(len(members))*lambda)
+
+		sum := 0.0
+		for _, i := range members {
+			r := m.y[i]
+			for a := 0; a < p; a++ {
+				r -= m.x[i][a] * beta.AtVec(a)
+			}
+			q += r * r
+			sum += r
+		}
+		q -= c * sum * sum
+	}
+
+	return &beta, &inv, q, nil
+}
+
+// reml returns the restricted log-likelihood, up to a constant, with the
+// residual variance profiled out.
+func (m mixedFit) reml(lambda float64) float64 {
+	_, inv, q, err := m.gls(lambda)
+	if err != nil || q <= 0 {
+		return math.NaN()
+	}
+
+	n := 0
+	logDetV := 0.0
+	for _, members := range m.groups {
+		n += len(members)
+		logDetV += math.Log(1 + float64(len(members))*lambda)
+	}
+
+	// log|X'V^-1X| = -log|(X'V^-1X)^-1|
+	logDet, _ := mat.LogDet(inv)
+	logDetXVX := -logDet
+
+	df := float64(n - m.p)
+	return -0.5 * (df*math.Log(q) + logDetV + logDetXVX)
+}
@@ -0,0 +1,67 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRandomIntercept(t *testing.T) {
+	// Four groups of three, the first two in arm 0 and the last two in arm 1
+	y := []float64{1, 2, 3, 3, 4, 5, 5, 6, 7, 9, 10, 11}
+	groups := []int{0, 0, 0, 1, 1, 1, 2, 2, 2, 3, 3, 3}
+
+	var x [][]float64
+	for _, g := range groups {
+		x = append(x, []float64{indicator(g >= 2)})
+	}
+
+	mm, err := RandomIntercept(y, x, groups)
+	if err != nil {
+		t.Fatalf("RandomIntercept() error = %v, want nil", err)
+	}
+
+	expected := []struct {
+		name      string
+		got, want float64
+	}{
+		{"intercept", mm.Coefficients[0], 3},
+		{"arm effect", mm.Coefficients[1], 5},
+		{"arm standard error", mm.StandardErrors[1], math.Sqrt(5)},
+		{"residual variance", mm.Variance, 1},
+		{"group variance", mm.GroupVariance, 14.0 / 3},
+		{"ICC", mm.ICC, (14.0 / 3) / (17.0 / 3)},
+	}
+
+	for _, e := range expected {
+		if math.Abs(e.got-e.want) > 0.001 {
+			t.Errorf("RandomIntercept() %s = %.4f; want %.4f", e.name, e.got, e.want)
+		}
+	}
+
+	if mm.DF != 2 || mm.Groups != 4 {
+		t.Errorf("RandomIntercept() DF = %d, groups = %d; want 2, 4", mm.DF, mm.Groups)
+	}
+}
+
+func TestRandomInterceptWithoutGroupVariance(t *testing.T) {
+	y := []float64{1, 2, 3, 2, 3, 1, 4, 5, 6, 5, 6, 4}
+	groups := []int{0, 0, 0, 1, 1, 1, 2, 2, 2, 3, 3, 3}
+
+	var x [][]float64
+	for _, g := range groups {
+		x = append(x, []float64{indicator(g >= 2)})
+	}
+
+	mm, err := RandomIntercept(y, x, groups)
+	if err != nil {
+		t.Fatalf("RandomIntercept() error = %v, want nil", err)
+	}
+
+	if mm.ICC > 0.001 {
+		t.Errorf("RandomIntercept() ICC = %.4f; want 0", mm.ICC)
+	}
+
+	if math.Abs(mm.Coefficients[1]-3) > 0.001 {
+		t.Errorf("RandomIntercept() arm effect = %.4f; want 3", mm.Coefficients[1])
+	}
+}
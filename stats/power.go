@@ -0,0 +1,117 @@
+package stats
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// maxSampleSize caps the search for the required sample size per cohort.
+const maxSampleSize = 100000
+
+// Power holds the inputs and the outcome of a sample size calculation.
+type Power struct {
+	EffectSize float64 // Expected standardized effect size (Cohen's d)
+	Alpha      float64 // Significance level (two-tailed)
+	Power      float64 // Desired statistical power (1 - beta)
+	Cohorts    int     // Number of cohorts, including the control
+	PerCohort  int     // Required participants per cohort
+	Total      int     // Required participants across all cohorts
+}
+
+// RequiredSampleSize calculates how many participants each cohort needs to
+// detect the given effect size when every intervention cohort is compared
+// against the control. The significance level is Bonferroni-adjusted for the
+// number of comparisons when there are more than two cohorts.
+func RequiredSampleSize(effectSize, alpha, power float64, cohorts int) Power {
+	p := Power{
+		EffectSize: effectSize,
+		Alpha:      alpha,
+		Power:      power,
+		Cohorts:    cohorts,
+	}
+
+	if effectSize <= 0 || alpha <= 0 || alpha >= 1 || power <= 0 || power >= 1 || cohorts < 2 {
+		return p
+	}
+
+	adjusted := adjustAlpha(alpha, cohorts)
+
+	for n := 2; n <= maxSampleSize; n++ {
+		if TwoSampleTPower(effectSize, n, n, adjusted) >= power {
+			p.PerCohort = n
+			p.Total = n * cohorts
+			break
+		}
+	}
+
+	return p
+}
+
+// TwoSampleTPower calculates the power of a two-tailed, two-sample t-test to
+// detect an effect size d with n1 and n2 participants per group.
+// The noncentral t-distribution is approximated by shifting the central one.
+func TwoSampleTPower(d float64, n1, n2 int, alpha float64) float64 {
+	if n1 < 2 || n2 < 2 || alpha <= 0 || alpha >= 1 {
+		return math.NaN()
+	}
+
+	df := float64(n1 + n2 - 2)
+	ncp := math.Abs(d) * math.Sqrt(float64(n1*n2)/float64(n1+n2))
+
+	tDist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}
+	tCritical := tDist.Quantile(1 - alpha/2)
+
+	return 1 - tDist.CDF(tCritical-ncp) + tDist.CDF(-tCritical-ncp)
+}
+
+// MinimumDetectableEffect calculates the smallest standardized effect size
+// (Cohen's d) a two-sample t-test can detect with the given group sizes,
+// significance level and power.
+func MinimumDetectableEffect(n1, n2 int, alpha, power float64) float64 {
+	if n1 < 2 || n2 < 2 || power <= 0 || power >= 1 {
+		return math.NaN()
+	}
+
+	low, high := 0.0, 10.0
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2
+		if TwoSampleTPower(mid, n1, n2, alpha) < power {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	return high
+}
+
+// CohensD calculates the standardized mean difference between two groups
+// using their pooled standard deviation.
+func CohensD(control, intervention []float64) float64 {
+	n1, n2 := float64(len(control)), float64(len(intervention))
+	if n1 < 2 || n2 < 2 {
+		return math.NaN()
+	}
+
+	m1, v1 := stat.MeanVariance(control, nil)
+	m2, v2 := stat.MeanVariance(intervention, nil)
+
+	pooled := math.Sqrt(((n1-1)*v1 + (n2-1)*v2) / (n1 + n2 - 2))
+	if pooled == 0 {
+		return 0
+	}
+
+	return (m2 - m1) / pooled
+}
+
+// adjustAlpha applies a Bonferroni correction for comparing each intervention
+// cohort against the control.
+func adjustAlpha(alpha float64, cohorts int) float64 {
+	comparisons := cohorts - 1
+	if comparisons < 1 {
+		comparisons = 1
+	}
+	return alpha / float64(comparisons)
+}
@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRequiredSampleSize(t *testing.T) {
+	tests := []struct {
+		effectSize float64
+		alpha      float64
+		power      float64
+		cohorts    int
+		perCohort  int
+	}{
+		{effectSize: 0.5, alpha: 0.05, power: 0.8, cohorts: 2, perCohort: 64},
+		{effectSize: 0.8, alpha: 0.05, power: 0.8, cohorts: 2, perCohort: 26},
+		{effectSize: 0.2, alpha: 0.05, power: 0.8, cohorts: 2, perCohort: 394},
+		{effectSize: 0.5, alpha: 0.05, power: 0.8, cohorts: 3, perCohort: 78},
+		{effectSize: 0, alpha: 0.05, power: 0.8, cohorts: 2, perCohort: 0},
+		{effectSize: 0.5, alpha: 0.05, power: 0.8, cohorts: 1, perCohort: 0},
+	}
+
+	for _, tt := range tests {
+		p := RequiredSampleSize(tt.effectSize, tt.alpha, tt.power, tt.cohorts)
+		if p.PerCohort != tt.perCohort {
+			t.Errorf("RequiredSampleSize(%.2f, %.2f, %.2f, %d) = %d; want %d",
+				tt.effectSize, tt.alpha, tt.power, tt.cohorts, p.PerCohort, tt.perCohort)
+		}
+		if p.Total != tt.perCohort*tt.cohorts && p.PerCohort != 0 {
+			t.Errorf("RequiredSampleSize() total = %d; want %d", p.Total, tt.perCohort*tt.cohorts)
+		}
+	}
+}
+
+func TestMinimumDetectableEffect(t *testing.T) {
+	mde := MinimumDetectableEffect(64, 64, 0.05, 0.8)
+	if math.Abs(mde-0.5) > 0.01 {
+		t.Errorf("MinimumDetectableEffect(64, 64) = %f; want ~0.5", mde)
+	}
+
+	if !math.IsNaN(MinimumDetectableEffect(1, 64, 0.05, 0.8)) {
+		t.Error("MinimumDetectableEffect() with a single participant should be NaN")
+	}
+}
+
+func TestCohensD(t *testing.T) {
+	control := []float64{1, 2, 3, 4, 5}
+	intervention := []float64{2, 3, 4, 5, 6}
+
+	d := CohensD(control, intervention)
+	want := 1 / math.Sqrt(2.5)
+	if math.Abs(d-want) > 1e-9 {
+		t.Errorf("CohensD() = %f; want %f", d, want)
+	}
+}
@@ -0,0 +1,184 @@
+package stats
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Regression holds the results of an ordinary least squares fit.
+// The first coefficient is always the intercept.
+type Regression struct {
+	Coefficients   []float64
+	StandardErrors []float64
+	PValues        []float64
+	RSquared       float64
+	RSS            float64 // Residual sum of squares
+	DF             int     // Residual degrees of freedom
+}
+
+// Interaction holds the test of whether the effect of the intervention
+// differs across subgroups (cohort x subgroup interaction).
+type Interaction struct {
+	Full    Regression
+	F       float64
+	PValue  float64
+	Numer   int // Numerator degrees of freedom
+	Denom   int // Denominator degrees of freedom
+	Invalid bool
+}
+
+// OLS fits y = b0 + b1*x1 + ... + bk*xk by ordinary least squares.
+// Each row of x holds the predictors of one observation; the intercept is added.
+func OLS(y []float64, x [][]float64) (Regression, error) {
+	n := len(y)
+	if n == 0 || len(x) != n {
+		return Regression{}, errors.New("mismatched number of observations")
+	}
+
+	p := len(x[0]) + 1
+	if n <= p {
+		return Regression{}, errors.New("not enough observations")
+	}
+
+	X := mat.NewDense(n, p, nil)
+	for i, row := range x {
+		X.Set(i, 0, 1)
+		for j, v := range row {
+			X.Set(i, j+1, v)
+		}
+	}
+	Y := mat.NewVecDense(n, y)
+
+	var xtx mat.Dense
+	xtx.Mul(X.T(), X)
+
+	var inv mat.Dense
+	if err := inv.Inverse(&xtx); err != nil {
+		return Regression{}, errors.New("predictors are collinear")
+	}
+
+	var xty mat.VecDense
+	xty.MulVec(X.T(), Y)
+
+	var beta mat.VecDense
+	beta.MulVec(&inv, &xty)
+
+	var fitted mat.VecDense
+	fitted.MulVec(X, &beta)
+
+	mean := 0.0
+	for _, v := range y {
+		mean += v
+	}
+	mean /= float64(n)
+
+	var rss, tss float64
+	for i, v := range y {
+		r := v - fitted.AtVec(i)
+		rss += r * r
+		tss += (v - mean) * (v - mean)
+	}
+
+	df := n - p
+	sigma2 := rss / float64(df)
+
+	tDist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(df)}
+
+	reg := Regression{
+		Coefficients:   make([]float64, p),
+		StandardErrors: make([]float64, p),
+		PValues:        make([]float64, p),
+		RSS:            rss,
+		DF:             df,
+	}
+
+	if tss > 0 {
+		reg.RSquared = 1 - rss/tss
+	}
+
+	for j := 0; j < p; j++ {
+		reg.Coefficients[j] = beta.AtVec(j)
+		reg.StandardErrors[j] = math.Sqrt(sigma2 * inv.At(j, j))
+
+		t := reg.Coefficients[j] / reg.StandardErrors[j]
+		reg.PValues[j] = 2 * (1 - tDist.CDF(math.Abs(t)))
+		if math.IsNaN(reg.PValues[j]) {
+			reg.PValues[j] = 1.0
+		}
+	}
+
+	return reg, nil
+}
+
+// FTest compares a reduced model nested in a full model, returning the
+// F-statistic and its p-value.
+func FTest(reduced, full Regression) (float64, float64) {
+	numer := reduced.DF - full.DF
+	if numer <= 0 || full.DF <= 0 || full.RSS == 0 {
+		return math.NaN(), 1.0
+	}
+
+	f := ((reduced.RSS - full.RSS) / float64(numer)) / (full.RSS / float64(full.DF))
+
+	fDist := distuv.F{D1: float64(numer), D2: float64(full.DF)}
+	return f, 1 - fDist.CDF(f)
+}
+
+// InteractionTest fits gains on cohort and subgroup indicators, with and
+// without their products, to test whether the cohort effect differs across
+// subgroups. Cohort and subgroup 0 are the reference levels.
+func InteractionTest(gains []float64, cohorts []int, subgroups []int,
+	numCohorts, numSubgroups int) Interaction {
+
+	var reduced, full [][]float64
+	for i := range gains {
+		main := make([]float64, 0, numCohorts+numSubgroups)
+		for c := 1; c < numCohorts; c++ {
+			main = append(main, indicator(cohorts[i] == c))
+		}
+		for s := 1; s < numSubgroups; s++ {
+			main = append(main, indicator(subgroups[i] == s))
+		}
+
+		products := append([]float64{}, main...)
+		for c := 1; c < numCohorts; c++ {
+			for s := 1; s < numSubgroups; s++ {
+				products = append(products, indicator(cohorts[i] == c && subgroups[i] == s))
+			}
+		}
+
+		reduced = append(reduced, main)
+		full = append(full, products)
+	}
+
+	fullReg, err := OLS(gains, full)
+	if err != nil {
+		return Interaction{Invalid: true}
+	}
+
+	reducedReg, err := OLS(gains, reduced)
+	if err != nil {
+		return Interaction{Invalid: true}
+	}
+
+	f, p := FTest(reducedReg, fullReg)
+
+	return Interaction{
+		Full:    fullReg,
+		F:       f,
+		PValue:  p,
+		Numer:   reducedReg.DF - fullReg.DF,
+		Denom:   fullReg.DF,
+		Invalid: math.IsNaN(f),
+	}
+}
+
+func indicator(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOLS(t *testing.T) {
+	y := []float64{3.1, 4.9, 7.2, 8.8, 11.1}
+	x := [][]float64{{1}, {2}, {3}, {4}, {5}}
+
+	reg, err := OLS(y, x)
+	if err != nil {
+		t.Fatalf("OLS() error = %v, want nil", err)
+	}
+
+	if math.Abs(reg.Coefficients[0]-1.05) > 1e-9 {
+		t.Errorf("OLS() intercept = %f; want 1.05", reg.Coefficients[0])
+	}
+
+	if math.Abs(reg.Coefficients[1]-1.99) > 1e-9 {
+		t.Errorf("OLS() slope = %f; want 1.99", reg.Coefficients[1])
+	}
+
+	if reg.DF != 3 {
+		t.Errorf("OLS() df = %d; want 3", reg.DF)
+	}
+
+	if reg.PValues[1] > 0.001 {
+		t.Errorf("OLS() slope p-value = %f; want < 0.001", reg.PValues[1])
+	}
+
+	_, err = OLS(y[:2], x[:2])
+	if err == nil {
+		t.Error("OLS() with too few observations error = nil, want error")
+	}
+}
+
+func TestInteractionTest(t *testing.T) {
+	var gains []float64
+	var cohorts, subgroups []int
+
+	// The intervention only helps subgroup 1
+	noise := []float64{-0.02, 0.01, 0.03, -0.01, 0.0, 0.02, -0.03, 0.01}
+	for c := 0; c < 2; c++ {
+		for s := 0; s < 2; s++ {
+			for _, e := range noise {
+				gain := 0.1 + e
+				if c == 1 && s == 1 {
+					gain += 0.3
+				}
+				gains = append(gains, gain)
+				cohorts = append(cohorts, c)
+				subgroups = append(subgroups, s)
+			}
+		}
+	}
+
+	interaction := InteractionTest(gains, cohorts, subgroups, 2, 2)
+	if interaction.Invalid {
+		t.Fatal("InteractionTest() invalid, want valid")
+	}
+
+	if interaction.Numer != 1 {
+		t.Errorf("InteractionTest() numerator df = %d; want 1", interaction.Numer)
+	}
+
+	if interaction.PValue > 0.001 {
+		t.Errorf("InteractionTest() p-value = %f; want < 0.001", interaction.PValue)
+	}
+}
@@ -39,110 +39,277 @@ func init() {
 }
 
 var messageKeyToIndex = map[string]int{
-	"\n### Introduction\nEduLab is designed to help educators incorporate scientific methods into their teaching strategies. This guide provides step-by-step instructions on using the platform to evaluate and refine your teaching methods with evidence-based insights.\n\n---\n\n### Step 1: Set Up an Experiment\n1. **Define Your Teaching Interventions**  \n   Identify the different teaching methods or approaches you want to compare (e.g., traditional lecture vs. interactive workshops).\n   \n2. **Create Cohorts**  \n   Use EduLab's cohort feature to group students who will experience specific teaching interventions. For example:\n   - **Control**: Traditional lecture method.\n   - **Intervention**: Interactive workshop approach.\n\n3. **Develop Assessments**  \n   Design a set of pre- and post-assessment questions to measure the effectiveness of each teaching method. Ensure these questions align with the learning objectives.\n\n---\n\n### Step 2: Conduct Pre-Assessment\n- Share the pre-assessment link with your cohorts before introducing any teaching intervention. \n- Encourage students to complete the assessment to establish a baseline for their knowledge.\n\n---\n\n### Step 3: Implement Your Teaching Interventions\n- Conduct your planned teaching methods for each cohort.\n- Ensure that the interventions are distinct and well-documented for accurate comparisons.\n\n---\n\n### Step 4: Conduct Post-Assessment\n- After completing the intervention, share the post-assessment link with the same cohorts.\n- Collect responses to measure the knowledge gained through each teaching method.\n\n---\n\n### Step 5: Analyze Results\n- Use EduLab's **Learning Gain Analysis** to compare pre- and post-assessment scores within and across cohorts. This allows you to:\n  - Identify which teaching method led to higher learning gains.\n  - Understand how different demographic groups responded to the interventions.\n  \n- Utilize the demographic data to tailor future teaching methods to meet the diverse needs of your students.\n\n---\n\n### Step 6: Iterate and Refine\n- Based on the results, refine your teaching strategies to optimize learning outcomes. Repeat the process to continually improve your methods.": 122,
-	"### 1. Purpose\n\nEduLab is a prototype platform designed for educational purposes only. It is not intended for commercial use. By using this platform, you agree to these Terms of Service.\n\n### 2. User-Generated Content\n\n* You retain ownership of any content you create or upload to EduLab.\n* EduLab does not claim ownership of user-generated content and acts solely as a tool to facilitate educational activities.\n* By using the platform, you grant EduLab the right to store and process your content as part of its educational functionality.\n\n### 3. Content Guidelines\n\n* You agree not to upload or create content that:\n  * Violates copyright, trademark, or other intellectual property rights.\n  * Contains offensive, harmful, or inappropriate material.\n  * Violates any applicable laws or regulations.\n* EduLab reserves the right to remove content that violates these guidelines without prior notice.\n\n### 4. Disclaimer of Liability\n\n* EduLab is provided \"as is,\" without warranties of any kind, expressed or implied.\n* EduLab is not responsible for the accuracy, reliability, or legality of user-generated content.\n* The platform is not moderated, and EduLab is not liable for any damages resulting from the use of the platform or the content hosted on it.\n\n### 5. No Accounts or Personal Data\n\n* EduLab does not require user accounts or collect personal data.\n* Any data submitted is stored temporarily and used solely for educational purposes.\n\n### 6. Indemnification\n\nBy using EduLab, you agree to indemnify and hold harmless the developers of EduLab from any claims or liabilities arising from your use of the platform or content you create.\n\n### 7. Updates to Terms\n\nThese Terms of Service may be updated periodically. Continued use of the platform constitutes agreement to the updated terms.":                                                                                                                                                                                                                                                                                                                                                                                              126,
-	"### How is data privacy ensured on EduLab?  \nEduLab anonymizes all student data, ensuring no personally identifiable information is stored or shared. The platform also complies with data protection standards.\n\n---\n\n### Can I customize the assessments?  \nYes, you can create and edit multiple-choice questions to align with your specific learning objectives.\n\n---\n\n### What types of demographic data can I collect?  \nEduLab allows you to collect data on gender, age group, year of study, and major, helping you understand how different factors influence learning outcomes.\n\n---\n\n### How do I interpret the learning gain analysis?  \nLearning gains are calculated as the difference between pre- and post-assessment scores, normalized to account for the initial baseline. Higher gains indicate more effective teaching methods.\n\n---\n\n### Is the platform open-source?  \nYes, EduLab provides access to its open-source code, allowing you to customize the platform to fit your needs.\n\n---\n\n### Can I use EduLab for non-science subjects?  \nAbsolutely! While EduLab is designed with science education in mind, its features are applicable across disciplines.":                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                     124,
-	"%d days ago":                       21,
-	"%d hours ago":                      20,
-	"%d mins ago":                       19,
-	"%s - %s":                           45,
-	"18 to 20":                          134,
-	"21 to 23":                          135,
-	"24 to 26":                          136,
-	"About":                             79,
-	"Actions":                           27,
-	"Add Assessment":                    28,
-	"Add Cohort":                        47,
-	"Add Demographic":                   58,
-	"Add Question":                      40,
-	"Age Group":                         132,
-	"Assessment":                        38,
-	"Assessments":                       16,
-	"Assessments Results":               105,
-	"Average Correct Answers by Cohort": 107,
-	"Back":                              44,
-	"Choices":                           88,
-	"Cohort: %s":                        55,
-	"Cohorts":                           17,
-	"Coming Soon":                       32,
-	"Coming soon":                       98,
-	"Control":                           64,
-	"Correct":                           95,
-	"Create":                            37,
-	"Created":                           68,
-	"Demographic":                       57,
-	"Demographics":                      56,
-	"Demographics Results":              101,
-	"Description":                       34,
-	"Earth & Environmental Sciences":    146,
-	"Edit":                              30,
-	"Edit Experiment":                   71,
-	"Edit Experiment: %s":               70,
-	"EduLab":                            78,
-	"EduLab - Empowering Educators":     112,
-	"EduLab brings **data-driven** experimentation into the classroom, empowering you to evaluate and refine teaching methods across distinct **cohorts**.\n\nBy running controlled pre- and post-assessments, you gain **evidence-based insights** into how different teaching approaches impact learning outcomes.\n\nCompare cohorts, **measure learning gains**, and adapt strategies to elevate student engagement—all supported by real-time educational data.": 114,
-	"Educator's Guide": 116,
-	"Empowering Educators Through Evidence-Based Insights": 113,
-	"Engineering":                148,
-	"Experiment %s":              73,
-	"Experiment: %s":             72,
-	"Experiments":                66,
-	"Export as CSV":              102,
-	"FAQ":                        80,
-	"Female":                     129,
-	"Frequently Asked Questions": 123,
-	"Gains Results":              106,
-	"Gender":                     127,
-	"Home":                       15,
-	"If you would like to contribute to the project, for example, adding more translations, get in touch:": 120,
-	"Internal Server Error":                99,
-	"Intervention":                         65,
-	"Learning Gain by Cohort (Post - Pre)": 108,
-	"Learning Gains":                       77,
-	"Less than one min ago":                18,
-	"Life Sciences":                        145,
-	"Male":                                 128,
-	"Markdown supported":                   86,
-	"Markdown supported. Empty choices will be ignored.": 89,
-	"Mathematics & Computer Science":                     147,
-	"Multiple Choice":                                    23,
-	"Name":                                               48,
-	"New Assessment":                                     33,
-	"New Cohort":                                         50,
-	"New Experiment":                                     61,
-	"New Question":                                       85,
-	"Next":                                               60,
-	"No assessments yet":                                 29,
-	"No available experiments":                           69,
-	"No cohorts found":                                   49,
-	"No comparison pairs available yet":                  111,
-	"No data available yet":                              104,
-	"No demographics have been added yet.":               59,
-	"No questions yet":                                   41,
-	"Non-binary":                                         130,
-	"Not visible to participants.":                       52,
-	"Optional. Markdown supported.":                      35,
-	"Optional. Not visible to participants.":             54,
-	"Options":                                            103,
-	"Other":                                              149,
-	"Page Not Found":                                     100,
-	"Participants":                                       67,
-	"Participation Links":                                75,
-	"Physical Sciences":                                  144,
-	"Post":                                               110,
-	"Post-Assessment":                                    13,
-	"Pre":                                                109,
-	"Pre-Assessment":                                     12,
-	"Prefer not to say":                                  131,
-	"Preview":                                            31,
-	"Preview Assessment":                                 42,
-	"Previous Experiments":                               117,
-	"Question":                                           97,
-	"Question: %s":                                       96,
-	"Questions":                                          26,
-	"Read our draft paper:":                              115,
-	"References":                                         118,
-	"Results":                                            76,
+	"\n### Introduction\nEduLab is designed to help educators incorporate scientific methods into their teaching strategies. This guide provides step-by-step instructions on using the platform to evaluate and refine your teaching methods with evidence-based insights.\n\n---\n\n### Step 1: Set Up an Experiment\n1. **Define Your Teaching Interventions**  \n   Identify the different teaching methods or approaches you want to compare (e.g., traditional lecture vs. interactive workshops).\n   \n2. **Create Cohorts**  \n   Use EduLab's cohort feature to group students who will experience specific teaching interventions. For example:\n   - **Control**: Traditional lecture method.\n   - **Intervention**: Interactive workshop approach.\n\n3. **Develop Assessments**  \n   Design a set of pre- and post-assessment questions to measure the effectiveness of each teaching method. Ensure these questions align with the learning objectives.\n\n---\n\n### Step 2: Conduct Pre-Assessment\n- Share the pre-assessment link with your cohorts before introducing any teaching intervention. \n- Encourage students to complete the assessment to establish a baseline for their knowledge.\n\n---\n\n### Step 3: Implement Your Teaching Interventions\n- Conduct your planned teaching methods for each cohort.\n- Ensure that the interventions are distinct and well-documented for accurate comparisons.\n\n---\n\n### Step 4: Conduct Post-Assessment\n- After completing the intervention, share the post-assessment link with the same cohorts.\n- Collect responses to measure the knowledge gained through each teaching method.\n\n---\n\n### Step 5: Analyze Results\n- Use EduLab's **Learning Gain Analysis** to compare pre- and post-assessment scores within and across cohorts. This allows you to:\n  - Identify which teaching method led to higher learning gains.\n  - Understand how different demographic groups responded to the interventions.\n  \n- Utilize the demographic data to tailor future teaching methods to meet the diverse needs of your students.\n\n---\n\n### Step 6: Iterate and Refine\n- Based on the results, refine your teaching strategies to optimize learning outcomes. Repeat the process to continually improve your methods.": 341,
+	"### 1. Purpose\n\nEduLab is a prototype platform designed for educational purposes only. It is not intended for commercial use. By using this platform, you agree to these Terms of Service.\n\n### 2. User-Generated Content\n\n* You retain ownership of any content you create or upload to EduLab.\n* EduLab does not claim ownership of user-generated content and acts solely as a tool to facilitate educational activities.\n* By using the platform, you grant EduLab the right to store and process your content as part of its educational functionality.\n\n### 3. Content Guidelines\n\n* You agree not to upload or create content that:\n  * Violates copyright, trademark, or other intellectual property rights.\n  * Contains offensive, harmful, or inappropriate material.\n  * Violates any applicable laws or regulations.\n* EduLab reserves the right to remove content that violates these guidelines without prior notice.\n\n### 4. Disclaimer of Liability\n\n* EduLab is provided \"as is,\" without warranties of any kind, expressed or implied.\n* EduLab is not responsible for the accuracy, reliability, or legality of user-generated content.\n* The platform is not moderated, and EduLab is not liable for any damages resulting from the use of the platform or the content hosted on it.\n\n### 5. No Accounts or Personal Data\n\n* EduLab does not require user accounts or collect personal data.\n* Any data submitted is stored temporarily and used solely for educational purposes.\n\n### 6. Indemnification\n\nBy using EduLab, you agree to indemnify and hold harmless the developers of EduLab from any claims or liabilities arising from your use of the platform or content you create.\n\n### 7. Updates to Terms\n\nThese Terms of Service may be updated periodically. Continued use of the platform constitutes agreement to the updated terms.":                                                                                                                                                                                                                                                                                                                                                                                              345,
+	"### How is data privacy ensured on EduLab?  \nEduLab anonymizes all student data, ensuring no personally identifiable information is stored or shared. The platform also complies with data protection standards.\n\n---\n\n### Can I customize the assessments?  \nYes, you can create and edit multiple-choice questions to align with your specific learning objectives.\n\n---\n\n### What types of demographic data can I collect?  \nEduLab allows you to collect data on gender, age group, year of study, and major, helping you understand how different factors influence learning outcomes.\n\n---\n\n### How do I interpret the learning gain analysis?  \nLearning gains are calculated as the difference between pre- and post-assessment scores, normalized to account for the initial baseline. Higher gains indicate more effective teaching methods.\n\n---\n\n### Is the platform open-source?  \nYes, EduLab provides access to its open-source code, allowing you to customize the platform to fit your needs.\n\n---\n\n### Can I use EduLab for non-science subjects?  \nAbsolutely! While EduLab is designed with science education in mind, its features are applicable across disciplines.":                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                     343,
+	"%.3f":                        289,
+	"%.3f ± %.3f (n = %d)":        328,
+	"%d (%v)":                     295,
+	"%d days ago":                 30,
+	"%d hours ago":                29,
+	"%d mins ago":                 28,
+	"%d questions were imported.": 69,
+	"%d responses can be imported, from %d new participants. %d responses replace an earlier import.": 227,
+	"%d values couldn't be matched, nothing was imported":                                             408,
+	"%s (%s)":   330,
+	"%s (copy)": 150,
+	"%s - %s":   94,
+	"%s closed the gap between subgroups by %.3f compared to %s.":                                  276,
+	"%s did not change the gap between subgroups compared to %s.":                                  278,
+	"%s is missing from the package":                                                               386,
+	"%s isn't supported":                                                                           392,
+	"%s questions aren't supported":                                                                381,
+	"%s stratifies the randomization and can't be deleted. Change the randomization first.":        130,
+	"%s stratifies the randomization and can't lose its options. Change the randomization first.":  126,
+	"%s stratifies the randomization and must stay single choice. Change the randomization first.": 125,
+	"%s to %s": 329,
+	"%s was answered by participants and can't be removed.":          123,
+	"%s was answered by participants and its text can't be changed.": 124,
+	"%s widened the gap between subgroups by %.3f compared to %s.":   277,
+	"%s%v":                       298,
+	"%s: %.3f (SD %.3f, n = %d)": 288,
+	"%v":                         297,
+	"%v probability that the intervention outperforms control (difference: %.3f, %v credible interval: %.3f to %.3f)": 264,
+	"0.2 is small, 0.5 is medium and 0.8 is large.":                                                                   174,
+	"18 to 20": 355,
+	"21 to 23": 356,
+	"24 to 26": 357,
+	"A QTI 2.1 package (.zip), Moodle XML (.xml) or GIFT (.gift, .txt) file exported from your learning management system. Single choice, multiple choice, true/false and open-ended questions are imported.": 62,
+	"A randomization link assigns each new participant to a cohort at random.\nParticipants who return for another assessment keep the cohort they were first assigned to.":                                   204,
+	"About":           165,
+	"Actions":         38,
+	"Add Arm":         35,
+	"Add Assessment":  55,
+	"Add Cohort":      96,
+	"Add Demographic": 115,
+	"Add Question":    77,
+	"Add a delayed post-assessment to measure retention.":                     327,
+	"Adjusted for multiple comparisons when there are more than two cohorts.": 176,
+	"After the %s":     104,
+	"Age Group":        353,
+	"All cohorts":      296,
+	"All participants": 259,
+	"Allocated at":     221,
+	"Allocations":      215,
+	"An experiment in the same format as the ones downloaded from an experiment page. You can review it before it is created.": 137,
+	"Another question of this assessment already has the link ID %q.":                                                          199,
+	"Arm":                           97,
+	"Arm effects compared to %s":    315,
+	"Arm x demographic interaction": 269,
+	"Arm: %s":                       51,
+	"Arms":                          20,
+	"Arms are the treatment conditions of the experiment. Results compare each arm against the control arm.": 34,
+	"Arms: %s":             241,
+	"Assessment":           74,
+	"Assessment started":   24,
+	"Assessment submitted": 25,
+	"Assessments":          18,
+	"Assessments Results":  243,
+	"At least two arms are needed to compare learning gains": 261,
+	"Attrition": 161,
+	"Attrition between pre- and post-assessment": 301,
+	"Attrition rate":                 306,
+	"Average Correct Answers by Arm": 248,
+	"Back":                           93,
+	"Back to the assessment":         236,
+	"Baseline Equivalence":           160,
+	"Bayesian estimate":              255,
+	"Block size":                     207,
+	"Blocks keep the cohorts balanced as participants join.": 206,
+	"Calculate":        180,
+	"Cancel":           140,
+	"Check":            235,
+	"Chi-square test":  285,
+	"Choices":          193,
+	"Clone Experiment": 148,
+	"Cohort":           218,
+	"Cohort: %s":       105,
+	"Cohorts":          19,
+	"Cohorts assigned to the same arm are analysed together. Participants are nested in their cohorts, so the arm effect is estimated with a random intercept per cohort.": 309,
+	"Cohorts in the same arm receive the same treatment, such as lab sections taught with the same method.":                                                                103,
+	"Column":                     231,
+	"Coming Soon":                79,
+	"Coming soon":                239,
+	"Compare control with":       260,
+	"Completion funnel":          299,
+	"Continue to the assessment": 73,
+	"Control":                    37,
+	"Control arm":                47,
+	"Copy all questions and choices into a new assessment. The copies are linked to these questions so they are compared in the results.":                                                        81,
+	"Copy the assessments, arms, cohorts, demographics and randomization settings into a new experiment, e.g. to run the same study next term. Participants and their responses are not copied.": 149,
+	"Correct":                195,
+	"Correct answers (post)": 256,
+	"Could not calculate the sample size for these values.": 183,
+	"Create":                  49,
+	"Created":                 144,
+	"Crossover":               106,
+	"Delayed Post-Assessment": 15,
+	"Delete":                  114,
+	"Demographic":             110,
+	"Demographics":            21,
+	"Demographics Results":    237,
+	"Demographics submitted":  23,
+	"Demographics with a p-value below 0.05 are not balanced across arms.": 287,
+	"Description": 44,
+	"Designate another arm as control to change it.":                           50,
+	"Difference between the highest and lowest scoring subgroups in each arm.": 273,
+	"Difference in gain":     311,
+	"Differential attrition": 307,
+	"Disabled":               200,
+	"Download YAML":          151,
+	"Download the questions to give this assessment inside a learning management system, such as Canvas or Moodle.": 85,
+	"Duplicate":    80,
+	"Duplicate as": 82,
+	"Each arm needs more than one cohort to estimate the variation between cohorts. The mixed model p-values are not available.": 319,
+	"Earth & Environmental Sciences": 367,
+	"Edit":                           39,
+	"Edit Experiment":                147,
+	"Edit Experiment: %s":            146,
+	"EduLab":                         164,
+	"EduLab - Empowering Educators":  331,
+	"EduLab brings **data-driven** experimentation into the classroom, empowering you to evaluate and refine teaching methods across distinct **cohorts**.\n\nBy running controlled pre- and post-assessments, you gain **evidence-based insights** into how different teaching approaches impact learning outcomes.\n\nCompare cohorts, **measure learning gains**, and adapt strategies to elevate student engagement—all supported by real-time educational data.": 333,
+	"Educator's Guide":        335,
+	"Effect size (Cohen's d)": 252,
+	"Empowering Educators Through Evidence-Based Insights": 332,
+	"Engineering": 369,
+	"Equity":      270,
+	"Equivalence": 284,
+	"Estimate how many participants each cohort needs before running the experiment.": 172,
+	"Expected effect size (Cohen's d)":                                                173,
+	"Experiment %s":                                                                   153,
+	"Experiment: %s":                                                                  152,
+	"Experiments":                                                                     142,
+	"Export":                                                                          84,
+	"Export as CSV":                                                                   238,
+	"F(%d, %d) = %.3f, p-value: %.4f":                                                 274,
+	"FAQ":                                                                             166,
+	"Female":                                                                          350,
+	"Filter":                                                                          258,
+	"First Row":                                                                       233,
+	"Fisher's exact test p-value: %.4f, Cramér's V: %.3f": 245,
+	"Frequently Asked Questions":                          342,
+	"Gain":                                                268,
+	"Gains Results":                                       247,
+	"Gains between consecutive timepoints, grouped by the arm each cohort was in during the period. Cohorts that cross over count towards a different arm in each period.": 323,
+	"Gains by Period": 322,
+	"Gap after":       272,
+	"Gap before":      271,
+	"Gender":          348,
+	"Hedges' g":       281,
+	"Home":            17,
+	"If you would like to contribute to the project, for example, adding more translations, get in touch:": 339,
+	"Ignore":                 224,
+	"Import":                 64,
+	"Import Assessment":      68,
+	"Import Experiment":      139,
+	"Import Responses":       88,
+	"Import a Question Bank": 61,
+	"In crossover designs, cohorts swap arms between assessments. Gains of each period are compared by the arm of the cohort during that period.": 107,
+	"Internal Server Error":              222,
+	"Intervention":                       141,
+	"Intraclass correlation (ICC): %.3f": 316,
+	"Item":                               71,
+	"Keep the same arm":                  108,
+	"Learning Gain by Arm (Post - Pre)":  249,
+	"Learning Gains":                     157,
+	"Learning gain":                      257,
+	"Leave empty to generate a new seed. Changing it only affects future allocations.": 212,
+	"Less than one min ago": 27,
+	"Life Sciences":         366,
+	"Line":                  230,
+	"Line %d: %s":           132,
+	"Link ID":               75,
+	"Link opened":           22,
+	"Lost":                  305,
+	"Male":                  349,
+	"Mann-Whitney U":        283,
+	"Maps To":               234,
+	"Markdown supported":    119,
+	"Markdown supported. Empty choices will be ignored.": 194,
+	"Mathematics & Computer Science":                     368,
+	"Mean gain":                                          310,
+	"Mean scores on the questions asked in more than one assessment, in the order of the timepoints.": 320,
+	"Method":                               205,
+	"Mid-Assessment":                       13,
+	"Minimum detectable effect (%v power)": 254,
+	"Mixed model":                          312,
+	"Moodle XML":                           87,
+	"Move down":                            113,
+	"Move up":                              112,
+	"Multiple Choice":                      32,
+	"Name":                                 36,
+	"New Arm":                              41,
+	"New Assessment":                       58,
+	"New Cohort":                           99,
+	"New Demographic":                      117,
+	"New Experiment":                       133,
+	"New Question":                         189,
+	"New arm named after the cohort":       102,
+	"Next":                                 131,
+	"No arms found":                        40,
+	"No assessments yet":                   56,
+	"No available experiments":             145,
+	"No cohorts found":                     98,
+	"No comparison pairs available yet":    262,
+	"No data available yet":                240,
+	"No demographics have been added yet.": 116,
+	"No participants have been allocated yet":  216,
+	"No questions yet":                         78,
+	"Non-binary":                               351,
+	"Not enough data":                          263,
+	"Not enough data to compare arms.":         314,
+	"Not enough data to test the interaction.": 275,
+	"Not satisfied":                            294,
+	"Not visible to participants.":             43,
+	"Number of cohorts":                        179,
+	"Number of participants who reached each step, relative to the step reached by most participants.": 300,
+	"Observed power":                         253,
+	"Optional. Markdown supported.":          59,
+	"Optional. Not visible to participants.": 46,
+	"Optional. Questions with the same link ID are compared across assessments, even if their texts differ. Without one, questions are matched by their exact text.": 192,
+	"Options": 111,
+	"Options are shown by their order. Empty options are removed, and text demographics have none.": 121,
+	"Order":                 122,
+	"Other":                 370,
+	"Overall attrition: %v": 308,
+	"Page Not Found":        223,
+	"Participant":           217,
+	"Participants":          143,
+	"Participants answer the demographics before being assigned to a cohort.":                                                                                   210,
+	"Participants answer the demographics before their first assessment. Answers already given to a deleted demographic or option are left out of the results.": 109,
+	"Participants per cohort: %d": 181,
+	"Participants see the question in the language they chose for the website. Leave a text empty to show it as written. Answers in every language are counted as the same question and choices.": 347,
+	"Participants who submitted the pre-assessment but not the post-assessment. Differential attrition between arms can bias the learning gains.":                                                 302,
+	"Participation Links":   155,
+	"Permuted blocks":       202,
+	"Physical Sciences":     365,
+	"Post":                  251,
+	"Post-Assessment":       14,
+	"Power":                 177,
+	"Pre":                   250,
+	"Pre vs post: %s":       242,
+	"Pre-Assessment":        12,
+	"Pre-assessment scores": 279,
+	"Prefer not to say":     352,
+	"Preview":               57,
+	"Preview Assessment":    91,
+	"Previous Experiments":  336,
+	"Probability of detecting the effect if it exists. 0.8 is the usual target.": 178,
+	"QTI 1.2 packages aren't supported, export the questions as QTI 2.1":         384,
+	"QTI 2.1 Package":     86,
+	"Question":            197,
+	"Question %d: %s":     226,
+	"Question: %s":        196,
+	"Questions":           54,
+	"Randomization":       162,
+	"Randomization Links": 213,
+	"Randomization is enabled. Share the randomization links so participants are assigned to a cohort at random.": 168,
+	"Read our draft paper:":           334,
+	"Reason":                          72,
+	"References":                      337,
+	"Requires statistical adjustment": 293,
+	"Responses collected elsewhere, such as on paper or in a learning management system, as a CSV file with a header row: a column identifying each student, a column with their cohort and a column per question. You can check how the columns and values match before importing.": 89,
+	"Results": 156,
 	"Results are marginally significant, but the small sample size limits reliability. Collect more data.":   1,
 	"Results are marginally significant, suggesting a possible effect. Further analysis recommended.":        10,
 	"Results are marginally significant. Consider increasing sample size for validation.":                    4,
@@ -153,91 +320,271 @@ var messageKeyToIndex = map[string]int{
 	"Results are statistically significant and supported by a large sample size, providing robust evidence.": 11,
 	"Results are statistically significant, but a larger sample size would strengthen confidence.":           5,
 	"Results are statistically significant, supported by an adequate sample size.":                           8,
-	"STEM Major": 143,
+	"Retention": 325,
+	"Retention gain is the delayed score minus the post-assessment score. Negative values show how much was forgotten.": 326,
+	"Rounded up to a multiple of the number of cohorts. Current size: %d":                                               208,
+	"SD":                               324,
+	"SE %.3f":                          317,
+	"SE %.3f, p-value: %.4f (df = %d)": 318,
+	"STEM Major":                       364,
+	"Sample Size Planner":              158,
 	"Sample size too small to draw reliable conclusions. More data is needed.": 0,
-	"Settings":      74,
-	"Single Choice": 22,
-	"Source Code":   121,
-	"Statistical significance reached, but the small sample size limits confidence. Validation with more data is recommended.": 2,
-	"Submit":                       43,
-	"Terms":                        81,
-	"Terms of Service":             125,
-	"Text":                         24,
-	"Thank you for participating!": 83,
-	"This project was created as part of the course, Physical Science in Contemporary Society, at the University of Toronto with the intention of being a free resource for educators.": 119,
-	"Type":                    25,
-	"Under 18":                133,
-	"Unknown Assessment Type": 14,
-	"Update":                  39,
-	"Warning: This assessment doesn't have any questions yet.\nPlease add questions before sharing the link with participants.": 82,
-	"Warning: This assessment doesn't have any questions yet.\nPlease contact your instructor for assistance.":                  46,
-	"Year 1":        138,
-	"Year 2":        139,
-	"Year 3":        140,
-	"Year 4":        141,
-	"Year 5+":       142,
-	"Year of Study": 137,
-	"Your participation has been successfully recorded.\n\nYou can now close this page.": 84,
-	"e.g. Cohort attending lecture-based instruction":                                    53,
-	"e.g. Control":         51,
-	"e.g. Earth's Seasons": 62,
-	"e.g. Gauge your current knowledge about the causes of Earth's...": 36,
-	"e.g. The Earth's elliptical orbit":                                92,
-	"e.g. The Earth's revolution":                                      94,
-	"e.g. The Earth's rotation":                                        93,
-	"e.g. The distance from the Sun":                                   91,
-	"e.g. The tilt of Earth's axis":                                    90,
-	"e.g. This experiment will compare 2 cohorts of students. One attending a traditional lecture and the other a workshop...": 63,
-	"e.g. What is the best explanation for the cause of Earth's seasons?":                                                      87,
+	"Satisfied": 292,
+	"Seed":      211,
+	"Select":    266,
+	"Sequence":  220,
+	"Settings":  154,
+	"Share the same link with every participant instead of one link per cohort.": 214,
+	"Shuffle the order of the choices":                                           83,
+	"Significance level (alpha)":                                                 175,
+	"Simple":                                                                     201,
+	"Single Choice":                                                              31,
+	"Single and multiple choice demographics need at least one option.": 128,
+	"Source Code": 340,
+	"Standardized differences (Hedges' g) up to 0.05 satisfy baseline equivalence, between 0.05 and 0.25 require a statistical adjustment and above 0.25 are not equivalent.": 280,
+	"Statistical significance reached, but the small sample size limits confidence. Validation with more data is recommended.":                                                2,
+	"Stratified permuted blocks":         203,
+	"Stratify by":                        209,
+	"Stratum":                            219,
+	"Student":                            225,
+	"Subgroup":                           267,
+	"Subgroup Results":                   265,
+	"Subgroups":                          159,
+	"Submit":                             92,
+	"Submitted post":                     304,
+	"Submitted pre":                      303,
+	"Terms":                              167,
+	"Terms of Service":                   344,
+	"Text":                               33,
+	"Thank you for participating!":       170,
+	"The demographic couldn't be saved:": 118,
+	"The demographic has an unknown type %q.":                                                 129,
+	"The demographic has no text.":                                                            127,
+	"The file couldn't be imported:":                                                          63,
+	"The other arms are compared against the control arm.":                                    48,
+	"The question couldn't be saved:":                                                         190,
+	"The question has an unknown type %q.":                                                    198,
+	"The responses couldn't be imported:":                                                     228,
+	"These items were left out, or changed to fit an assessment:":                             70,
+	"These values don't match and must be fixed in the file or the mapping before importing:": 229,
+	"This project was created as part of the course, Physical Science in Contemporary Society, at the University of Toronto with the intention of being a free resource for educators.": 338,
+	"Timepoints":                           163,
+	"Total participants: %d":               182,
+	"Trajectories":                         321,
+	"Translate":                            76,
+	"Translations":                         346,
+	"Treating participants as independent": 313,
+	"Type":                                 53,
+	"U = %.1f, p-value: %.4f":              291,
+	"Under 18":                             354,
+	"Unknown Assessment Type":              16,
+	"Unknown event":                        26,
+	"Update":                               52,
+	"Upload":                               90,
+	"Upload a YAML File":                   136,
+	"Value":                                232,
+	"Warning: This assessment doesn't have any questions yet.\nPlease add questions before sharing the link with participants.": 169,
+	"Warning: This assessment doesn't have any questions yet.\nPlease contact your instructor for assistance.":                  95,
+	"Welch's t-test": 282,
+	"Year 1":         359,
+	"Year 2":         360,
+	"Year 3":         361,
+	"Year 4":         362,
+	"Year 5+":        363,
+	"Year of Study":  358,
+	"Your participation has been successfully recorded.\n\nYou can now close this page.": 171,
+	"already responded on line %d":                                            416,
+	"answer %d gives %s of the credit, but counts as wrong":                   398,
+	"answer %d has an invalid fraction %q":                                    382,
+	"answer %d has no text":                                                   401,
+	"choice %s has no text":                                                   393,
+	"column %q is mapped to question %d, but the assessment has %d questions": 413,
+	"columns %q and %q are both mapped to question %d":                        414,
+	"could not read responses: %s":                                            405,
+	"could not read the item: %s":                                             388,
+	"doesn't match a choice":                                                  419,
+	"doesn't match a cohort":                                                  417,
+	"e.g. Cohort attending lecture-based instruction":                         101,
+	"e.g. Control":                                                            100,
+	"e.g. Earth's Seasons":                                                    134,
+	"e.g. Gauge your current knowledge about the causes of Earth's...":        60,
+	"e.g. Interactive workshop with peer instruction":                         45,
+	"e.g. Intervention A":                                                     42,
+	"e.g. The Earth's elliptical orbit":                                       186,
+	"e.g. The Earth's revolution":                                             188,
+	"e.g. The Earth's rotation":                                               187,
+	"e.g. The distance from the Sun":                                          185,
+	"e.g. The tilt of Earth's axis":                                           184,
+	"e.g. This experiment will compare 2 cohorts of students. One attending a traditional lecture and the other a workshop...": 135,
+	"e.g. What is the best explanation for the cause of Earth's seasons?":                                                      191,
+	"e.g. Which program are you enrolled in?":                                                                                  120,
+	"image %s is too large, so it was left out":                                                                                387,
+	"is missing": 415,
+	"it accepts %d answers as correct, but only one can be chosen": 403,
+	"it has %d interactions, only items with one can be imported":  391,
+	"it has no answers":                   400,
+	"it has no choices":                   394,
+	"it has no correct answer":            402,
+	"it has no correct response":          395,
+	"it has no interaction":               390,
+	"it has no text":                      399,
+	"it is not a QTI 2.1 item":            389,
+	"its answers aren't closed with }":    373,
+	"its answers don't start with = or ~": 377,
+	"its correct answers give different credit, but count the same":                 397,
+	"its feedback was left out":                                                     396,
+	"its title isn't closed with ::":                                                372,
+	"matching questions aren't supported":                                           375,
+	"no column identifies the cohorts, which is required with more than one cohort": 411,
+	"no column identifies the students":                                             410,
+	"no column is mapped to a question":                                             412,
+	"no file was uploaded":                                                          66,
+	"none of the items could be converted":                                          67,
+	"not enough data":                                                               244,
+	"numerical questions aren't supported":                                          374,
+	"short answer questions aren't supported":                                       376,
+	"t(%.1f) = %.3f, p-value: %.4f":                                                 290,
+	"the file has no %q column":                                                     409,
+	"the file has no GIFT questions":                                                371,
+	"the file has no Moodle questions":                                              380,
+	"the file has no responses":                                                     407,
+	"the file is empty":                                                             406,
+	"the file is larger than 1 MB":                                                  138,
+	"the file is larger than 10 MB":                                                 65,
+	"the package has no QTI 2.1 items":                                              385,
+	"the package has no imsmanifest.xml":                                            383,
+	"the weight of %q isn't a number":                                               379,
+	"the weight of %q isn't closed with %s":                                         378,
+	"unknown question bank format %q, use a QTI 2.1 package (.zip), Moodle XML (.xml) or GIFT (.gift, .txt)": 404,
+	"was imported in another cohort":                 418,
+	"χ²(%d) = %.3f, p-value: %.4f":                   286,
+	"χ²(%d) = %.3f, p-value: %.4f, Cramér's V: %.3f": 246,
 }
 
-var enIndex = []uint32{ // 151 elements
+var enIndex = []uint32{ // 421 elements
 	// Entry 0 - 1F
 	0x00000000, 0x00000049, 0x000000ae, 0x00000127,
 	0x00000179, 0x000001cd, 0x0000022a, 0x00000278,
 	0x000002c4, 0x00000311, 0x00000376, 0x000003d6,
-	0x0000043d, 0x0000044c, 0x0000045c, 0x00000474,
-	0x00000479, 0x00000485, 0x0000048d, 0x000004a3,
-	0x000004b2, 0x000004c2, 0x000004d1, 0x000004df,
-	0x000004ef, 0x000004f4, 0x000004f9, 0x00000503,
-	0x0000050b, 0x0000051a, 0x0000052d, 0x00000532,
+	0x0000043d, 0x0000044c, 0x0000045b, 0x0000046b,
+	0x00000483, 0x0000049b, 0x000004a0, 0x000004ac,
+	0x000004b4, 0x000004b9, 0x000004c6, 0x000004d2,
+	0x000004e9, 0x000004fc, 0x00000511, 0x0000051f,
+	0x00000535, 0x00000544, 0x00000554, 0x00000563,
 	// Entry 20 - 3F
-	0x0000053a, 0x00000546, 0x00000555, 0x00000561,
-	0x0000057f, 0x000005c0, 0x000005c7, 0x000005d2,
-	0x000005d9, 0x000005e6, 0x000005f7, 0x0000060a,
-	0x00000611, 0x00000616, 0x00000624, 0x0000068c,
-	0x00000697, 0x0000069c, 0x000006ad, 0x000006b8,
-	0x000006c5, 0x000006e2, 0x00000712, 0x00000739,
-	0x00000747, 0x00000754, 0x00000760, 0x00000770,
-	0x00000795, 0x0000079a, 0x000007a9, 0x000007be,
+	0x00000571, 0x00000581, 0x00000586, 0x000005ed,
+	0x000005f5, 0x000005fa, 0x00000602, 0x0000060a,
+	0x0000060f, 0x0000061d, 0x00000625, 0x00000639,
+	0x00000656, 0x00000662, 0x00000692, 0x000006b9,
+	0x000006c5, 0x000006fa, 0x00000701, 0x00000730,
+	0x0000073b, 0x00000742, 0x00000747, 0x00000751,
+	0x00000760, 0x00000773, 0x0000077b, 0x0000078a,
+	0x000007a8, 0x000007e9, 0x00000800, 0x000008c8,
 	// Entry 40 - 5F
-	0x00000837, 0x0000083f, 0x0000084c, 0x00000858,
-	0x00000865, 0x0000086d, 0x00000886, 0x0000089d,
-	0x000008ad, 0x000008bf, 0x000008d0, 0x000008d9,
-	0x000008ed, 0x000008f5, 0x00000904, 0x0000090b,
-	0x00000911, 0x00000915, 0x0000091b, 0x00000994,
-	0x000009b1, 0x00000a02, 0x00000a0f, 0x00000a22,
-	0x00000a66, 0x00000a6e, 0x00000aa1, 0x00000abf,
-	0x00000ade, 0x00000b00, 0x00000b1a, 0x00000b36,
+	0x000008e7, 0x000008ee, 0x0000090c, 0x00000921,
+	0x00000946, 0x00000958, 0x00000977, 0x000009b3,
+	0x000009b8, 0x000009bf, 0x000009da, 0x000009e5,
+	0x000009ed, 0x000009f7, 0x00000a04, 0x00000a15,
+	0x00000a21, 0x00000a2b, 0x00000aaf, 0x00000abc,
+	0x00000add, 0x00000ae4, 0x00000b52, 0x00000b62,
+	0x00000b6d, 0x00000b7e, 0x00000c8d, 0x00000c94,
+	0x00000ca7, 0x00000cae, 0x00000cb3, 0x00000cc1,
 	// Entry 60 - 7F
-	0x00000b3e, 0x00000b4e, 0x00000b57, 0x00000b63,
-	0x00000b79, 0x00000b88, 0x00000b9d, 0x00000bab,
-	0x00000bb3, 0x00000bc9, 0x00000bdd, 0x00000beb,
-	0x00000c0d, 0x00000c32, 0x00000c36, 0x00000c3b,
-	0x00000c5d, 0x00000c7b, 0x00000cb0, 0x00000e6e,
-	0x00000e84, 0x00000e95, 0x00000eaa, 0x00000eb5,
-	0x00000f67, 0x00000fcc, 0x00000fd8, 0x00001856,
-	0x00001871, 0x00001cec, 0x00001cfd, 0x00002404,
+	0x00000d29, 0x00000d34, 0x00000d38, 0x00000d49,
+	0x00000d54, 0x00000d61, 0x00000d91, 0x00000db0,
+	0x00000e16, 0x00000e26, 0x00000e34, 0x00000e3e,
+	0x00000eca, 0x00000edc, 0x00000f76, 0x00000f82,
+	0x00000f8a, 0x00000f92, 0x00000f9c, 0x00000fa3,
+	0x00000fb3, 0x00000fd8, 0x00000fe8, 0x0000100b,
+	0x0000101e, 0x00001046, 0x000010a4, 0x000010aa,
+	0x000010e3, 0x00001125, 0x00001185, 0x000011e4,
 	// Entry 80 - 9F
-	0x0000240b, 0x00002410, 0x00002417, 0x00002422,
-	0x00002434, 0x0000243e, 0x00002447, 0x00002450,
-	0x00002459, 0x00002462, 0x00002470, 0x00002477,
-	0x0000247e, 0x00002485, 0x0000248c, 0x00002494,
-	0x0000249f, 0x000024b1, 0x000024bf, 0x000024de,
-	0x000024fd, 0x00002509, 0x0000250f,
-} // Size: 628 bytes
+	0x00001201, 0x00001243, 0x0000126e, 0x000012c7,
+	0x000012cc, 0x000012de, 0x000012ed, 0x00001302,
+	0x0000137b, 0x0000138e, 0x00001407, 0x00001424,
+	0x00001436, 0x0000143d, 0x0000144a, 0x00001456,
+	0x00001463, 0x0000146b, 0x00001484, 0x0000149b,
+	0x000014ab, 0x000014bc, 0x00001577, 0x00001584,
+	0x00001592, 0x000015a4, 0x000015b5, 0x000015be,
+	0x000015d2, 0x000015da, 0x000015e9, 0x000015fd,
+	// Entry A0 - BF
+	0x00001607, 0x0000161c, 0x00001626, 0x00001634,
+	0x0000163f, 0x00001646, 0x0000164c, 0x00001650,
+	0x00001656, 0x000016c2, 0x0000173b, 0x00001758,
+	0x000017a9, 0x000017f9, 0x0000181a, 0x00001848,
+	0x00001863, 0x000018ab, 0x000018b1, 0x000018fc,
+	0x0000190e, 0x00001918, 0x00001937, 0x00001951,
+	0x00001987, 0x000019a5, 0x000019c4, 0x000019e6,
+	0x00001a00, 0x00001a1c, 0x00001a29, 0x00001a49,
+	// Entry C0 - DF
+	0x00001a8d, 0x00001b2c, 0x00001b34, 0x00001b67,
+	0x00001b6f, 0x00001b7f, 0x00001b88, 0x00001bb0,
+	0x00001bf3, 0x00001bfc, 0x00001c03, 0x00001c13,
+	0x00001c2e, 0x00001cd3, 0x00001cda, 0x00001d11,
+	0x00001d1c, 0x00001d63, 0x00001d6f, 0x00001db7,
+	0x00001dbc, 0x00001e0d, 0x00001e21, 0x00001e6c,
+	0x00001e78, 0x00001ea0, 0x00001eac, 0x00001eb3,
+	0x00001ebb, 0x00001ec4, 0x00001ed1, 0x00001ee7,
+	// Entry E0 - FF
+	0x00001ef6, 0x00001efd, 0x00001f05, 0x00001f1b,
+	0x00001f84, 0x00001fa8, 0x00002000, 0x00002005,
+	0x0000200c, 0x00002012, 0x0000201c, 0x00002024,
+	0x0000202a, 0x00002041, 0x00002056, 0x00002064,
+	0x00002070, 0x00002086, 0x00002092, 0x000020a5,
+	0x000020b9, 0x000020c9, 0x00002104, 0x00002142,
+	0x00002150, 0x0000216f, 0x00002191, 0x00002195,
+	0x0000219a, 0x000021b2, 0x000021c1, 0x000021e9,
+	// Entry 100 - 11F
+	0x000021fb, 0x00002212, 0x00002220, 0x00002227,
+	0x00002238, 0x0000224d, 0x00002284, 0x000022a6,
+	0x000022b6, 0x00002335, 0x00002346, 0x0000234d,
+	0x00002356, 0x0000235b, 0x00002379, 0x00002380,
+	0x0000238b, 0x00002395, 0x000023de, 0x0000240a,
+	0x00002433, 0x00002478, 0x000024be, 0x00002500,
+	0x00002516, 0x000025be, 0x000025c8, 0x000025d7,
+	0x000025e6, 0x000025f2, 0x00002602, 0x0000262a,
+	// Entry 120 - 13F
+	0x0000266f, 0x00002696, 0x0000269e, 0x000026c5,
+	0x000026e3, 0x000026ed, 0x0000270d, 0x0000271b,
+	0x00002729, 0x00002735, 0x0000273b, 0x00002746,
+	0x00002758, 0x000027b9, 0x000027e4, 0x00002870,
+	0x0000287e, 0x0000288d, 0x00002892, 0x000028a1,
+	0x000028b8, 0x000028d1, 0x00002976, 0x00002980,
+	0x00002993, 0x0000299f, 0x000029c4, 0x000029e5,
+	0x00002a03, 0x00002a29, 0x00002a34, 0x00002a5e,
+	// Entry 140 - 15F
+	0x00002ad9, 0x00002b39, 0x00002b46, 0x00002b56,
+	0x00002bfb, 0x00002bfe, 0x00002c08, 0x00002c7a,
+	0x00002cae, 0x00002ccd, 0x00002cdc, 0x00002cea,
+	0x00002d08, 0x00002d3d, 0x00002efb, 0x00002f11,
+	0x00002f22, 0x00002f37, 0x00002f42, 0x00002ff4,
+	0x00003059, 0x00003065, 0x000038e3, 0x000038fe,
+	0x00003d79, 0x00003d8a, 0x00004491, 0x0000449e,
+	0x0000455a, 0x00004561, 0x00004566, 0x0000456d,
+	// Entry 160 - 17F
+	0x00004578, 0x0000458a, 0x00004594, 0x0000459d,
+	0x000045a6, 0x000045af, 0x000045b8, 0x000045c6,
+	0x000045cd, 0x000045d4, 0x000045db, 0x000045e2,
+	0x000045ea, 0x000045f5, 0x00004607, 0x00004615,
+	0x00004634, 0x00004653, 0x0000465f, 0x00004665,
+	0x00004684, 0x000046a3, 0x000046c4, 0x000046e9,
+	0x0000470d, 0x00004735, 0x00004759, 0x00004785,
+	0x000047a8, 0x000047c9, 0x000047ea, 0x00004815,
+	// Entry 180 - 19F
+	0x00004838, 0x0000487b, 0x0000489c, 0x000048be,
+	0x000048eb, 0x0000490a, 0x00004923, 0x00004939,
+	0x00004978, 0x0000498e, 0x000049a7, 0x000049b9,
+	0x000049d4, 0x000049ee, 0x00004a2c, 0x00004a68,
+	0x00004a77, 0x00004a89, 0x00004aa2, 0x00004abb,
+	0x00004afb, 0x00004b65, 0x00004b85, 0x00004b97,
+	0x00004bb1, 0x00004be8, 0x00004c05, 0x00004c27,
+	0x00004c75, 0x00004c97, 0x00004ce8, 0x00004d22,
+	// Entry 1A0 - 1BF
+	0x00004d2d, 0x00004d4d, 0x00004d64, 0x00004d83,
+	0x00004d9a,
+} // Size: 1708 bytes
 
-const enData string = "" + // Size: 9487 bytes
+const enData string = "" + // Size: 19866 bytes
 	"\x02Sample size too small to draw reliable conclusions. More data is nee" +
 	"ded.\x02Results are marginally significant, but the small sample size li" +
 	"mits reliability. Collect more data.\x02Statistical significance reached" +
@@ -253,48 +600,173 @@ const enData string = "" + // Size: 9487 bytes
 	"ample size. Effect may be too small or non-existent.\x02Results are marg" +
 	"inally significant, suggesting a possible effect. Further analysis recom" +
 	"mended.\x02Results are statistically significant and supported by a larg" +
-	"e sample size, providing robust evidence.\x02Pre-Assessment\x02Post-Asse" +
-	"ssment\x02Unknown Assessment Type\x02Home\x02Assessments\x02Cohorts\x02L" +
-	"ess than one min ago\x02%[1]d mins ago\x02%[1]d hours ago\x02%[1]d days " +
-	"ago\x02Single Choice\x02Multiple Choice\x02Text\x02Type\x02Questions\x02" +
-	"Actions\x02Add Assessment\x02No assessments yet\x02Edit\x02Preview\x02Co" +
-	"ming Soon\x02New Assessment\x02Description\x02Optional. Markdown support" +
-	"ed.\x02e.g. Gauge your current knowledge about the causes of Earth's..." +
-	"\x02Create\x02Assessment\x02Update\x02Add Question\x02No questions yet" +
-	"\x02Preview Assessment\x02Submit\x02Back\x02%[1]s - %[2]s\x02Warning: Th" +
-	"is assessment doesn't have any questions yet.\x0aPlease contact your ins" +
-	"tructor for assistance.\x02Add Cohort\x02Name\x02No cohorts found\x02New" +
-	" Cohort\x02e.g. Control\x02Not visible to participants.\x02e.g. Cohort a" +
-	"ttending lecture-based instruction\x02Optional. Not visible to participa" +
-	"nts.\x02Cohort: %[1]s\x02Demographics\x02Demographic\x02Add Demographic" +
-	"\x02No demographics have been added yet.\x02Next\x02New Experiment\x02e." +
-	"g. Earth's Seasons\x02e.g. This experiment will compare 2 cohorts of stu" +
-	"dents. One attending a traditional lecture and the other a workshop..." +
-	"\x02Control\x02Intervention\x02Experiments\x02Participants\x02Created" +
-	"\x02No available experiments\x02Edit Experiment: %[1]s\x02Edit Experimen" +
-	"t\x02Experiment: %[1]s\x02Experiment %[1]s\x02Settings\x02Participation " +
-	"Links\x02Results\x02Learning Gains\x02EduLab\x02About\x02FAQ\x02Terms" +
-	"\x02Warning: This assessment doesn't have any questions yet.\x0aPlease a" +
-	"dd questions before sharing the link with participants.\x02Thank you for" +
-	" participating!\x02Your participation has been successfully recorded." +
-	"\x0a\x0aYou can now close this page.\x02New Question\x02Markdown support" +
-	"ed\x02e.g. What is the best explanation for the cause of Earth's seasons" +
-	"?\x02Choices\x02Markdown supported. Empty choices will be ignored.\x02e." +
-	"g. The tilt of Earth's axis\x02e.g. The distance from the Sun\x02e.g. Th" +
-	"e Earth's elliptical orbit\x02e.g. The Earth's rotation\x02e.g. The Eart" +
-	"h's revolution\x02Correct\x02Question: %[1]s\x02Question\x02Coming soon" +
-	"\x02Internal Server Error\x02Page Not Found\x02Demographics Results\x02E" +
-	"xport as CSV\x02Options\x02No data available yet\x02Assessments Results" +
-	"\x02Gains Results\x02Average Correct Answers by Cohort\x02Learning Gain " +
-	"by Cohort (Post - Pre)\x02Pre\x02Post\x02No comparison pairs available y" +
-	"et\x02EduLab - Empowering Educators\x02Empowering Educators Through Evid" +
-	"ence-Based Insights\x02EduLab brings **data-driven** experimentation int" +
-	"o the classroom, empowering you to evaluate and refine teaching methods " +
-	"across distinct **cohorts**.\x0a\x0aBy running controlled pre- and post-" +
-	"assessments, you gain **evidence-based insights** into how different tea" +
-	"ching approaches impact learning outcomes.\x0a\x0aCompare cohorts, **mea" +
-	"sure learning gains**, and adapt strategies to elevate student engagemen" +
-	"t—all supported by real-time educational data.\x02Read our draft paper:" +
+	"e sample size, providing robust evidence.\x02Pre-Assessment\x02Mid-Asses" +
+	"sment\x02Post-Assessment\x02Delayed Post-Assessment\x02Unknown Assessmen" +
+	"t Type\x02Home\x02Assessments\x02Cohorts\x02Arms\x02Demographics\x02Link" +
+	" opened\x02Demographics submitted\x02Assessment started\x02Assessment su" +
+	"bmitted\x02Unknown event\x02Less than one min ago\x02%[1]d mins ago\x02%" +
+	"[1]d hours ago\x02%[1]d days ago\x02Single Choice\x02Multiple Choice\x02" +
+	"Text\x02Arms are the treatment conditions of the experiment. Results com" +
+	"pare each arm against the control arm.\x02Add Arm\x02Name\x02Control\x02" +
+	"Actions\x02Edit\x02No arms found\x02New Arm\x02e.g. Intervention A\x02No" +
+	"t visible to participants.\x02Description\x02e.g. Interactive workshop w" +
+	"ith peer instruction\x02Optional. Not visible to participants.\x02Contro" +
+	"l arm\x02The other arms are compared against the control arm.\x02Create" +
+	"\x02Designate another arm as control to change it.\x02Arm: %[1]s\x02Upda" +
+	"te\x02Type\x02Questions\x02Add Assessment\x02No assessments yet\x02Previ" +
+	"ew\x02New Assessment\x02Optional. Markdown supported.\x02e.g. Gauge your" +
+	" current knowledge about the causes of Earth's...\x02Import a Question B" +
+	"ank\x02A QTI 2.1 package (.zip), Moodle XML (.xml) or GIFT (.gift, .txt)" +
+	" file exported from your learning management system. Single choice, mult" +
+	"iple choice, true/false and open-ended questions are imported.\x02The fi" +
+	"le couldn't be imported:\x02Import\x02the file is larger than 10 MB\x02n" +
+	"o file was uploaded\x02none of the items could be converted\x02Import As" +
+	"sessment\x02%[1]d questions were imported.\x02These items were left out," +
+	" or changed to fit an assessment:\x02Item\x02Reason\x02Continue to the a" +
+	"ssessment\x02Assessment\x02Link ID\x02Translate\x02Add Question\x02No qu" +
+	"estions yet\x02Coming Soon\x02Duplicate\x02Copy all questions and choice" +
+	"s into a new assessment. The copies are linked to these questions so the" +
+	"y are compared in the results.\x02Duplicate as\x02Shuffle the order of t" +
+	"he choices\x02Export\x02Download the questions to give this assessment i" +
+	"nside a learning management system, such as Canvas or Moodle.\x02QTI 2.1" +
+	" Package\x02Moodle XML\x02Import Responses\x02Responses collected elsewh" +
+	"ere, such as on paper or in a learning management system, as a CSV file " +
+	"with a header row: a column identifying each student, a column with thei" +
+	"r cohort and a column per question. You can check how the columns and va" +
+	"lues match before importing.\x02Upload\x02Preview Assessment\x02Submit" +
+	"\x02Back\x02%[1]s - %[2]s\x02Warning: This assessment doesn't have any q" +
+	"uestions yet.\x0aPlease contact your instructor for assistance.\x02Add C" +
+	"ohort\x02Arm\x02No cohorts found\x02New Cohort\x02e.g. Control\x02e.g. C" +
+	"ohort attending lecture-based instruction\x02New arm named after the coh" +
+	"ort\x02Cohorts in the same arm receive the same treatment, such as lab s" +
+	"ections taught with the same method.\x02After the %[1]s\x02Cohort: %[1]s" +
+	"\x02Crossover\x02In crossover designs, cohorts swap arms between assessm" +
+	"ents. Gains of each period are compared by the arm of the cohort during " +
+	"that period.\x02Keep the same arm\x02Participants answer the demographic" +
+	"s before their first assessment. Answers already given to a deleted demo" +
+	"graphic or option are left out of the results.\x02Demographic\x02Options" +
+	"\x02Move up\x02Move down\x02Delete\x02Add Demographic\x02No demographics" +
+	" have been added yet.\x02New Demographic\x02The demographic couldn't be " +
+	"saved:\x02Markdown supported\x02e.g. Which program are you enrolled in?" +
+	"\x02Options are shown by their order. Empty options are removed, and tex" +
+	"t demographics have none.\x02Order\x02%[1]s was answered by participants" +
+	" and can't be removed.\x02%[1]s was answered by participants and its tex" +
+	"t can't be changed.\x02%[1]s stratifies the randomization and must stay " +
+	"single choice. Change the randomization first.\x02%[1]s stratifies the r" +
+	"andomization and can't lose its options. Change the randomization first." +
+	"\x02The demographic has no text.\x02Single and multiple choice demograph" +
+	"ics need at least one option.\x02The demographic has an unknown type %[1" +
+	"]q.\x02%[1]s stratifies the randomization and can't be deleted. Change t" +
+	"he randomization first.\x02Next\x02Line %[1]d: %[2]s\x02New Experiment" +
+	"\x02e.g. Earth's Seasons\x02e.g. This experiment will compare 2 cohorts " +
+	"of students. One attending a traditional lecture and the other a worksho" +
+	"p...\x02Upload a YAML File\x02An experiment in the same format as the on" +
+	"es downloaded from an experiment page. You can review it before it is cr" +
+	"eated.\x02the file is larger than 1 MB\x02Import Experiment\x02Cancel" +
+	"\x02Intervention\x02Experiments\x02Participants\x02Created\x02No availab" +
+	"le experiments\x02Edit Experiment: %[1]s\x02Edit Experiment\x02Clone Exp" +
+	"eriment\x02Copy the assessments, arms, cohorts, demographics and randomi" +
+	"zation settings into a new experiment, e.g. to run the same study next t" +
+	"erm. Participants and their responses are not copied.\x02%[1]s (copy)" +
+	"\x02Download YAML\x02Experiment: %[1]s\x02Experiment %[1]s\x02Settings" +
+	"\x02Participation Links\x02Results\x02Learning Gains\x02Sample Size Plan" +
+	"ner\x02Subgroups\x02Baseline Equivalence\x02Attrition\x02Randomization" +
+	"\x02Timepoints\x02EduLab\x02About\x02FAQ\x02Terms\x02Randomization is en" +
+	"abled. Share the randomization links so participants are assigned to a c" +
+	"ohort at random.\x02Warning: This assessment doesn't have any questions " +
+	"yet.\x0aPlease add questions before sharing the link with participants." +
+	"\x02Thank you for participating!\x02Your participation has been successf" +
+	"ully recorded.\x0a\x0aYou can now close this page.\x02Estimate how many " +
+	"participants each cohort needs before running the experiment.\x02Expecte" +
+	"d effect size (Cohen's d)\x020.2 is small, 0.5 is medium and 0.8 is larg" +
+	"e.\x02Significance level (alpha)\x02Adjusted for multiple comparisons wh" +
+	"en there are more than two cohorts.\x02Power\x02Probability of detecting" +
+	" the effect if it exists. 0.8 is the usual target.\x02Number of cohorts" +
+	"\x02Calculate\x02Participants per cohort: %[1]d\x02Total participants: %" +
+	"[1]d\x02Could not calculate the sample size for these values.\x02e.g. Th" +
+	"e tilt of Earth's axis\x02e.g. The distance from the Sun\x02e.g. The Ear" +
+	"th's elliptical orbit\x02e.g. The Earth's rotation\x02e.g. The Earth's r" +
+	"evolution\x02New Question\x02The question couldn't be saved:\x02e.g. Wha" +
+	"t is the best explanation for the cause of Earth's seasons?\x02Optional." +
+	" Questions with the same link ID are compared across assessments, even i" +
+	"f their texts differ. Without one, questions are matched by their exact " +
+	"text.\x02Choices\x02Markdown supported. Empty choices will be ignored." +
+	"\x02Correct\x02Question: %[1]s\x02Question\x02The question has an unknow" +
+	"n type %[1]q.\x02Another question of this assessment already has the lin" +
+	"k ID %[1]q.\x02Disabled\x02Simple\x02Permuted blocks\x02Stratified permu" +
+	"ted blocks\x02A randomization link assigns each new participant to a coh" +
+	"ort at random.\x0aParticipants who return for another assessment keep th" +
+	"e cohort they were first assigned to.\x02Method\x02Blocks keep the cohor" +
+	"ts balanced as participants join.\x02Block size\x02Rounded up to a multi" +
+	"ple of the number of cohorts. Current size: %[1]d\x02Stratify by\x02Part" +
+	"icipants answer the demographics before being assigned to a cohort.\x02S" +
+	"eed\x02Leave empty to generate a new seed. Changing it only affects futu" +
+	"re allocations.\x02Randomization Links\x02Share the same link with every" +
+	" participant instead of one link per cohort.\x02Allocations\x02No partic" +
+	"ipants have been allocated yet\x02Participant\x02Cohort\x02Stratum\x02Se" +
+	"quence\x02Allocated at\x02Internal Server Error\x02Page Not Found\x02Ign" +
+	"ore\x02Student\x02Question %[1]d: %[2]s\x02%[1]d responses can be import" +
+	"ed, from %[2]d new participants. %[3]d responses replace an earlier impo" +
+	"rt.\x02The responses couldn't be imported:\x02These values don't match a" +
+	"nd must be fixed in the file or the mapping before importing:\x02Line" +
+	"\x02Column\x02Value\x02First Row\x02Maps To\x02Check\x02Back to the asse" +
+	"ssment\x02Demographics Results\x02Export as CSV\x02Coming soon\x02No dat" +
+	"a available yet\x02Arms: %[1]s\x02Pre vs post: %[1]s\x02Assessments Resu" +
+	"lts\x02not enough data\x02Fisher's exact test p-value: %.4[1]f, Cramér's" +
+	" V: %.3[2]f\x02χ²(%[1]d) = %.3[2]f, p-value: %.4[3]f, Cramér's V: %.3[4]" +
+	"f\x02Gains Results\x02Average Correct Answers by Arm\x02Learning Gain by" +
+	" Arm (Post - Pre)\x02Pre\x02Post\x02Effect size (Cohen's d)\x02Observed " +
+	"power\x02Minimum detectable effect (%[1]v power)\x02Bayesian estimate" +
+	"\x02Correct answers (post)\x02Learning gain\x02Filter\x02All participant" +
+	"s\x02Compare control with\x02At least two arms are needed to compare lea" +
+	"rning gains\x02No comparison pairs available yet\x02Not enough data\x02%" +
+	"[1]v probability that the intervention outperforms control (difference: " +
+	"%.3[2]f, %[3]v credible interval: %.3[4]f to %.3[5]f)\x02Subgroup Result" +
+	"s\x02Select\x02Subgroup\x02Gain\x02Arm x demographic interaction\x02Equi" +
+	"ty\x02Gap before\x02Gap after\x02Difference between the highest and lowe" +
+	"st scoring subgroups in each arm.\x02F(%[1]d, %[2]d) = %.3[3]f, p-value:" +
+	" %.4[4]f\x02Not enough data to test the interaction.\x02%[1]s closed the" +
+	" gap between subgroups by %.3[2]f compared to %[3]s.\x02%[1]s widened th" +
+	"e gap between subgroups by %.3[2]f compared to %[3]s.\x02%[1]s did not c" +
+	"hange the gap between subgroups compared to %[2]s.\x02Pre-assessment sco" +
+	"res\x02Standardized differences (Hedges' g) up to 0.05 satisfy baseline " +
+	"equivalence, between 0.05 and 0.25 require a statistical adjustment and " +
+	"above 0.25 are not equivalent.\x02Hedges' g\x02Welch's t-test\x02Mann-Wh" +
+	"itney U\x02Equivalence\x02Chi-square test\x02χ²(%[1]d) = %.3[2]f, p-valu" +
+	"e: %.4[3]f\x02Demographics with a p-value below 0.05 are not balanced ac" +
+	"ross arms.\x02%[1]s: %.3[2]f (SD %.3[3]f, n = %[4]d)\x02%.3[1]f\x02t(%.1" +
+	"[1]f) = %.3[2]f, p-value: %.4[3]f\x02U = %.1[1]f, p-value: %.4[2]f\x02Sa" +
+	"tisfied\x02Requires statistical adjustment\x02Not satisfied\x02%[1]d (%[" +
+	"2]v)\x02All cohorts\x02%[1]v\x02%[1]s%[2]v\x02Completion funnel\x02Numbe" +
+	"r of participants who reached each step, relative to the step reached by" +
+	" most participants.\x02Attrition between pre- and post-assessment\x02Par" +
+	"ticipants who submitted the pre-assessment but not the post-assessment. " +
+	"Differential attrition between arms can bias the learning gains.\x02Subm" +
+	"itted pre\x02Submitted post\x02Lost\x02Attrition rate\x02Differential at" +
+	"trition\x02Overall attrition: %[1]v\x02Cohorts assigned to the same arm " +
+	"are analysed together. Participants are nested in their cohorts, so the " +
+	"arm effect is estimated with a random intercept per cohort.\x02Mean gain" +
+	"\x02Difference in gain\x02Mixed model\x02Treating participants as indepe" +
+	"ndent\x02Not enough data to compare arms.\x02Arm effects compared to %[1" +
+	"]s\x02Intraclass correlation (ICC): %.3[1]f\x02SE %.3[1]f\x02SE %.3[1]f," +
+	" p-value: %.4[2]f (df = %[3]d)\x02Each arm needs more than one cohort to" +
+	" estimate the variation between cohorts. The mixed model p-values are no" +
+	"t available.\x02Mean scores on the questions asked in more than one asse" +
+	"ssment, in the order of the timepoints.\x02Trajectories\x02Gains by Peri" +
+	"od\x02Gains between consecutive timepoints, grouped by the arm each coho" +
+	"rt was in during the period. Cohorts that cross over count towards a dif" +
+	"ferent arm in each period.\x02SD\x02Retention\x02Retention gain is the d" +
+	"elayed score minus the post-assessment score. Negative values show how m" +
+	"uch was forgotten.\x02Add a delayed post-assessment to measure retention" +
+	".\x02%.3[1]f ± %.3[2]f (n = %[3]d)\x02%[1]s to %[2]s\x02%[1]s (%[2]s)" +
+	"\x02EduLab - Empowering Educators\x02Empowering Educators Through Eviden" +
+	"ce-Based Insights\x02EduLab brings **data-driven** experimentation into " +
+	"the classroom, empowering you to evaluate and refine teaching methods ac" +
+	"ross distinct **cohorts**.\x0a\x0aBy running controlled pre- and post-as" +
+	"sessments, you gain **evidence-based insights** into how different teach" +
+	"ing approaches impact learning outcomes.\x0a\x0aCompare cohorts, **measu" +
+	"re learning gains**, and adapt strategies to elevate student engagement—" +
+	"all supported by real-time educational data.\x02Read our draft paper:" +
 	"\x02Educator's Guide\x02Previous Experiments\x02References\x02This proje" +
 	"ct was created as part of the course, Physical Science in Contemporary S" +
 	"ociety, at the University of Toronto with the intention of being a free " +
@@ -376,60 +848,168 @@ const enData string = "" + // Size: 9487 bytes
 	"evelopers of EduLab from any claims or liabilities arising from your use" +
 	" of the platform or content you create.\x0a\x0a### 7. Updates to Terms" +
 	"\x0a\x0aThese Terms of Service may be updated periodically. Continued us" +
-	"e of the platform constitutes agreement to the updated terms.\x02Gender" +
-	"\x02Male\x02Female\x02Non-binary\x02Prefer not to say\x02Age Group\x02Un" +
-	"der 18\x0218 to 20\x0221 to 23\x0224 to 26\x02Year of Study\x02Year 1" +
-	"\x02Year 2\x02Year 3\x02Year 4\x02Year 5+\x02STEM Major\x02Physical Scie" +
-	"nces\x02Life Sciences\x02Earth & Environmental Sciences\x02Mathematics &" +
-	" Computer Science\x02Engineering\x02Other"
+	"e of the platform constitutes agreement to the updated terms.\x02Transla" +
+	"tions\x02Participants see the question in the language they chose for th" +
+	"e website. Leave a text empty to show it as written. Answers in every la" +
+	"nguage are counted as the same question and choices.\x02Gender\x02Male" +
+	"\x02Female\x02Non-binary\x02Prefer not to say\x02Age Group\x02Under 18" +
+	"\x0218 to 20\x0221 to 23\x0224 to 26\x02Year of Study\x02Year 1\x02Year " +
+	"2\x02Year 3\x02Year 4\x02Year 5+\x02STEM Major\x02Physical Sciences\x02L" +
+	"ife Sciences\x02Earth & Environmental Sciences\x02Mathematics & Computer" +
+	" Science\x02Engineering\x02Other\x02the file has no GIFT questions\x02it" +
+	"s title isn't closed with ::\x02its answers aren't closed with }\x02nume" +
+	"rical questions aren't supported\x02matching questions aren't supported" +
+	"\x02short answer questions aren't supported\x02its answers don't start w" +
+	"ith = or ~\x02the weight of %[1]q isn't closed with %[2]s\x02the weight " +
+	"of %[1]q isn't a number\x02the file has no Moodle questions\x02%[1]s que" +
+	"stions aren't supported\x02answer %[1]d has an invalid fraction %[2]q" +
+	"\x02the package has no imsmanifest.xml\x02QTI 1.2 packages aren't suppor" +
+	"ted, export the questions as QTI 2.1\x02the package has no QTI 2.1 items" +
+	"\x02%[1]s is missing from the package\x02image %[1]s is too large, so it" +
+	" was left out\x02could not read the item: %[1]s\x02it is not a QTI 2.1 i" +
+	"tem\x02it has no interaction\x02it has %[1]d interactions, only items wi" +
+	"th one can be imported\x02%[1]s isn't supported\x02choice %[1]s has no t" +
+	"ext\x02it has no choices\x02it has no correct response\x02its feedback w" +
+	"as left out\x02its correct answers give different credit, but count the " +
+	"same\x02answer %[1]d gives %[2]s of the credit, but counts as wrong\x02i" +
+	"t has no text\x02it has no answers\x02answer %[1]d has no text\x02it has" +
+	" no correct answer\x02it accepts %[1]d answers as correct, but only one " +
+	"can be chosen\x02unknown question bank format %[1]q, use a QTI 2.1 packa" +
+	"ge (.zip), Moodle XML (.xml) or GIFT (.gift, .txt)\x02could not read res" +
+	"ponses: %[1]s\x02the file is empty\x02the file has no responses\x02%[1]d" +
+	" values couldn't be matched, nothing was imported\x02the file has no %[1" +
+	"]q column\x02no column identifies the students\x02no column identifies t" +
+	"he cohorts, which is required with more than one cohort\x02no column is " +
+	"mapped to a question\x02column %[1]q is mapped to question %[2]d, but th" +
+	"e assessment has %[3]d questions\x02columns %[1]q and %[2]q are both map" +
+	"ped to question %[3]d\x02is missing\x02already responded on line %[1]d" +
+	"\x02doesn't match a cohort\x02was imported in another cohort\x02doesn't " +
+	"match a choice"
 
-var pt_BRIndex = []uint32{ // 151 elements
+var pt_BRIndex = []uint32{ // 421 elements
 	// Entry 0 - 1F
 	0x00000000, 0x00000063, 0x000000e1, 0x0000016c,
 	0x000001d6, 0x00000241, 0x000002ad, 0x0000030d,
 	0x00000372, 0x000003d5, 0x00000455, 0x000004c5,
-	0x00000548, 0x00000559, 0x0000056a, 0x0000058b,
-	0x00000593, 0x000005a0, 0x000005a8, 0x000005c2,
-	0x000005d4, 0x000005e4, 0x000005f3, 0x00000602,
-	0x00000614, 0x0000061a, 0x0000061f, 0x00000629,
-	0x00000631, 0x00000647, 0x00000661, 0x00000668,
+	0x00000548, 0x00000559, 0x00000574, 0x00000585,
+	0x0000059d, 0x000005be, 0x000005c6, 0x000005d3,
+	0x000005db, 0x000005e3, 0x000005ee, 0x000005fa,
+	0x0000060d, 0x00000622, 0x00000636, 0x0000064a,
+	0x00000664, 0x00000676, 0x00000686, 0x00000695,
 	// Entry 20 - 3F
-	0x00000673, 0x0000067c, 0x0000068d, 0x00000699,
-	0x000006b5, 0x00000703, 0x00000709, 0x00000715,
-	0x0000071f, 0x00000732, 0x00000749, 0x00000760,
-	0x00000767, 0x0000076e, 0x0000077c, 0x000007ef,
-	0x00000800, 0x00000805, 0x0000081f, 0x0000082b,
-	0x00000839, 0x0000085e, 0x0000089c, 0x000008cb,
-	0x000008d9, 0x000008e4, 0x000008f1, 0x00000906,
-	0x0000092f, 0x00000938, 0x00000949, 0x00000960,
+	0x000006a4, 0x000006b6, 0x000006bc, 0x00000733,
+	0x00000744, 0x00000749, 0x00000752, 0x0000075a,
+	0x00000761, 0x0000077a, 0x00000786, 0x0000079b,
+	0x000007c0, 0x000007cc, 0x000007fe, 0x0000082d,
+	0x00000840, 0x0000087c, 0x00000882, 0x000008b6,
+	0x000008c4, 0x000008ce, 0x000008d3, 0x000008dd,
+	0x000008f3, 0x0000090d, 0x00000918, 0x00000929,
+	0x00000945, 0x00000993, 0x000009b2, 0x00000a8b,
 	// Entry 40 - 5F
-	0x000009de, 0x000009e7, 0x000009f5, 0x00000a02,
-	0x00000a10, 0x00000a17, 0x00000a36, 0x00000a50,
-	0x00000a63, 0x00000a76, 0x00000a88, 0x00000a98,
-	0x00000ab0, 0x00000abb, 0x00000ad1, 0x00000ad8,
-	0x00000ade, 0x00000ae4, 0x00000aeb, 0x00000b66,
-	0x00000b7f, 0x00000bd5, 0x00000be3, 0x00000bf6,
-	0x00000c3f, 0x00000c48, 0x00000c7e, 0x00000ca3,
-	0x00000cbc, 0x00000cde, 0x00000cf8, 0x00000d14,
+	0x00000aaf, 0x00000ab8, 0x00000ad5, 0x00000af0,
+	0x00000b16, 0x00000b2b, 0x00000b4d, 0x00000b9d,
+	0x00000ba2, 0x00000ba9, 0x00000bc6, 0x00000bd2,
+	0x00000be1, 0x00000bea, 0x00000bfd, 0x00000c14,
+	0x00000c1d, 0x00000c26, 0x00000cb8, 0x00000cc6,
+	0x00000ce6, 0x00000cef, 0x00000d63, 0x00000d72,
+	0x00000d7d, 0x00000d90, 0x00000ecd, 0x00000ed4,
+	0x00000eeb, 0x00000ef2, 0x00000ef9, 0x00000f07,
 	// Entry 60 - 7F
-	0x00000d1c, 0x00000d2c, 0x00000d35, 0x00000d3e,
-	0x00000d57, 0x00000d6f, 0x00000d88, 0x00000d98,
-	0x00000da1, 0x00000dbf, 0x00000ddb, 0x00000df1,
-	0x00000e19, 0x00000e47, 0x00000e4c, 0x00000e51,
-	0x00000e7e, 0x00000e9e, 0x00000ede, 0x000010e9,
-	0x00001107, 0x00001118, 0x00001130, 0x0000113d,
-	0x000011f0, 0x0000125d, 0x0000126b, 0x00001bef,
-	0x00001c04, 0x0000217e, 0x00002191, 0x00002985,
+	0x00000f7a, 0x00000f8b, 0x00000f92, 0x00000fac,
+	0x00000fb8, 0x00000fc6, 0x00001004, 0x00001025,
+	0x00001094, 0x000010a2, 0x000010b0, 0x000010bb,
+	0x0000115e, 0x00001174, 0x0000121c, 0x00001229,
+	0x00001232, 0x00001242, 0x00001253, 0x0000125b,
+	0x00001270, 0x00001299, 0x000012a9, 0x000012cc,
+	0x000012df, 0x0000130b, 0x00001380, 0x00001386,
+	0x000013c7, 0x00001414, 0x00001486, 0x000014f0,
 	// Entry 80 - 9F
-	0x0000298d, 0x00002997, 0x000029a0, 0x000029ae,
-	0x000029c1, 0x000029cf, 0x000029e0, 0x000029ed,
-	0x000029fa, 0x00002a07, 0x00002a15, 0x00002a1b,
-	0x00002a21, 0x00002a27, 0x00002a2d, 0x00002a34,
-	0x00002a3f, 0x00002a52, 0x00002a68, 0x00002a88,
-	0x00002aaf, 0x00002aba, 0x00002ac0,
-} // Size: 628 bytes
+	0x0000150d, 0x0000155b, 0x00001588, 0x000015e8,
+	0x000015f1, 0x00001604, 0x00001615, 0x0000162c,
+	0x000016aa, 0x000016c1, 0x00001737, 0x00001753,
+	0x00001768, 0x00001771, 0x0000177f, 0x0000178c,
+	0x0000179a, 0x000017a1, 0x000017c0, 0x000017da,
+	0x000017ed, 0x00001800, 0x000018e9, 0x000018f8,
+	0x00001904, 0x00001917, 0x00001929, 0x00001939,
+	0x00001951, 0x0000195c, 0x00001972, 0x00001993,
+	// Entry A0 - BF
+	0x0000199d, 0x000019bc, 0x000019c4, 0x000019d3,
+	0x000019dc, 0x000019e3, 0x000019e9, 0x000019ef,
+	0x000019f6, 0x00001a85, 0x00001b00, 0x00001b19,
+	0x00001b6f, 0x00001bc1, 0x00001be9, 0x00001c18,
+	0x00001c38, 0x00001c80, 0x00001c86, 0x00001ccf,
+	0x00001ce2, 0x00001ceb, 0x00001d0b, 0x00001d29,
+	0x00001d6e, 0x00001d93, 0x00001dac, 0x00001dce,
+	0x00001de8, 0x00001e04, 0x00001e12, 0x00001e33,
+	// Entry C0 - DF
+	0x00001e7c, 0x00001f2d, 0x00001f36, 0x00001f6c,
+	0x00001f74, 0x00001f84, 0x00001f8d, 0x00001fb8,
+	0x00001ff9, 0x00002004, 0x0000200c, 0x0000201e,
+	0x0000203f, 0x000020fb, 0x00002103, 0x00002154,
+	0x00002165, 0x000021b9, 0x000021ca, 0x0000221b,
+	0x00002223, 0x0000227c, 0x00002294, 0x000022e6,
+	0x000022f2, 0x00002318, 0x00002325, 0x0000232c,
+	0x00002334, 0x0000233f, 0x0000234a, 0x00002363,
+	// Entry E0 - FF
+	0x0000237b, 0x00002383, 0x0000238d, 0x000023a3,
+	0x0000241d, 0x00002447, 0x000024b0, 0x000024b6,
+	0x000024bd, 0x000024c3, 0x000024d2, 0x000024e0,
+	0x000024e9, 0x00002503, 0x0000251c, 0x0000252c,
+	0x00002535, 0x00002553, 0x00002562, 0x00002576,
+	0x00002592, 0x000025a6, 0x000025e7, 0x00002626,
+	0x0000263c, 0x00002664, 0x00002692, 0x00002697,
+	0x0000269c, 0x000026bb, 0x000026cb, 0x000026f7,
+	// Entry 100 - 11F
+	0x0000270c, 0x00002726, 0x0000273b, 0x00002743,
+	0x0000275a, 0x00002772, 0x000027c3, 0x000027f0,
+	0x00002804, 0x0000288f, 0x000028a7, 0x000028b2,
+	0x000028bb, 0x000028c1, 0x000028e1, 0x000028ea,
+	0x000028fb, 0x0000290d, 0x0000295d, 0x00002989,
+	0x000029b8, 0x00002a0c, 0x00002a61, 0x00002aaf,
+	0x00002ad0, 0x00002b86, 0x00002b92, 0x00002ba3,
+	0x00002bb5, 0x00002bc3, 0x00002bd6, 0x00002bfe,
+	// Entry 120 - 13F
+	0x00002c50, 0x00002c77, 0x00002c7f, 0x00002ca6,
+	0x00002cc4, 0x00002ccf, 0x00002ce9, 0x00002cf9,
+	0x00002d07, 0x00002d18, 0x00002d1e, 0x00002d29,
+	0x00002d3d, 0x00002db3, 0x00002ddd, 0x00002e75,
+	0x00002e85, 0x00002e95, 0x00002e9e, 0x00002eae,
+	0x00002ec2, 0x00002ed7, 0x00002f9a, 0x00002fa7,
+	0x00002fbb, 0x00002fc8, 0x00002ff5, 0x00003023,
+	0x00003051, 0x00003079, 0x00003084, 0x000030ae,
+	// Entry 140 - 15F
+	0x00003138, 0x00003195, 0x000031a2, 0x000031b6,
+	0x0000326d, 0x00003270, 0x0000327b, 0x00003304,
+	0x00003342, 0x00003361, 0x0000336f, 0x0000337d,
+	0x0000339d, 0x000033dd, 0x000035e8, 0x00003606,
+	0x00003617, 0x0000362f, 0x0000363c, 0x000036ef,
+	0x0000375c, 0x0000376a, 0x000040ee, 0x00004103,
+	0x0000467d, 0x00004690, 0x00004e84, 0x00004e90,
+	0x00004f5d, 0x00004f65, 0x00004f6f, 0x00004f78,
+	// Entry 160 - 17F
+	0x00004f86, 0x00004f99, 0x00004fa7, 0x00004fb8,
+	0x00004fc5, 0x00004fd2, 0x00004fdf, 0x00004fed,
+	0x00004ff3, 0x00004ff9, 0x00004fff, 0x00005005,
+	0x0000500c, 0x00005017, 0x0000502a, 0x00005040,
+	0x00005060, 0x00005087, 0x00005092, 0x00005098,
+	0x000050ba, 0x000050df, 0x0000510a, 0x00005134,
+	0x00005163, 0x00005194, 0x000051bf, 0x000051e9,
+	0x0000520c, 0x00005233, 0x00005258, 0x0000528a,
+	// Entry 180 - 19F
+	0x000052ac, 0x000052f4, 0x00005314, 0x00005333,
+	0x0000536f, 0x00005394, 0x000053ac, 0x000053c1,
+	0x00005400, 0x00005418, 0x00005437, 0x00005449,
+	0x00005463, 0x00005486, 0x000054cd, 0x0000550b,
+	0x0000551a, 0x0000552d, 0x0000554d, 0x00005567,
+	0x000055ac, 0x00005624, 0x0000564f, 0x00005665,
+	0x00005682, 0x000056c4, 0x000056e6, 0x0000570a,
+	0x0000575d, 0x0000578b, 0x000057e3, 0x00005826,
+	// Entry 1A0 - 1BF
+	0x00005835, 0x00005852, 0x00005870, 0x0000588e,
+	0x000058ad,
+} // Size: 1708 bytes
 
-const pt_BRData string = "" + // Size: 10944 bytes
+const pt_BRData string = "" + // Size: 22701 bytes
 	"\x02Tamanho da amostra muito pequeno para tirar conclusões confiáveis. M" +
 	"ais dados são necessários.\x02Os resultados são marginalmente significat" +
 	"ivos, mas o tamanho da amostra pequeno limita a confiabilidade. Colete m" +
@@ -449,146 +1029,315 @@ const pt_BRData string = "" + // Size: 10944 bytes
 	"ignificativos, sugerindo um possível efeito. Análise adicional recomenda" +
 	"da.\x02Os resultados são estatisticamente significativos e suportados po" +
 	"r um tamanho de amostra grande, fornecendo evidências robustas.\x02Pré-a" +
-	"valiação\x02Pós-avaliação\x02Tipo de avaliação desconhecido\x02Início" +
-	"\x02Avaliações\x02Coortes\x02Menos de um minuto atrás\x02Há %[1]d minuto" +
-	"s\x02Há %[1]d horas\x02Há %[1]d dias\x02Escolha única\x02Escolha múltipl" +
-	"a\x02Texto\x02Tipo\x02Perguntas\x02Ações\x02Adicionar Avaliação\x02Nenhu" +
-	"ma avaliação ainda\x02Editar\x02Visualizar\x02Em breve\x02Nova Avaliação" +
-	"\x02Descrição\x02Opcional. Suporta Markdown.\x02Ex.: Avalie seu conhecim" +
-	"ento atual sobre as causas das estações da Terra...\x02Criar\x02Avaliaçã" +
-	"o\x02Atualizar\x02Adicionar Pergunta\x02Nenhuma pergunta ainda\x02Visual" +
-	"izar Avaliação\x02Enviar\x02Voltar\x02%[1]s - %[2]s\x02Aviso: Esta avali" +
-	"ação ainda não tem perguntas.\x0aPor favor, entre em contato com seu ins" +
-	"trutor para assistência.\x02Adicionar Coorte\x02Nome\x02Nenhuma coorte e" +
-	"ncontrada\x02Nova Coorte\x02Ex.: Controle\x02Não visível para os partici" +
-	"pantes.\x02Ex.: Coorte assistindo a uma instrução baseada em palestras" +
-	"\x02Opcional. Não visível para os participantes.\x02Coorte: %[1]s\x02Dem" +
-	"ografia\x02Demográfico\x02Adicionar Demografia\x02Nenhuma demografia foi" +
-	" adicionada ainda.\x02Próximo\x02Novo Experimento\x02Ex.: Estações do An" +
-	"o\x02Ex.: Este experimento irá comparar 2 coortes de estudantes. Uma ass" +
-	"istindo a uma aula tradicional e a outra a um workshop...\x02Controle" +
-	"\x02Intervenção\x02Experimentos\x02Participantes\x02Criado\x02Nenhum exp" +
-	"erimento disponível\x02Editar Experimento: %[1]s\x02Editar Experimento" +
-	"\x02Experimento: %[1]s\x02Experimento %[1]s\x02Configurações\x02Links de" +
-	" Participação\x02Resultados\x02Ganhos de Aprendizado\x02EduLab\x02Sobre" +
-	"\x02Ajuda\x02Termos\x02Aviso: Esta avaliação ainda não possui perguntas." +
-	"\x0aAdicione perguntas antes de compartilhar o link com os participantes" +
-	".\x02Obrigado por participar!\x02Sua participação foi registrada com suc" +
-	"esso.\x0a\x0aAgora você pode fechar esta página.\x02Nova Pergunta\x02Mar" +
-	"kdown suportado\x02Ex.: Qual é a melhor explicação para a causa das esta" +
-	"ções da Terra?\x02Opções\x02Markdown suportado. Opções vazias serão ign" +
-	"oradas.\x02Ex.: A inclinação do eixo da Terra\x02Ex.: A distância do Sol" +
-	"\x02Ex.: A órbita elíptica da Terra\x02Ex.: A rotação da Terra\x02Ex.: A" +
-	" revolução da Terra\x02Correto\x02Questão: %[1]s\x02Questão\x02Em breve" +
-	"\x02Erro Interno do Servidor\x02Página Não Encontrada\x02Resultados Demo" +
-	"gráficos\x02Exporte com CSV\x02Opções\x02Nenhum dado disponível ainda" +
-	"\x02Resultados das Avaliações\x02Resultados dos Ganhos\x02Média de Respo" +
-	"stas Corretas por Coorte\x02Ganho de Aprendizado por Coorte (Pós - Pré)" +
-	"\x02Pré\x02Pós\x02Nenhum par de comparação disponível ainda\x02EduLab - " +
-	"Capacitando Educadores\x02Capacitando Educadores com Perspectivas Basead" +
-	"as em Evidências\x02O EduLab traz experimentação **baseada em dados** pa" +
-	"ra a sala de aula, capacitando você a avaliar e refinar métodos de ensin" +
-	"o em diferentes **coortes**.\x0a\x0aAo realizar avaliações controladas a" +
-	"ntes e depois das aulas, você obtém **insights baseados em evidências** " +
-	"sobre como diferentes abordagens de ensino impactam os resultados de apr" +
-	"endizagem.\x0a\x0aCompare coortes, **meça ganhos de aprendizado** e adap" +
-	"te estratégias para aumentar o engajamento dos alunos—tudo com o suporte" +
-	" de dados educacionais em tempo real.\x02Leia nosso artigo preliminar:" +
-	"\x02Guia do Educador\x02Experimentos Anteriores\x02Referências\x02Este p" +
-	"rojeto foi criado como parte do curso Ciência Física na Sociedade Contem" +
-	"porânea, na Universidade de Toronto, com a intenção de ser um recurso gr" +
-	"atuito para educadores.\x02Se você gostaria de contribuir para o projeto" +
-	", por exemplo, adicionando mais traduções, entre em contato:\x02Código F" +
-	"onte\x04\x01\x0a\x00\xfe\x12\x02### Introdução\x0aO EduLab foi projetado" +
-	" para ajudar educadores a incorporar métodos científicos em suas estraté" +
-	"gias de ensino. Este guia fornece instruções passo a passo sobre como us" +
-	"ar a plataforma para avaliar e refinar seus métodos de ensino com insigh" +
-	"ts baseados em evidências.\x0a\x0a---\x0a\x0a### Etapa 1: Configurar um " +
-	"Experimento\x0a1. **Defina Suas Intervenções de Ensino**  \x0a   Identif" +
-	"ique os diferentes métodos ou abordagens de ensino que você deseja compa" +
-	"rar (ex.: aula tradicional vs. workshops interativos).\x0a\x0a2. **Crie " +
-	"Coortes**  \x0a   Use o recurso de coortes do EduLab para agrupar estuda" +
-	"ntes que experimentarão intervenções de ensino específicas. Por exemplo:" +
-	"\x0a   - **Controle**: Método de aula tradicional.\x0a   - **Intervenção" +
-	"**: Abordagem de workshop interativo.\x0a\x0a3. **Desenvolva Avaliações*" +
-	"*  \x0a   Projete um conjunto de perguntas de pré e pós-avaliação para m" +
-	"edir a eficácia de cada método de ensino. Certifique-se de que essas per" +
-	"guntas estejam alinhadas com os objetivos de aprendizagem.\x0a\x0a---" +
-	"\x0a\x0a### Etapa 2: Realizar a Pré-Avaliação\x0a- Compartilhe o link da" +
-	" pré-avaliação com suas coortes antes de introduzir qualquer intervenção" +
-	" de ensino. \x0a- Incentive os estudantes a completar a avaliação para e" +
-	"stabelecer uma linha de base de conhecimento.\x0a\x0a---\x0a\x0a### Etap" +
-	"a 3: Implemente Suas Intervenções de Ensino\x0a- Conduza os métodos de e" +
-	"nsino planejados para cada coorte.\x0a- Certifique-se de que as interven" +
-	"ções sejam distintas e bem documentadas para comparações precisas.\x0a" +
-	"\x0a---\x0a\x0a### Etapa 4: Realizar a Pós-Avaliação\x0a- Após concluir " +
-	"a intervenção, compartilhe o link da pós-avaliação com as mesmas coortes" +
-	".\x0a- Colete respostas para medir o conhecimento adquirido por meio de " +
-	"cada método de ensino.\x0a\x0a---\x0a\x0a### Etapa 5: Analisar os Result" +
-	"ados\x0a- Use a **Análise de Ganho de Aprendizado** do EduLab para compa" +
-	"rar os resultados das pré e pós-avaliações dentro e entre coortes. Isso " +
-	"permite que você:\x0a  - Identifique qual método de ensino gerou maiores" +
-	" ganhos de aprendizado.\x0a  - Compreenda como diferentes grupos demográ" +
-	"ficos responderam às intervenções.\x0a  \x0a- Utilize os dados demográfi" +
-	"cos para adaptar futuros métodos de ensino às diversas necessidades de s" +
-	"eus estudantes.\x0a\x0a---\x0a\x0a### Etapa 6: Iterar e Refinar\x0a- Com" +
-	" base nos resultados, refine suas estratégias de ensino para otimizar os" +
-	" resultados de aprendizagem. Repita o processo para melhorar continuamen" +
-	"te seus métodos.\x02Perguntas Frequentes\x02### Como a privacidade dos d" +
-	"ados é garantida no EduLab?  \x0aO EduLab anonimiza todos os dados dos e" +
-	"studantes, garantindo que nenhuma informação pessoalmente identificável " +
-	"seja armazenada ou compartilhada. A plataforma também está em conformida" +
-	"de com os padrões de proteção de dados.\x0a\x0a---\x0a\x0a### Posso pers" +
-	"onalizar as avaliações?  \x0aSim, você pode criar e editar perguntas de " +
-	"múltipla escolha para alinhá-las aos seus objetivos específicos de apren" +
-	"dizado.\x0a\x0a---\x0a\x0a### Que tipos de dados demográficos posso cole" +
-	"tar?  \x0aO EduLab permite a coleta de dados como gênero, faixa etária, " +
-	"ano de estudo e área de formação, ajudando você a entender como diferent" +
-	"es fatores influenciam os resultados de aprendizado.\x0a\x0a---\x0a\x0a#" +
-	"## Como interpreto a análise de ganho de aprendizado?  \x0aOs ganhos de " +
-	"aprendizado são calculados como a diferença entre as pontuações de pré e" +
-	" pós-avaliação, normalizados para levar em conta a linha de base inicial" +
-	". Ganhos mais altos indicam métodos de ensino mais eficazes.\x0a\x0a---" +
-	"\x0a\x0a### A plataforma é de código aberto?  \x0aSim, o EduLab oferece " +
-	"acesso ao seu código aberto, permitindo que você personalize a plataform" +
-	"a de acordo com suas necessidades.\x0a\x0a---\x0a\x0a### Posso usar o Ed" +
-	"uLab para disciplinas não relacionadas às ciências?  \x0aCom certeza! Em" +
-	"bora o EduLab seja projetado com foco na educação científica, seus recur" +
-	"sos são aplicáveis a outras disciplinas.\x02Termos de Serviço\x02### 1. " +
-	"Finalidade\x0a\x0aO EduLab é um protótipo desenvolvido exclusivamente pa" +
-	"ra fins educacionais. Ele não possui fins comerciais. Ao utilizar esta p" +
-	"lataforma, você concorda com estes Termos de Uso.\x0a\x0a### 2. Conteúdo" +
-	" Gerado pelo Usuário\x0a\x0a* Você mantém a propriedade de qualquer cont" +
-	"eúdo que criar ou enviar ao EduLab.\x0a\x0a* O EduLab não reivindica a p" +
-	"ropriedade do conteúdo gerado pelos usuários e atua apenas como uma ferr" +
-	"amenta para facilitar atividades educacionais.\x0a\x0a* Ao usar a plataf" +
-	"orma, você concede ao EduLab o direito de armazenar e processar seu cont" +
-	"eúdo como parte de suas funcionalidades educacionais.\x0a\x0a### 3. Dire" +
-	"trizes de Conteúdo\x0a\x0a* Você concorda em não enviar ou criar conteúd" +
-	"o que:\x0a\x0a* Viole direitos autorais, marcas registradas ou outros di" +
-	"reitos de propriedade intelectual.\x0a\x0a* Contenha material ofensivo, " +
-	"prejudicial ou inadequado.\x0a\x0a* Viole quaisquer leis ou regulamentos" +
-	" aplicáveis.\x0a\x0a* O EduLab reserva-se o direito de remover conteúdos" +
-	" que violem essas diretrizes sem aviso prévio.\x0a\x0a### 4. Isenção de " +
-	"Responsabilidade\x0a\x0a* O EduLab é fornecido \x22como está\x22, sem ga" +
-	"rantias de qualquer tipo, expressas ou implícitas.\x0a\x0a* O EduLab não" +
-	" se responsabiliza pela precisão, confiabilidade ou legalidade do conteú" +
-	"do gerado pelos usuários.\x0a\x0a* A plataforma não é moderada, e o EduL" +
-	"ab não se responsabiliza por quaisquer danos decorrentes do uso da plata" +
-	"forma ou do conteúdo hospedado nela.\x0a\x0a### 5. Sem Contas ou Dados P" +
-	"essoais\x0a\x0a* O EduLab não exige contas de usuário nem coleta dados p" +
-	"essoais.\x0a\x0a* Quaisquer dados enviados são armazenados temporariamen" +
-	"te e usados exclusivamente para fins educacionais.\x0a\x0a### 6. Indeniz" +
-	"ação\x0a\x0aAo usar o EduLab, você concorda em indenizar e isentar os de" +
-	"senvolvedores do EduLab de quaisquer reivindicações ou responsabilidades" +
-	" decorrentes do uso da plataforma ou do conteúdo que você criar.\x0a\x0a" +
-	"### 7. Atualizações nos Termos\x0a\x0aEstes Termos de Uso podem ser atua" +
-	"lizados periodicamente. O uso contínuo da plataforma constitui concordân" +
-	"cia com os termos atualizados.\x02Gênero\x02Masculino\x02Feminino\x02Não" +
-	" binário\x02Prefiro não dizer\x02Faixa Etária\x02Menor de 18 anos\x0218 " +
-	"a 20 anos\x0221 a 23 anos\x0224 a 26 anos\x02Ano de Estudo\x02Ano 1\x02A" +
-	"no 2\x02Ano 3\x02Ano 4\x02Ano 5+\x02Curso STEM\x02Ciências Físicas\x02Ci" +
-	"ências Biológicas\x02Ciências da Terra e Ambientais\x02Matemática e Ciê" +
-	"ncia da Computação\x02Engenharia\x02Outro"
+	"valiação\x02Avaliação Intermediária\x02Pós-avaliação\x02Pós-avaliação Ta" +
+	"rdia\x02Tipo de avaliação desconhecido\x02Início\x02Avaliações\x02Coorte" +
+	"s\x02Braços\x02Demografia\x02Link aberto\x02Demografia enviada\x02Avalia" +
+	"ção iniciada\x02Avaliação enviada\x02Evento desconhecido\x02Menos de um" +
+	" minuto atrás\x02Há %[1]d minutos\x02Há %[1]d horas\x02Há %[1]d dias\x02" +
+	"Escolha única\x02Escolha múltipla\x02Texto\x02Braços são as condições de" +
+	" tratamento do experimento. Os resultados comparam cada braço com o braç" +
+	"o de controle.\x02Adicionar Braço\x02Nome\x02Controle\x02Ações\x02Editar" +
+	"\x02Nenhum braço encontrado\x02Novo Braço\x02Ex.: Intervenção A\x02Não v" +
+	"isível para os participantes.\x02Descrição\x02Ex.: Oficina interativa co" +
+	"m instrução por pares\x02Opcional. Não visível para os participantes." +
+	"\x02Braço de controle\x02Os outros braços são comparados com o braço de " +
+	"controle.\x02Criar\x02Designe outro braço como controle para alterá-lo." +
+	"\x02Braço: %[1]s\x02Atualizar\x02Tipo\x02Perguntas\x02Adicionar Avaliaçã" +
+	"o\x02Nenhuma avaliação ainda\x02Visualizar\x02Nova Avaliação\x02Opcional" +
+	". Suporta Markdown.\x02Ex.: Avalie seu conhecimento atual sobre as causa" +
+	"s das estações da Terra...\x02Importar um Banco de Perguntas\x02Um pacot" +
+	"e QTI 2.1 (.zip), arquivo Moodle XML (.xml) ou GIFT (.gift, .txt) export" +
+	"ado do seu ambiente virtual de aprendizagem. Perguntas de escolha única," +
+	" escolha múltipla, verdadeiro/falso e abertas são importadas.\x02O arqui" +
+	"vo não pôde ser importado:\x02Importar\x02o arquivo é maior que 10 MB" +
+	"\x02nenhum arquivo foi enviado\x02nenhum dos itens pôde ser convertido" +
+	"\x02Importar Avaliação\x02%[1]d perguntas foram importadas.\x02Estes ite" +
+	"ns foram deixados de fora, ou alterados para caber em uma avaliação:\x02" +
+	"Item\x02Motivo\x02Continuar para a avaliação\x02Avaliação\x02ID de víncu" +
+	"lo\x02Traduzir\x02Adicionar Pergunta\x02Nenhuma pergunta ainda\x02Em bre" +
+	"ve\x02Duplicar\x02Copia todas as perguntas e opções para uma nova avalia" +
+	"ção. As cópias são vinculadas a estas perguntas para serem comparadas n" +
+	"os resultados.\x02Duplicar como\x02Embaralhar a ordem das opções\x02Expo" +
+	"rtar\x02Baixe as perguntas para aplicar esta avaliação em um ambiente vi" +
+	"rtual de aprendizagem, como o Canvas ou o Moodle.\x02Pacote QTI 2.1\x02M" +
+	"oodle XML\x02Importar Respostas\x02Respostas coletadas em outro lugar, c" +
+	"omo em papel ou em um ambiente virtual de aprendizagem, em um arquivo CS" +
+	"V com uma linha de cabeçalho: uma coluna identificando cada estudante, u" +
+	"ma coluna com a sua coorte e uma coluna por pergunta. Você pode conferir" +
+	" como as colunas e os valores correspondem antes de importar.\x02Enviar" +
+	"\x02Visualizar Avaliação\x02Enviar\x02Voltar\x02%[1]s - %[2]s\x02Aviso: " +
+	"Esta avaliação ainda não tem perguntas.\x0aPor favor, entre em contato c" +
+	"om seu instrutor para assistência.\x02Adicionar Coorte\x02Braço\x02Nenhu" +
+	"ma coorte encontrada\x02Nova Coorte\x02Ex.: Controle\x02Ex.: Coorte assi" +
+	"stindo a uma instrução baseada em palestras\x02Novo braço com o nome da " +
+	"coorte\x02Coortes no mesmo braço recebem o mesmo tratamento, como turmas" +
+	" de laboratório ensinadas com o mesmo método.\x02Após a %[1]s\x02Coorte:" +
+	" %[1]s\x02Cruzamento\x02Em desenhos cruzados, as coortes trocam de braço" +
+	" entre as avaliações. Os ganhos de cada período são comparados pelo braç" +
+	"o da coorte durante aquele período.\x02Manter o mesmo braço\x02Os partic" +
+	"ipantes respondem à demografia antes da primeira avaliação. Respostas já" +
+	" dadas a uma demografia ou opção excluída são deixadas de fora dos resul" +
+	"tados.\x02Demográfico\x02Opções\x02Mover para cima\x02Mover para baixo" +
+	"\x02Excluir\x02Adicionar Demografia\x02Nenhuma demografia foi adicionada" +
+	" ainda.\x02Nova Demografia\x02A demografia não pôde ser salva:\x02Markdo" +
+	"wn suportado\x02Ex.: Em qual curso você está matriculado?\x02As opções s" +
+	"ão exibidas pela sua ordem. Opções vazias são removidas, e demografias " +
+	"de texto não têm opções.\x02Ordem\x02%[1]s foi respondida por participan" +
+	"tes e não pode ser removida.\x02%[1]s foi respondida por participantes e" +
+	" o seu texto não pode ser alterado.\x02%[1]s estratifica a randomização " +
+	"e precisa continuar sendo de escolha única. Altere a randomização primei" +
+	"ro.\x02%[1]s estratifica a randomização e não pode perder as suas opções" +
+	". Altere a randomização primeiro.\x02A demografia não tem texto.\x02Demo" +
+	"grafias de escolha única e múltipla precisam de pelo menos uma opção." +
+	"\x02A demografia tem um tipo desconhecido %[1]q.\x02%[1]s estratifica a " +
+	"randomização e não pode ser excluída. Altere a randomização primeiro." +
+	"\x02Próximo\x02Linha %[1]d: %[2]s\x02Novo Experimento\x02Ex.: Estações d" +
+	"o Ano\x02Ex.: Este experimento irá comparar 2 coortes de estudantes. Uma" +
+	" assistindo a uma aula tradicional e a outra a um workshop...\x02Enviar " +
+	"um Arquivo YAML\x02Um experimento no mesmo formato dos baixados na págin" +
+	"a de um experimento. Você pode revisá-lo antes de ser criado.\x02o arqui" +
+	"vo é maior que 1 MB\x02Importar Experimento\x02Cancelar\x02Intervenção" +
+	"\x02Experimentos\x02Participantes\x02Criado\x02Nenhum experimento dispon" +
+	"ível\x02Editar Experimento: %[1]s\x02Editar Experimento\x02Clonar Exper" +
+	"imento\x02Copia as avaliações, braços, coortes, demografia e configuraçõ" +
+	"es de randomização para um novo experimento, por exemplo para repetir o " +
+	"mesmo estudo no próximo semestre. Os participantes e as suas respostas n" +
+	"ão são copiados.\x02%[1]s (cópia)\x02Baixar YAML\x02Experimento: %[1]s" +
+	"\x02Experimento %[1]s\x02Configurações\x02Links de Participação\x02Resul" +
+	"tados\x02Ganhos de Aprendizado\x02Planejador de Tamanho de Amostra\x02Su" +
+	"bgrupos\x02Equivalência de Linha de Base\x02Evasão\x02Randomização\x02Mo" +
+	"mentos\x02EduLab\x02Sobre\x02Ajuda\x02Termos\x02A randomização está ativ" +
+	"ada. Compartilhe os links de randomização para que os participantes seja" +
+	"m designados a uma coorte aleatoriamente.\x02Aviso: Esta avaliação ainda" +
+	" não possui perguntas.\x0aAdicione perguntas antes de compartilhar o lin" +
+	"k com os participantes.\x02Obrigado por participar!\x02Sua participação " +
+	"foi registrada com sucesso.\x0a\x0aAgora você pode fechar esta página." +
+	"\x02Estime quantos participantes cada coorte precisa antes de realizar o" +
+	" experimento.\x02Tamanho de efeito esperado (d de Cohen)\x020,2 é pequen" +
+	"o, 0,5 é médio e 0,8 é grande.\x02Nível de significância (alfa)\x02Ajust" +
+	"ado para comparações múltiplas quando há mais de duas coortes.\x02Poder" +
+	"\x02Probabilidade de detectar o efeito caso ele exista. 0,8 é o alvo usu" +
+	"al.\x02Número de coortes\x02Calcular\x02Participantes por coorte: %[1]d" +
+	"\x02Total de participantes: %[1]d\x02Não foi possível calcular o tamanho" +
+	" da amostra para estes valores.\x02Ex.: A inclinação do eixo da Terra" +
+	"\x02Ex.: A distância do Sol\x02Ex.: A órbita elíptica da Terra\x02Ex.: A" +
+	" rotação da Terra\x02Ex.: A revolução da Terra\x02Nova Pergunta\x02A per" +
+	"gunta não pôde ser salva:\x02Ex.: Qual é a melhor explicação para a caus" +
+	"a das estações da Terra?\x02Opcional. Perguntas com o mesmo ID de víncul" +
+	"o são comparadas entre avaliações, mesmo que os textos sejam diferentes." +
+	" Sem ele, as perguntas são associadas pelo texto exato.\x02Opções\x02Mar" +
+	"kdown suportado. Opções vazias serão ignoradas.\x02Correto\x02Questão: %" +
+	"[1]s\x02Questão\x02A pergunta tem um tipo desconhecido %[1]q.\x02Outra p" +
+	"ergunta desta avaliação já tem o ID de vínculo %[1]q.\x02Desativada\x02S" +
+	"imples\x02Blocos permutados\x02Blocos permutados estratificados\x02Um li" +
+	"nk de randomização designa cada novo participante a uma coorte aleatoria" +
+	"mente.\x0aParticipantes que retornam para outra avaliação mantêm a coort" +
+	"e à qual foram designados primeiro.\x02Método\x02Os blocos mantêm as coo" +
+	"rtes equilibradas à medida que os participantes entram.\x02Tamanho do bl" +
+	"oco\x02Arredondado para cima até um múltiplo do número de coortes. Taman" +
+	"ho atual: %[1]d\x02Estratificar por\x02Os participantes respondem à demo" +
+	"grafia antes de serem designados a uma coorte.\x02Semente\x02Deixe vazio" +
+	" para gerar uma nova semente. Alterá-la afeta apenas as alocações futura" +
+	"s.\x02Links de Randomização\x02Compartilhe o mesmo link com todos os par" +
+	"ticipantes em vez de um link por coorte.\x02Alocações\x02Nenhum particip" +
+	"ante foi alocado ainda\x02Participante\x02Coorte\x02Estrato\x02Sequência" +
+	"\x02Alocado em\x02Erro Interno do Servidor\x02Página Não Encontrada\x02I" +
+	"gnorar\x02Estudante\x02Pergunta %[1]d: %[2]s\x02%[1]d respostas podem se" +
+	"r importadas, de %[2]d novos participantes. %[3]d respostas substituem u" +
+	"ma importação anterior.\x02As respostas não puderam ser importadas:\x02E" +
+	"stes valores não correspondem e precisam ser corrigidos no arquivo ou no" +
+	" mapeamento antes de importar:\x02Linha\x02Coluna\x02Valor\x02Primeira L" +
+	"inha\x02Corresponde a\x02Conferir\x02Voltar para a avaliação\x02Resultad" +
+	"os Demográficos\x02Exporte com CSV\x02Em breve\x02Nenhum dado disponível" +
+	" ainda\x02Braços: %[1]s\x02Pré vs pós: %[1]s\x02Resultados das Avaliaçõe" +
+	"s\x02dados insuficientes\x02Valor-p do teste exato de Fisher: %.4[1]f, V" +
+	" de Cramér: %.3[2]f\x02χ²(%[1]d) = %.3[2]f, valor-p: %.4[3]f, V de Cramé" +
+	"r: %.3[4]f\x02Resultados dos Ganhos\x02Média de Respostas Corretas por B" +
+	"raço\x02Ganho de Aprendizado por Braço (Pós - Pré)\x02Pré\x02Pós\x02Tama" +
+	"nho de efeito (d de Cohen)\x02Poder observado\x02Efeito mínimo detectáve" +
+	"l (poder de %[1]v)\x02Estimativa bayesiana\x02Respostas corretas (pós)" +
+	"\x02Ganho de aprendizado\x02Filtrar\x02Todos os participantes\x02Compara" +
+	"r o controle com\x02São necessários pelo menos dois braços para comparar" +
+	" os ganhos de aprendizado\x02Nenhum par de comparação disponível ainda" +
+	"\x02Dados insuficientes\x02%[1]v de probabilidade de a intervenção super" +
+	"ar o controle (diferença: %.3[2]f, intervalo de credibilidade de %[3]v: " +
+	"%.3[4]f a %.3[5]f)\x02Resultados por Subgrupo\x02Selecionar\x02Subgrupo" +
+	"\x02Ganho\x02Interação braço x demografia\x02Equidade\x02Diferença antes" +
+	"\x02Diferença depois\x02Diferença entre os subgrupos com a maior e a men" +
+	"or pontuação em cada braço.\x02F(%[1]d, %[2]d) = %.3[3]f, valor-p: %.4[4" +
+	"]f\x02Dados insuficientes para testar a interação.\x02%[1]s reduziu a di" +
+	"ferença entre os subgrupos em %.3[2]f em comparação com %[3]s.\x02%[1]s " +
+	"aumentou a diferença entre os subgrupos em %.3[2]f em comparação com %[3" +
+	"]s.\x02%[1]s não alterou a diferença entre os subgrupos em comparação co" +
+	"m %[2]s.\x02Pontuações da pré-avaliação\x02Diferenças padronizadas (g de" +
+	" Hedges) até 0,05 satisfazem a equivalência de linha de base, entre 0,05" +
+	" e 0,25 exigem um ajuste estatístico e acima de 0,25 não são equivalente" +
+	"s.\x02g de Hedges\x02Teste t de Welch\x02U de Mann-Whitney\x02Equivalênc" +
+	"ia\x02Teste qui-quadrado\x02χ²(%[1]d) = %.3[2]f, valor-p: %.4[3]f\x02Dem" +
+	"ografias com valor-p abaixo de 0,05 não estão equilibradas entre os braç" +
+	"os.\x02%[1]s: %.3[2]f (DP %.3[3]f, n = %[4]d)\x02%.3[1]f\x02t(%.1[1]f) =" +
+	" %.3[2]f, valor-p: %.4[3]f\x02U = %.1[1]f, valor-p: %.4[2]f\x02Satisfeit" +
+	"a\x02Exige ajuste estatístico\x02Não satisfeita\x02%[1]d (%[2]v)\x02Toda" +
+	"s as coortes\x02%[1]v\x02%[1]s%[2]v\x02Funil de conclusão\x02Número de p" +
+	"articipantes que alcançaram cada etapa, em relação à etapa alcançada pel" +
+	"a maioria dos participantes.\x02Evasão entre a pré e a pós-avaliação\x02" +
+	"Participantes que enviaram a pré-avaliação, mas não a pós-avaliação. A e" +
+	"vasão diferencial entre braços pode enviesar os ganhos de aprendizado." +
+	"\x02Enviaram a pré\x02Enviaram a pós\x02Perdidos\x02Taxa de evasão\x02Ev" +
+	"asão diferencial\x02Evasão total: %[1]v\x02Coortes designadas ao mesmo b" +
+	"raço são analisadas em conjunto. Os participantes estão aninhados nas su" +
+	"as coortes, então o efeito do braço é estimado com um intercepto aleatór" +
+	"io por coorte.\x02Ganho médio\x02Diferença no ganho\x02Modelo misto\x02T" +
+	"ratando os participantes como independentes\x02Dados insuficientes para " +
+	"comparar os braços.\x02Efeitos dos braços em comparação com %[1]s\x02Cor" +
+	"relação intraclasse (ICC): %.3[1]f\x02EP %.3[1]f\x02EP %.3[1]f, valor-p:" +
+	" %.4[2]f (gl = %[3]d)\x02Cada braço precisa de mais de uma coorte para e" +
+	"stimar a variação entre coortes. Os valores-p do modelo misto não estão " +
+	"disponíveis.\x02Pontuações médias nas perguntas feitas em mais de uma av" +
+	"aliação, na ordem dos momentos.\x02Trajetórias\x02Ganhos por Período\x02" +
+	"Ganhos entre momentos consecutivos, agrupados pelo braço em que cada coo" +
+	"rte estava durante o período. Coortes que trocam de braço contam para um" +
+	" braço diferente em cada período.\x02DP\x02Retenção\x02O ganho de retenç" +
+	"ão é a pontuação tardia menos a pontuação da pós-avaliação. Valores neg" +
+	"ativos mostram o quanto foi esquecido.\x02Adicione uma pós-avaliação tar" +
+	"dia para medir a retenção.\x02%.3[1]f ± %.3[2]f (n = %[3]d)\x02%[1]s a %" +
+	"[2]s\x02%[1]s (%[2]s)\x02EduLab - Capacitando Educadores\x02Capacitando " +
+	"Educadores com Perspectivas Baseadas em Evidências\x02O EduLab traz expe" +
+	"rimentação **baseada em dados** para a sala de aula, capacitando você a " +
+	"avaliar e refinar métodos de ensino em diferentes **coortes**.\x0a\x0aAo" +
+	" realizar avaliações controladas antes e depois das aulas, você obtém **" +
+	"insights baseados em evidências** sobre como diferentes abordagens de en" +
+	"sino impactam os resultados de aprendizagem.\x0a\x0aCompare coortes, **m" +
+	"eça ganhos de aprendizado** e adapte estratégias para aumentar o engajam" +
+	"ento dos alunos—tudo com o suporte de dados educacionais em tempo real." +
+	"\x02Leia nosso artigo preliminar:\x02Guia do Educador\x02Experimentos An" +
+	"teriores\x02Referências\x02Este projeto foi criado como parte do curso C" +
+	"iência Física na Sociedade Contemporânea, na Universidade de Toronto, co" +
+	"m a intenção de ser um recurso gratuito para educadores.\x02Se você gost" +
+	"aria de contribuir para o projeto, por exemplo, adicionando mais traduçõ" +
+	"es, entre em contato:\x02Código Fonte\x04\x01\x0a\x00\xfe\x12\x02### Int" +
+	"rodução\x0aO EduLab foi projetado para ajudar educadores a incorporar mé" +
+	"todos científicos em suas estratégias de ensino. Este guia fornece instr" +
+	"uções passo a passo sobre como usar a plataforma para avaliar e refinar " +
+	"seus métodos de ensino com insights baseados em evidências.\x0a\x0a---" +
+	"\x0a\x0a### Etapa 1: Configurar um Experimento\x0a1. **Defina Suas Inter" +
+	"venções de Ensino**  \x0a   Identifique os diferentes métodos ou abordag" +
+	"ens de ensino que você deseja comparar (ex.: aula tradicional vs. worksh" +
+	"ops interativos).\x0a\x0a2. **Crie Coortes**  \x0a   Use o recurso de co" +
+	"ortes do EduLab para agrupar estudantes que experimentarão intervenções " +
+	"de ensino específicas. Por exemplo:\x0a   - **Controle**: Método de aula" +
+	" tradicional.\x0a   - **Intervenção**: Abordagem de workshop interativo." +
+	"\x0a\x0a3. **Desenvolva Avaliações**  \x0a   Projete um conjunto de perg" +
+	"untas de pré e pós-avaliação para medir a eficácia de cada método de ens" +
+	"ino. Certifique-se de que essas perguntas estejam alinhadas com os objet" +
+	"ivos de aprendizagem.\x0a\x0a---\x0a\x0a### Etapa 2: Realizar a Pré-Aval" +
+	"iação\x0a- Compartilhe o link da pré-avaliação com suas coortes antes de" +
+	" introduzir qualquer intervenção de ensino. \x0a- Incentive os estudante" +
+	"s a completar a avaliação para estabelecer uma linha de base de conhecim" +
+	"ento.\x0a\x0a---\x0a\x0a### Etapa 3: Implemente Suas Intervenções de Ens" +
+	"ino\x0a- Conduza os métodos de ensino planejados para cada coorte.\x0a- " +
+	"Certifique-se de que as intervenções sejam distintas e bem documentadas " +
+	"para comparações precisas.\x0a\x0a---\x0a\x0a### Etapa 4: Realizar a Pós" +
+	"-Avaliação\x0a- Após concluir a intervenção, compartilhe o link da pós-a" +
+	"valiação com as mesmas coortes.\x0a- Colete respostas para medir o conhe" +
+	"cimento adquirido por meio de cada método de ensino.\x0a\x0a---\x0a\x0a#" +
+	"## Etapa 5: Analisar os Resultados\x0a- Use a **Análise de Ganho de Apre" +
+	"ndizado** do EduLab para comparar os resultados das pré e pós-avaliações" +
+	" dentro e entre coortes. Isso permite que você:\x0a  - Identifique qual " +
+	"método de ensino gerou maiores ganhos de aprendizado.\x0a  - Compreenda " +
+	"como diferentes grupos demográficos responderam às intervenções.\x0a  " +
+	"\x0a- Utilize os dados demográficos para adaptar futuros métodos de ensi" +
+	"no às diversas necessidades de seus estudantes.\x0a\x0a---\x0a\x0a### Et" +
+	"apa 6: Iterar e Refinar\x0a- Com base nos resultados, refine suas estrat" +
+	"égias de ensino para otimizar os resultados de aprendizagem. Repita o p" +
+	"rocesso para melhorar continuamente seus métodos.\x02Perguntas Frequente" +
+	"s\x02### Como a privacidade dos dados é garantida no EduLab?  \x0aO EduL" +
+	"ab anonimiza todos os dados dos estudantes, garantindo que nenhuma infor" +
+	"mação pessoalmente identificável seja armazenada ou compartilhada. A pla" +
+	"taforma também está em conformidade com os padrões de proteção de dados." +
+	"\x0a\x0a---\x0a\x0a### Posso personalizar as avaliações?  \x0aSim, você " +
+	"pode criar e editar perguntas de múltipla escolha para alinhá-las aos se" +
+	"us objetivos específicos de aprendizado.\x0a\x0a---\x0a\x0a### Que tipos" +
+	" de dados demográficos posso coletar?  \x0aO EduLab permite a coleta de " +
+	"dados como gênero, faixa etária, ano de estudo e área de formação, ajuda" +
+	"ndo você a entender como diferentes fatores influenciam os resultados de" +
+	" aprendizado.\x0a\x0a---\x0a\x0a### Como interpreto a análise de ganho d" +
+	"e aprendizado?  \x0aOs ganhos de aprendizado são calculados como a difer" +
+	"ença entre as pontuações de pré e pós-avaliação, normalizados para levar" +
+	" em conta a linha de base inicial. Ganhos mais altos indicam métodos de " +
+	"ensino mais eficazes.\x0a\x0a---\x0a\x0a### A plataforma é de código abe" +
+	"rto?  \x0aSim, o EduLab oferece acesso ao seu código aberto, permitindo " +
+	"que você personalize a plataforma de acordo com suas necessidades.\x0a" +
+	"\x0a---\x0a\x0a### Posso usar o EduLab para disciplinas não relacionadas" +
+	" às ciências?  \x0aCom certeza! Embora o EduLab seja projetado com foco " +
+	"na educação científica, seus recursos são aplicáveis a outras disciplina" +
+	"s.\x02Termos de Serviço\x02### 1. Finalidade\x0a\x0aO EduLab é um protót" +
+	"ipo desenvolvido exclusivamente para fins educacionais. Ele não possui f" +
+	"ins comerciais. Ao utilizar esta plataforma, você concorda com estes Ter" +
+	"mos de Uso.\x0a\x0a### 2. Conteúdo Gerado pelo Usuário\x0a\x0a* Você man" +
+	"tém a propriedade de qualquer conteúdo que criar ou enviar ao EduLab." +
+	"\x0a\x0a* O EduLab não reivindica a propriedade do conteúdo gerado pelos" +
+	" usuários e atua apenas como uma ferramenta para facilitar atividades ed" +
+	"ucacionais.\x0a\x0a* Ao usar a plataforma, você concede ao EduLab o dire" +
+	"ito de armazenar e processar seu conteúdo como parte de suas funcionalid" +
+	"ades educacionais.\x0a\x0a### 3. Diretrizes de Conteúdo\x0a\x0a* Você co" +
+	"ncorda em não enviar ou criar conteúdo que:\x0a\x0a* Viole direitos auto" +
+	"rais, marcas registradas ou outros direitos de propriedade intelectual." +
+	"\x0a\x0a* Contenha material ofensivo, prejudicial ou inadequado.\x0a\x0a" +
+	"* Viole quaisquer leis ou regulamentos aplicáveis.\x0a\x0a* O EduLab res" +
+	"erva-se o direito de remover conteúdos que violem essas diretrizes sem a" +
+	"viso prévio.\x0a\x0a### 4. Isenção de Responsabilidade\x0a\x0a* O EduLab" +
+	" é fornecido \x22como está\x22, sem garantias de qualquer tipo, expressa" +
+	"s ou implícitas.\x0a\x0a* O EduLab não se responsabiliza pela precisão, " +
+	"confiabilidade ou legalidade do conteúdo gerado pelos usuários.\x0a\x0a*" +
+	" A plataforma não é moderada, e o EduLab não se responsabiliza por quais" +
+	"quer danos decorrentes do uso da plataforma ou do conteúdo hospedado nel" +
+	"a.\x0a\x0a### 5. Sem Contas ou Dados Pessoais\x0a\x0a* O EduLab não exig" +
+	"e contas de usuário nem coleta dados pessoais.\x0a\x0a* Quaisquer dados " +
+	"enviados são armazenados temporariamente e usados exclusivamente para fi" +
+	"ns educacionais.\x0a\x0a### 6. Indenização\x0a\x0aAo usar o EduLab, você" +
+	" concorda em indenizar e isentar os desenvolvedores do EduLab de quaisqu" +
+	"er reivindicações ou responsabilidades decorrentes do uso da plataforma " +
+	"ou do conteúdo que você criar.\x0a\x0a### 7. Atualizações nos Termos\x0a" +
+	"\x0aEstes Termos de Uso podem ser atualizados periodicamente. O uso cont" +
+	"ínuo da plataforma constitui concordância com os termos atualizados." +
+	"\x02Traduções\x02Os participantes veem a pergunta no idioma que escolher" +
+	"am para o site. Deixe um texto vazio para exibi-lo como foi escrito. Res" +
+	"postas em todos os idiomas contam como a mesma pergunta e as mesmas opçõ" +
+	"es.\x02Gênero\x02Masculino\x02Feminino\x02Não binário\x02Prefiro não diz" +
+	"er\x02Faixa Etária\x02Menor de 18 anos\x0218 a 20 anos\x0221 a 23 anos" +
+	"\x0224 a 26 anos\x02Ano de Estudo\x02Ano 1\x02Ano 2\x02Ano 3\x02Ano 4" +
+	"\x02Ano 5+\x02Curso STEM\x02Ciências Físicas\x02Ciências Biológicas\x02C" +
+	"iências da Terra e Ambientais\x02Matemática e Ciência da Computação\x02E" +
+	"ngenharia\x02Outro\x02o arquivo não tem perguntas GIFT\x02o seu título n" +
+	"ão é fechado com ::\x02as suas respostas não são fechadas com }\x02perg" +
+	"untas numéricas não são suportadas\x02perguntas de associação não são su" +
+	"portadas\x02perguntas de resposta curta não são suportadas\x02as suas re" +
+	"spostas não começam com = ou ~\x02o peso de %[1]q não é fechado com %[2]" +
+	"s\x02o peso de %[1]q não é um número\x02o arquivo não tem perguntas do M" +
+	"oodle\x02perguntas %[1]s não são suportadas\x02a resposta %[1]d tem uma " +
+	"fração inválida %[2]q\x02o pacote não tem imsmanifest.xml\x02pacotes QTI" +
+	" 1.2 não são suportados, exporte as perguntas como QTI 2.1\x02o pacote n" +
+	"ão tem itens QTI 2.1\x02%[1]s está faltando no pacote\x02a imagem %[1]s" +
+	" é grande demais, então foi deixada de fora\x02não foi possível ler o it" +
+	"em: %[1]s\x02não é um item QTI 2.1\x02não tem interação\x02tem %[1]d int" +
+	"erações, só itens com uma podem ser importados\x02%[1]s não é suportado" +
+	"\x02a opção %[1]s não tem texto\x02não tem opções\x02não tem resposta co" +
+	"rreta\x02o seu feedback foi deixado de fora\x02as suas respostas correta" +
+	"s dão créditos diferentes, mas contam igual\x02a resposta %[1]d dá %[2]s" +
+	" do crédito, mas conta como errada\x02não tem texto\x02não tem respostas" +
+	"\x02a resposta %[1]d não tem texto\x02não tem resposta correta\x02aceita" +
+	" %[1]d respostas como corretas, mas só uma pode ser escolhida\x02formato" +
+	" de banco de perguntas desconhecido %[1]q, use um pacote QTI 2.1 (.zip)," +
+	" Moodle XML (.xml) ou GIFT (.gift, .txt)\x02não foi possível ler as resp" +
+	"ostas: %[1]s\x02o arquivo está vazio\x02o arquivo não tem respostas\x02%" +
+	"[1]d valores não puderam ser correspondidos, nada foi importado\x02o arq" +
+	"uivo não tem a coluna %[1]q\x02nenhuma coluna identifica os alunos\x02ne" +
+	"nhuma coluna identifica as coortes, o que é obrigatório com mais de uma " +
+	"coorte\x02nenhuma coluna está associada a uma pergunta\x02a coluna %[1]q" +
+	" está associada à pergunta %[2]d, mas a avaliação tem %[3]d perguntas" +
+	"\x02as colunas %[1]q e %[2]q estão ambas associadas à pergunta %[3]d\x02" +
+	"está faltando\x02já respondeu na linha %[1]d\x02não corresponde a uma co" +
+	"orte\x02foi importado em outra coorte\x02não corresponde a uma opção"
 
-	// Total table size 21687 bytes (21KiB); checksum: 60D2D4BA
+	// Total table size 45983 bytes (44KiB); checksum: 731C8B99
@@ -31,16 +31,21 @@ func AssessmentType(printer *message.Printer, t edulab.AssessmentType) string {
 	switch t {
 	case edulab.AssessmentTypePre:
 		return printer.Sprintf("Pre-Assessment")
+	case edulab.AssessmentTypeMid:
+		return printer.Sprintf("Mid-Assessment")
 	case edulab.AssessmentTypePost:
 		return printer.Sprintf("Post-Assessment")
+	case edulab.AssessmentTypeDelayed:
+		return printer.Sprintf("Delayed Post-Assessment")
 	default:
 		return printer.Sprintf("Unknown Assessment Type")
 	}
 }
 
 func AssessmentTypes(printer *message.Printer) [][]string {
-	return [][]string{
-		{string(edulab.AssessmentTypePre), AssessmentType(printer, edulab.AssessmentTypePre)},
-		{string(edulab.AssessmentTypePost), AssessmentType(printer, edulab.AssessmentTypePost)},
+	types := make([][]string, len(edulab.AssessmentTypes))
+	for i, t := range edulab.AssessmentTypes {
+		types[i] = []string{string(t), AssessmentType(printer, t)}
 	}
+	return types
 }
@@ -123,11 +123,21 @@ func TestAssessmentType(t *testing.T) {
 			input:    edulab.AssessmentTypePre,
 			expected: "Pre-Assessment",
 		},
+		{
+			name:     "Mid-Assessment",
+			input:    edulab.AssessmentTypeMid,
+			expected: "Mid-Assessment",
+		},
 		{
 			name:     "Post-Assessment",
 			input:    edulab.AssessmentTypePost,
 			expected: "Post-Assessment",
 		},
+		{
+			name:     "Delayed Post-Assessment",
+			input:    edulab.AssessmentTypeDelayed,
+			expected: "Delayed Post-Assessment",
+		},
 		{
 			name:     "Unknown Assessment Type",
 			input:    edulab.AssessmentType("Unknown"),
@@ -149,7 +159,9 @@ func TestAssessmentTypes(t *testing.T) {
 
 	expected := [][]string{
 		{string(edulab.AssessmentTypePre), "Pre-Assessment"},
+		{string(edulab.AssessmentTypeMid), "Mid-Assessment"},
 		{string(edulab.AssessmentTypePost), "Post-Assessment"},
+		{string(edulab.AssessmentTypeDelayed), "Delayed Post-Assessment"},
 	}
 
 	actual := AssessmentTypes(printer)
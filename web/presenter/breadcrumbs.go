@@ -71,6 +71,22 @@ func CohortBreadcrumb(e edulab.Experiment, printer *message.Printer) template.HT
 	})
 }
 
+func ArmBreadcrumb(e edulab.Experiment, printer *message.Printer) template.HTML {
+	return renderBreadcrumbs([]Breadcrumb{
+		{URL: "/", Name: printer.Sprintf("Home")},
+		{URL: fmt.Sprintf("/experiments/%s", e.PublicID), Name: e.Name},
+		{URL: fmt.Sprintf("/experiments/%s/arms", e.PublicID), Name: printer.Sprintf("Arms")},
+	})
+}
+
+func DemographicBreadcrumb(e edulab.Experiment, printer *message.Printer) template.HTML {
+	return renderBreadcrumbs([]Breadcrumb{
+		{URL: "/", Name: printer.Sprintf("Home")},
+		{URL: fmt.Sprintf("/experiments/%s", e.PublicID), Name: e.Name},
+		{URL: fmt.Sprintf("/experiments/%s/demographics", e.PublicID), Name: printer.Sprintf("Demographics")},
+	})
+}
+
 func renderBreadcrumbs(breadcrumbs []Breadcrumb) template.HTML {
 	var sb strings.Builder
 	sb.WriteString(`<nav class="breadcrumb">`)
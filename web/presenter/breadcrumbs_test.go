@@ -58,3 +58,23 @@ func TestCohortBreadcrumb(t *testing.T) {
 		t.Errorf("expected %s, got %s", expected, result)
 	}
 }
+
+func TestArmBreadcrumb(t *testing.T) {
+	printer := message.NewPrinter(language.English)
+	experiment := edulab.Experiment{Name: "Test Experiment", PublicID: "123"}
+	expected := `<nav class="breadcrumb"><a href="/">Home</a> &rsaquo; <a href="/experiments/123">Test Experiment</a> &rsaquo; <a href="/experiments/123/arms">Arms</a></nav>`
+	result := ArmBreadcrumb(experiment, printer)
+	if template.HTML(expected) != result {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestDemographicBreadcrumb(t *testing.T) {
+	printer := message.NewPrinter(language.English)
+	experiment := edulab.Experiment{Name: "Test Experiment", PublicID: "123"}
+	expected := `<nav class="breadcrumb"><a href="/">Home</a> &rsaquo; <a href="/experiments/123">Test Experiment</a> &rsaquo; <a href="/experiments/123/demographics">Demographics</a></nav>`
+	result := DemographicBreadcrumb(experiment, printer)
+	if template.HTML(expected) != result {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
@@ -1,6 +1,10 @@
 package presenter
 
 import (
+	"strings"
+
+	"golang.org/x/text/message"
+
 	"github.com/louisbranch/edulab"
 )
 
@@ -32,3 +36,34 @@ func SortDemographics(ds []edulab.Demographic, do map[string]Demographic) []Demo
 	}
 	return sorted
 }
+
+// Translate returns the translation of a demographic or option text in the
+// language of the printer. The default demographics are stored as their
+// message keys; texts written by instructors have no translation and are
+// returned as written.
+func Translate(printer *message.Printer, text string) string {
+	if strings.Contains(text, "%") {
+		return text // Not a message key, and not a format either
+	}
+	return printer.Sprintf(text)
+}
+
+// TranslateDemographics returns copies of demographics and their options with
+// their texts translated.
+func TranslateDemographics(printer *message.Printer, ds []edulab.Demographic,
+	dos []edulab.DemographicOption) ([]edulab.Demographic, []edulab.DemographicOption) {
+
+	translated := make([]edulab.Demographic, len(ds))
+	for i, d := range ds {
+		d.Text = Translate(printer, d.Text)
+		translated[i] = d
+	}
+
+	options := make([]edulab.DemographicOption, len(dos))
+	for i, o := range dos {
+		o.Text = Translate(printer, o.Text)
+		options[i] = o
+	}
+
+	return translated, options
+}
@@ -4,7 +4,11 @@ import (
 	"reflect"
 	"testing"
 
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
 	"github.com/louisbranch/edulab"
+	_ "github.com/louisbranch/edulab/translations"
 )
 
 func TestNewDemographics(t *testing.T) {
@@ -60,3 +64,32 @@ func TestSortDemographics(t *testing.T) {
 		t.Errorf("SortDemographics() = %v, want %v", result, expected)
 	}
 }
+
+func TestTranslateDemographics(t *testing.T) {
+	ds := []edulab.Demographic{
+		{ID: "1", Text: "Gender"},
+		{ID: "2", Text: "Which program are you in?"},
+	}
+	dos := []edulab.DemographicOption{
+		{ID: "1", DemographicID: "1", Text: "Female"},
+		{ID: "2", DemographicID: "2", Text: "100% online"},
+	}
+
+	printer := message.NewPrinter(language.MustParse("pt-BR"))
+	translated, options := TranslateDemographics(printer, ds, dos)
+
+	if translated[0].Text == "Gender" || options[0].Text == "Female" {
+		t.Errorf("expected the default texts to be translated, got %q and %q", translated[0].Text, options[0].Text)
+	}
+	if translated[1].Text != ds[1].Text || options[1].Text != dos[1].Text {
+		t.Errorf("expected custom texts as written, got %q and %q", translated[1].Text, options[1].Text)
+	}
+	if ds[0].Text != "Gender" {
+		t.Errorf("expected the demographics to be left untouched, got %q", ds[0].Text)
+	}
+
+	english, _ := TranslateDemographics(message.NewPrinter(language.English), ds, dos)
+	if english[0].Text != "Gender" {
+		t.Errorf("expected the English text, got %q", english[0].Text)
+	}
+}
@@ -0,0 +1,22 @@
+package presenter
+
+import (
+	"golang.org/x/text/message"
+
+	"github.com/louisbranch/edulab"
+)
+
+func EventType(printer *message.Printer, t edulab.EventType) string {
+	switch t {
+	case edulab.EventLinkOpened:
+		return printer.Sprintf("Link opened")
+	case edulab.EventDemographicsSubmitted:
+		return printer.Sprintf("Demographics submitted")
+	case edulab.EventAssessmentStarted:
+		return printer.Sprintf("Assessment started")
+	case edulab.EventAssessmentSubmitted:
+		return printer.Sprintf("Assessment submitted")
+	default:
+		return printer.Sprintf("Unknown event")
+	}
+}
@@ -0,0 +1,58 @@
+package presenter
+
+import (
+	"errors"
+
+	"golang.org/x/text/message"
+
+	"github.com/louisbranch/edulab/wizard"
+)
+
+// Message returns a message of the wizard in the language of the printer. Its
+// format is the message key, so messages without a translation are formatted
+// as they are.
+func Message(printer *message.Printer, m wizard.Message) string {
+	return printer.Sprintf(m.Format, m.Args...)
+}
+
+// ErrorMessage returns the message of an error in the language of the
+// printer, or its text when it has no message.
+func ErrorMessage(printer *message.Printer, err error) string {
+	var m wizard.Message
+	if errors.As(err, &m) {
+		return Message(printer, m)
+	}
+	return err.Error()
+}
+
+// Skipped is an item of a question bank that was skipped or changed, with its
+// reason translated.
+type Skipped struct {
+	Item   string
+	Reason string
+}
+
+func NewSkipped(printer *message.Printer, skipped []wizard.Skipped) []Skipped {
+	items := make([]Skipped, len(skipped))
+	for i, s := range skipped {
+		items[i] = Skipped{Item: s.Item, Reason: Message(printer, s.Reason)}
+	}
+	return items
+}
+
+// Unmatched is a value of a response file that couldn't be matched, with its
+// reason translated.
+type Unmatched struct {
+	Line   int
+	Column string
+	Value  string
+	Reason string
+}
+
+func NewUnmatched(printer *message.Printer, unmatched []wizard.Unmatched) []Unmatched {
+	values := make([]Unmatched, len(unmatched))
+	for i, u := range unmatched {
+		values[i] = Unmatched{Line: u.Line, Column: u.Column, Value: u.Value, Reason: Message(printer, u.Reason)}
+	}
+	return values
+}
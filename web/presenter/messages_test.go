@@ -0,0 +1,49 @@
+package presenter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	_ "github.com/louisbranch/edulab/translations"
+	"github.com/louisbranch/edulab/wizard"
+)
+
+func TestErrorMessage(t *testing.T) {
+	_, _, err := wizard.ParseQuestionBank("bank.doc", nil)
+
+	printer := message.NewPrinter(language.MustParse("pt-BR"))
+	if got := ErrorMessage(printer, err); !strings.HasPrefix(got, `formato de banco de perguntas desconhecido ".doc"`) {
+		t.Errorf("expected the error to be translated, got %q", got)
+	}
+
+	english := message.NewPrinter(language.English)
+	if got := ErrorMessage(english, err); got != err.Error() {
+		t.Errorf("expected the English error %q, got %q", err.Error(), got)
+	}
+
+	if got := ErrorMessage(printer, errors.New("disk full")); got != "disk full" {
+		t.Errorf("expected an error without a message as it is, got %q", got)
+	}
+}
+
+func TestNewSkipped(t *testing.T) {
+	_, skipped, err := wizard.ParseGIFT(strings.NewReader("::Orbit:: How long is a year? {#365}\n\n" +
+		"::Seasons:: What causes the seasons? {=The tilt ~%50%The distance}\n"))
+	if err != nil {
+		t.Fatalf("failed to parse questions: %v", err)
+	}
+
+	printer := message.NewPrinter(language.MustParse("pt-BR"))
+	want := []Skipped{
+		{Item: "Orbit", Reason: "perguntas numéricas não são suportadas"},
+		{Item: "Seasons", Reason: "a resposta 2 dá 50% do crédito, mas conta como errada"},
+	}
+	if got := NewSkipped(printer, skipped); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
@@ -45,6 +45,71 @@ func GroupQuestions(questions []edulab.Question, choices []edulab.QuestionChoice
 	return sorted
 }
 
+// TranslateQuestions returns copies of questions and their choices with their
+// texts in lang. Texts without a translation are kept as written. IDs are
+// kept too, so answers are recorded against the same items in any language.
+func TranslateQuestions(lang string, questions []edulab.Question, choices []edulab.QuestionChoice,
+	translations []edulab.Translation) ([]edulab.Question, []edulab.QuestionChoice) {
+
+	texts := make(map[[2]string]string)
+	for _, t := range translations {
+		if t.Lang == lang {
+			texts[[2]string{t.QuestionID, t.ChoiceID}] = t.Text
+		}
+	}
+
+	translated := make([]edulab.Question, len(questions))
+	for i, q := range questions {
+		if text, ok := texts[[2]string{q.ID, ""}]; ok {
+			q.Text = text
+		}
+		translated[i] = q
+	}
+
+	tchoices := make([]edulab.QuestionChoice, len(choices))
+	for i, c := range choices {
+		if text, ok := texts[[2]string{c.QuestionID, c.ID}]; ok {
+			c.Text = text
+		}
+		tchoices[i] = c
+	}
+
+	return translated, tchoices
+}
+
+// Translations holds the texts of a question and of its choices in a
+// language, by choice ID, for the translation editor.
+type Translations struct {
+	Lang    string
+	Name    string
+	Text    string
+	Choices map[string]string
+}
+
+// NewTranslations returns the translations of a question in a language.
+func NewTranslations(lang, name string, question edulab.Question,
+	translations []edulab.Translation) Translations {
+
+	tr := Translations{
+		Lang:    lang,
+		Name:    name,
+		Choices: make(map[string]string),
+	}
+
+	for _, t := range translations {
+		if t.QuestionID != question.ID || t.Lang != lang {
+			continue
+		}
+		if t.ChoiceID == "" {
+			tr.Text = t.Text
+		} else {
+			tr.Choices[t.ChoiceID] = t.Text
+		}
+	}
+
+	return tr
+}
+
 func QuestionTypes(printer *message.Printer) []QuestionType {
 	return []QuestionType{
 		{Value: string(edulab.InputSingle), Text: printer.Sprintf("Single Choice")},
@@ -56,3 +56,44 @@ func TestQuestionTypes(t *testing.T) {
 		}
 	}
 }
+
+func TestTranslateQuestions(t *testing.T) {
+	questions := []edulab.Question{
+		{ID: "q1", Text: "What causes seasons?"},
+		{ID: "q2", Text: "Explain your answer"},
+	}
+
+	choices := []edulab.QuestionChoice{
+		{ID: "c1", QuestionID: "q1", Text: "The tilt of Earth's axis"},
+		{ID: "c2", QuestionID: "q1", Text: "The distance from the Sun"},
+	}
+
+	translations := []edulab.Translation{
+		{QuestionID: "q1", Lang: "pt-BR", Text: "O que causa as estações?"},
+		{QuestionID: "q1", ChoiceID: "c1", Lang: "pt-BR", Text: "A inclinação do eixo da Terra"},
+		{QuestionID: "q2", Lang: "en", Text: "Explain why"},
+	}
+
+	tq, tc := TranslateQuestions("pt-BR", questions, choices, translations)
+
+	if tq[0].Text != "O que causa as estações?" || tq[0].ID != "q1" {
+		t.Errorf("expected q1 translated, got %v", tq[0])
+	}
+	if tq[1].Text != "Explain your answer" {
+		t.Errorf("expected q2 as written, got %q", tq[1].Text)
+	}
+	if tc[0].Text != "A inclinação do eixo da Terra" || tc[0].ID != "c1" {
+		t.Errorf("expected c1 translated, got %v", tc[0])
+	}
+	if tc[1].Text != "The distance from the Sun" {
+		t.Errorf("expected c2 as written, got %q", tc[1].Text)
+	}
+	if questions[0].Text != "What causes seasons?" || choices[0].Text != "The tilt of Earth's axis" {
+		t.Error("expected the original texts to be unchanged")
+	}
+
+	tr := NewTranslations("pt-BR", "Português", questions[0], translations)
+	if tr.Text != "O que causa as estações?" || len(tr.Choices) != 1 || tr.Choices["c1"] != "A inclinação do eixo da Terra" {
+		t.Errorf("unexpected translations %v", tr)
+	}
+}
@@ -8,12 +8,13 @@ import (
 
 type DemographicsResult struct {
 	Demographics   []Demographic
+	arms           []edulab.Arm
 	cohorts        []edulab.Cohort
 	participants   []edulab.Participant
 	participations []edulab.Participation
 	Categories     [][]string
-	Cohorts        []string
-	Data           [][][]int // [category][cohort][count]
+	Arms           []string
+	Data           [][][]int // [category][arm][count]
 }
 
 func (dr DemographicsResult) categories() [][]string {
@@ -28,20 +29,23 @@ func (dr DemographicsResult) categories() [][]string {
 	return labels
 }
 
-func (dr DemographicsResult) cohortNames() []string {
+func (dr DemographicsResult) armNames() []string {
 	var names []string
-	for _, c := range dr.cohorts {
-		names = append(names, c.Name)
+	for _, a := range dr.arms {
+		names = append(names, a.Name)
 	}
 	return names
 }
 
+// NewDemographicsResult counts the demographic options selected by the
+// participants of each arm.
 func NewDemographicsResult(ds []edulab.Demographic, dos []edulab.DemographicOption,
-	cohorts []edulab.Cohort, participants []edulab.Participant,
+	arms []edulab.Arm, cohorts []edulab.Cohort, participants []edulab.Participant,
 	participations []edulab.Participation) (DemographicsResult, error) {
 
 	dr := DemographicsResult{
 		Demographics:   NewDemographics(ds, dos),
+		arms:           arms,
 		cohorts:        cohorts,
 		participants:   participants,
 		participations: participations,
@@ -53,20 +57,26 @@ func NewDemographicsResult(ds []edulab.Demographic, dos []edulab.DemographicOpti
 
 	dr.Categories = dr.categories()
 	dr.Data = data
-	dr.Cohorts = dr.cohortNames()
+	dr.Arms = dr.armNames()
 
 	return dr, nil
 }
 
 func (dr DemographicsResult) data() ([][][]int, error) {
 
-	// Map of participant ID to cohort ID
+	// Map of cohort ID to arm ID
+	cohorts := make(map[string]string)
+	for _, c := range dr.cohorts {
+		cohorts[c.ID] = c.ArmID
+	}
+
+	// Map of participant ID to arm ID
 	participants := make(map[string]string)
 	for _, p := range dr.participants {
-		participants[p.ID] = p.CohortID
+		participants[p.ID] = cohorts[p.CohortID]
 	}
 
-	// cohort ID -> demographic ID -> option ID -> count
+	// arm ID -> demographic ID -> option ID -> count
 	data := make(map[string]map[string]map[string]int)
 
 	for _, p := range dr.participations {
@@ -97,16 +107,16 @@ func (dr DemographicsResult) data() ([][][]int, error) {
 			}
 			// Add to result only if all items were strings
 			if stringArray != nil {
-				cohortID := participants[p.ParticipantID]
-				if _, ok := data[cohortID]; !ok {
-					data[cohortID] = make(map[string]map[string]int)
+				armID := participants[p.ParticipantID]
+				if _, ok := data[armID]; !ok {
+					data[armID] = make(map[string]map[string]int)
 				}
-				if _, ok := data[cohortID][demographicID]; !ok {
-					data[cohortID][demographicID] = make(map[string]int)
+				if _, ok := data[armID][demographicID]; !ok {
+					data[armID][demographicID] = make(map[string]int)
 				}
 
 				for _, optionID := range stringArray {
-					data[cohortID][demographicID][optionID]++
+					data[armID][demographicID][optionID]++
 				}
 			}
 		}
@@ -115,12 +125,12 @@ func (dr DemographicsResult) data() ([][][]int, error) {
 	values := make([][][]int, len(dr.Demographics))
 
 	for i, d := range dr.Demographics {
-		dv := make([][]int, len(dr.cohorts))
+		dv := make([][]int, len(dr.arms))
 
-		for j, c := range dr.cohorts {
+		for j, a := range dr.arms {
 			do := make([]int, len(d.Options))
 			for k, o := range d.Options {
-				count := data[c.ID][d.ID][o.ID]
+				count := data[a.ID][d.ID][o.ID]
 				do[k] = count
 			}
 
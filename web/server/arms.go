@@ -0,0 +1,291 @@
+package server
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/web/presenter"
+)
+
+func (srv *Server) armsHandler(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, segments []string) {
+
+	log.Print("[DEBUG] Routing arms")
+
+	if len(segments) < 1 {
+		if r.Method == http.MethodPost {
+			srv.createArm(w, r, experiment)
+			return
+		} else if r.Method == http.MethodGet {
+			srv.listArms(w, r, experiment)
+			return
+		}
+	}
+
+	pid := segments[0]
+	if len(segments) == 1 && r.Method == http.MethodPost {
+		srv.updateArm(w, r, experiment, pid)
+		return
+	}
+
+	switch pid {
+	case "new":
+		srv.newArm(w, r, experiment)
+		return
+	default:
+		srv.showArm(w, r, experiment, pid)
+		return
+	}
+}
+
+func (srv *Server) listArms(w http.ResponseWriter, r *http.Request, experiment edulab.Experiment) {
+	printer, page := srv.i18n(w, r)
+
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	cohorts, err := srv.DB.FindCohorts(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, c := range cohorts {
+		counts[c.ArmID]++
+	}
+
+	title := printer.Sprintf("Arms")
+	page.Title = title
+	page.Partials = []string{"arms"}
+	page.Content = struct {
+		Title       string
+		Breadcrumbs template.HTML
+		Experiment  edulab.Experiment
+		Arms        []edulab.Arm
+		Cohorts     map[string]int
+		Texts       interface{}
+	}{
+		Title:       title,
+		Breadcrumbs: presenter.ExperimentBreadcrumb(experiment, printer),
+		Experiment:  experiment,
+		Arms:        arms,
+		Cohorts:     counts,
+		Texts: struct {
+			Help    string
+			Add     string
+			Name    string
+			Cohorts string
+			Control string
+			Actions string
+			Edit    string
+			NoArms  string
+		}{
+			Help:    printer.Sprintf("Arms are the treatment conditions of the experiment. Results compare each arm against the control arm."),
+			Add:     printer.Sprintf("Add Arm"),
+			Name:    printer.Sprintf("Name"),
+			Cohorts: printer.Sprintf("Cohorts"),
+			Control: printer.Sprintf("Control"),
+			Actions: printer.Sprintf("Actions"),
+			Edit:    printer.Sprintf("Edit"),
+			NoArms:  printer.Sprintf("No arms found"),
+		},
+	}
+
+	srv.render(w, page)
+}
+
+func (srv *Server) newArm(w http.ResponseWriter, r *http.Request, experiment edulab.Experiment) {
+
+	printer, page := srv.i18n(w, r)
+
+	title := printer.Sprintf("New Arm")
+	page.Title = title
+	page.Partials = []string{"arm_new"}
+	page.Content = struct {
+		Breadcrumbs template.HTML
+		Experiment  edulab.Experiment
+		Title       string
+		Texts       interface{}
+	}{
+		Breadcrumbs: presenter.ArmBreadcrumb(experiment, printer),
+		Experiment:  experiment,
+		Title:       title,
+		Texts: struct {
+			Name                   string
+			NamePlaceholder        string
+			NameHelp               string
+			Description            string
+			DescriptionPlaceholder string
+			DescriptionHelp        string
+			Control                string
+			ControlHelp            string
+			Create                 string
+		}{
+			Name:                   printer.Sprintf("Name"),
+			NamePlaceholder:        printer.Sprintf("e.g. Intervention A"),
+			NameHelp:               printer.Sprintf("Not visible to participants."),
+			Description:            printer.Sprintf("Description"),
+			DescriptionPlaceholder: printer.Sprintf("e.g. Interactive workshop with peer instruction"),
+			DescriptionHelp:        printer.Sprintf("Optional. Not visible to participants."),
+			Control:                printer.Sprintf("Control arm"),
+			ControlHelp:            printer.Sprintf("The other arms are compared against the control arm."),
+			Create:                 printer.Sprintf("Create"),
+		},
+	}
+
+	srv.render(w, page)
+}
+
+func (srv *Server) createArm(w http.ResponseWriter, r *http.Request, experiment edulab.Experiment) {
+	err := r.ParseForm()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	arm := &edulab.Arm{
+		PublicID:     srv.newPublicID(3),
+		ExperimentID: experiment.ID,
+		Name:         r.FormValue("name"),
+		Description:  r.FormValue("description"),
+		Control:      r.FormValue("control") == "on" || len(arms) == 0,
+	}
+
+	err = srv.DB.CreateArm(arm)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	if arm.Control {
+		err = srv.designateControl(experiment, arms, arm.PublicID)
+		if err != nil {
+			srv.renderError(w, r, err)
+			return
+		}
+	}
+
+	http.Redirect(w, r, "/experiments/"+experiment.PublicID+"/arms", http.StatusSeeOther)
+}
+
+func (srv *Server) updateArm(w http.ResponseWriter, r *http.Request, experiment edulab.Experiment, pid string) {
+	err := r.ParseForm()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	arm, err := srv.DB.FindArm(experiment.ID, pid)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	arm.Name = r.FormValue("name")
+	arm.Description = r.FormValue("description")
+
+	// The control arm can only change by designating another arm
+	designate := r.FormValue("control") == "on" && !arm.Control
+
+	err = srv.DB.UpdateArm(experiment.ID, arm)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	if designate {
+		arms, err := srv.DB.FindArms(experiment.ID)
+		if err != nil {
+			srv.renderError(w, r, err)
+			return
+		}
+
+		err = srv.designateControl(experiment, arms, arm.PublicID)
+		if err != nil {
+			srv.renderError(w, r, err)
+			return
+		}
+	}
+
+	http.Redirect(w, r, "/experiments/"+experiment.PublicID+"/arms", http.StatusSeeOther)
+}
+
+// designateControl makes an arm the only control arm of an experiment.
+func (srv *Server) designateControl(experiment edulab.Experiment, arms []edulab.Arm, pid string) error {
+	for _, a := range arms {
+		control := a.PublicID == pid
+		if a.Control == control {
+			continue
+		}
+
+		a.Control = control
+		err := srv.DB.UpdateArm(experiment.ID, a)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (srv *Server) showArm(w http.ResponseWriter, r *http.Request, experiment edulab.Experiment, pid string) {
+
+	printer, page := srv.i18n(w, r)
+
+	arm, err := srv.DB.FindArm(experiment.ID, pid)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	controlHelp := printer.Sprintf("The other arms are compared against the control arm.")
+	if arm.Control {
+		controlHelp = printer.Sprintf("Designate another arm as control to change it.")
+	}
+
+	title := printer.Sprintf("Arm: %s", arm.Name)
+	page.Title = title
+	page.Partials = []string{"arm"}
+	page.Content = struct {
+		Breadcrumbs template.HTML
+		Experiment  edulab.Experiment
+		Arm         edulab.Arm
+		Texts       interface{}
+	}{
+		Breadcrumbs: presenter.ArmBreadcrumb(experiment, printer),
+		Experiment:  experiment,
+		Arm:         arm,
+		Texts: struct {
+			Title           string
+			Name            string
+			NameHelp        string
+			Description     string
+			DescriptionHelp string
+			Control         string
+			ControlHelp     string
+			Update          string
+		}{
+			Title:           title,
+			Name:            printer.Sprintf("Name"),
+			NameHelp:        printer.Sprintf("Not visible to participants."),
+			Description:     printer.Sprintf("Description"),
+			DescriptionHelp: printer.Sprintf("Optional. Not visible to participants."),
+			Control:         printer.Sprintf("Control arm"),
+			ControlHelp:     controlHelp,
+			Update:          printer.Sprintf("Update"),
+		},
+	}
+
+	srv.render(w, page)
+}
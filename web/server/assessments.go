@@ -1,12 +1,17 @@
 package server
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"html/template"
+	"io"
 	"log"
 	"net/http"
 
 	"github.com/louisbranch/edulab"
 	"github.com/louisbranch/edulab/web/presenter"
+	"github.com/louisbranch/edulab/wizard"
 )
 
 // assessmentsHandler handles the assessments subroutes in an experiment for the instructor.
@@ -15,14 +20,27 @@ func (srv *Server) assessmentsHandler(w http.ResponseWriter, r *http.Request,
 
 	log.Print("[DEBUG] Routing assessments")
 
-	if len(segments) < 1 {
+	if len(segments) < 1 || segments[0] == "" {
+		if r.Method == http.MethodPost {
+			srv.createAssessment(w, r, experiment)
+			return
+		}
 		srv.listAssessments(w, r, experiment)
 		return
 	}
 
 	pid := segments[0]
 	if pid == "new" {
-		srv.newAssessment(w, r, experiment)
+		srv.newAssessment(w, r, experiment, nil)
+		return
+	}
+
+	if pid == "import" {
+		if r.Method != http.MethodPost {
+			srv.renderNotFound(w, r)
+			return
+		}
+		srv.importAssessment(w, r, experiment)
 		return
 	}
 
@@ -44,6 +62,23 @@ func (srv *Server) assessmentsHandler(w http.ResponseWriter, r *http.Request,
 	case "questions":
 		srv.questionsHandler(w, r, experiment, assessment, segments[2:])
 		return
+	case "duplicate":
+		if r.Method != http.MethodPost {
+			srv.renderNotFound(w, r)
+			return
+		}
+		srv.duplicateAssessment(w, r, experiment, assessment)
+		return
+	case "export":
+		srv.exportAssessment(w, r, experiment, assessment)
+		return
+	case "responses":
+		if r.Method != http.MethodPost {
+			srv.renderNotFound(w, r)
+			return
+		}
+		srv.importResponses(w, r, experiment, assessment)
+		return
 	default:
 		srv.renderNotFound(w, r)
 		return
@@ -74,34 +109,33 @@ func (srv *Server) listAssessments(w http.ResponseWriter, r *http.Request,
 		Experiment:  experiment,
 		Assessments: presenter.NewAssessments(assessments, printer),
 		Texts: struct {
-			Title      string
-			Type       string
-			Questions  string
-			Actions    string
-			Edit       string
-			Add        string
-			Empty      string
-			Preview    string
-			ComingSoon string
+			Title     string
+			Type      string
+			Questions string
+			Actions   string
+			Edit      string
+			Add       string
+			Empty     string
+			Preview   string
 		}{
-			Title:      printer.Sprintf("Assessments"),
-			Type:       printer.Sprintf("Type"),
-			Questions:  printer.Sprintf("Questions"),
-			Actions:    printer.Sprintf("Actions"),
-			Add:        printer.Sprintf("Add Assessment"),
-			Empty:      printer.Sprintf("No assessments yet"),
-			Edit:       printer.Sprintf("Edit"),
-			Preview:    printer.Sprintf("Preview"),
-			ComingSoon: printer.Sprintf("Coming Soon"),
+			Title:     printer.Sprintf("Assessments"),
+			Type:      printer.Sprintf("Type"),
+			Questions: printer.Sprintf("Questions"),
+			Actions:   printer.Sprintf("Actions"),
+			Add:       printer.Sprintf("Add Assessment"),
+			Empty:     printer.Sprintf("No assessments yet"),
+			Edit:      printer.Sprintf("Edit"),
+			Preview:   printer.Sprintf("Preview"),
 		},
 	}
 
 	srv.render(w, page)
 }
 
-// newAssessment displays the assessment editor to the instructor.
+// newAssessment displays the assessment editor to the instructor, with the
+// problems found in the last question bank uploaded.
 func (srv *Server) newAssessment(w http.ResponseWriter, r *http.Request,
-	experiment edulab.Experiment) {
+	experiment edulab.Experiment, problems []string) {
 
 	printer, page := srv.i18n(w, r)
 
@@ -112,11 +146,13 @@ func (srv *Server) newAssessment(w http.ResponseWriter, r *http.Request,
 		Breadcrumbs template.HTML
 		Experiment  edulab.Experiment
 		Types       [][]string
+		Problems    []string
 		Texts       interface{}
 	}{
 		Breadcrumbs: presenter.ExperimentBreadcrumb(experiment, printer),
 		Experiment:  experiment,
 		Types:       presenter.AssessmentTypes(printer),
+		Problems:    problems,
 		Texts: struct {
 			Title                  string
 			Description            string
@@ -124,7 +160,10 @@ func (srv *Server) newAssessment(w http.ResponseWriter, r *http.Request,
 			DescriptionPlaceholder string
 			Type                   string
 			Submit                 string
-			ComingSoon             string
+			Upload                 string
+			UploadHelp             string
+			Invalid                string
+			Import                 string
 		}{
 			Title:                  title,
 			Description:            printer.Sprintf("Description"),
@@ -132,13 +171,410 @@ func (srv *Server) newAssessment(w http.ResponseWriter, r *http.Request,
 			DescriptionPlaceholder: printer.Sprintf("e.g. Gauge your current knowledge about the causes of Earth's..."),
 			Type:                   printer.Sprintf("Type"),
 			Submit:                 printer.Sprintf("Create"),
-			ComingSoon:             printer.Sprintf("Coming Soon"),
+			Upload:                 printer.Sprintf("Import a Question Bank"),
+			UploadHelp:             printer.Sprintf("A QTI 2.1 package (.zip), Moodle XML (.xml) or GIFT (.gift, .txt) file exported from your learning management system. Single choice, multiple choice, true/false and open-ended questions are imported."),
+			Invalid:                printer.Sprintf("The file couldn't be imported:"),
+			Import:                 printer.Sprintf("Import"),
 		},
 	}
 
+	if len(problems) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
 	srv.render(w, page)
 }
 
+// createAssessment creates a new assessment at one of the experiment timepoints.
+func (srv *Server) createAssessment(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment) {
+
+	err := r.ParseForm()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	assessment := &edulab.Assessment{
+		ExperimentID: experiment.ID,
+		PublicID:     srv.newPublicID(2),
+		Description:  r.FormValue("description"),
+		Type:         edulab.AssessmentType(r.FormValue("type")),
+	}
+
+	if !assessment.Type.Valid() {
+		srv.renderError(w, r, fmt.Errorf("unknown assessment type %q", assessment.Type))
+		return
+	}
+
+	err = srv.DB.CreateAssessment(assessment)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/experiments/"+experiment.PublicID+"/assessments/"+assessment.PublicID,
+		http.StatusSeeOther)
+}
+
+// maxPackage is the size limit of the question banks uploaded by instructors,
+// which can include images.
+const maxPackage = 10 << 20
+
+// importAssessment creates an assessment from an uploaded question bank. The
+// items that couldn't be converted are listed once it is created.
+func (srv *Server) importAssessment(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment) {
+
+	printer, _ := srv.i18n(w, r)
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxPackage)
+
+	file, header, err := r.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	switch {
+	case err == nil:
+		defer file.Close()
+	case errors.As(err, &tooLarge):
+		srv.newAssessment(w, r, experiment, []string{printer.Sprintf("the file is larger than 10 MB")})
+		return
+	case errors.Is(err, http.ErrMissingFile), errors.Is(err, http.ErrNotMultipart):
+		srv.newAssessment(w, r, experiment, []string{printer.Sprintf("no file was uploaded")})
+		return
+	default:
+		srv.renderError(w, r, err)
+		return
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	questions, skipped, err := wizard.ParseQuestionBank(header.Filename, content)
+	if err != nil {
+		srv.newAssessment(w, r, experiment, []string{presenter.ErrorMessage(printer, err)})
+		return
+	}
+
+	if len(questions) == 0 {
+		problems := []string{printer.Sprintf("none of the items could be converted")}
+		for _, s := range presenter.NewSkipped(printer, skipped) {
+			problems = append(problems, s.Item+": "+s.Reason)
+		}
+		srv.newAssessment(w, r, experiment, problems)
+		return
+	}
+
+	assessment := &edulab.Assessment{
+		ExperimentID: experiment.ID,
+		PublicID:     srv.newPublicID(2),
+		Description:  r.FormValue("description"),
+		Type:         edulab.AssessmentType(r.FormValue("type")),
+	}
+
+	if !assessment.Type.Valid() {
+		srv.renderError(w, r, fmt.Errorf("unknown assessment type %q", assessment.Type))
+		return
+	}
+
+	err = srv.DB.Transaction(func(db edulab.Database) error {
+		return wizard.ImportQuestions(db, assessment, questions)
+	})
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	path := "/experiments/" + experiment.PublicID + "/assessments/" + assessment.PublicID
+	if len(skipped) == 0 {
+		http.Redirect(w, r, path, http.StatusSeeOther)
+		return
+	}
+
+	printer, page := srv.i18n(w, r)
+
+	page.Title = printer.Sprintf("Import Assessment")
+	page.Partials = []string{"assessment_import"}
+	page.Content = struct {
+		Breadcrumbs template.HTML
+		Path        string
+		Skipped     []presenter.Skipped
+		Texts       interface{}
+	}{
+		Breadcrumbs: presenter.AssessmentsBreadcrumb(experiment, printer),
+		Path:        path,
+		Skipped:     presenter.NewSkipped(printer, skipped),
+		Texts: struct {
+			Title    string
+			Imported string
+			Skipped  string
+			Item     string
+			Reason   string
+			Continue string
+		}{
+			Title:    printer.Sprintf("Import Assessment"),
+			Imported: printer.Sprintf("%d questions were imported.", len(questions)),
+			Skipped:  printer.Sprintf("These items were left out, or changed to fit an assessment:"),
+			Item:     printer.Sprintf("Item"),
+			Reason:   printer.Sprintf("Reason"),
+			Continue: printer.Sprintf("Continue to the assessment"),
+		},
+	}
+
+	srv.render(w, page)
+}
+
+// duplicateAssessment copies the questions and choices of an assessment into a
+// new one, linking each copy to its original so they are compared in the
+// results. Choices are shuffled when requested.
+func (srv *Server) duplicateAssessment(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, original edulab.Assessment) {
+
+	err := r.ParseForm()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	assessment := &edulab.Assessment{
+		ExperimentID: experiment.ID,
+		PublicID:     srv.newPublicID(2),
+		Description:  original.Description,
+		Type:         edulab.AssessmentType(r.FormValue("type")),
+	}
+
+	if !assessment.Type.Valid() {
+		srv.renderError(w, r, fmt.Errorf("unknown assessment type %q", assessment.Type))
+		return
+	}
+
+	questions, err := srv.DB.FindQuestions(original.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	choices, err := srv.DB.FindQuestionChoices(original.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	translations, err := srv.DB.FindTranslations(original.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	anchored, err := srv.anchorQuestions(experiment, original, questions)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	shuffle := r.FormValue("shuffle") == "on"
+
+	err = srv.DB.Transaction(func(db edulab.Database) error {
+		err := db.CreateAssessment(assessment)
+		if err != nil {
+			return err
+		}
+
+		for i, q := range anchored {
+			if q.Anchor != questions[i].Anchor {
+				err = db.UpdateQuestion(q)
+				if err != nil {
+					return err
+				}
+			}
+
+			question := edulab.Question{
+				AssessmentID: assessment.ID,
+				Text:         q.Text,
+				Type:         q.Type,
+				Anchor:       q.Anchor,
+			}
+
+			err = db.CreateQuestion(&question)
+			if err != nil {
+				return err
+			}
+
+			// Translations are copied to the new question and choices, mapping
+			// the original choice IDs to the new ones
+			ids := map[string]string{"": ""}
+
+			var qchoices []edulab.QuestionChoice
+			for _, c := range choices {
+				if c.QuestionID == q.ID {
+					qchoices = append(qchoices, c)
+				}
+			}
+
+			if shuffle {
+				srv.Random.Shuffle(len(qchoices), func(i, j int) {
+					qchoices[i], qchoices[j] = qchoices[j], qchoices[i]
+				})
+			}
+
+			for _, c := range qchoices {
+				qc := edulab.QuestionChoice{
+					QuestionID: question.ID,
+					Text:       c.Text,
+					IsCorrect:  c.IsCorrect,
+				}
+
+				err = db.CreateQuestionChoice(&qc)
+				if err != nil {
+					return err
+				}
+				ids[c.ID] = qc.ID
+			}
+
+			for _, t := range translations {
+				if t.QuestionID != q.ID {
+					continue
+				}
+
+				t.QuestionID = question.ID
+				t.ChoiceID = ids[t.ChoiceID]
+
+				err = db.UpdateTranslation(t)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/experiments/"+experiment.PublicID+"/assessments/"+assessment.PublicID,
+		http.StatusSeeOther)
+}
+
+// exportAssessment downloads the questions of an assessment as a question
+// bank, a QTI 2.1 package or a Moodle XML file.
+func (srv *Server) exportAssessment(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, assessment edulab.Assessment) {
+
+	var buf bytes.Buffer
+	var err error
+	var contentType, filename string
+
+	name := experiment.PublicID + "-" + assessment.PublicID
+
+	switch r.URL.Query().Get("format") {
+	case "qti", "":
+		err = wizard.ExportQTI(srv.DB, experiment, assessment, &buf)
+		contentType, filename = "application/zip", name+".zip"
+	case "moodle":
+		err = wizard.ExportMoodleXML(srv.DB, experiment, assessment, &buf)
+		contentType, filename = "application/xml; charset=utf-8", name+".xml"
+	default:
+		srv.renderNotFound(w, r)
+		return
+	}
+
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(buf.Bytes())
+}
+
+// anchorQuestions returns a copy of the questions of an assessment being
+// duplicated, giving a link ID to the ones without one so the source and its
+// copy stay compared if either is edited. Questions already matched by text
+// with another assessment are left as they are, keeping that match.
+func (srv *Server) anchorQuestions(experiment edulab.Experiment, assessment edulab.Assessment,
+	questions []edulab.Question) ([]edulab.Question, error) {
+
+	assessments, err := srv.DB.FindAssessments(experiment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make(map[string]bool)
+	for _, a := range assessments {
+		if a.ID == assessment.ID {
+			continue
+		}
+
+		others, err := srv.DB.FindQuestions(a.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, q := range others {
+			if q.Anchor == "" {
+				matched[q.Text] = true
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, q := range questions {
+		used[q.Anchor] = true
+	}
+
+	// Generated link IDs skip the ones the assessment already uses
+	anchor := func(n int) string {
+		for ; ; n++ {
+			id := fmt.Sprintf("%s-%d", assessment.PublicID, n)
+			if !used[id] {
+				used[id] = true
+				return id
+			}
+		}
+	}
+
+	anchored := make([]edulab.Question, len(questions))
+	for i, q := range questions {
+		if q.Anchor == "" && !matched[q.Text] {
+			q.Anchor = anchor(i + 1)
+		}
+		anchored[i] = q
+	}
+
+	return anchored, nil
+}
+
+// duplicateType suggests the type of a duplicate: the post-assessment of a
+// pre-assessment, otherwise the first timepoint after the assessment that the
+// experiment doesn't have yet, or its own type when every later one is taken.
+func duplicateType(assessment edulab.Assessment, assessments []edulab.Assessment) edulab.AssessmentType {
+	used := make(map[edulab.AssessmentType]bool)
+	for _, a := range assessments {
+		used[a.Type] = true
+	}
+
+	if assessment.Type == edulab.AssessmentTypePre && !used[edulab.AssessmentTypePost] {
+		return edulab.AssessmentTypePost
+	}
+
+	after := false
+	for _, t := range edulab.AssessmentTypes {
+		if after && !used[t] {
+			return t
+		}
+		if t == assessment.Type {
+			after = true
+		}
+	}
+
+	return assessment.Type
+}
+
 // editAssessment displays the assessment editor to the instructor.
 func (srv *Server) editAssessment(w http.ResponseWriter, r *http.Request,
 	experiment edulab.Experiment, assessment edulab.Assessment) {
@@ -149,47 +585,83 @@ func (srv *Server) editAssessment(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	assessments, err := srv.DB.FindAssessments(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
 	printer, page := srv.i18n(w, r)
 
 	page.Title = printer.Sprintf("Assessment")
 	page.Partials = []string{"assessment_edit"}
 	page.Content = struct {
-		Breadcrumbs template.HTML
-		Experiment  edulab.Experiment
-		Assessment  presenter.Assessment
-		Questions   []edulab.Question
-		Texts       interface{}
+		Breadcrumbs   template.HTML
+		Experiment    edulab.Experiment
+		Assessment    presenter.Assessment
+		Questions     []edulab.Question
+		Types         [][]string
+		DuplicateType edulab.AssessmentType
+		Texts         interface{}
 	}{
-		Breadcrumbs: presenter.AssessmentsBreadcrumb(experiment, printer),
-		Experiment:  experiment,
-		Assessment:  presenter.NewAssessment(assessment, printer),
-		Questions:   questions,
+		Breadcrumbs:   presenter.AssessmentsBreadcrumb(experiment, printer),
+		Experiment:    experiment,
+		Assessment:    presenter.NewAssessment(assessment, printer),
+		Questions:     questions,
+		Types:         presenter.AssessmentTypes(printer),
+		DuplicateType: duplicateType(assessment, assessments),
 		Texts: struct {
 			Description            string
 			DescriptionHelp        string
 			DescriptionPlaceholder string
 			Questions              string
 			Text                   string
+			Anchor                 string
 			Actions                string
 			Edit                   string
+			Translate              string
 			Update                 string
 			Add                    string
 			Empty                  string
 			Preview                string
 			ComingSoon             string
+			Duplicate              string
+			DuplicateHelp          string
+			DuplicateAs            string
+			Shuffle                string
+			Export                 string
+			ExportHelp             string
+			ExportQTI              string
+			ExportMoodle           string
+			Responses              string
+			ResponsesHelp          string
+			Upload                 string
 		}{
 			Description:            printer.Sprintf("Description"),
 			DescriptionHelp:        printer.Sprintf("Optional. Markdown supported."),
 			DescriptionPlaceholder: printer.Sprintf("e.g. Gauge your current knowledge about the causes of Earth's..."),
 			Questions:              printer.Sprintf("Questions"),
 			Text:                   printer.Sprintf("Text"),
+			Anchor:                 printer.Sprintf("Link ID"),
 			Actions:                printer.Sprintf("Actions"),
 			Edit:                   printer.Sprintf("Edit"),
+			Translate:              printer.Sprintf("Translate"),
 			Update:                 printer.Sprintf("Update"),
 			Add:                    printer.Sprintf("Add Question"),
 			Empty:                  printer.Sprintf("No questions yet"),
 			Preview:                printer.Sprintf("Preview"),
 			ComingSoon:             printer.Sprintf("Coming Soon"),
+			Duplicate:              printer.Sprintf("Duplicate"),
+			DuplicateHelp:          printer.Sprintf("Copy all questions and choices into a new assessment. The copies are linked to these questions so they are compared in the results."),
+			DuplicateAs:            printer.Sprintf("Duplicate as"),
+			Shuffle:                printer.Sprintf("Shuffle the order of the choices"),
+			Export:                 printer.Sprintf("Export"),
+			ExportHelp:             printer.Sprintf("Download the questions to give this assessment inside a learning management system, such as Canvas or Moodle."),
+			ExportQTI:              printer.Sprintf("QTI 2.1 Package"),
+			ExportMoodle:           printer.Sprintf("Moodle XML"),
+			Responses:              printer.Sprintf("Import Responses"),
+			ResponsesHelp:          printer.Sprintf("Responses collected elsewhere, such as on paper or in a learning management system, as a CSV file with a header row: a column identifying each student, a column with their cohort and a column per question. You can check how the columns and values match before importing."),
+			Upload:                 printer.Sprintf("Upload"),
 		},
 	}
 
@@ -212,10 +684,17 @@ func (srv *Server) previewAssessment(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	qp := presenter.GroupQuestions(questions, choices)
+	translations, err := srv.DB.FindTranslations(assessment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
 
 	printer, page := srv.i18n(w, r)
 
+	questions, choices = presenter.TranslateQuestions(page.Lang, questions, choices, translations)
+	qp := presenter.GroupQuestions(questions, choices)
+
 	page.Title = printer.Sprintf("Preview Assessment")
 	page.Partials = []string{"assessment_preview"}
 	page.Content = struct {
@@ -263,6 +742,15 @@ func (srv *Server) showAssessment(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	translations, err := srv.DB.FindTranslations(assessment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	// Participants read the questions in their language but answer the same
+	// questions and choices, so results count every language together
+	questions, choices = presenter.TranslateQuestions(page.Lang, questions, choices, translations)
 	qp := presenter.GroupQuestions(questions, choices)
 
 	page.Title = printer.Sprintf("%s - %s", experiment.Name, assessment.Type)
@@ -1,11 +1,14 @@
 package server
 
 import (
+	"fmt"
 	"html/template"
 	"log"
 	"net/http"
+	"net/url"
 
 	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/result"
 	"github.com/louisbranch/edulab/web/presenter"
 )
 
@@ -49,6 +52,17 @@ func (srv *Server) listCohorts(w http.ResponseWriter, r *http.Request, experimen
 		return
 	}
 
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	names := make(map[string]string)
+	for _, a := range arms {
+		names[a.ID] = a.Name
+	}
+
 	title := printer.Sprintf("Cohorts")
 	page.Title = title
 	page.Partials = []string{"cohorts"}
@@ -57,21 +71,25 @@ func (srv *Server) listCohorts(w http.ResponseWriter, r *http.Request, experimen
 		Breadcrumbs template.HTML
 		Experiment  edulab.Experiment
 		Cohorts     []edulab.Cohort
+		Arms        map[string]string
 		Texts       interface{}
 	}{
 		Title:       title,
 		Breadcrumbs: presenter.ExperimentBreadcrumb(experiment, printer),
 		Experiment:  experiment,
 		Cohorts:     cohorts,
+		Arms:        names,
 		Texts: struct {
 			Add       string
 			Name      string
+			Arm       string
 			Actions   string
 			Edit      string
 			NoCohorts string
 		}{
 			Add:       printer.Sprintf("Add Cohort"),
 			Name:      printer.Sprintf("Name"),
+			Arm:       printer.Sprintf("Arm"),
 			Actions:   printer.Sprintf("Actions"),
 			Edit:      printer.Sprintf("Edit"),
 			NoCohorts: printer.Sprintf("No cohorts found"),
@@ -85,17 +103,25 @@ func (srv *Server) newCohort(w http.ResponseWriter, r *http.Request, experiment
 
 	printer, page := srv.i18n(w, r)
 
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
 	title := printer.Sprintf("New Cohort")
 	page.Title = title
 	page.Partials = []string{"cohort_new"}
 	page.Content = struct {
 		Breadcrumbs template.HTML
 		Experiment  edulab.Experiment
+		Arms        []edulab.Arm
 		Title       string
 		Texts       interface{}
 	}{
 		Breadcrumbs: presenter.CohortBreadcrumb(experiment, printer),
 		Experiment:  experiment,
+		Arms:        arms,
 		Title:       title,
 		Texts: struct {
 			Name                   string
@@ -104,6 +130,9 @@ func (srv *Server) newCohort(w http.ResponseWriter, r *http.Request, experiment
 			Description            string
 			DescriptionPlaceholder string
 			DescriptionHelp        string
+			Arm                    string
+			NewArm                 string
+			ArmHelp                string
 			Create                 string
 		}{
 			Name:                   printer.Sprintf("Name"),
@@ -112,6 +141,9 @@ func (srv *Server) newCohort(w http.ResponseWriter, r *http.Request, experiment
 			Description:            printer.Sprintf("Description"),
 			DescriptionPlaceholder: printer.Sprintf("e.g. Cohort attending lecture-based instruction"),
 			DescriptionHelp:        printer.Sprintf("Optional. Not visible to participants."),
+			Arm:                    printer.Sprintf("Arm"),
+			NewArm:                 printer.Sprintf("New arm named after the cohort"),
+			ArmHelp:                printer.Sprintf("Cohorts in the same arm receive the same treatment, such as lab sections taught with the same method."),
 			Create:                 printer.Sprintf("Create"),
 		},
 	}
@@ -129,9 +161,16 @@ func (srv *Server) createCohort(w http.ResponseWriter, r *http.Request, experime
 	name := r.FormValue("name")
 	description := r.FormValue("description")
 
+	armID, err := srv.cohortArm(experiment, r.FormValue("arm"), name)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
 	cohort := &edulab.Cohort{
 		PublicID:     srv.newPublicID(3),
 		ExperimentID: experiment.ID,
+		ArmID:        armID,
 		Name:         name,
 		Description:  description,
 	}
@@ -152,24 +191,89 @@ func (srv *Server) updateCohort(w http.ResponseWriter, r *http.Request, experime
 		return
 	}
 
-	name := r.FormValue("name")
-	description := r.FormValue("description")
+	cohort, err := srv.DB.FindCohort(experiment.ID, pid)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
 
-	cohort := edulab.Cohort{
-		Name:        name,
-		Description: description,
-		PublicID:    pid,
+	// Without a selected arm, the cohort keeps its current one.
+	if armPID := r.FormValue("arm"); armPID != "" {
+		arm, err := srv.DB.FindArm(experiment.ID, armPID)
+		if err != nil {
+			srv.renderError(w, r, err)
+			return
+		}
+		cohort.ArmID = arm.ID
 	}
 
+	cohort.Name = r.FormValue("name")
+	cohort.Description = r.FormValue("description")
+
 	err = srv.DB.UpdateCohort(experiment.ID, cohort)
 	if err != nil {
 		srv.renderError(w, r, err)
 		return
 	}
 
+	err = srv.updateCohortPeriods(experiment, pid, r.PostForm)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
 	http.Redirect(w, r, "/experiments/"+experiment.PublicID+"/cohorts", http.StatusSeeOther)
 }
 
+// updateCohortPeriods saves the arms a cohort switches to after each
+// assessment in crossover designs. Assessments missing from the form are
+// left unchanged.
+func (srv *Server) updateCohortPeriods(experiment edulab.Experiment, pid string, form url.Values) error {
+	cohort, err := srv.DB.FindCohort(experiment.ID, pid)
+	if err != nil {
+		return err
+	}
+
+	assessments, err := srv.DB.FindAssessments(experiment.ID)
+	if err != nil {
+		return err
+	}
+
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		return err
+	}
+
+	armIDs := make(map[string]string)
+	for _, a := range arms {
+		armIDs[a.PublicID] = a.ID
+	}
+
+	for _, a := range assessments {
+		values, ok := form["period_"+a.PublicID]
+		if !ok || len(values) == 0 {
+			continue
+		}
+
+		armID, ok := armIDs[values[0]]
+		if !ok && values[0] != "" {
+			return fmt.Errorf("unknown arm %q", values[0])
+		}
+
+		err = srv.DB.UpdateCohortPeriod(edulab.CohortPeriod{
+			ExperimentID: experiment.ID,
+			CohortID:     cohort.ID,
+			AssessmentID: a.ID,
+			ArmID:        armID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (srv *Server) showCohort(w http.ResponseWriter, r *http.Request, experiment edulab.Experiment, pid string) {
 
 	printer, page := srv.i18n(w, r)
@@ -180,6 +284,49 @@ func (srv *Server) showCohort(w http.ResponseWriter, r *http.Request, experiment
 		return
 	}
 
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	assessments, err := srv.DB.FindAssessments(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	cohortPeriods, err := srv.DB.FindCohortPeriods(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	switches := make(map[string]string)
+	for _, p := range cohortPeriods {
+		if p.CohortID == cohort.ID {
+			switches[p.AssessmentID] = p.ArmID
+		}
+	}
+
+	type period struct {
+		Assessment edulab.Assessment
+		Label      string
+		ArmID      string
+	}
+
+	// Cohorts can switch arms after any assessment but the first and the last
+	var periods []period
+	timepoints := result.SortTimepoints(assessments)
+	for i := 1; i < len(timepoints)-1; i++ {
+		a := timepoints[i]
+		periods = append(periods, period{
+			Assessment: a,
+			Label:      printer.Sprintf("After the %s", presenter.AssessmentType(printer, a.Type)),
+			ArmID:      switches[a.ID],
+		})
+	}
+
 	title := printer.Sprintf("Cohort: %s", cohort.Name)
 	page.Title = title
 	page.Partials = []string{"cohort"}
@@ -187,17 +334,26 @@ func (srv *Server) showCohort(w http.ResponseWriter, r *http.Request, experiment
 		Breadcrumbs template.HTML
 		Experiment  edulab.Experiment
 		Cohort      edulab.Cohort
+		Arms        []edulab.Arm
+		Periods     []period
 		Texts       interface{}
 	}{
 		Breadcrumbs: presenter.CohortBreadcrumb(experiment, printer),
 		Experiment:  experiment,
 		Cohort:      cohort,
+		Arms:        arms,
+		Periods:     periods,
 		Texts: struct {
 			Title           string
 			Name            string
 			NameHelp        string
 			Description     string
 			DescriptionHelp string
+			Arm             string
+			ArmHelp         string
+			Crossover       string
+			CrossoverHelp   string
+			SameArm         string
 			Update          string
 		}{
 			Title:           title,
@@ -205,9 +361,54 @@ func (srv *Server) showCohort(w http.ResponseWriter, r *http.Request, experiment
 			NameHelp:        printer.Sprintf("Not visible to participants."),
 			Description:     printer.Sprintf("Description"),
 			DescriptionHelp: printer.Sprintf("Optional. Not visible to participants."),
+			Arm:             printer.Sprintf("Arm"),
+			ArmHelp:         printer.Sprintf("Cohorts in the same arm receive the same treatment, such as lab sections taught with the same method."),
+			Crossover:       printer.Sprintf("Crossover"),
+			CrossoverHelp:   printer.Sprintf("In crossover designs, cohorts swap arms between assessments. Gains of each period are compared by the arm of the cohort during that period."),
+			SameArm:         printer.Sprintf("Keep the same arm"),
 			Update:          printer.Sprintf("Update"),
 		},
 	}
 
 	srv.render(w, page)
 }
+
+// newArm is the arm form value asking for a new arm named after the cohort.
+const newArm = "new"
+
+// cohortArm returns the ID of the arm selected for a new cohort. Selecting
+// newArm creates an arm with the cohort's name, becoming the control arm when
+// it is the first of the experiment. Without a selection, the cohort has no
+// arm.
+func (srv *Server) cohortArm(experiment edulab.Experiment, pid string, name string) (string, error) {
+	switch pid {
+	case "":
+		return "", nil
+	case newArm:
+	default:
+		arm, err := srv.DB.FindArm(experiment.ID, pid)
+		if err != nil {
+			return "", err
+		}
+		return arm.ID, nil
+	}
+
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		return "", err
+	}
+
+	arm := &edulab.Arm{
+		PublicID:     srv.newPublicID(3),
+		ExperimentID: experiment.ID,
+		Name:         name,
+		Control:      len(arms) == 0,
+	}
+
+	err = srv.DB.CreateArm(arm)
+	if err != nil {
+		return "", err
+	}
+
+	return arm.ID, nil
+}
@@ -2,11 +2,15 @@ package server
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
 	"log"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/louisbranch/edulab"
 	"github.com/louisbranch/edulab/web/presenter"
@@ -19,7 +23,11 @@ func (srv *Server) demographicsHandler(w http.ResponseWriter, r *http.Request,
 	log.Print("[DEBUG] Routing demographics")
 
 	if len(segments) < 1 {
-		srv.listDemographics(w, r, experiment)
+		if r.Method == http.MethodPost {
+			srv.createDemographic(w, r, experiment)
+			return
+		}
+		srv.listDemographics(w, r, experiment, nil)
 		return
 	}
 
@@ -27,15 +35,40 @@ func (srv *Server) demographicsHandler(w http.ResponseWriter, r *http.Request,
 	case "preview":
 		srv.previewDemographics(w, r, experiment)
 		return
-	default:
+	case "new":
+		srv.newDemographic(w, r, experiment)
+		return
+	}
+
+	id := segments[0]
+
+	if len(segments) == 2 && r.Method == http.MethodPost {
+		switch segments[1] {
+		case "move":
+			srv.moveDemographic(w, r, experiment, id)
+			return
+		case "delete":
+			srv.deleteDemographic(w, r, experiment, id)
+			return
+		}
+	}
+
+	if len(segments) > 1 {
 		srv.renderNotFound(w, r)
 		return
 	}
+
+	if r.Method == http.MethodPost {
+		srv.updateDemographic(w, r, experiment, id)
+		return
+	}
+	srv.showDemographic(w, r, experiment, id)
 }
 
-// listDemographics lists the demographics for an experiment to the instructor.
+// listDemographics lists the demographics for an experiment to the instructor,
+// with the problems that kept the last change from being made.
 func (srv *Server) listDemographics(w http.ResponseWriter, r *http.Request,
-	experiment edulab.Experiment) {
+	experiment edulab.Experiment, problems []string) {
 
 	printer, page := srv.i18n(w, r)
 
@@ -45,59 +78,559 @@ func (srv *Server) listDemographics(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	options, err := srv.DB.FindDemographicOptions(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	demographics, options = presenter.TranslateDemographics(printer, demographics, options)
+
+	types := make(map[edulab.InputType]string)
+	for _, t := range presenter.QuestionTypes(printer) {
+		types[edulab.InputType(t.Value)] = t.Text
+	}
+
 	title := printer.Sprintf("Demographics")
 	page.Title = title
 	page.Partials = []string{"demographics"}
 	page.Content = struct {
 		Breadcrumbs  template.HTML
 		Experiment   edulab.Experiment
-		Demographics []edulab.Demographic
+		Demographics []presenter.Demographic
+		Types        map[edulab.InputType]string
+		Problems     []string
 		Texts        interface{}
 	}{
 		Breadcrumbs:  presenter.ExperimentBreadcrumb(experiment, printer),
 		Experiment:   experiment,
-		Demographics: demographics,
+		Demographics: presenter.NewDemographics(demographics, options),
+		Types:        types,
+		Problems:     problems,
 		Texts: struct {
 			Title       string
+			Help        string
 			Demographic string
 			Text        string
+			Type        string
+			Options     string
 			Actions     string
+			Edit        string
+			Up          string
+			Down        string
+			Delete      string
 			Add         string
-			ComingSoon  string
 			Preview     string
 			Empty       string
 		}{
 			Title:       title,
+			Help:        printer.Sprintf("Participants answer the demographics before their first assessment. Answers already given to a deleted demographic or option are left out of the results."),
 			Demographic: printer.Sprintf("Demographic"),
 			Text:        printer.Sprintf("Text"),
+			Type:        printer.Sprintf("Type"),
+			Options:     printer.Sprintf("Options"),
 			Actions:     printer.Sprintf("Actions"),
+			Edit:        printer.Sprintf("Edit"),
+			Up:          printer.Sprintf("Move up"),
+			Down:        printer.Sprintf("Move down"),
+			Delete:      printer.Sprintf("Delete"),
 			Add:         printer.Sprintf("Add Demographic"),
-			ComingSoon:  printer.Sprintf("Coming Soon"),
 			Preview:     printer.Sprintf("Preview"),
 			Empty:       printer.Sprintf("No demographics have been added yet."),
 		},
 	}
 
+	if len(problems) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
 	srv.render(w, page)
 }
 
-// previewDemographics displays the demographics preview for the instructor.
-func (srv *Server) previewDemographics(w http.ResponseWriter, r *http.Request,
+// newDemographic displays the form to add a demographic.
+func (srv *Server) newDemographic(w http.ResponseWriter, r *http.Request,
 	experiment edulab.Experiment) {
 
+	srv.demographicForm(w, r, experiment, edulab.Demographic{Type: edulab.InputSingle}, nil, nil)
+}
+
+// showDemographic displays the form to edit a demographic and its options.
+func (srv *Server) showDemographic(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, id string) {
+
+	demographic, err := srv.DB.FindDemographic(experiment.ID, id)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	options, err := srv.demographicOptions(experiment, demographic)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	srv.demographicForm(w, r, experiment, demographic, options, nil)
+}
+
+// demographicForm renders the form of a new demographic, without an ID, or of
+// an existing one, with the problems found when submitting it.
+func (srv *Server) demographicForm(w http.ResponseWriter, r *http.Request, experiment edulab.Experiment,
+	demographic edulab.Demographic, options []edulab.DemographicOption, problems []string) {
+
 	printer, page := srv.i18n(w, r)
 
+	title := printer.Sprintf("New Demographic")
+	action := fmt.Sprintf("/experiments/%s/demographics", experiment.PublicID)
+	submit := printer.Sprintf("Create")
+	if demographic.ID != "" {
+		title = printer.Sprintf("Demographic")
+		action = fmt.Sprintf("/experiments/%s/demographics/%s", experiment.PublicID, demographic.ID)
+		submit = printer.Sprintf("Update")
+	}
+
+	// Blank rows to add options
+	for i := 0; i < 3; i++ {
+		options = append(options, edulab.DemographicOption{})
+	}
+
+	page.Title = title
+	page.Partials = []string{"demographic"}
+	page.Content = struct {
+		Breadcrumbs template.HTML
+		Experiment  edulab.Experiment
+		Demographic edulab.Demographic
+		Options     []edulab.DemographicOption
+		Types       []presenter.QuestionType
+		Action      string
+		Problems    []string
+		Texts       interface{}
+	}{
+		Breadcrumbs: presenter.DemographicBreadcrumb(experiment, printer),
+		Experiment:  experiment,
+		Demographic: demographic,
+		Options:     options,
+		Types:       presenter.QuestionTypes(printer),
+		Action:      action,
+		Problems:    problems,
+		Texts: struct {
+			Title           string
+			Invalid         string
+			Text            string
+			TextHelp        string
+			TextPlaceholder string
+			Type            string
+			Options         string
+			OptionsHelp     string
+			Order           string
+			Submit          string
+		}{
+			Title:           title,
+			Invalid:         printer.Sprintf("The demographic couldn't be saved:"),
+			Text:            printer.Sprintf("Text"),
+			TextHelp:        printer.Sprintf("Markdown supported"),
+			TextPlaceholder: printer.Sprintf("e.g. Which program are you enrolled in?"),
+			Type:            printer.Sprintf("Type"),
+			Options:         printer.Sprintf("Options"),
+			OptionsHelp:     printer.Sprintf("Options are shown by their order. Empty options are removed, and text demographics have none."),
+			Order:           printer.Sprintf("Order"),
+			Submit:          submit,
+		},
+	}
+
+	if len(problems) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	srv.render(w, page)
+}
+
+func (srv *Server) createDemographic(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment) {
+
+	err := r.ParseForm()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
 	demographics, err := srv.DB.FindDemographics(experiment.ID)
 	if err != nil {
 		srv.renderError(w, r, err)
 		return
 	}
 
-	dp := make(map[string]presenter.Demographic)
-	for _, d := range demographics {
-		dp[d.ID] = presenter.Demographic{
-			Demographic: d,
+	demographic := edulab.Demographic{
+		ExperimentID: experiment.ID,
+		Position:     len(demographics) + 1,
+	}
+
+	demographic, options, problems := srv.parseDemographic(w, r, demographic, nil)
+	if len(problems) > 0 {
+		srv.demographicForm(w, r, experiment, demographic, options, problems)
+		return
+	}
+
+	err = srv.DB.Transaction(func(db edulab.Database) error {
+		if err := db.CreateDemographic(&demographic); err != nil {
+			return err
+		}
+
+		for _, o := range options {
+			o.DemographicID = demographic.ID
+			if err := db.CreateDemographicOption(&o); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/experiments/"+experiment.PublicID+"/demographics", http.StatusSeeOther)
+}
+
+// updateDemographic updates a demographic and its options. The demographic
+// the randomization link is stratified by must stay single choice and keep
+// its options, which are the strata of the allocations. Options participants
+// already answered can't be removed or change their text, as the answers refer
+// to them.
+func (srv *Server) updateDemographic(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, id string) {
+
+	printer, _ := srv.i18n(w, r)
+
+	err := r.ParseForm()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	demographic, err := srv.DB.FindDemographic(experiment.ID, id)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	existing, err := srv.demographicOptions(experiment, demographic)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	randomization, err := srv.DB.FindRandomization(experiment.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	answered, err := srv.answeredOptions(experiment, demographic)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	text := demographic.Text
+	demographic, options, problems := srv.parseDemographic(w, r, demographic, existing)
+
+	updated := make(map[string]edulab.DemographicOption)
+	for _, o := range options {
+		updated[o.ID] = o
+	}
+	for _, o := range existing {
+		if !answered[o.ID] {
+			continue
+		}
+		switch u, ok := updated[o.ID]; {
+		case !ok:
+			problems = append(problems, printer.Sprintf("%s was answered by participants and can't be removed.", presenter.Translate(printer, o.Text)))
+		case u.Text != o.Text:
+			problems = append(problems, printer.Sprintf("%s was answered by participants and its text can't be changed.", presenter.Translate(printer, o.Text)))
+		}
+	}
+
+	if randomization.Method == edulab.RandomizationStratified && randomization.DemographicID == demographic.ID {
+		remaining := make(map[string]bool)
+		for _, o := range options {
+			remaining[o.ID] = true
+		}
+		removed := false
+		for _, o := range existing {
+			removed = removed || !remaining[o.ID]
 		}
+
+		switch {
+		case demographic.Type != edulab.InputSingle:
+			problems = append(problems, printer.Sprintf("%s stratifies the randomization and must stay single choice. Change the randomization first.", text))
+		case removed:
+			problems = append(problems, printer.Sprintf("%s stratifies the randomization and can't lose its options. Change the randomization first.", text))
+		}
+	}
+
+	if len(problems) > 0 {
+		srv.demographicForm(w, r, experiment, demographic, options, problems)
+		return
+	}
+
+	err = srv.DB.Transaction(func(db edulab.Database) error {
+		if err := db.UpdateDemographic(demographic); err != nil {
+			return err
+		}
+
+		for _, o := range options {
+			o.DemographicID = demographic.ID
+
+			var err error
+			if o.ID == "" {
+				err = db.CreateDemographicOption(&o)
+			} else {
+				err = db.UpdateDemographicOption(o)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, o := range existing {
+			if _, ok := updated[o.ID]; ok {
+				continue
+			}
+			if err := db.DeleteDemographicOption(demographic.ID, o.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/experiments/"+experiment.PublicID+"/demographics", http.StatusSeeOther)
+}
+
+// parseDemographic reads the text, type and options of a demographic from its
+// form. Options are sorted by their order and numbered again; those left
+// empty are dropped, and so are all of them for text demographics. Options
+// that aren't among the existing ones of the demographic are new.
+func (srv *Server) parseDemographic(w http.ResponseWriter, r *http.Request, demographic edulab.Demographic,
+	existing []edulab.DemographicOption) (edulab.Demographic, []edulab.DemographicOption, []string) {
+
+	printer, _ := srv.i18n(w, r)
+
+	demographic.Text = strings.TrimSpace(r.FormValue("text"))
+	demographic.Type = edulab.InputType(r.FormValue("type"))
+
+	ids := r.Form["option_ids[]"]
+	texts := r.Form["options[]"]
+	positions := r.Form["positions[]"]
+
+	known := make(map[string]bool)
+	for _, o := range existing {
+		known[o.ID] = true
+	}
+
+	var options []edulab.DemographicOption
+	for i, text := range texts {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		o := edulab.DemographicOption{
+			DemographicID: demographic.ID,
+			Text:          text,
+			Position:      i + 1,
+		}
+		if i < len(ids) && known[ids[i]] {
+			o.ID = ids[i]
+		}
+		if i < len(positions) {
+			if p, err := strconv.Atoi(positions[i]); err == nil {
+				o.Position = p
+			}
+		}
+
+		options = append(options, o)
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		return options[i].Position < options[j].Position
+	})
+	for i := range options {
+		options[i].Position = i + 1
+	}
+
+	var problems []string
+	if demographic.Text == "" {
+		problems = append(problems, printer.Sprintf("The demographic has no text."))
+	}
+
+	switch demographic.Type {
+	case edulab.InputSingle, edulab.InputMultiple:
+		if len(options) == 0 {
+			problems = append(problems, printer.Sprintf("Single and multiple choice demographics need at least one option."))
+		}
+	case edulab.InputText:
+		options = nil
+	default:
+		problems = append(problems, printer.Sprintf("The demographic has an unknown type %q.", demographic.Type))
+	}
+
+	return demographic, options, problems
+}
+
+// moveDemographic swaps a demographic with the one before or after it, then
+// numbers the positions of all of them again.
+func (srv *Server) moveDemographic(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, id string) {
+
+	demographics, err := srv.DB.FindDemographics(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	from := -1
+	for i, d := range demographics {
+		if d.ID == id {
+			from = i
+		}
+	}
+	if from == -1 {
+		srv.renderNotFound(w, r)
+		return
+	}
+
+	to := from + 1
+	if r.FormValue("direction") == "up" {
+		to = from - 1
+	}
+
+	if to >= 0 && to < len(demographics) {
+		demographics[from], demographics[to] = demographics[to], demographics[from]
+	}
+
+	err = srv.DB.Transaction(func(db edulab.Database) error {
+		for i, d := range demographics {
+			if d.Position == i+1 {
+				continue
+			}
+
+			d.Position = i + 1
+			if err := db.UpdateDemographic(d); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/experiments/"+experiment.PublicID+"/demographics", http.StatusSeeOther)
+}
+
+// deleteDemographic deletes a demographic with its options, unless the
+// randomization link is stratified by it.
+func (srv *Server) deleteDemographic(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, id string) {
+
+	printer, _ := srv.i18n(w, r)
+
+	demographic, err := srv.DB.FindDemographic(experiment.ID, id)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	randomization, err := srv.DB.FindRandomization(experiment.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	if randomization.Method == edulab.RandomizationStratified && randomization.DemographicID == demographic.ID {
+		srv.listDemographics(w, r, experiment, []string{
+			printer.Sprintf("%s stratifies the randomization and can't be deleted. Change the randomization first.", demographic.Text),
+		})
+		return
+	}
+
+	err = srv.DB.DeleteDemographic(experiment.ID, demographic.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/experiments/"+experiment.PublicID+"/demographics", http.StatusSeeOther)
+}
+
+// answeredOptions returns the options of a demographic that participants
+// selected, by ID.
+func (srv *Server) answeredOptions(experiment edulab.Experiment,
+	demographic edulab.Demographic) (map[string]bool, error) {
+
+	participations, err := srv.DB.FindParticipations(experiment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	answered := make(map[string]bool)
+	for _, p := range participations {
+		if p.Demographics == nil {
+			continue
+		}
+
+		// Answers are an option ID or a list of them
+		var values map[string]interface{}
+		if err := json.Unmarshal(p.Demographics, &values); err != nil {
+			continue
+		}
+
+		switch v := values[demographic.ID].(type) {
+		case string:
+			answered[v] = true
+		case []interface{}:
+			for _, item := range v {
+				if id, ok := item.(string); ok {
+					answered[id] = true
+				}
+			}
+		}
+	}
+	return answered, nil
+}
+
+// demographicOptions returns the options of a demographic, in order.
+func (srv *Server) demographicOptions(experiment edulab.Experiment,
+	demographic edulab.Demographic) ([]edulab.DemographicOption, error) {
+
+	options, err := srv.DB.FindDemographicOptions(experiment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []edulab.DemographicOption
+	for _, o := range options {
+		if o.DemographicID == demographic.ID {
+			result = append(result, o)
+		}
+	}
+	return result, nil
+}
+
+// previewDemographics displays the demographics preview for the instructor.
+func (srv *Server) previewDemographics(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment) {
+
+	printer, page := srv.i18n(w, r)
+
+	demographics, err := srv.DB.FindDemographics(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
 	}
 
 	options, err := srv.DB.FindDemographicOptions(experiment.ID)
@@ -106,6 +639,15 @@ func (srv *Server) previewDemographics(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	demographics, options = presenter.TranslateDemographics(printer, demographics, options)
+
+	dp := make(map[string]presenter.Demographic)
+	for _, d := range demographics {
+		dp[d.ID] = presenter.Demographic{
+			Demographic: d,
+		}
+	}
+
 	for _, o := range options {
 		d, ok := dp[o.DemographicID]
 		if !ok {
@@ -141,13 +683,22 @@ func (srv *Server) previewDemographics(w http.ResponseWriter, r *http.Request,
 	srv.render(w, page)
 }
 
-// showDemographics displays the demographics form for the participant.
+// showDemographics displays the demographics form for the participant, in
+// their language.
 func (srv *Server) showDemographics(w http.ResponseWriter, r *http.Request,
 	experiment edulab.Experiment, cohort edulab.Cohort, participant edulab.Participant,
 	assessment edulab.Assessment, demographics []edulab.Demographic) {
 
 	printer, page := srv.i18n(w, r)
 
+	options, err := srv.DB.FindDemographicOptions(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	demographics, options = presenter.TranslateDemographics(printer, demographics, options)
+
 	dp := make(map[string]presenter.Demographic)
 	for _, d := range demographics {
 		dp[d.ID] = presenter.Demographic{
@@ -155,12 +706,6 @@ func (srv *Server) showDemographics(w http.ResponseWriter, r *http.Request,
 		}
 	}
 
-	options, err := srv.DB.FindDemographicOptions(experiment.ID)
-	if err != nil {
-		srv.renderError(w, r, err)
-		return
-	}
-
 	for _, o := range options {
 		d, ok := dp[o.DemographicID]
 		if !ok {
@@ -254,7 +799,15 @@ func (srv *Server) participateDemographics(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	participant, err := srv.DB.FindParticipant(experiment.ID, token)
+	var participant edulab.Participant
+	if cid == "" {
+		// Stratified randomization links have no cohort until now
+		var cohort edulab.Cohort
+		participant, cohort, err = srv.stratifiedParticipant(experiment, token, inputs)
+		cid = cohort.PublicID
+	} else {
+		participant, err = srv.DB.FindParticipant(experiment.ID, token)
+	}
 	if err != nil {
 		srv.renderError(w, r, err)
 		return
@@ -289,6 +842,8 @@ func (srv *Server) participateDemographics(w http.ResponseWriter, r *http.Reques
 		}
 	}
 
+	srv.trackEvent(participant, assessment, edulab.EventDemographicsSubmitted)
+
 	http.Redirect(w, r, fmt.Sprintf("/%s-%s-%s", eid, cid, aid), http.StatusTemporaryRedirect)
 }
 
@@ -384,5 +939,7 @@ func (srv *Server) participateAssessments(w http.ResponseWriter, r *http.Request
 
 	}
 
+	srv.trackEvent(participant, assessment, edulab.EventAssessmentSubmitted)
+
 	http.Redirect(w, r, fmt.Sprintf("/%s-%s-%s", eid, cid, aid), http.StatusTemporaryRedirect)
 }
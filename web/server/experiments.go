@@ -1,8 +1,11 @@
 package server
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"log"
 	"net/http"
 	"strings"
@@ -35,7 +38,16 @@ func (srv *Server) experimentsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if pid == "new" {
-		srv.newExperimentForm(w, r)
+		srv.newExperimentForm(w, r, nil)
+		return
+	}
+
+	if pid == "import" {
+		if r.Method != http.MethodPost {
+			srv.renderNotFound(w, r)
+			return
+		}
+		srv.importExperiment(w, r)
 		return
 	}
 
@@ -55,21 +67,40 @@ func (srv *Server) experimentsHandler(w http.ResponseWriter, r *http.Request) {
 		case "edit":
 			srv.editExperiment(w, r, experiment)
 			return
+		case "export":
+			srv.exportExperiment(w, r, experiment)
+			return
+		case "clone":
+			if r.Method != http.MethodPost {
+				srv.renderNotFound(w, r)
+				return
+			}
+			srv.cloneExperiment(w, r, experiment)
+			return
 		case "assessments":
 			srv.assessmentsHandler(w, r, experiment, segments[2:])
 			return
 		case "demographics":
 			srv.demographicsHandler(w, r, experiment, segments[2:])
 			return
+		case "arms":
+			srv.armsHandler(w, r, experiment, segments[2:])
+			return
 		case "cohorts":
 			srv.cohortsHandler(w, r, experiment, segments[2:])
 			return
+		case "randomization":
+			srv.randomizationHandler(w, r, experiment, segments[2:])
+			return
 		case "participate":
 			srv.participateHandler(w, r, experiment)
 			return
 		case "results":
 			srv.resultsHandler(w, r, experiment, segments[2:])
 			return
+		case "planner":
+			srv.plannerHandler(w, r, experiment)
+			return
 		default:
 			srv.renderNotFound(w, r)
 			return
@@ -79,16 +110,29 @@ func (srv *Server) experimentsHandler(w http.ResponseWriter, r *http.Request) {
 	srv.showExperiment(w, r, experiment)
 }
 
-func (srv *Server) newExperimentForm(w http.ResponseWriter, r *http.Request) {
+// newExperimentForm displays the form to create an experiment, or to upload
+// one, with the problems found in the last upload.
+func (srv *Server) newExperimentForm(w http.ResponseWriter, r *http.Request, problems wizard.Problems) {
 	printer, page := srv.i18n(w, r)
 
+	var msgs []string
+	for _, p := range problems {
+		if p.Line > 0 {
+			msgs = append(msgs, printer.Sprintf("Line %d: %s", p.Line, p.Message))
+		} else {
+			msgs = append(msgs, p.Message)
+		}
+	}
+
 	page.Title = printer.Sprintf("New Experiment")
 	page.Partials = []string{"experiment_new"}
 	page.Content = struct {
 		Breadcrumbs template.HTML
+		Problems    []string
 		Texts       interface{}
 	}{
 		Breadcrumbs: presenter.HomeBreadcrumbs(printer),
+		Problems:    msgs,
 		Texts: struct {
 			Title                  string
 			Name                   string
@@ -97,6 +141,10 @@ func (srv *Server) newExperimentForm(w http.ResponseWriter, r *http.Request) {
 			DescriptionHelp        string
 			DescriptionPlaceholder string
 			Create                 string
+			Upload                 string
+			UploadHelp             string
+			Invalid                string
+			Preview                string
 		}{
 			Title:                  printer.Sprintf("New Experiment"),
 			Name:                   printer.Sprintf("Name"),
@@ -105,6 +153,145 @@ func (srv *Server) newExperimentForm(w http.ResponseWriter, r *http.Request) {
 			DescriptionHelp:        printer.Sprintf("Optional. Not visible to participants."),
 			DescriptionPlaceholder: printer.Sprintf("e.g. This experiment will compare 2 cohorts of students. One attending a traditional lecture and the other a workshop..."),
 			Create:                 printer.Sprintf("Create"),
+			Upload:                 printer.Sprintf("Upload a YAML File"),
+			UploadHelp:             printer.Sprintf("An experiment in the same format as the ones downloaded from an experiment page. You can review it before it is created."),
+			Invalid:                printer.Sprintf("The file couldn't be imported:"),
+			Preview:                printer.Sprintf("Preview"),
+		},
+	}
+
+	if len(problems) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	srv.render(w, page)
+}
+
+// maxUpload is the size limit of the experiment files uploaded by instructors.
+const maxUpload = 1 << 20
+
+// importExperiment validates an uploaded experiment file and shows what will
+// be created. Once confirmed, the experiment is created from the same file,
+// with new public IDs.
+func (srv *Server) importExperiment(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUpload)
+
+	var content []byte
+
+	file, _, err := r.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	switch {
+	case err == nil:
+		defer file.Close()
+		content, err = io.ReadAll(file)
+		if err != nil {
+			srv.renderError(w, r, err)
+			return
+		}
+	case errors.As(err, &tooLarge):
+		printer, _ := srv.i18n(w, r)
+		srv.newExperimentForm(w, r, wizard.Problems{{Message: printer.Sprintf("the file is larger than 1 MB")}})
+		return
+	case errors.Is(err, http.ErrMissingFile), errors.Is(err, http.ErrNotMultipart):
+		content = []byte(r.PostFormValue("yaml"))
+	default:
+		srv.renderError(w, r, err)
+		return
+	}
+
+	experimentData, err := wizard.Parse(bytes.NewReader(content))
+	var problems wizard.Problems
+	if errors.As(err, &problems) {
+		srv.newExperimentForm(w, r, problems)
+		return
+	}
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	// Uploaded files can't replace existing experiments or simulate
+	// participants
+	experimentData.ForceDelete = false
+	experimentData.BootstrapConfig = wizard.BootstrapConfig{}
+
+	if r.PostFormValue("confirm") == "" {
+		srv.previewExperiment(w, r, experimentData, string(content))
+		return
+	}
+
+	experimentData.Rename(func() string {
+		return srv.newPublicID(2)
+	})
+
+	err = srv.DB.Transaction(func(db edulab.Database) error {
+		return wizard.Import(db, experimentData)
+	})
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	uri := fmt.Sprintf("/experiments/%s", experimentData.PublicID)
+	http.Redirect(w, r, uri, http.StatusSeeOther)
+}
+
+// previewExperiment shows what an uploaded experiment file contains before it
+// is created.
+func (srv *Server) previewExperiment(w http.ResponseWriter, r *http.Request,
+	experimentData wizard.Experiment, content string) {
+
+	printer, page := srv.i18n(w, r)
+
+	type assessment struct {
+		Type      string
+		Questions int
+	}
+
+	var assessments []assessment
+	questions := 0
+	for _, a := range experimentData.Assessments {
+		assessments = append(assessments, assessment{
+			Type:      presenter.AssessmentType(printer, a.Type),
+			Questions: len(a.Questions),
+		})
+		questions += len(a.Questions)
+	}
+
+	title := printer.Sprintf("Import Experiment")
+	page.Title = title
+	page.Partials = []string{"experiment_import"}
+	page.Content = struct {
+		Breadcrumbs template.HTML
+		Experiment  wizard.Experiment
+		Assessments []assessment
+		Questions   int
+		YAML        string
+		Texts       interface{}
+	}{
+		Breadcrumbs: presenter.HomeBreadcrumbs(printer),
+		Experiment:  experimentData,
+		Assessments: assessments,
+		Questions:   questions,
+		YAML:        content,
+		Texts: struct {
+			Title       string
+			Assessment  string
+			Questions   string
+			Assessments string
+			Arms        string
+			Cohorts     string
+			Create      string
+			Cancel      string
+		}{
+			Title:       title,
+			Assessment:  printer.Sprintf("Assessment"),
+			Questions:   printer.Sprintf("Questions"),
+			Assessments: printer.Sprintf("Assessments"),
+			Arms:        printer.Sprintf("Arms"),
+			Cohorts:     printer.Sprintf("Cohorts"),
+			Create:      printer.Sprintf("Create"),
+			Cancel:      printer.Sprintf("Cancel"),
 		},
 	}
 
@@ -160,16 +347,31 @@ func (srv *Server) createExperiment(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	cohorts := []string{
+	arms := []string{
 		printer.Sprintf("Control"),
 		printer.Sprintf("Intervention"),
 	}
 
-	for _, name := range cohorts {
+	// Each arm starts with a cohort of the same name
+	for i, name := range arms {
+		arm := &edulab.Arm{
+			PublicID:     srv.newPublicID(3),
+			Name:         name,
+			ExperimentID: experiment.ID,
+			Control:      i == 0,
+		}
+
+		err = srv.DB.CreateArm(arm)
+		if err != nil {
+			srv.renderError(w, r, err)
+			return
+		}
+
 		cohort := &edulab.Cohort{
 			PublicID:     srv.newPublicID(2),
 			Name:         name,
 			ExperimentID: experiment.ID,
+			ArmID:        arm.ID,
 		}
 
 		err = srv.DB.CreateCohort(cohort)
@@ -237,6 +439,10 @@ func (srv *Server) editExperiment(w http.ResponseWriter, r *http.Request, experi
 			DescriptionHelp        string
 			DescriptionPlaceholder string
 			Update                 string
+			Clone                  string
+			CloneHelp              string
+			CloneName              string
+			Export                 string
 		}{
 			Edit:                   printer.Sprintf("Edit Experiment"),
 			Name:                   printer.Sprintf("Name"),
@@ -245,6 +451,10 @@ func (srv *Server) editExperiment(w http.ResponseWriter, r *http.Request, experi
 			DescriptionHelp:        printer.Sprintf("Optional. Not visible to participants."),
 			DescriptionPlaceholder: printer.Sprintf("e.g. This experiment will compare 2 cohorts of students. One attending a traditional lecture and the other a workshop..."),
 			Update:                 printer.Sprintf("Update"),
+			Clone:                  printer.Sprintf("Clone Experiment"),
+			CloneHelp:              printer.Sprintf("Copy the assessments, arms, cohorts, demographics and randomization settings into a new experiment, e.g. to run the same study next term. Participants and their responses are not copied."),
+			CloneName:              printer.Sprintf("%s (copy)", experiment.Name),
+			Export:                 printer.Sprintf("Download YAML"),
 		},
 	}
 
@@ -273,6 +483,13 @@ func (srv *Server) showExperiment(w http.ResponseWriter, r *http.Request, experi
 			Publish       string
 			Results       string
 			LearningGains string
+			Planner       string
+			Subgroups     string
+			Baseline      string
+			Attrition     string
+			Arms          string
+			Randomization string
+			Timepoints    string
 		}{
 			Experiment:    printer.Sprintf("Experiment %s", experiment.Name),
 			Settings:      printer.Sprintf("Settings"),
@@ -283,6 +500,13 @@ func (srv *Server) showExperiment(w http.ResponseWriter, r *http.Request, experi
 			Publish:       printer.Sprintf("Participation Links"),
 			Results:       printer.Sprintf("Results"),
 			LearningGains: printer.Sprintf("Learning Gains"),
+			Planner:       printer.Sprintf("Sample Size Planner"),
+			Subgroups:     printer.Sprintf("Subgroups"),
+			Baseline:      printer.Sprintf("Baseline Equivalence"),
+			Attrition:     printer.Sprintf("Attrition"),
+			Arms:          printer.Sprintf("Arms"),
+			Randomization: printer.Sprintf("Randomization"),
+			Timepoints:    printer.Sprintf("Timepoints"),
 		},
 	}
 
@@ -309,3 +533,44 @@ func (srv *Server) updateExperiment(w http.ResponseWriter, r *http.Request, expe
 	uri := fmt.Sprintf("/experiments/%s", experiment.PublicID)
 	http.Redirect(w, r, uri, http.StatusFound)
 }
+
+// cloneExperiment copies the design of an experiment into a new one, without
+// its participants.
+func (srv *Server) cloneExperiment(w http.ResponseWriter, r *http.Request, experiment edulab.Experiment) {
+	err := r.ParseForm()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	// The public ID is drawn by the clone, again if it is already taken
+	clone := edulab.Experiment{
+		Name:        r.PostForm.Get("name"),
+		Description: experiment.Description,
+	}
+
+	err = wizard.Clone(srv.DB, experiment, &clone, srv.Random)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	uri := fmt.Sprintf("/experiments/%s", clone.PublicID)
+	http.Redirect(w, r, uri, http.StatusSeeOther)
+}
+
+// exportExperiment downloads the design of an experiment in the YAML format
+// used to import experiments.
+func (srv *Server) exportExperiment(w http.ResponseWriter, r *http.Request, experiment edulab.Experiment) {
+	var buf bytes.Buffer
+
+	err := wizard.ExportYAML(srv.DB, experiment.PublicID, &buf)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", experiment.PublicID+".yaml"))
+	w.Write(buf.Bytes())
+}
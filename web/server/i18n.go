@@ -10,6 +10,13 @@ import (
 	"github.com/louisbranch/edulab/web"
 )
 
+// locales are the languages pages are shown in, named in their own language.
+// Assessments can be translated to any of them.
+var locales = []web.Language{
+	{Code: "en", Name: "English"},
+	{Code: "pt-BR", Name: "Português"},
+}
+
 func (s *Server) i18n(w http.ResponseWriter, r *http.Request) (*message.Printer, web.Page) {
 
 	var lang language.Tag
@@ -35,6 +42,7 @@ func (s *Server) i18n(w http.ResponseWriter, r *http.Request) (*message.Printer,
 	default:
 		lang = language.MustParse("en")
 	}
+	code := lang.String()
 
 	printer := message.NewPrinter(lang)
 
@@ -52,6 +60,7 @@ func (s *Server) i18n(w http.ResponseWriter, r *http.Request) (*message.Printer,
 	}
 
 	page := web.Page{
+		Lang:      code,
 		Header:    printer.Sprintf("EduLab"),
 		Website:   printer.Sprintf("EduLab"),
 		About:     printer.Sprintf("About"),
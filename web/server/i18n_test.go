@@ -31,6 +31,10 @@ func TestI18n(t *testing.T) {
 		t.Errorf("expected Sobre, got %s", page.About)
 	}
 
+	if page.Lang != "pt-BR" {
+		t.Errorf("expected pt-BR, got %s", page.Lang)
+	}
+
 	cookies := res.Result().Cookies()
 
 	if len(cookies) != 1 {
@@ -31,6 +31,12 @@ func (srv *Server) participateHandler(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	randomization, err := srv.findRandomization(experiment)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
 	title := printer.Sprintf("Participation Links")
 	page.Title = title
 	page.Partials = []string{"participate"}
@@ -40,6 +46,7 @@ func (srv *Server) participateHandler(w http.ResponseWriter, r *http.Request,
 		edulab.Experiment
 		Cohorts     []edulab.Cohort
 		Assessments []presenter.Assessment
+		Randomized  bool
 		Texts       interface{}
 	}{
 		Breadcrumbs: presenter.ExperimentBreadcrumb(experiment, printer),
@@ -47,11 +54,14 @@ func (srv *Server) participateHandler(w http.ResponseWriter, r *http.Request,
 		Experiment:  experiment,
 		Cohorts:     cohorts,
 		Assessments: presenter.NewAssessments(assessments, printer),
+		Randomized:  randomization.Method != "",
 		Texts: struct {
-			Title   string
-			Warning string
+			Title         string
+			Warning       string
+			Randomization string
 		}{
-			Title: title,
+			Title:         title,
+			Randomization: printer.Sprintf("Randomization is enabled. Share the randomization links so participants are assigned to a cohort at random."),
 			Warning: printer.Sprintf(`Warning: This assessment doesn't have any questions yet.
 Please add questions before sharing the link with participants.`),
 		},
@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"html/template"
 	"log"
 	"net/http"
@@ -13,6 +12,7 @@ import (
 
 	"github.com/louisbranch/edulab"
 	"github.com/louisbranch/edulab/web/presenter"
+	"github.com/louisbranch/edulab/wizard"
 )
 
 // participationsHandler handles the participations subroutes in an experiment for the participant.
@@ -22,6 +22,11 @@ func (srv *Server) participationsHandler(w http.ResponseWriter, r *http.Request,
 	log.Print("[DEBUG] Routing participations")
 
 	pids := strings.Split(pid, "-")
+	if len(pids) == 2 {
+		srv.randomizeParticipant(w, r, pids[0], pids[1])
+		return
+	}
+
 	if len(pids) != 3 {
 		srv.renderNotFound(w, r)
 		return
@@ -50,23 +55,23 @@ func (srv *Server) participationsHandler(w http.ResponseWriter, r *http.Request,
 	log.Printf("[DEBUG] Initiated participation for experiment %s, cohort %s, assessment %s",
 		experiment.PublicID, cohort.PublicID, assessment.PublicID)
 
-	var token string
-	at, err := r.Cookie("access_token")
-	if err == nil {
-		token = at.Value
-	} else {
-		b := make([]byte, 8)
-		srv.Random.Read(b)
-		token = fmt.Sprintf("%x", b)
-		http.SetCookie(w, &http.Cookie{
-			Name:   "access_token",
-			Value:  token,
-			Path:   "/",
-			MaxAge: 24 * 60 * 60 * 180, // 180 days
-		})
-	}
+	token := srv.accessToken(w, r)
 
 	participant, err := srv.DB.FindParticipant(experiment.ID, token)
+	if err == nil && participant.CohortID != cohort.ID {
+		_, err := srv.DB.FindAllocation(experiment.ID, participant.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			srv.renderError(w, r, err)
+			return
+		}
+
+		// Randomized participants keep their allocated cohort
+		if err == nil {
+			srv.redirectToCohort(w, r, experiment, participant, assessment)
+			return
+		}
+	}
+
 	if errors.Is(err, sql.ErrNoRows) {
 		participant = edulab.Participant{
 			PublicID:     srv.newPublicID(3),
@@ -104,6 +109,8 @@ func (srv *Server) participationsHandler(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	srv.trackEvent(participant, assessment, edulab.EventLinkOpened)
+
 	if participation.Demographics == nil {
 		demographics, err := srv.DB.FindDemographics(experiment.ID)
 		if err != nil {
@@ -117,9 +124,48 @@ func (srv *Server) participationsHandler(w http.ResponseWriter, r *http.Request,
 		}
 	}
 
+	srv.trackEvent(participant, assessment, edulab.EventAssessmentStarted)
 	srv.showAssessment(w, r, experiment, cohort, participant, assessment)
 }
 
+// accessToken returns the participant access token stored in a cookie,
+// creating a new one for first-time participants.
+func (srv *Server) accessToken(w http.ResponseWriter, r *http.Request) string {
+	at, err := r.Cookie("access_token")
+	if err == nil {
+		return at.Value
+	}
+
+	token := wizard.AccessToken(srv.Random)
+	http.SetCookie(w, &http.Cookie{
+		Name:   "access_token",
+		Value:  token,
+		Path:   "/",
+		MaxAge: 24 * 60 * 60 * 180, // 180 days
+	})
+
+	return token
+}
+
+// trackEvent records a participant event for the attrition funnel. Failures
+// are only logged so they never block a participant.
+func (srv *Server) trackEvent(participant edulab.Participant, assessment edulab.Assessment,
+	eventType edulab.EventType) {
+
+	event := edulab.ParticipantEvent{
+		ExperimentID:  participant.ExperimentID,
+		CohortID:      participant.CohortID,
+		AssessmentID:  assessment.ID,
+		ParticipantID: participant.ID,
+		Type:          eventType,
+	}
+
+	err := srv.DB.CreateParticipantEvent(&event)
+	if err != nil {
+		log.Printf("[ERROR] Failed to track %s event: %v", eventType, err)
+	}
+}
+
 // participationFinished displays the participation finished page.
 func (srv *Server) participationFinished(w http.ResponseWriter, r *http.Request,
 	experiment edulab.Experiment, participant edulab.Participant,
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/stats"
+	"github.com/louisbranch/edulab/web/presenter"
+)
+
+// plannerHandler displays the sample size planner for an experiment to the instructor.
+func (srv *Server) plannerHandler(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment) {
+
+	log.Print("[DEBUG] Routing planner")
+
+	cohorts, err := srv.DB.FindCohorts(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+
+	numCohorts := len(cohorts)
+	if numCohorts < 2 {
+		numCohorts = 2
+	}
+
+	effectSize := parseFloat(query.Get("effect_size"), 0.5)
+	alpha := parseFloat(query.Get("alpha"), 0.05)
+	power := parseFloat(query.Get("power"), 0.8)
+	numCohorts = int(parseFloat(query.Get("cohorts"), float64(numCohorts)))
+
+	plan := stats.RequiredSampleSize(effectSize, alpha, power, numCohorts)
+
+	printer, page := srv.i18n(w, r)
+
+	title := printer.Sprintf("Sample Size Planner")
+	page.Title = title
+	page.Partials = []string{"planner"}
+	page.Content = struct {
+		Breadcrumbs template.HTML
+		Experiment  edulab.Experiment
+		Plan        stats.Power
+		Texts       interface{}
+	}{
+		Breadcrumbs: presenter.ExperimentBreadcrumb(experiment, printer),
+		Experiment:  experiment,
+		Plan:        plan,
+		Texts: struct {
+			Title          string
+			Help           string
+			EffectSize     string
+			EffectSizeHelp string
+			Alpha          string
+			AlphaHelp      string
+			Power          string
+			PowerHelp      string
+			Cohorts        string
+			Calculate      string
+			PerCohort      string
+			Total          string
+			Invalid        string
+		}{
+			Title:          title,
+			Help:           printer.Sprintf("Estimate how many participants each cohort needs before running the experiment."),
+			EffectSize:     printer.Sprintf("Expected effect size (Cohen's d)"),
+			EffectSizeHelp: printer.Sprintf("0.2 is small, 0.5 is medium and 0.8 is large."),
+			Alpha:          printer.Sprintf("Significance level (alpha)"),
+			AlphaHelp:      printer.Sprintf("Adjusted for multiple comparisons when there are more than two cohorts."),
+			Power:          printer.Sprintf("Power"),
+			PowerHelp:      printer.Sprintf("Probability of detecting the effect if it exists. 0.8 is the usual target."),
+			Cohorts:        printer.Sprintf("Number of cohorts"),
+			Calculate:      printer.Sprintf("Calculate"),
+			PerCohort:      printer.Sprintf("Participants per cohort: %d", plan.PerCohort),
+			Total:          printer.Sprintf("Total participants: %d", plan.Total),
+			Invalid:        printer.Sprintf("Could not calculate the sample size for these values."),
+		},
+	}
+
+	srv.render(w, page)
+}
+
+// parseFloat parses a form value, returning the fallback when it is missing or invalid.
+func parseFloat(value string, fallback float64) float64 {
+	if value == "" {
+		return fallback
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback
+	}
+
+	return f
+}
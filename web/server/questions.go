@@ -5,6 +5,7 @@ import (
 	"html/template"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -18,7 +19,7 @@ func (srv *Server) questionsHandler(w http.ResponseWriter, r *http.Request,
 	log.Print("[DEBUG] web/server/questions.go: handling questions")
 
 	if len(segments) < 1 {
-		if r.Method != http.MethodPost {
+		if r.Method == http.MethodPost {
 			srv.createQuestion(w, r, experiment, assessment)
 			return
 		} else {
@@ -31,37 +32,87 @@ func (srv *Server) questionsHandler(w http.ResponseWriter, r *http.Request,
 
 	switch pid {
 	case "new":
-		srv.newQuestionForm(w, r, experiment, assessment)
+		srv.newQuestionForm(w, r, experiment, assessment, edulab.Question{}, nil, nil)
 		return
-	default:
-		srv.showQuestion(w, r, experiment, assessment, pid)
+	}
+
+	if len(segments) == 2 && segments[1] == "translations" {
+		if r.Method == http.MethodPost {
+			srv.updateTranslations(w, r, experiment, assessment, pid)
+			return
+		}
+		srv.showTranslations(w, r, experiment, assessment, pid)
+		return
+	}
+
+	if len(segments) > 1 {
+		srv.renderNotFound(w, r)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		srv.updateQuestion(w, r, experiment, assessment, pid)
 		return
 	}
+	srv.showQuestion(w, r, experiment, assessment, pid)
 }
 
+// newQuestionForm renders the form of a new question, filled with the
+// submitted question and choices when they couldn't be created.
 func (srv *Server) newQuestionForm(w http.ResponseWriter, r *http.Request,
-	experiment edulab.Experiment, assessment edulab.Assessment) {
+	experiment edulab.Experiment, assessment edulab.Assessment,
+	question edulab.Question, choices []edulab.QuestionChoice, problems []string) {
 
 	printer, page := srv.i18n(w, r)
 
+	anchors, err := srv.questionAnchors(experiment)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	placeholders := []string{
+		printer.Sprintf("e.g. The tilt of Earth's axis"),
+		printer.Sprintf("e.g. The distance from the Sun"),
+		printer.Sprintf("e.g. The Earth's elliptical orbit"),
+		printer.Sprintf("e.g. The Earth's rotation"),
+		printer.Sprintf("e.g. The Earth's revolution"),
+	}
+
+	// Every placeholder has a choice, empty unless it was submitted
+	for len(choices) < len(placeholders) {
+		choices = append(choices, edulab.QuestionChoice{})
+	}
+
 	page.Title = printer.Sprintf("New Question")
 	page.Partials = []string{"question_new"}
 	page.Content = struct {
 		Breadcrumbs   template.HTML
 		Experiment    edulab.Experiment
 		Assessment    edulab.Assessment
+		Question      edulab.Question
+		Choices       []edulab.QuestionChoice
 		QuestionTypes []presenter.QuestionType
+		Anchors       []string
+		Problems      []string
 		Texts         interface{}
 	}{
 		Breadcrumbs:   presenter.AssessmentBreadcrumb(experiment, assessment, printer),
 		Experiment:    experiment,
 		Assessment:    assessment,
+		Question:      question,
+		Choices:       choices,
 		QuestionTypes: presenter.QuestionTypes(printer),
+		Anchors:       anchors,
+		Problems:      problems,
 		Texts: struct {
+			Invalid            string
 			Text               string
 			TextHelp           string
 			TextPlaceholder    string
 			Type               string
+			Anchor             string
+			AnchorHelp         string
 			Choices            string
 			ChoicesHelp        string
 			ChoicePlaceholders []string
@@ -69,52 +120,54 @@ func (srv *Server) newQuestionForm(w http.ResponseWriter, r *http.Request,
 			Create             string
 			NewQuestion        string
 		}{
-			Text:            printer.Sprintf("Text"),
-			TextHelp:        printer.Sprintf("Markdown supported"),
-			TextPlaceholder: printer.Sprintf("e.g. What is the best explanation for the cause of Earth's seasons?"),
-			Type:            printer.Sprintf("Type"),
-			Choices:         printer.Sprintf("Choices"),
-			ChoicesHelp:     printer.Sprintf("Markdown supported. Empty choices will be ignored."),
-			ChoicePlaceholders: []string{
-				printer.Sprintf("e.g. The tilt of Earth's axis"),
-				printer.Sprintf("e.g. The distance from the Sun"),
-				printer.Sprintf("e.g. The Earth's elliptical orbit"),
-				printer.Sprintf("e.g. The Earth's rotation"),
-				printer.Sprintf("e.g. The Earth's revolution"),
-			},
-			Correct:     printer.Sprintf("Correct"),
-			Create:      printer.Sprintf("Create"),
-			NewQuestion: printer.Sprintf("New Question"),
+			Invalid:            printer.Sprintf("The question couldn't be saved:"),
+			Text:               printer.Sprintf("Text"),
+			TextHelp:           printer.Sprintf("Markdown supported"),
+			TextPlaceholder:    printer.Sprintf("e.g. What is the best explanation for the cause of Earth's seasons?"),
+			Type:               printer.Sprintf("Type"),
+			Anchor:             printer.Sprintf("Link ID"),
+			AnchorHelp:         printer.Sprintf("Optional. Questions with the same link ID are compared across assessments, even if their texts differ. Without one, questions are matched by their exact text."),
+			Choices:            printer.Sprintf("Choices"),
+			ChoicesHelp:        printer.Sprintf("Markdown supported. Empty choices will be ignored."),
+			ChoicePlaceholders: placeholders,
+			Correct:            printer.Sprintf("Correct"),
+			Create:             printer.Sprintf("Create"),
+			NewQuestion:        printer.Sprintf("New Question"),
 		},
 	}
 
+	if len(problems) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
 	srv.render(w, page)
 }
 
 func (srv *Server) showQuestion(w http.ResponseWriter, r *http.Request,
 	experiment edulab.Experiment, assessment edulab.Assessment, pid string) {
 
-	printer, page := srv.i18n(w, r)
-
-	question, err := srv.DB.FindQuestion(assessment.ID, pid)
+	question, qchoices, err := srv.findQuestion(assessment, pid)
 	if err != nil {
 		srv.renderError(w, r, err)
 		return
 	}
 
-	choices, err := srv.DB.FindQuestionChoices(question.AssessmentID)
+	srv.questionForm(w, r, experiment, assessment, question, qchoices, nil)
+}
+
+// questionForm renders the form of an existing question, with the problems
+// that kept its changes from being saved.
+func (srv *Server) questionForm(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, assessment edulab.Assessment,
+	question edulab.Question, qchoices []edulab.QuestionChoice, problems []string) {
+
+	printer, page := srv.i18n(w, r)
+
+	anchors, err := srv.questionAnchors(experiment)
 	if err != nil {
 		srv.renderError(w, r, err)
 		return
 	}
 
-	var qchoices []edulab.QuestionChoice
-	for _, choice := range choices {
-		if choice.QuestionID == question.ID {
-			qchoices = append(qchoices, choice)
-		}
-	}
-
 	page.Title = printer.Sprintf("Question: %s", question.Text[:min(len(question.Text), 20)])
 	page.Partials = []string{"question"}
 	page.Content = struct {
@@ -124,6 +177,8 @@ func (srv *Server) showQuestion(w http.ResponseWriter, r *http.Request,
 		Question      edulab.Question
 		Choices       []edulab.QuestionChoice
 		QuestionTypes []presenter.QuestionType
+		Anchors       []string
+		Problems      []string
 		Texts         interface{}
 	}{
 		Breadcrumbs:   presenter.AssessmentBreadcrumb(experiment, assessment, printer),
@@ -132,24 +187,31 @@ func (srv *Server) showQuestion(w http.ResponseWriter, r *http.Request,
 		Question:      question,
 		Choices:       qchoices,
 		QuestionTypes: presenter.QuestionTypes(printer),
+		Anchors:       anchors,
+		Problems:      problems,
 		Texts: struct {
 			Title              string
+			Invalid            string
 			Text               string
 			TextHelp           string
 			TextPlaceholder    string
 			Type               string
+			Anchor             string
+			AnchorHelp         string
 			Choices            string
 			ChoicesHelp        string
 			ChoicePlaceholders []string
 			Correct            string
 			Submit             string
-			ComingSoon         string
 		}{
 			Title:           printer.Sprintf("Question"),
+			Invalid:         printer.Sprintf("The question couldn't be saved:"),
 			Text:            printer.Sprintf("Text"),
 			TextHelp:        printer.Sprintf("Markdown supported"),
 			TextPlaceholder: printer.Sprintf("e.g. What is the best explanation for the cause of Earth's seasons?"),
 			Type:            printer.Sprintf("Type"),
+			Anchor:          printer.Sprintf("Link ID"),
+			AnchorHelp:      printer.Sprintf("Optional. Questions with the same link ID are compared across assessments, even if their texts differ. Without one, questions are matched by their exact text."),
 			Choices:         printer.Sprintf("Choices"),
 			ChoicesHelp:     printer.Sprintf("Markdown supported. Empty choices will be ignored."),
 			ChoicePlaceholders: []string{
@@ -159,12 +221,14 @@ func (srv *Server) showQuestion(w http.ResponseWriter, r *http.Request,
 				printer.Sprintf("e.g. The Earth's rotation"),
 				printer.Sprintf("e.g. The Earth's revolution"),
 			},
-			Correct:    printer.Sprintf("Correct"),
-			Submit:     printer.Sprintf("Update"),
-			ComingSoon: printer.Sprintf("Coming soon"),
+			Correct: printer.Sprintf("Correct"),
+			Submit:  printer.Sprintf("Update"),
 		},
 	}
 
+	if len(problems) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
 	srv.render(w, page)
 }
 
@@ -196,6 +260,22 @@ func (srv *Server) createQuestion(w http.ResponseWriter, r *http.Request,
 		AssessmentID: assessment.ID,
 		Text:         text,
 		Type:         edulab.InputType(qtype),
+		Anchor:       strings.TrimSpace(r.FormValue("anchor")),
+	}
+
+	problems, err := srv.questionProblems(w, r, question)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	if len(problems) > 0 {
+		submitted := make([]edulab.QuestionChoice, len(choices))
+		for i, choice := range choices {
+			submitted[i] = edulab.QuestionChoice{Text: choice, IsCorrect: correct[i]}
+		}
+		srv.newQuestionForm(w, r, experiment, assessment, question, submitted, problems)
+		return
 	}
 
 	err = srv.DB.CreateQuestion(&question)
@@ -225,3 +305,170 @@ func (srv *Server) createQuestion(w http.ResponseWriter, r *http.Request,
 	uri := fmt.Sprintf("/experiments/%s/assessments/%s", experiment.PublicID, assessment.PublicID)
 	http.Redirect(w, r, uri, http.StatusFound)
 }
+
+func (srv *Server) updateQuestion(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, assessment edulab.Assessment, pid string) {
+
+	err := r.ParseForm()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	question, qchoices, err := srv.findQuestion(assessment, pid)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	question.Text = r.FormValue("text")
+	question.Type = edulab.InputType(r.FormValue("type"))
+	question.Anchor = strings.TrimSpace(r.FormValue("anchor"))
+
+	ids := r.Form["choice_ids[]"]
+	choices := r.Form["choices[]"]
+
+	correct := make(map[int]bool)
+	for _, indexStr := range r.Form["correct[]"] {
+		index, err := strconv.Atoi(indexStr)
+		if err == nil {
+			correct[index] = true
+		}
+	}
+
+	problems, err := srv.questionProblems(w, r, question)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	if len(problems) > 0 {
+		for i := range qchoices {
+			if i < len(choices) {
+				qchoices[i].Text = choices[i]
+			}
+			qchoices[i].IsCorrect = correct[i]
+		}
+		srv.questionForm(w, r, experiment, assessment, question, qchoices, problems)
+		return
+	}
+
+	err = srv.DB.UpdateQuestion(question)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	// Choices can be reworded but not removed, as participants may have
+	// already selected them
+	for i, id := range ids {
+		if i >= len(choices) || strings.Trim(choices[i], " ") == "" {
+			continue
+		}
+
+		qc := edulab.QuestionChoice{
+			ID:         id,
+			QuestionID: question.ID,
+			Text:       choices[i],
+			IsCorrect:  correct[i],
+		}
+
+		err = srv.DB.UpdateQuestionChoice(qc)
+		if err != nil {
+			srv.renderError(w, r, err)
+			return
+		}
+	}
+
+	uri := fmt.Sprintf("/experiments/%s/assessments/%s", experiment.PublicID, assessment.PublicID)
+	http.Redirect(w, r, uri, http.StatusFound)
+}
+
+// questionProblems lists the reasons a question can't be saved: an unknown
+// input type, or a link ID already used by another question of the same
+// assessment, which would make the comparison pairs ambiguous.
+func (srv *Server) questionProblems(w http.ResponseWriter, r *http.Request,
+	question edulab.Question) ([]string, error) {
+
+	printer, _ := srv.i18n(w, r)
+
+	var problems []string
+
+	switch question.Type {
+	case edulab.InputSingle, edulab.InputMultiple, edulab.InputText:
+	default:
+		problems = append(problems, printer.Sprintf("The question has an unknown type %q.", question.Type))
+	}
+
+	if question.Anchor == "" {
+		return problems, nil
+	}
+
+	questions, err := srv.DB.FindQuestions(question.AssessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, q := range questions {
+		if q.ID != question.ID && q.Anchor == question.Anchor {
+			problems = append(problems, printer.Sprintf("Another question of this assessment already has the link ID %q.", question.Anchor))
+			break
+		}
+	}
+
+	return problems, nil
+}
+
+// findQuestion returns a question of an assessment with its choices.
+func (srv *Server) findQuestion(assessment edulab.Assessment,
+	pid string) (edulab.Question, []edulab.QuestionChoice, error) {
+
+	question, err := srv.DB.FindQuestion(assessment.ID, pid)
+	if err != nil {
+		return question, nil, err
+	}
+
+	choices, err := srv.DB.FindQuestionChoices(assessment.ID)
+	if err != nil {
+		return question, nil, err
+	}
+
+	var qchoices []edulab.QuestionChoice
+	for _, c := range choices {
+		if c.QuestionID == question.ID {
+			qchoices = append(qchoices, c)
+		}
+	}
+
+	return question, qchoices, nil
+}
+
+// questionAnchors returns the link IDs already used in an experiment, to
+// suggest them when linking questions across assessments.
+func (srv *Server) questionAnchors(experiment edulab.Experiment) ([]string, error) {
+	assessments, err := srv.DB.FindAssessments(experiment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var anchors []string
+	for _, a := range assessments {
+		questions, err := srv.DB.FindQuestions(a.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, q := range questions {
+			if q.Anchor == "" || seen[q.Anchor] {
+				continue
+			}
+			seen[q.Anchor] = true
+			anchors = append(anchors, q.Anchor)
+		}
+	}
+
+	sort.Strings(anchors)
+
+	return anchors, nil
+}
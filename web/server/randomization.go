@@ -0,0 +1,433 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/randomize"
+	"github.com/louisbranch/edulab/web/presenter"
+)
+
+func (srv *Server) randomizationHandler(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, segments []string) {
+
+	log.Print("[DEBUG] Routing randomization")
+
+	if len(segments) > 0 {
+		srv.renderNotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		srv.showRandomization(w, r, experiment)
+	case http.MethodPost:
+		srv.updateRandomization(w, r, experiment)
+	default:
+		srv.renderNotFound(w, r)
+	}
+}
+
+// findRandomization returns the randomization of an experiment, which is
+// disabled when it has never been configured.
+func (srv *Server) findRandomization(experiment edulab.Experiment) (edulab.Randomization, error) {
+	randomization, err := srv.DB.FindRandomization(experiment.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return edulab.Randomization{ExperimentID: experiment.ID}, nil
+	}
+	return randomization, err
+}
+
+func (srv *Server) showRandomization(w http.ResponseWriter, r *http.Request, experiment edulab.Experiment) {
+	printer, page := srv.i18n(w, r)
+
+	randomization, err := srv.findRandomization(experiment)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	cohorts, err := srv.DB.FindCohorts(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	assessments, err := srv.DB.FindAssessments(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	demographics, err := srv.DB.FindDemographics(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	options, err := srv.DB.FindDemographicOptions(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	demographics, options = presenter.TranslateDemographics(printer, demographics, options)
+
+	participants, err := srv.DB.FindParticipants(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	allocations, err := srv.DB.FindAllocations(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	var strata []edulab.Demographic
+	for _, d := range demographics {
+		if d.Type == edulab.InputSingle {
+			strata = append(strata, d)
+		}
+	}
+
+	cohortNames := make(map[string]string)
+	for _, c := range cohorts {
+		cohortNames[c.ID] = c.Name
+	}
+
+	optionTexts := make(map[string]string)
+	for _, o := range options {
+		optionTexts[o.ID] = o.Text
+	}
+
+	participantIDs := make(map[string]string)
+	for _, p := range participants {
+		participantIDs[p.ID] = p.PublicID
+	}
+
+	type allocation struct {
+		Participant string
+		Cohort      string
+		Stratum     string
+		Sequence    int
+		Seed        int64
+		CreatedAt   string
+	}
+
+	rows := make([]allocation, len(allocations))
+	for i, a := range allocations {
+		var stratum []string
+		for _, id := range strings.Split(a.Stratum, ",") {
+			if text, ok := optionTexts[id]; ok {
+				stratum = append(stratum, text)
+			}
+		}
+
+		rows[i] = allocation{
+			Participant: participantIDs[a.ParticipantID],
+			Cohort:      cohortNames[a.CohortID],
+			Stratum:     strings.Join(stratum, ", "),
+			Sequence:    a.Sequence + 1,
+			Seed:        a.Seed,
+			CreatedAt:   a.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+
+	title := printer.Sprintf("Randomization")
+	page.Title = title
+	page.Partials = []string{"randomization"}
+	page.Content = struct {
+		Breadcrumbs   template.HTML
+		Domain        string
+		Experiment    edulab.Experiment
+		Randomization edulab.Randomization
+		Assessments   []presenter.Assessment
+		Demographics  []edulab.Demographic
+		Allocations   []allocation
+		Methods       [][]string
+		Texts         interface{}
+	}{
+		Breadcrumbs:   presenter.ExperimentBreadcrumb(experiment, printer),
+		Domain:        getDomainBase(r),
+		Experiment:    experiment,
+		Randomization: randomization,
+		Assessments:   presenter.NewAssessments(assessments, printer),
+		Demographics:  strata,
+		Allocations:   rows,
+		Methods: [][]string{
+			{"", printer.Sprintf("Disabled")},
+			{string(edulab.RandomizationSimple), printer.Sprintf("Simple")},
+			{string(edulab.RandomizationBlock), printer.Sprintf("Permuted blocks")},
+			{string(edulab.RandomizationStratified), printer.Sprintf("Stratified permuted blocks")},
+		},
+		Texts: struct {
+			Title           string
+			Help            string
+			Method          string
+			MethodHelp      string
+			BlockSize       string
+			BlockSizeHelp   string
+			Demographic     string
+			DemographicHelp string
+			Seed            string
+			SeedHelp        string
+			Update          string
+			Links           string
+			LinksHelp       string
+			Allocations     string
+			NoAllocations   string
+			Participant     string
+			Cohort          string
+			Stratum         string
+			Sequence        string
+			CreatedAt       string
+		}{
+			Title: title,
+			Help: printer.Sprintf(`A randomization link assigns each new participant to a cohort at random.
+Participants who return for another assessment keep the cohort they were first assigned to.`),
+			Method:          printer.Sprintf("Method"),
+			MethodHelp:      printer.Sprintf("Blocks keep the cohorts balanced as participants join."),
+			BlockSize:       printer.Sprintf("Block size"),
+			BlockSizeHelp:   printer.Sprintf("Rounded up to a multiple of the number of cohorts. Current size: %d", randomize.BlockSize(randomization.BlockSize, len(cohorts))),
+			Demographic:     printer.Sprintf("Stratify by"),
+			DemographicHelp: printer.Sprintf("Participants answer the demographics before being assigned to a cohort."),
+			Seed:            printer.Sprintf("Seed"),
+			SeedHelp:        printer.Sprintf("Leave empty to generate a new seed. Changing it only affects future allocations."),
+			Update:          printer.Sprintf("Update"),
+			Links:           printer.Sprintf("Randomization Links"),
+			LinksHelp:       printer.Sprintf("Share the same link with every participant instead of one link per cohort."),
+			Allocations:     printer.Sprintf("Allocations"),
+			NoAllocations:   printer.Sprintf("No participants have been allocated yet"),
+			Participant:     printer.Sprintf("Participant"),
+			Cohort:          printer.Sprintf("Cohort"),
+			Stratum:         printer.Sprintf("Stratum"),
+			Sequence:        printer.Sprintf("Sequence"),
+			CreatedAt:       printer.Sprintf("Allocated at"),
+		},
+	}
+
+	srv.render(w, page)
+}
+
+func (srv *Server) updateRandomization(w http.ResponseWriter, r *http.Request, experiment edulab.Experiment) {
+	err := r.ParseForm()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	randomization := edulab.Randomization{
+		ExperimentID:  experiment.ID,
+		Method:        edulab.RandomizationMethod(r.FormValue("method")),
+		DemographicID: r.FormValue("demographic_id"),
+	}
+
+	switch randomization.Method {
+	case "", edulab.RandomizationSimple, edulab.RandomizationBlock, edulab.RandomizationStratified:
+	default:
+		srv.renderError(w, r, fmt.Errorf("unknown randomization method %q", randomization.Method))
+		return
+	}
+
+	if randomization.Method == edulab.RandomizationStratified && randomization.DemographicID == "" {
+		srv.renderError(w, r, fmt.Errorf("stratified randomization needs a demographic"))
+		return
+	}
+
+	if size := r.FormValue("block_size"); size != "" {
+		randomization.BlockSize, err = strconv.Atoi(size)
+		if err != nil {
+			srv.renderError(w, r, err)
+			return
+		}
+	}
+
+	if seed := r.FormValue("seed"); seed != "" {
+		randomization.Seed, err = strconv.ParseInt(seed, 10, 64)
+		if err != nil {
+			srv.renderError(w, r, err)
+			return
+		}
+	} else {
+		randomization.Seed = srv.Random.Int63()
+	}
+
+	err = srv.DB.UpdateRandomization(randomization)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, "/experiments/"+experiment.PublicID+"/randomization", http.StatusSeeOther)
+}
+
+// randomizeParticipant handles the experiment-level randomization link. New
+// participants are allocated to a cohort, while returning participants are
+// sent to the cohort they were first assigned to.
+func (srv *Server) randomizeParticipant(w http.ResponseWriter, r *http.Request,
+	eid, aid string) {
+
+	experiment, err := srv.DB.FindExperiment(eid)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	assessment, err := srv.DB.FindAssessment(experiment.ID, aid)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	randomization, err := srv.findRandomization(experiment)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	if randomization.Method == "" {
+		srv.renderNotFound(w, r)
+		return
+	}
+
+	token := srv.accessToken(w, r)
+
+	participant, err := srv.DB.FindParticipant(experiment.ID, token)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	if err == nil {
+		srv.redirectToCohort(w, r, experiment, participant, assessment)
+		return
+	}
+
+	if randomization.Method == edulab.RandomizationStratified {
+		demographics, err := srv.DB.FindDemographics(experiment.ID)
+		if err != nil {
+			srv.renderError(w, r, err)
+			return
+		}
+
+		// The cohort is allocated once the demographics are submitted
+		participant = edulab.Participant{AccessToken: token}
+		srv.showDemographics(w, r, experiment, edulab.Cohort{}, participant, assessment, demographics)
+		return
+	}
+
+	participant, cohort, err := srv.allocateParticipant(experiment, randomization, token, "")
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/%s-%s-%s", experiment.PublicID, cohort.PublicID,
+		assessment.PublicID), http.StatusSeeOther)
+}
+
+// allocateParticipant creates a participant in a randomly allocated cohort
+// and records the allocation.
+func (srv *Server) allocateParticipant(experiment edulab.Experiment, randomization edulab.Randomization,
+	token, stratum string) (edulab.Participant, edulab.Cohort, error) {
+
+	cohorts, err := srv.DB.FindCohorts(experiment.ID)
+	if err != nil {
+		return edulab.Participant{}, edulab.Cohort{}, err
+	}
+
+	participant := edulab.Participant{
+		PublicID:     srv.newPublicID(3),
+		ExperimentID: experiment.ID,
+		AccessToken:  token,
+	}
+
+	allocation, err := srv.DB.AllocateParticipant(&participant,
+		func(allocations []edulab.Allocation) (edulab.Allocation, error) {
+			return randomize.Allocate(randomization, cohorts, allocations, stratum)
+		})
+	if err != nil {
+		return participant, edulab.Cohort{}, err
+	}
+
+	var cohort edulab.Cohort
+	for _, c := range cohorts {
+		if c.ID == allocation.CohortID {
+			cohort = c
+			break
+		}
+	}
+
+	log.Printf("[DEBUG] Allocated participant %s to cohort %s (%s, seed %d)",
+		participant.PublicID, cohort.PublicID, allocation.Method, allocation.Seed)
+
+	return participant, cohort, nil
+}
+
+// stratifiedParticipant allocates a new participant of a stratified
+// randomization link using their answer to the stratification demographic.
+// Participants who already have a cohort keep it.
+func (srv *Server) stratifiedParticipant(experiment edulab.Experiment, token string,
+	answers map[string][]string) (edulab.Participant, edulab.Cohort, error) {
+
+	participant, err := srv.DB.FindParticipant(experiment.ID, token)
+	if err == nil {
+		cohorts, err := srv.DB.FindCohorts(experiment.ID)
+		if err != nil {
+			return participant, edulab.Cohort{}, err
+		}
+		for _, c := range cohorts {
+			if c.ID == participant.CohortID {
+				return participant, c, nil
+			}
+		}
+		return participant, edulab.Cohort{}, sql.ErrNoRows
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return participant, edulab.Cohort{}, err
+	}
+
+	randomization, err := srv.findRandomization(experiment)
+	if err != nil {
+		return participant, edulab.Cohort{}, err
+	}
+
+	if randomization.Method == "" {
+		return participant, edulab.Cohort{}, sql.ErrNoRows
+	}
+
+	stratum := strings.Join(answers[randomization.DemographicID], ",")
+
+	return srv.allocateParticipant(experiment, randomization, token, stratum)
+}
+
+// redirectToCohort sends a returning participant to the assessment link of
+// the cohort they belong to.
+func (srv *Server) redirectToCohort(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, participant edulab.Participant, assessment edulab.Assessment) {
+
+	cohorts, err := srv.DB.FindCohorts(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	for _, c := range cohorts {
+		if c.ID == participant.CohortID {
+			http.Redirect(w, r, fmt.Sprintf("/%s-%s-%s", experiment.PublicID, c.PublicID,
+				assessment.PublicID), http.StatusSeeOther)
+			return
+		}
+	}
+
+	srv.renderNotFound(w, r)
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/web/presenter"
+	"github.com/louisbranch/edulab/wizard"
+)
+
+// responseColumn is a column of an uploaded response file, with the value of
+// its first row and what it is mapped to.
+type responseColumn struct {
+	Index   int
+	Header  string
+	Example string
+	Mapping string
+}
+
+// importResponses imports a CSV file of responses collected outside of
+// edulab into an assessment. An upload maps the columns by their headers and
+// checks the file without importing it; the instructor can then change the
+// mapping, check it again or import the responses.
+func (srv *Server) importResponses(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, assessment edulab.Assessment) {
+
+	printer, _ := srv.i18n(w, r)
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxPackage)
+
+	err := r.ParseMultipartForm(maxPackage)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		srv.responses(w, r, experiment, assessment, "", nil, wizard.ResponseColumns{}, nil,
+			[]string{printer.Sprintf("the file is larger than 10 MB")})
+		return
+	case err != nil && !errors.Is(err, http.ErrNotMultipart):
+		srv.renderError(w, r, err)
+		return
+	}
+
+	questions, err := srv.DB.FindQuestions(assessment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	content := r.FormValue("responses")
+	uploaded := false
+
+	upload, _, err := r.FormFile("file")
+	switch {
+	case err == nil:
+		defer upload.Close()
+		b, err := io.ReadAll(upload)
+		if err != nil {
+			srv.renderError(w, r, err)
+			return
+		}
+		content, uploaded = string(b), true
+	case !errors.Is(err, http.ErrMissingFile) && !errors.Is(err, http.ErrNotMultipart):
+		srv.renderError(w, r, err)
+		return
+	}
+
+	file, err := wizard.ParseResponses(strings.NewReader(content))
+	if err != nil {
+		srv.responses(w, r, experiment, assessment, "", nil, wizard.ResponseColumns{}, nil,
+			[]string{presenter.ErrorMessage(printer, err)})
+		return
+	}
+
+	var columns wizard.ResponseColumns
+	if uploaded {
+		columns = file.GuessColumns(questions)
+	} else {
+		columns.Questions = make(map[string]int)
+		for i, h := range file.Header {
+			switch v := r.FormValue(fmt.Sprintf("column_%d", i)); v {
+			case "student":
+				columns.Student = h
+			case "cohort":
+				columns.Cohort = h
+			default:
+				if n, err := strconv.Atoi(v); err == nil {
+					columns.Questions[h] = n
+				}
+			}
+		}
+	}
+
+	dryRun := r.FormValue("action") != "import"
+
+	report, err := wizard.ImportResponses(srv.DB, experiment, assessment, file, columns, srv.Random, dryRun)
+	if err != nil {
+		srv.responses(w, r, experiment, assessment, content, &file, columns, &report, []string{presenter.ErrorMessage(printer, err)})
+		return
+	}
+
+	if !dryRun {
+		http.Redirect(w, r, "/experiments/"+experiment.PublicID+"/results", http.StatusSeeOther)
+		return
+	}
+
+	srv.responses(w, r, experiment, assessment, content, &file, columns, &report, nil)
+}
+
+// responses displays the column mapping of a response file to the
+// instructor, with what importing it would do or the problems found.
+func (srv *Server) responses(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, assessment edulab.Assessment, content string,
+	file *wizard.ResponseFile, mapping wizard.ResponseColumns, report *wizard.ResponseImport,
+	problems []string) {
+
+	questions, err := srv.DB.FindQuestions(assessment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	printer, page := srv.i18n(w, r)
+
+	options := [][]string{
+		{"", printer.Sprintf("Ignore")},
+		{"student", printer.Sprintf("Student")},
+		{"cohort", printer.Sprintf("Cohort")},
+	}
+	for i, q := range questions {
+		options = append(options, []string{strconv.Itoa(i + 1),
+			printer.Sprintf("Question %d: %s", i+1, wizard.QuestionTitle(q.Text))})
+	}
+
+	var columns []responseColumn
+	if file != nil {
+		for i, h := range file.Header {
+			column := responseColumn{Index: i, Header: h}
+			if i < len(file.Rows[0].Values) {
+				column.Example = file.Rows[0].Values[i]
+			}
+			switch {
+			case h == mapping.Student:
+				column.Mapping = "student"
+			case h == mapping.Cohort:
+				column.Mapping = "cohort"
+			case mapping.Questions[h] > 0:
+				column.Mapping = strconv.Itoa(mapping.Questions[h])
+			}
+			columns = append(columns, column)
+		}
+	}
+
+	var summary string
+	var unmatched []presenter.Unmatched
+	if report != nil {
+		unmatched = presenter.NewUnmatched(printer, report.Unmatched)
+	}
+	if report != nil && len(problems) == 0 {
+		summary = printer.Sprintf("%d responses can be imported, from %d new participants. %d responses replace an earlier import.",
+			report.Responses, report.Participants, report.Replaced)
+	}
+
+	title := printer.Sprintf("Import Responses")
+	page.Title = title
+	page.Partials = []string{"assessment_responses"}
+	page.Content = struct {
+		Breadcrumbs template.HTML
+		Path        string
+		Responses   string
+		Columns     []responseColumn
+		Options     [][]string
+		Report      *wizard.ResponseImport
+		Unmatched   []presenter.Unmatched
+		Problems    []string
+		Texts       interface{}
+	}{
+		Breadcrumbs: presenter.AssessmentBreadcrumb(experiment, assessment, printer),
+		Path:        "/experiments/" + experiment.PublicID + "/assessments/" + assessment.PublicID,
+		Responses:   content,
+		Columns:     columns,
+		Options:     options,
+		Report:      report,
+		Unmatched:   unmatched,
+		Problems:    problems,
+		Texts: struct {
+			Title     string
+			Invalid   string
+			Summary   string
+			Unmatched string
+			Line      string
+			Column    string
+			Value     string
+			Reason    string
+			Header    string
+			Example   string
+			Mapping   string
+			Check     string
+			Import    string
+			Back      string
+		}{
+			Title:     title,
+			Invalid:   printer.Sprintf("The responses couldn't be imported:"),
+			Summary:   summary,
+			Unmatched: printer.Sprintf("These values don't match and must be fixed in the file or the mapping before importing:"),
+			Line:      printer.Sprintf("Line"),
+			Column:    printer.Sprintf("Column"),
+			Value:     printer.Sprintf("Value"),
+			Reason:    printer.Sprintf("Reason"),
+			Header:    printer.Sprintf("Column"),
+			Example:   printer.Sprintf("First Row"),
+			Mapping:   printer.Sprintf("Maps To"),
+			Check:     printer.Sprintf("Check"),
+			Import:    printer.Sprintf("Import"),
+			Back:      printer.Sprintf("Back to the assessment"),
+		},
+	}
+
+	if len(problems) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	srv.render(w, page)
+}
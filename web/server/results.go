@@ -12,6 +12,8 @@ import (
 	"github.com/louisbranch/edulab/result"
 	"github.com/louisbranch/edulab/stats"
 	"github.com/louisbranch/edulab/web/presenter"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
 	"gonum.org/v1/gonum/stat"
 )
 
@@ -45,6 +47,21 @@ func (srv *Server) resultsHandler(w http.ResponseWriter, r *http.Request,
 	case "gains":
 		srv.gainsResult(w, r, experiment)
 		return
+	case "subgroups":
+		srv.subgroupsResult(w, r, experiment)
+		return
+	case "baseline":
+		srv.baselineResult(w, r, experiment)
+		return
+	case "attrition":
+		srv.attritionResult(w, r, experiment)
+		return
+	case "arms":
+		srv.armsResult(w, r, experiment)
+		return
+	case "timepoints":
+		srv.timepointsResult(w, r, experiment)
+		return
 	default:
 		srv.renderNotFound(w, r)
 		return
@@ -54,6 +71,14 @@ func (srv *Server) resultsHandler(w http.ResponseWriter, r *http.Request,
 func (srv *Server) demographicsResult(w http.ResponseWriter, r *http.Request,
 	experiment edulab.Experiment) {
 
+	printer, page := srv.i18n(w, r)
+
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
 	cohorts, err := srv.DB.FindCohorts(experiment.ID)
 	if err != nil {
 		srv.renderError(w, r, err)
@@ -72,6 +97,8 @@ func (srv *Server) demographicsResult(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	demographics, options = presenter.TranslateDemographics(printer, demographics, options)
+
 	participants, err := srv.DB.FindParticipants(experiment.ID)
 	if err != nil {
 		srv.renderError(w, r, err)
@@ -84,7 +111,7 @@ func (srv *Server) demographicsResult(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	dr, err := presenter.NewDemographicsResult(demographics, options, cohorts,
+	dr, err := presenter.NewDemographicsResult(demographics, options, arms, cohorts,
 		participants, participations)
 	if err != nil {
 		srv.renderError(w, r, err)
@@ -103,8 +130,6 @@ func (srv *Server) demographicsResult(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	printer, page := srv.i18n(w, r)
-
 	title := printer.Sprintf("Demographics Results")
 	page.Title = title
 	page.Partials = []string{"results_demographics"}
@@ -148,6 +173,17 @@ func (srv *Server) assessmentsResult(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	var armLabels []string
+	for _, a := range arms {
+		armLabels = append(armLabels, a.Name)
+	}
+
 	participations, err := srv.DB.FindParticipations(experiment.ID)
 	if err != nil {
 		srv.renderError(w, r, err)
@@ -197,14 +233,34 @@ func (srv *Server) assessmentsResult(w http.ResponseWriter, r *http.Request,
 
 	if r.Header.Get("Content-type") == "application/json" {
 
-		counts, err := result.CountChoicesByCohorts(srv.DB, experiment)
+		qcs, err := result.ChoiceTests(srv.DB, experiment)
 		if err != nil {
 			srv.renderError(w, r, err)
 			return
 		}
 
+		type questionResult struct {
+			result.QuestionChoices
+			Summary []string `json:"summary"`
+		}
+
+		var response []questionResult
+		for _, qc := range qcs {
+			qr := questionResult{
+				QuestionChoices: qc,
+				Summary: []string{
+					printer.Sprintf("Arms: %s", contingencySummary(printer, qc.Arms)),
+				},
+			}
+			if qc.PrePost != nil {
+				qr.Summary = append(qr.Summary,
+					printer.Sprintf("Pre vs post: %s", contingencySummary(printer, *qc.PrePost)))
+			}
+			response = append(response, qr)
+		}
+
 		w.Header().Set("Content-Type", "application/json")
-		err = json.NewEncoder(w).Encode(counts)
+		err = json.NewEncoder(w).Encode(response)
 		if err != nil {
 			srv.renderError(w, r, err)
 			return
@@ -232,25 +288,38 @@ func (srv *Server) assessmentsResult(w http.ResponseWriter, r *http.Request,
 			Choices      string
 			Participants string
 			Empty        string
-			CohortLabels []string
+			ArmLabels    []string
 		}{
 			Title:        title,
 			Choices:      printer.Sprintf("Choices"),
 			Participants: printer.Sprintf("Participants"),
 			Empty:        printer.Sprintf("No data available yet"),
-			CohortLabels: []string{
-				printer.Sprintf("Control"),
-				printer.Sprintf("Intervention"),
-			},
+			ArmLabels:    armLabels,
 		},
 	}
 
 	srv.render(w, page)
 }
 
+// contingencySummary describes the result of a contingency table test.
+func contingencySummary(printer *message.Printer, c stats.Contingency) string {
+	switch {
+	case c.Invalid:
+		return printer.Sprintf("not enough data")
+	case c.Exact:
+		return printer.Sprintf("Fisher's exact test p-value: %.4f, Cramér's V: %.3f",
+			c.PValue, c.CramersV)
+	default:
+		return printer.Sprintf("χ²(%d) = %.3f, p-value: %.4f, Cramér's V: %.3f",
+			c.DF, c.ChiSquare, c.PValue, c.CramersV)
+	}
+}
+
 func (srv *Server) gainsResult(w http.ResponseWriter, r *http.Request,
 	experiment edulab.Experiment) {
 
+	printer, page := srv.i18n(w, r)
+
 	res, err := result.New(srv.DB, experiment.ID)
 	if err != nil {
 		log.Printf("[ERROR] Failed to create result: %v", err)
@@ -258,7 +327,43 @@ func (srv *Server) gainsResult(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	printer, page := srv.i18n(w, r)
+	demographics, err := srv.DB.FindDemographics(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	options, err := srv.DB.FindDemographicOptions(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	demographics, options = presenter.TranslateDemographics(printer, demographics, options)
+
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	// The control arm is compared with one intervention arm at a time
+	var control, intervention edulab.Arm
+	var interventions []edulab.Arm
+	if len(arms) > 1 {
+		control = arms[0]
+		interventions = arms[1:]
+		intervention = interventions[0]
+		for _, a := range interventions {
+			if a.PublicID == r.URL.Query().Get("arm") {
+				intervention = a
+			}
+		}
+	}
+
+	// Optional demographic option to filter participants by
+	option := r.URL.Query().Get("option")
+	cacheKey := experiment.ID + "?option=" + option + "&arm=" + intervention.ID
 
 	type texts struct {
 		Title           string
@@ -268,32 +373,55 @@ func (srv *Server) gainsResult(w http.ResponseWriter, r *http.Request,
 		Empty           string
 		PlotTitles      []string
 		AssessmentTypes []string
-		CohortLabels    []string
+		ArmLabels       []string
+		EffectSize      string
+		Power           string
+		MDE             string
+		Bayesian        string
+		BayesCorrect    string
+		BayesGain       string
+		Filter          string
+		AllParticipants string
+		CompareWith     string
 	}
 
 	content := struct {
-		Breadcrumbs template.HTML
-		Experiment  edulab.Experiment
-		Texts       texts
+		Breadcrumbs   template.HTML
+		Experiment    edulab.Experiment
+		Demographics  []presenter.Demographic
+		Option        string
+		Interventions []edulab.Arm
+		Intervention  edulab.Arm
+		Texts         texts
 	}{
-		Breadcrumbs: presenter.ExperimentBreadcrumb(experiment, printer),
-		Experiment:  experiment,
+		Breadcrumbs:   presenter.ExperimentBreadcrumb(experiment, printer),
+		Experiment:    experiment,
+		Demographics:  presenter.NewDemographics(demographics, options),
+		Option:        option,
+		Interventions: interventions,
+		Intervention:  intervention,
 		Texts: texts{
 			Title:      printer.Sprintf("Gains Results"),
 			Download:   printer.Sprintf("Export as CSV"),
 			ComingSoon: printer.Sprintf("Coming soon"),
 			PlotTitles: []string{
-				printer.Sprintf("Average Correct Answers by Cohort"),
-				printer.Sprintf("Learning Gain by Cohort (Post - Pre)"),
+				printer.Sprintf("Average Correct Answers by Arm"),
+				printer.Sprintf("Learning Gain by Arm (Post - Pre)"),
 			},
 			AssessmentTypes: []string{
 				printer.Sprintf("Pre"),
 				printer.Sprintf("Post"),
 			},
-			CohortLabels: []string{
-				printer.Sprintf("Control"),
-				printer.Sprintf("Intervention"),
-			},
+			ArmLabels:       []string{control.Name, intervention.Name},
+			EffectSize:      printer.Sprintf("Effect size (Cohen's d)"),
+			Power:           printer.Sprintf("Observed power"),
+			MDE:             printer.Sprintf("Minimum detectable effect (%v power)", number.Percent(0.8)),
+			Bayesian:        printer.Sprintf("Bayesian estimate"),
+			BayesCorrect:    printer.Sprintf("Correct answers (post)"),
+			BayesGain:       printer.Sprintf("Learning gain"),
+			Filter:          printer.Sprintf("Filter"),
+			AllParticipants: printer.Sprintf("All participants"),
+			CompareWith:     printer.Sprintf("Compare control with"),
 		},
 	}
 
@@ -302,7 +430,7 @@ func (srv *Server) gainsResult(w http.ResponseWriter, r *http.Request,
 	page.Partials = []string{"results_gains"}
 	page.Content = content
 
-	cache, ok := gainsCache[experiment.ID]
+	cache, ok := gainsCache[cacheKey]
 	if ok && cache.participations == res.Participations() {
 		if r.Header.Get("Content-type") != "application/json" {
 			srv.render(w, page)
@@ -321,6 +449,13 @@ func (srv *Server) gainsResult(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	if len(arms) < 2 {
+		content.Texts.Error = printer.Sprintf("At least two arms are needed to compare learning gains")
+		page.Content = content
+		srv.render(w, page)
+		return
+	}
+
 	err = res.Load()
 	if err != nil {
 		log.Printf("[ERROR] Failed to load result: %v", err)
@@ -328,7 +463,11 @@ func (srv *Server) gainsResult(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	cohorts, items := res.ComparisonPairs()
+	if option != "" {
+		res.FilterByDemographic(option)
+	}
+
+	_, items := res.ComparisonPairs()
 	if len(items) == 0 {
 		content.Texts.Error = printer.Sprintf("No comparison pairs available yet")
 		page.Content = content
@@ -378,9 +517,22 @@ func (srv *Server) gainsResult(w http.ResponseWriter, r *http.Request,
 		Beta1            float64 `json:"beta1"`
 		RSquared         float64 `json:"rSquared"`
 		PValue           float64 `json:"pValue"`
+		EffectSize       float64 `json:"effectSize"`
+		Power            float64 `json:"power"`
+		MDE              float64 `json:"mde"`
+		BayesCorrect     string  `json:"bayesCorrect"`
+		BayesGain        string  `json:"bayesGain"`
 		Message          string  `json:"message"`
 	}
 
+	describe := func(p stats.Posterior) string {
+		if p.Invalid {
+			return printer.Sprintf("Not enough data")
+		}
+		return printer.Sprintf("%v probability that the intervention outperforms control (difference: %.3f, %v credible interval: %.3f to %.3f)",
+			number.Percent(p.ProbabilityBetter, number.Scale(1)), p.Mean, number.Percent(0.95), p.Lower, p.Upper)
+	}
+
 	var payload []chart
 
 	for i, item := range items {
@@ -390,7 +542,7 @@ func (srv *Server) gainsResult(w http.ResponseWriter, r *http.Request,
 			label = labels[i]
 		}
 
-		comparison, err := result.NewComparison(res, item, cohorts)
+		comparison, err := result.NewComparison(res, item, []string{control.ID, intervention.ID})
 		if err != nil {
 			srv.renderError(w, r, err)
 			return
@@ -412,13 +564,47 @@ func (srv *Server) gainsResult(w http.ResponseWriter, r *http.Request,
 			rSquared = 0.0
 		}
 
-		var preControl, postControl, preIntervention, postIntervention []float64
-		for _, d := range data {
-			preControl = append(preControl, d.PreControl)
-			postControl = append(postControl, d.PostControl)
-			preIntervention = append(preIntervention, d.PreIntervention)
-			postIntervention = append(postIntervention, d.PostIntervention)
+		preControl := comparison.Scores("pre_control")
+		postControl := comparison.Scores("post_control")
+		preIntervention := comparison.Scores("pre_intervention")
+		postIntervention := comparison.Scores("post_intervention")
+
+		// Gains pair the pre- and post-assessment scores of each participant,
+		// with every participant of the arms who answered both
+		armGains, err := res.GainsByArm([][]result.AssessmentQuestions{item})
+		if err != nil {
+			srv.renderError(w, r, err)
+			return
+		}
+		gainsControl, gainsIntervention := armGains[control.ID], armGains[intervention.ID]
+
+		// Post-hoc power and minimum detectable effect for the current sample
+		effectSize := stats.CohensD(gainsControl, gainsIntervention)
+		power := stats.TwoSampleTPower(effectSize, len(gainsControl), len(gainsIntervention), 0.05)
+		mde := stats.MinimumDetectableEffect(len(gainsControl), len(gainsIntervention), 0.05, 0.8)
+
+		for _, v := range []*float64{&effectSize, &power, &mde} {
+			if math.IsNaN(*v) {
+				*v = 0.0
+			}
+		}
+
+		// Bayesian estimates of the proportion of fully correct post-assessment
+		// answers, over every answer of each arm, and of the mean learning gains
+		correct := func(scores []float64) int {
+			n := 0
+			for _, s := range scores {
+				if s == 1 {
+					n++
+				}
+			}
+			return n
 		}
+		correctControl, correctIntervention := correct(postControl), correct(postIntervention)
+
+		bayesCorrect := stats.BetaBinomial(correctControl, len(postControl),
+			correctIntervention, len(postIntervention), stats.BayesSeed)
+		bayesGain := stats.NormalGains(gainsControl, gainsIntervention, stats.BayesSeed)
 
 		payload = append(payload, chart{
 			Question:         label,
@@ -430,6 +616,11 @@ func (srv *Server) gainsResult(w http.ResponseWriter, r *http.Request,
 			Beta1:            beta1,
 			RSquared:         rSquared,
 			PValue:           pValue,
+			EffectSize:       effectSize,
+			Power:            power,
+			MDE:              mde,
+			BayesCorrect:     describe(bayesCorrect),
+			BayesGain:        describe(bayesGain),
 			Message:          result.EvaluateExperiment(len(participants), pValue, printer),
 		})
 
@@ -443,7 +634,7 @@ func (srv *Server) gainsResult(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	gainsCache[experiment.ID] = cached{
+	gainsCache[cacheKey] = cached{
 		experimentID:   experiment.ID,
 		participations: res.Participations(),
 		payload:        response,
@@ -451,3 +642,923 @@ func (srv *Server) gainsResult(w http.ResponseWriter, r *http.Request,
 
 	w.Write(response)
 }
+
+func (srv *Server) subgroupsResult(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment) {
+
+	printer, page := srv.i18n(w, r)
+
+	demographics, err := srv.DB.FindDemographics(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	options, err := srv.DB.FindDemographicOptions(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	demographics, options = presenter.TranslateDemographics(printer, demographics, options)
+
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	dps := presenter.NewDemographics(demographics, options)
+
+	var selected presenter.Demographic
+	did := r.URL.Query().Get("demographic")
+	for i, d := range dps {
+		if d.ID == did || (did == "" && i == 0) {
+			selected = d
+		}
+	}
+
+	type texts struct {
+		Title         string
+		Error         string
+		Demographic   string
+		Select        string
+		Subgroup      string
+		Participants  string
+		Pre           string
+		Post          string
+		Gain          string
+		Interaction   string
+		InteractionOK string
+		Equity        string
+		PreGap        string
+		PostGap       string
+		EquityHelp    string
+		Messages      []string
+	}
+
+	content := struct {
+		Breadcrumbs  template.HTML
+		Experiment   edulab.Experiment
+		Demographics []presenter.Demographic
+		Selected     presenter.Demographic
+		Arms         []edulab.Arm
+		Analysis     result.SubgroupAnalysis
+		Texts        texts
+	}{
+		Breadcrumbs:  presenter.ExperimentBreadcrumb(experiment, printer),
+		Experiment:   experiment,
+		Demographics: dps,
+		Selected:     selected,
+		Texts: texts{
+			Title:        printer.Sprintf("Subgroup Results"),
+			Demographic:  printer.Sprintf("Demographic"),
+			Select:       printer.Sprintf("Select"),
+			Subgroup:     printer.Sprintf("Subgroup"),
+			Participants: printer.Sprintf("Participants"),
+			Pre:          printer.Sprintf("Pre"),
+			Post:         printer.Sprintf("Post"),
+			Gain:         printer.Sprintf("Gain"),
+			Interaction:  printer.Sprintf("Arm x demographic interaction"),
+			Equity:       printer.Sprintf("Equity"),
+			PreGap:       printer.Sprintf("Gap before"),
+			PostGap:      printer.Sprintf("Gap after"),
+			EquityHelp:   printer.Sprintf("Difference between the highest and lowest scoring subgroups in each arm."),
+		},
+	}
+
+	title := content.Texts.Title
+	page.Title = title
+	page.Partials = []string{"results_subgroups"}
+
+	res, err := result.New(srv.DB, experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	if !res.Valid() || selected.ID == "" {
+		content.Texts.Error = printer.Sprintf("No data available yet")
+		page.Content = content
+		srv.render(w, page)
+		return
+	}
+
+	err = res.Load()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	armIDs, items := res.ComparisonPairs()
+	if len(items) == 0 {
+		content.Texts.Error = printer.Sprintf("No comparison pairs available yet")
+		page.Content = content
+		srv.render(w, page)
+		return
+	}
+
+	gains, err := res.ParticipantGains(items)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	sa := res.Subgroups(gains, selected.Demographic, selected.Options, armIDs)
+
+	names := make(map[string]edulab.Arm)
+	for _, a := range arms {
+		names[a.ID] = a
+	}
+	for _, id := range armIDs {
+		content.Arms = append(content.Arms, names[id])
+	}
+
+	content.Analysis = sa
+
+	if !sa.Interaction.Invalid {
+		content.Texts.InteractionOK = printer.Sprintf("F(%d, %d) = %.3f, p-value: %.4f",
+			sa.Interaction.Numer, sa.Interaction.Denom, sa.Interaction.F, sa.Interaction.PValue)
+	} else {
+		content.Texts.InteractionOK = printer.Sprintf("Not enough data to test the interaction.")
+	}
+
+	for i := 1; i < len(content.Arms); i++ {
+		diff := sa.Equity.Change(i) - sa.Equity.Change(0)
+		name := content.Arms[i].Name
+		switch {
+		case diff < 0:
+			content.Texts.Messages = append(content.Texts.Messages,
+				printer.Sprintf("%s closed the gap between subgroups by %.3f compared to %s.",
+					name, -diff, content.Arms[0].Name))
+		case diff > 0:
+			content.Texts.Messages = append(content.Texts.Messages,
+				printer.Sprintf("%s widened the gap between subgroups by %.3f compared to %s.",
+					name, diff, content.Arms[0].Name))
+		default:
+			content.Texts.Messages = append(content.Texts.Messages,
+				printer.Sprintf("%s did not change the gap between subgroups compared to %s.",
+					name, content.Arms[0].Name))
+		}
+	}
+
+	page.Content = content
+	srv.render(w, page)
+}
+
+func (srv *Server) baselineResult(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment) {
+
+	printer, page := srv.i18n(w, r)
+
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	cohorts, err := srv.DB.FindCohorts(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	demographics, err := srv.DB.FindDemographics(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	options, err := srv.DB.FindDemographicOptions(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	demographics, options = presenter.TranslateDemographics(printer, demographics, options)
+
+	participants, err := srv.DB.FindParticipants(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	participations, err := srv.DB.FindParticipations(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	dr, err := presenter.NewDemographicsResult(demographics, options, arms, cohorts,
+		participants, participations)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	type score struct {
+		Control      string
+		Intervention string
+		Difference   string
+		TTest        string
+		MannWhitney  string
+		Equivalence  string
+		Warning      bool
+	}
+
+	type balance struct {
+		Demographic string
+		Test        string
+		Warning     bool
+	}
+
+	type texts struct {
+		Title          string
+		Error          string
+		Scores         string
+		ScoresHelp     string
+		Demographics   string
+		Demographic    string
+		Control        string
+		Intervention   string
+		Difference     string
+		TTest          string
+		MannWhitney    string
+		Equivalence    string
+		ChiSquare      string
+		DemographicsOK string
+	}
+
+	content := struct {
+		Breadcrumbs template.HTML
+		Experiment  edulab.Experiment
+		Scores      []score
+		Balance     []balance
+		Texts       texts
+	}{
+		Breadcrumbs: presenter.ExperimentBreadcrumb(experiment, printer),
+		Experiment:  experiment,
+		Texts: texts{
+			Title:        printer.Sprintf("Baseline Equivalence"),
+			Scores:       printer.Sprintf("Pre-assessment scores"),
+			ScoresHelp:   printer.Sprintf("Standardized differences (Hedges' g) up to 0.05 satisfy baseline equivalence, between 0.05 and 0.25 require a statistical adjustment and above 0.25 are not equivalent."),
+			Demographics: printer.Sprintf("Demographics"),
+			Demographic:  printer.Sprintf("Demographic"),
+			Control:      printer.Sprintf("Control"),
+			Intervention: printer.Sprintf("Intervention"),
+			Difference:   printer.Sprintf("Hedges' g"),
+			TTest:        printer.Sprintf("Welch's t-test"),
+			MannWhitney:  printer.Sprintf("Mann-Whitney U"),
+			Equivalence:  printer.Sprintf("Equivalence"),
+			ChiSquare:    printer.Sprintf("Chi-square test"),
+		},
+	}
+
+	title := content.Texts.Title
+	page.Title = title
+	page.Partials = []string{"results_baseline"}
+
+	for i, d := range dr.Demographics {
+		chi2, df, p := stats.ChiSquareTest(dr.Data[i])
+		b := balance{
+			Demographic: d.Text,
+			Warning:     p < 0.05,
+		}
+		if math.IsNaN(chi2) {
+			b.Test = printer.Sprintf("Not enough data")
+		} else {
+			b.Test = printer.Sprintf("χ²(%d) = %.3f, p-value: %.4f", df, chi2, p)
+		}
+		content.Balance = append(content.Balance, b)
+	}
+	content.Texts.DemographicsOK = printer.Sprintf("Demographics with a p-value below 0.05 are not balanced across arms.")
+
+	res, err := result.New(srv.DB, experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	if !res.Valid() {
+		content.Texts.Error = printer.Sprintf("No data available yet")
+		page.Content = content
+		srv.render(w, page)
+		return
+	}
+
+	err = res.Load()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	armIDs, _ := res.ComparisonPairs()
+
+	comparisons, err := res.Baseline(armIDs)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	names := make(map[string]string)
+	for _, a := range arms {
+		names[a.ID] = a.Name
+	}
+
+	group := func(bg result.BaselineGroup) string {
+		return printer.Sprintf("%s: %.3f (SD %.3f, n = %d)", names[bg.ArmID], bg.Mean, bg.SD, bg.N)
+	}
+
+	for _, c := range comparisons {
+		s := score{
+			Control:      group(c.Control),
+			Intervention: group(c.Intervention),
+			Difference:   printer.Sprintf("%.3f", c.HedgesG),
+			TTest:        printer.Sprintf("t(%.1f) = %.3f, p-value: %.4f", c.DF, c.T, c.PValueT),
+			MannWhitney:  printer.Sprintf("U = %.1f, p-value: %.4f", c.U, c.PValueU),
+		}
+
+		switch c.Equivalence {
+		case stats.EquivalenceSatisfied:
+			s.Equivalence = printer.Sprintf("Satisfied")
+		case stats.EquivalenceAdjustment:
+			s.Equivalence = printer.Sprintf("Requires statistical adjustment")
+			s.Warning = true
+		case stats.EquivalenceNotSatisfied:
+			s.Equivalence = printer.Sprintf("Not satisfied")
+			s.Warning = true
+		default:
+			s.Equivalence = printer.Sprintf("Not enough data")
+			s.Difference = "-"
+			s.TTest = "-"
+			s.Warning = true
+		}
+
+		content.Scores = append(content.Scores, s)
+	}
+
+	page.Content = content
+	srv.render(w, page)
+}
+
+func (srv *Server) attritionResult(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment) {
+
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	cohorts, err := srv.DB.FindCohorts(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	assessments, err := srv.DB.FindAssessments(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	participants, err := srv.DB.FindParticipants(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	participations, err := srv.DB.FindParticipations(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	events, err := srv.DB.FindParticipantEvents(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	funnels := result.NewFunnels(cohorts, assessments, participants, participations, events)
+	armFunnels := result.NewArmFunnels(arms, cohorts, assessments, participants, participations, events)
+	attrition, overall := result.NewAttrition(cohorts, assessments, participants, participations)
+	armAttrition, _ := result.NewArmAttrition(arms, cohorts, assessments, participants, participations)
+
+	printer, page := srv.i18n(w, r)
+
+	armNames := make(map[string]string)
+	for _, a := range arms {
+		armNames[a.ID] = a.Name
+	}
+
+	cohortNames := make(map[string]string)
+	for _, c := range cohorts {
+		cohortNames[c.ID] = c.Name
+	}
+
+	// Each arm is followed by the cohorts it rolls up
+	type funnelRow struct {
+		Arm    string
+		Cohort string
+		Steps  []string
+	}
+
+	type funnelTable struct {
+		Assessment presenter.Assessment
+		Rows       []funnelRow
+	}
+
+	type attritionRow struct {
+		Arm          string
+		Cohort       string
+		Pre          int
+		Post         int
+		Lost         int
+		Rate         string
+		Differential string
+	}
+
+	var steps []string
+	for _, t := range edulab.EventTypes {
+		steps = append(steps, presenter.EventType(printer, t))
+	}
+
+	funnelSteps := func(f result.Funnel) []string {
+		var steps []string
+		for _, s := range f.Steps {
+			steps = append(steps, printer.Sprintf("%d (%v)", s.Participants, number.Percent(s.Rate)))
+		}
+		return steps
+	}
+
+	allCohorts := printer.Sprintf("All cohorts")
+
+	var tables []funnelTable
+	for _, a := range assessments {
+		table := funnelTable{
+			Assessment: presenter.NewAssessment(a, printer),
+		}
+		for _, af := range armFunnels {
+			if af.AssessmentID != a.ID {
+				continue
+			}
+			table.Rows = append(table.Rows, funnelRow{
+				Arm:    armNames[af.ArmID],
+				Cohort: allCohorts,
+				Steps:  funnelSteps(af),
+			})
+			for _, f := range funnels {
+				if f.AssessmentID != a.ID || f.ArmID != af.ArmID {
+					continue
+				}
+				table.Rows = append(table.Rows, funnelRow{
+					Cohort: cohortNames[f.CohortID],
+					Steps:  funnelSteps(f),
+				})
+			}
+		}
+		tables = append(tables, table)
+	}
+
+	newAttritionRow := func(a result.Attrition) attritionRow {
+		return attritionRow{
+			Pre:          a.Pre,
+			Post:         a.Post,
+			Lost:         a.Lost,
+			Rate:         printer.Sprintf("%v", number.Percent(a.Rate, number.Scale(1))),
+			Differential: "-",
+		}
+	}
+
+	// Differential attrition is only meaningful between arms
+	var rows []attritionRow
+	for i, aa := range armAttrition {
+		row := newAttritionRow(aa)
+		row.Arm = armNames[aa.ArmID]
+		row.Cohort = allCohorts
+		if i > 0 {
+			// Percent formatting has no sign flag, so increases get their plus sign here.
+			sign := ""
+			if aa.Differential > 0 {
+				sign = "+"
+			}
+			row.Differential = printer.Sprintf("%s%v", sign, number.Percent(aa.Differential, number.Scale(1)))
+		}
+		rows = append(rows, row)
+
+		for _, a := range attrition {
+			if a.ArmID != aa.ArmID {
+				continue
+			}
+			row := newAttritionRow(a)
+			row.Cohort = cohortNames[a.CohortID]
+			rows = append(rows, row)
+		}
+	}
+
+	title := printer.Sprintf("Attrition")
+	page.Title = title
+	page.Partials = []string{"results_attrition"}
+	page.Content = struct {
+		Breadcrumbs template.HTML
+		Experiment  edulab.Experiment
+		Steps       []string
+		Funnels     []funnelTable
+		Attrition   []attritionRow
+		Texts       interface{}
+	}{
+		Breadcrumbs: presenter.ExperimentBreadcrumb(experiment, printer),
+		Experiment:  experiment,
+		Steps:       steps,
+		Funnels:     tables,
+		Attrition:   rows,
+		Texts: struct {
+			Title         string
+			Funnel        string
+			FunnelHelp    string
+			Arm           string
+			Cohort        string
+			Attrition     string
+			AttritionHelp string
+			Pre           string
+			Post          string
+			Lost          string
+			Rate          string
+			Differential  string
+			Overall       string
+		}{
+			Title:         title,
+			Funnel:        printer.Sprintf("Completion funnel"),
+			FunnelHelp:    printer.Sprintf("Number of participants who reached each step, relative to the step reached by most participants."),
+			Arm:           printer.Sprintf("Arm"),
+			Cohort:        printer.Sprintf("Cohort"),
+			Attrition:     printer.Sprintf("Attrition between pre- and post-assessment"),
+			AttritionHelp: printer.Sprintf("Participants who submitted the pre-assessment but not the post-assessment. Differential attrition between arms can bias the learning gains."),
+			Pre:           printer.Sprintf("Submitted pre"),
+			Post:          printer.Sprintf("Submitted post"),
+			Lost:          printer.Sprintf("Lost"),
+			Rate:          printer.Sprintf("Attrition rate"),
+			Differential:  printer.Sprintf("Differential attrition"),
+			Overall:       printer.Sprintf("Overall attrition: %v", number.Percent(overall, number.Scale(1))),
+		},
+	}
+
+	srv.render(w, page)
+}
+
+func (srv *Server) armsResult(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment) {
+
+	cohorts, err := srv.DB.FindCohorts(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	printer, page := srv.i18n(w, r)
+
+	type arm struct {
+		Name     string
+		Cohorts  string
+		N        int
+		MeanGain string
+	}
+
+	type effect struct {
+		Arm         string
+		Estimate    string
+		Mixed       string
+		Independent string
+	}
+
+	type texts struct {
+		Title       string
+		Error       string
+		Help        string
+		Arm         string
+		Cohorts     string
+		MeanGain    string
+		Estimate    string
+		Mixed       string
+		Independent string
+		Effects     string
+		ICC         string
+		Warning     string
+	}
+
+	content := struct {
+		Breadcrumbs template.HTML
+		Experiment  edulab.Experiment
+		Arms        []arm
+		Effects     []effect
+		Texts       texts
+	}{
+		Breadcrumbs: presenter.ExperimentBreadcrumb(experiment, printer),
+		Experiment:  experiment,
+		Texts: texts{
+			Title:       printer.Sprintf("Arms"),
+			Help:        printer.Sprintf("Cohorts assigned to the same arm are analysed together. Participants are nested in their cohorts, so the arm effect is estimated with a random intercept per cohort."),
+			Arm:         printer.Sprintf("Arm"),
+			Cohorts:     printer.Sprintf("Cohorts"),
+			MeanGain:    printer.Sprintf("Mean gain"),
+			Estimate:    printer.Sprintf("Difference in gain"),
+			Mixed:       printer.Sprintf("Mixed model"),
+			Independent: printer.Sprintf("Treating participants as independent"),
+		},
+	}
+
+	title := content.Texts.Title
+	page.Title = title
+	page.Partials = []string{"results_arms"}
+
+	res, err := result.New(srv.DB, experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	if !res.Valid() {
+		content.Texts.Error = printer.Sprintf("No data available yet")
+		page.Content = content
+		srv.render(w, page)
+		return
+	}
+
+	err = res.Load()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	armIDs, items := res.ComparisonPairs()
+	if len(items) == 0 {
+		content.Texts.Error = printer.Sprintf("No comparison pairs available yet")
+		page.Content = content
+		srv.render(w, page)
+		return
+	}
+
+	gains, err := res.ParticipantGains(items)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	names := make(map[string]string)
+	for _, c := range cohorts {
+		names[c.ID] = c.Name
+	}
+
+	analysis, err := res.ArmEffect(gains, armIDs)
+
+	for _, a := range analysis.Arms {
+		var cs []string
+		for _, id := range a.CohortIDs {
+			cs = append(cs, names[id])
+		}
+		content.Arms = append(content.Arms, arm{
+			Name:     a.Arm.Name,
+			Cohorts:  strings.Join(cs, ", "),
+			N:        a.N,
+			MeanGain: printer.Sprintf("%.3f", a.MeanGain),
+		})
+	}
+
+	if err != nil || len(analysis.Arms) < 2 {
+		content.Texts.Error = printer.Sprintf("Not enough data to compare arms.")
+		page.Content = content
+		srv.render(w, page)
+		return
+	}
+
+	content.Texts.Effects = printer.Sprintf("Arm effects compared to %s", analysis.Arms[0].Arm.Name)
+	content.Texts.ICC = printer.Sprintf("Intraclass correlation (ICC): %.3f", analysis.Mixed.ICC)
+
+	for i, a := range analysis.Arms[1:] {
+		j := i + 1
+		e := effect{
+			Arm:      a.Arm.Name,
+			Estimate: printer.Sprintf("%.3f", analysis.Mixed.Coefficients[j]),
+			Mixed:    printer.Sprintf("SE %.3f", analysis.Mixed.StandardErrors[j]),
+			Independent: printer.Sprintf("SE %.3f, p-value: %.4f (df = %d)", analysis.OLS.StandardErrors[j],
+				analysis.OLS.PValues[j], analysis.OLS.DF),
+		}
+		if analysis.Mixed.DF > 0 {
+			e.Mixed = printer.Sprintf("SE %.3f, p-value: %.4f (df = %d)", analysis.Mixed.StandardErrors[j],
+				analysis.Mixed.PValues[j], analysis.Mixed.DF)
+		}
+		content.Effects = append(content.Effects, e)
+	}
+
+	if analysis.Mixed.DF < 1 {
+		content.Texts.Warning = printer.Sprintf("Each arm needs more than one cohort to estimate the variation between cohorts. The mixed model p-values are not available.")
+	}
+
+	page.Content = content
+	srv.render(w, page)
+}
+
+func (srv *Server) timepointsResult(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment) {
+
+	arms, err := srv.DB.FindArms(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	cohorts, err := srv.DB.FindCohorts(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	assessments, err := srv.DB.FindAssessments(experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	printer, page := srv.i18n(w, r)
+
+	type series struct {
+		Label string     `json:"label"`
+		Data  []*float64 `json:"data"`
+	}
+
+	type trajectory struct {
+		Cohort string
+		Scores []string
+	}
+
+	type gain struct {
+		Arm  string
+		N    int
+		Mean string
+		SD   string
+	}
+
+	type period struct {
+		Title string
+		Gains []gain
+	}
+
+	type texts struct {
+		Title         string
+		Error         string
+		Help          string
+		Trajectories  string
+		Cohort        string
+		Periods       string
+		PeriodsHelp   string
+		Arm           string
+		MeanGain      string
+		SD            string
+		Retention     string
+		RetentionHelp string
+		NoRetention   string
+	}
+
+	content := struct {
+		Breadcrumbs  template.HTML
+		Experiment   edulab.Experiment
+		Labels       []string
+		Series       []series
+		Trajectories []trajectory
+		Periods      []period
+		Retention    []gain
+		Texts        texts
+	}{
+		Breadcrumbs: presenter.ExperimentBreadcrumb(experiment, printer),
+		Experiment:  experiment,
+		Texts: texts{
+			Title:         printer.Sprintf("Timepoints"),
+			Help:          printer.Sprintf("Mean scores on the questions asked in more than one assessment, in the order of the timepoints."),
+			Trajectories:  printer.Sprintf("Trajectories"),
+			Cohort:        printer.Sprintf("Cohort"),
+			Periods:       printer.Sprintf("Gains by Period"),
+			PeriodsHelp:   printer.Sprintf("Gains between consecutive timepoints, grouped by the arm each cohort was in during the period. Cohorts that cross over count towards a different arm in each period."),
+			Arm:           printer.Sprintf("Arm"),
+			MeanGain:      printer.Sprintf("Mean gain"),
+			SD:            printer.Sprintf("SD"),
+			Retention:     printer.Sprintf("Retention"),
+			RetentionHelp: printer.Sprintf("Retention gain is the delayed score minus the post-assessment score. Negative values show how much was forgotten."),
+			NoRetention:   printer.Sprintf("Add a delayed post-assessment to measure retention."),
+		},
+	}
+
+	title := content.Texts.Title
+	page.Title = title
+	page.Partials = []string{"results_timepoints"}
+
+	res, err := result.New(srv.DB, experiment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	if !res.Valid() {
+		content.Texts.Error = printer.Sprintf("No data available yet")
+		page.Content = content
+		srv.render(w, page)
+		return
+	}
+
+	err = res.Load()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	_, items := res.ComparisonPairs()
+	if len(items) == 0 {
+		content.Texts.Error = printer.Sprintf("No comparison pairs available yet")
+		page.Content = content
+		srv.render(w, page)
+		return
+	}
+
+	scores, err := res.TimepointScores(items)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	labels := timepointLabels(assessments, printer)
+	for _, id := range res.Timepoints() {
+		content.Labels = append(content.Labels, labels[id])
+	}
+
+	armNames := make(map[string]string)
+	for _, a := range arms {
+		armNames[a.ID] = a.Name
+	}
+
+	cohortNames := make(map[string]string)
+	for _, c := range cohorts {
+		cohortNames[c.ID] = c.Name
+	}
+
+	for _, t := range res.Trajectories(scores) {
+		s := series{Label: cohortNames[t.CohortID]}
+		row := trajectory{Cohort: cohortNames[t.CohortID]}
+		for _, ts := range t.Scores {
+			if ts.N == 0 {
+				s.Data = append(s.Data, nil)
+				row.Scores = append(row.Scores, "-")
+				continue
+			}
+			mean := ts.Mean
+			s.Data = append(s.Data, &mean)
+			row.Scores = append(row.Scores, printer.Sprintf("%.3f ± %.3f (n = %d)", ts.Mean, ts.SD, ts.N))
+		}
+		content.Series = append(content.Series, s)
+		content.Trajectories = append(content.Trajectories, row)
+	}
+
+	armGains := func(gains []result.ArmGain) []gain {
+		var rows []gain
+		for _, g := range gains {
+			rows = append(rows, gain{
+				Arm:  armNames[g.ArmID],
+				N:    g.N,
+				Mean: printer.Sprintf("%.3f", g.Mean),
+				SD:   printer.Sprintf("%.3f", g.SD),
+			})
+		}
+		return rows
+	}
+
+	for _, p := range res.PeriodGains(scores) {
+		content.Periods = append(content.Periods, period{
+			Title: printer.Sprintf("%s to %s", labels[p.From], labels[p.To]),
+			Gains: armGains(p.Arms),
+		})
+	}
+
+	content.Retention = armGains(res.Retention(scores))
+
+	page.Content = content
+	srv.render(w, page)
+}
+
+// timepointLabels names the assessments by their type, adding the public ID
+// when several assessments share the same type.
+func timepointLabels(assessments []edulab.Assessment, printer *message.Printer) map[string]string {
+	count := make(map[edulab.AssessmentType]int)
+	for _, a := range assessments {
+		count[a.Type]++
+	}
+
+	labels := make(map[string]string)
+	for _, a := range assessments {
+		label := presenter.AssessmentType(printer, a.Type)
+		if count[a.Type] > 1 {
+			label = printer.Sprintf("%s (%s)", label, a.PublicID)
+		}
+		labels[a.ID] = label
+	}
+
+	return labels
+}
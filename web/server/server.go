@@ -7,6 +7,7 @@ import (
 
 	"github.com/louisbranch/edulab"
 	"github.com/louisbranch/edulab/web"
+	"github.com/louisbranch/edulab/wizard"
 )
 
 type Server struct {
@@ -80,27 +81,8 @@ Compare cohorts, **measure learning gains**, and adapt strategies to elevate stu
 	srv.render(w, page)
 }
 
-var alphanum = []rune("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ")
-
+// newPublicID returns a random public ID with a group of characters for each
+// length, separated by dashes.
 func (srv *Server) newPublicID(lens ...int) string {
-	sum := 0
-	for _, l := range lens {
-		sum += l
-	}
-
-	b := make([]rune, sum)
-	for i := range b {
-		b[i] = alphanum[srv.Random.Intn(len(alphanum))]
-	}
-
-	pid := ""
-	for i, l := range lens {
-		pid += string(b[:l])
-		if i < len(lens)-1 {
-			pid += "-"
-		}
-		b = b[l:]
-	}
-
-	return pid
+	return wizard.PublicID(srv.Random, lens...)
 }
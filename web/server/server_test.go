@@ -1,13 +1,22 @@
 package server
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
 	"math/rand"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/louisbranch/edulab"
 	"github.com/louisbranch/edulab/mock"
+	"github.com/louisbranch/edulab/web/presenter"
+	"github.com/louisbranch/edulab/wizard"
 )
 
 func serverTest(srv *Server, req *http.Request) *httptest.ResponseRecorder {
@@ -44,27 +53,58 @@ func TestGetRoutes(t *testing.T) {
 		{path: "/experiments/E1", statusCode: http.StatusOK},
 		{path: "/experiments/E2", statusCode: http.StatusNotFound},
 		{path: "/experiments/E1/edit", statusCode: http.StatusOK},
+		{path: "/experiments/E1/export", statusCode: http.StatusOK},
+		{path: "/experiments/E2/export", statusCode: http.StatusNotFound},
 		{path: "/experiments/E1/assessments", statusCode: http.StatusOK},
+		{path: "/experiments/E1/assessments/new", statusCode: http.StatusOK},
+		{path: "/experiments/E1/assessments/import", statusCode: http.StatusNotFound},
 		{path: "/experiments/E1/assessments/A1", statusCode: http.StatusOK},
 		{path: "/experiments/E1/assessments/A2", statusCode: http.StatusNotFound},
 		{path: "/experiments/E1/assessments/A1/preview", statusCode: http.StatusOK},
+		{path: "/experiments/E1/assessments/A1/export", statusCode: http.StatusOK},
+		{path: "/experiments/E1/assessments/A1/export?format=moodle", statusCode: http.StatusOK},
+		{path: "/experiments/E1/assessments/A1/export?format=pdf", statusCode: http.StatusNotFound},
+		{path: "/experiments/E1/assessments/A1/responses", statusCode: http.StatusNotFound},
 		{path: "/experiments/E1/assessments/A1/questions/", statusCode: http.StatusNotFound},
 		{path: "/experiments/E1/assessments/A1/questions/new", statusCode: http.StatusOK},
 		{path: "/experiments/E1/assessments/A1/questions/1", statusCode: http.StatusOK},
 		{path: "/experiments/E1/assessments/A1/questions/2", statusCode: http.StatusNotFound},
+		{path: "/experiments/E1/assessments/A1/questions/1/translations", statusCode: http.StatusOK},
+		{path: "/experiments/E1/assessments/A1/questions/2/translations", statusCode: http.StatusNotFound},
+		{path: "/experiments/E1/assessments/A1/questions/1/choices", statusCode: http.StatusNotFound},
 		{path: "/experiments/E1/demographics", statusCode: http.StatusOK},
+		{path: "/experiments/E1/demographics/new", statusCode: http.StatusOK},
+		{path: "/experiments/E1/demographics/1", statusCode: http.StatusOK},
+		{path: "/experiments/E1/demographics/9", statusCode: http.StatusNotFound},
+		{path: "/experiments/E1/demographics/1/delete", statusCode: http.StatusNotFound},
+		{path: "/experiments/E1/arms", statusCode: http.StatusOK},
+		{path: "/experiments/E1/arms/new", statusCode: http.StatusOK},
+		{path: "/experiments/E1/arms/R1", statusCode: http.StatusOK},
+		{path: "/experiments/E1/arms/A9", statusCode: http.StatusNotFound},
 		{path: "/experiments/E1/cohorts", statusCode: http.StatusOK},
+		{path: "/experiments/E1/cohorts/new", statusCode: http.StatusOK},
 		{path: "/experiments/E1/cohorts/C1", statusCode: http.StatusOK},
 		{path: "/experiments/E1/cohorts/C2", statusCode: http.StatusNotFound},
+		{path: "/experiments/E1/randomization", statusCode: http.StatusOK},
 		{path: "/experiments/E1/participate", statusCode: http.StatusOK},
 		{path: "/experiments/E1/results", statusCode: http.StatusNotFound},
 		{path: "/experiments/E1/results/demographics", statusCode: http.StatusOK},
 		{path: "/experiments/E1/results/assessments", statusCode: http.StatusOK},
 		{path: "/experiments/E1/results/gains", statusCode: http.StatusOK},
+		{path: "/experiments/E1/results/gains?option=1", statusCode: http.StatusOK},
+		{path: "/experiments/E1/results/gains?arm=R1", statusCode: http.StatusOK},
+		{path: "/experiments/E1/results/subgroups", statusCode: http.StatusOK},
+		{path: "/experiments/E1/results/baseline", statusCode: http.StatusOK},
+		{path: "/experiments/E1/results/attrition", statusCode: http.StatusOK},
+		{path: "/experiments/E1/results/arms", statusCode: http.StatusOK},
+		{path: "/experiments/E1/results/timepoints", statusCode: http.StatusOK},
+		{path: "/experiments/E1/planner", statusCode: http.StatusOK},
+		{path: "/experiments/E1/planner?effect_size=0.8&alpha=0.05&power=0.9&cohorts=3", statusCode: http.StatusOK},
 		{path: "/E1-C1-A1", statusCode: http.StatusOK},
 		{path: "/E2-C1-A1", statusCode: http.StatusNotFound},
 		{path: "/E1-C2-A1", statusCode: http.StatusNotFound},
 		{path: "/E1-C1-A2", statusCode: http.StatusNotFound},
+		{path: "/E1-A1", statusCode: http.StatusNotFound},
 		{path: "/about", statusCode: http.StatusOK},
 		{path: "/guide", statusCode: http.StatusOK},
 		{path: "/faq", statusCode: http.StatusOK},
@@ -100,9 +140,31 @@ func TestGetRoutes(t *testing.T) {
 		t.Fatalf("failed to create question: %v", err)
 	}
 
+	err = db.CreateDemographic(&edulab.Demographic{
+		ID:           "1",
+		ExperimentID: "1",
+		Text:         "Program",
+		Type:         edulab.InputSingle,
+	})
+	if err != nil {
+		t.Fatalf("failed to create demographic: %v", err)
+	}
+
+	err = db.CreateArm(&edulab.Arm{
+		ID:           "1",
+		ExperimentID: "1",
+		PublicID:     "R1",
+		Name:         "Control",
+		Control:      true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create arm: %v", err)
+	}
+
 	err = db.CreateCohort(&edulab.Cohort{
 		ID:           "1",
 		ExperimentID: "1",
+		ArmID:        "1",
 		PublicID:     "C1",
 		Name:         "Control",
 	})
@@ -129,6 +191,947 @@ func TestGetRoutes(t *testing.T) {
 	}
 }
 
+func TestRandomizationLink(t *testing.T) {
+	db := mock.NewDB()
+	db.CreateExperiment(&edulab.Experiment{ID: "1", PublicID: "E1"})
+	db.CreateAssessment(&edulab.Assessment{ID: "1", ExperimentID: "1", PublicID: "A1",
+		Type: edulab.AssessmentTypePre})
+	db.CreateAssessment(&edulab.Assessment{ID: "2", ExperimentID: "1", PublicID: "A2",
+		Type: edulab.AssessmentTypePost})
+	db.CreateCohort(&edulab.Cohort{ID: "1", ExperimentID: "1", PublicID: "C1"})
+	db.CreateCohort(&edulab.Cohort{ID: "2", ExperimentID: "1", PublicID: "C2"})
+	db.UpdateRandomization(edulab.Randomization{
+		ExperimentID: "1",
+		Method:       edulab.RandomizationBlock,
+		Seed:         3,
+	})
+
+	srv := &Server{DB: db}
+
+	req := httptest.NewRequest("GET", "/E1-A1", nil)
+	res := serverTest(srv, req)
+	if res.Code != http.StatusSeeOther {
+		t.Fatalf("expected status %d, got %d", http.StatusSeeOther, res.Code)
+	}
+
+	pre := res.Header().Get("Location")
+	if pre != "/E1-C1-A1" && pre != "/E1-C2-A1" {
+		t.Fatalf("expected a cohort link, got %s", pre)
+	}
+
+	allocations, _ := db.FindAllocations("1")
+	if len(allocations) != 1 || allocations[0].Seed == 0 {
+		t.Fatalf("expected one seeded allocation, got %v", allocations)
+	}
+
+	cookies := res.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected an access token cookie, got %v", cookies)
+	}
+
+	// The post-assessment keeps the allocated cohort
+	req = httptest.NewRequest("GET", "/E1-A2", nil)
+	req.AddCookie(cookies[0])
+	res = serverTest(srv, req)
+
+	post := res.Header().Get("Location")
+	if post != pre[:len(pre)-1]+"2" {
+		t.Errorf("expected post-assessment link in the same cohort as %s, got %s", pre, post)
+	}
+
+	allocations, _ = db.FindAllocations("1")
+	if len(allocations) != 1 {
+		t.Errorf("expected a single allocation, got %d", len(allocations))
+	}
+
+	// Another cohort's link sends allocated participants back to theirs
+	other := "/E1-C1-A2"
+	if pre == "/E1-C1-A1" {
+		other = "/E1-C2-A2"
+	}
+	req = httptest.NewRequest("GET", other, nil)
+	req.AddCookie(cookies[0])
+	res = serverTest(srv, req)
+	if got := res.Header().Get("Location"); res.Code != http.StatusSeeOther || got != post {
+		t.Errorf("expected a redirect to %s, got %d %s", post, res.Code, got)
+	}
+
+	// Participants who joined through a cohort link were never allocated
+	db.CreateParticipant(&edulab.Participant{ExperimentID: "1", CohortID: "1", AccessToken: "joined"})
+	req = httptest.NewRequest("GET", "/E1-C2-A2", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: "joined"})
+	res = serverTest(srv, req)
+	if res.Code != http.StatusOK {
+		t.Errorf("expected status %d for a participant without allocation, got %d %s",
+			http.StatusOK, res.Code, res.Header().Get("Location"))
+	}
+}
+
+func TestCohortLinkWithoutRandomization(t *testing.T) {
+	db := mock.NewDB()
+	db.CreateExperiment(&edulab.Experiment{ID: "1", PublicID: "E1"})
+	db.CreateAssessment(&edulab.Assessment{ID: "1", ExperimentID: "1", PublicID: "A1",
+		Type: edulab.AssessmentTypePre})
+	db.CreateCohort(&edulab.Cohort{ID: "1", ExperimentID: "1", PublicID: "C1"})
+	db.CreateCohort(&edulab.Cohort{ID: "2", ExperimentID: "1", PublicID: "C2"})
+	db.CreateParticipant(&edulab.Participant{ID: "1", ExperimentID: "1", CohortID: "1",
+		AccessToken: "token"})
+
+	srv := &Server{DB: db}
+
+	// Without randomization, the link of another cohort is not redirected
+	req := httptest.NewRequest("GET", "/E1-C2-A1", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: "token"})
+	res := serverTest(srv, req)
+	if res.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, res.Code)
+	}
+}
+
+func TestUpdateCohort(t *testing.T) {
+	db := &mock.DB{}
+	db.CreateExperiment(&edulab.Experiment{ID: "1", PublicID: "E1"})
+	db.CreateArm(&edulab.Arm{ID: "1", ExperimentID: "1", PublicID: "R1", Name: "Lecture", Control: true})
+	db.CreateCohort(&edulab.Cohort{ID: "1", ExperimentID: "1", ArmID: "1", PublicID: "C1", Name: "Section 1"})
+
+	srv := &Server{DB: db}
+
+	form := url.Values{"name": {"Section 2"}, "arm": {""}}
+	req := httptest.NewRequest("POST", "/experiments/E1/cohorts/C1", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res := serverTest(srv, req)
+	if res.Code != http.StatusSeeOther {
+		t.Fatalf("expected status %d, got %d", http.StatusSeeOther, res.Code)
+	}
+
+	cohort, _ := db.FindCohort("1", "C1")
+	arms, _ := db.FindArms("1")
+	if cohort.Name != "Section 2" || cohort.ArmID != "1" || len(arms) != 1 {
+		t.Errorf("expected the cohort to keep its arm, got %v %v", cohort, arms)
+	}
+}
+
+func TestUpdateQuestion(t *testing.T) {
+	db := mock.NewDB()
+	db.CreateExperiment(&edulab.Experiment{ID: "1", PublicID: "E1"})
+	db.CreateAssessment(&edulab.Assessment{ID: "1", ExperimentID: "1", PublicID: "A1",
+		Type: edulab.AssessmentTypePre})
+	db.CreateQuestion(&edulab.Question{ID: "1", AssessmentID: "1", Text: "Waht causes seasons?",
+		Type: edulab.InputSingle})
+	db.CreateQuestionChoice(&edulab.QuestionChoice{ID: "1", QuestionID: "1", Text: "Tilt"})
+	db.CreateQuestionChoice(&edulab.QuestionChoice{ID: "2", QuestionID: "1", Text: "Distance"})
+
+	srv := &Server{DB: db}
+
+	form := url.Values{
+		"text":         {"What causes seasons?"},
+		"type":         {"single"},
+		"anchor":       {" seasons "},
+		"choice_ids[]": {"1", "2"},
+		"choices[]":    {"The tilt", ""},
+		"correct[]":    {"0"},
+	}
+	req := httptest.NewRequest("POST", "/experiments/E1/assessments/A1/questions/1",
+		strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res := serverTest(srv, req)
+	if res.Code != http.StatusFound {
+		t.Fatalf("expected status %d, got %d", http.StatusFound, res.Code)
+	}
+
+	question, _ := db.FindQuestion("1", "1")
+	if question.Text != "What causes seasons?" || question.Anchor != "seasons" {
+		t.Errorf("expected the question to be updated, got %v", question)
+	}
+
+	choices, _ := db.FindQuestionChoices("1")
+	want := []edulab.QuestionChoice{
+		{ID: "1", QuestionID: "1", Text: "The tilt", IsCorrect: true},
+		{ID: "2", QuestionID: "1", Text: "Distance"},
+	}
+	if !reflect.DeepEqual(choices, want) {
+		t.Errorf("expected choices %v, got %v", want, choices)
+	}
+}
+
+func TestQuestionProblems(t *testing.T) {
+	db := mock.NewDB()
+	db.CreateExperiment(&edulab.Experiment{ID: "1", PublicID: "E1"})
+	db.CreateAssessment(&edulab.Assessment{ID: "1", ExperimentID: "1", PublicID: "A1",
+		Type: edulab.AssessmentTypePre})
+	db.CreateQuestion(&edulab.Question{ID: "1", AssessmentID: "1", Text: "What causes seasons?",
+		Type: edulab.InputSingle, Anchor: "seasons"})
+	db.CreateQuestion(&edulab.Question{ID: "2", AssessmentID: "1", Text: "What causes tides?",
+		Type: edulab.InputSingle})
+
+	srv := &Server{DB: db}
+
+	tests := []struct {
+		name string
+		path string
+		form url.Values
+	}{
+		{"duplicate anchor on create", "/experiments/E1/assessments/A1/questions",
+			url.Values{"text": {"Why is it cold?"}, "type": {"text"}, "anchor": {"seasons"}}},
+		{"unknown type on create", "/experiments/E1/assessments/A1/questions",
+			url.Values{"text": {"Why is it cold?"}, "type": {"essay"}}},
+		{"duplicate anchor on update", "/experiments/E1/assessments/A1/questions/2",
+			url.Values{"text": {"What causes tides?"}, "type": {"single"}, "anchor": {"seasons"}}},
+		{"unknown type on update", "/experiments/E1/assessments/A1/questions/2",
+			url.Values{"text": {"What causes tides?"}, "type": {"essay"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			res := serverTest(srv, req)
+			if res.Code != http.StatusUnprocessableEntity {
+				t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, res.Code)
+			}
+		})
+	}
+
+	questions, _ := db.FindQuestions("1")
+	if len(questions) != 2 || questions[1].Anchor != "" || questions[1].Type != edulab.InputSingle {
+		t.Errorf("expected the questions to be unchanged, got %v", questions)
+	}
+
+	// A question keeps its own link ID
+	form := url.Values{"text": {"What causes seasons?"}, "type": {"single"}, "anchor": {"seasons"}}
+	req := httptest.NewRequest("POST", "/experiments/E1/assessments/A1/questions/1",
+		strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res := serverTest(srv, req)
+	if res.Code != http.StatusFound {
+		t.Errorf("expected status %d, got %d", http.StatusFound, res.Code)
+	}
+}
+
+func TestDuplicateAssessment(t *testing.T) {
+	db := mock.NewDB()
+	db.CreateExperiment(&edulab.Experiment{ID: "1", PublicID: "E1"})
+	db.CreateAssessment(&edulab.Assessment{ID: "1", ExperimentID: "1", PublicID: "A1",
+		Type: edulab.AssessmentTypePre})
+	db.CreateAssessment(&edulab.Assessment{ID: "2", ExperimentID: "1", PublicID: "A2",
+		Type: edulab.AssessmentTypeMid})
+	db.CreateQuestion(&edulab.Question{ID: "1", AssessmentID: "1", Text: "What causes seasons?",
+		Type: edulab.InputSingle, Anchor: "A1-3"})
+	db.CreateQuestion(&edulab.Question{ID: "2", AssessmentID: "1", Text: "How long is a year?",
+		Type: edulab.InputSingle})
+	db.CreateQuestion(&edulab.Question{ID: "3", AssessmentID: "2", Text: "How long is a year?",
+		Type: edulab.InputSingle})
+	db.CreateQuestion(&edulab.Question{ID: "4", AssessmentID: "1", Text: "Why is the sky blue?",
+		Type: edulab.InputText})
+	for i, text := range []string{"Tilt", "Distance", "Rotation"} {
+		db.CreateQuestionChoice(&edulab.QuestionChoice{QuestionID: "1", Text: text, IsCorrect: i == 0})
+	}
+	db.CreateQuestionChoice(&edulab.QuestionChoice{QuestionID: "2", Text: "365 days", IsCorrect: true})
+	db.UpdateTranslation(edulab.Translation{QuestionID: "1", ChoiceID: "1", Lang: "pt-BR", Text: "Inclinação"})
+
+	srv := &Server{DB: db, Random: rand.New(rand.NewSource(1))}
+
+	form := url.Values{"type": {"post"}, "shuffle": {"on"}}
+	req := httptest.NewRequest("POST", "/experiments/E1/assessments/A1/duplicate",
+		strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res := serverTest(srv, req)
+	if res.Code != http.StatusSeeOther {
+		t.Fatalf("expected status %d, got %d", http.StatusSeeOther, res.Code)
+	}
+
+	assessments, _ := db.FindAssessments("1")
+	if len(assessments) != 3 || assessments[2].Type != edulab.AssessmentTypePost {
+		t.Fatalf("expected a new post-assessment, got %v", assessments)
+	}
+
+	questions, _ := db.FindQuestions(assessments[2].ID)
+	// The link ID of the third question is taken by the first one
+	if len(questions) != 3 || questions[0].Anchor != "A1-3" || questions[1].Anchor != "" ||
+		questions[2].Anchor != "A1-4" {
+		t.Fatalf("expected copies linked to the originals, got %v", questions)
+	}
+
+	source, _ := db.FindQuestion("1", "4")
+	if source.Anchor != "A1-4" {
+		t.Errorf("expected the source question to be linked to its copy, got %q", source.Anchor)
+	}
+
+	// Questions matched by text elsewhere keep that match, and other
+	// assessments are left unchanged
+	source, _ = db.FindQuestion("1", "2")
+	mid, _ := db.FindQuestion("2", "3")
+	if source.Anchor != "" || mid.Anchor != "" {
+		t.Errorf("expected questions matched by text to stay unanchored, got %q and %q",
+			source.Anchor, mid.Anchor)
+	}
+
+	choices, _ := db.FindQuestionChoices(assessments[2].ID)
+	correct := make(map[string]bool)
+	for _, c := range choices {
+		correct[c.Text] = c.IsCorrect
+	}
+	want := map[string]bool{"Tilt": true, "Distance": false, "Rotation": false, "365 days": true}
+	if !reflect.DeepEqual(correct, want) {
+		t.Errorf("expected choices %v, got %v", want, correct)
+	}
+
+	translations, _ := db.FindTranslations(assessments[2].ID)
+	if len(translations) != 1 || translations[0].QuestionID != questions[0].ID {
+		t.Fatalf("expected the translation to be copied, got %v", translations)
+	}
+	for _, c := range choices {
+		if c.ID == translations[0].ChoiceID && c.Text != "Tilt" {
+			t.Errorf("expected the translation of Tilt, got %q", c.Text)
+		}
+	}
+}
+
+func TestDuplicateType(t *testing.T) {
+	pre := edulab.Assessment{ID: "1", Type: edulab.AssessmentTypePre}
+	post := edulab.Assessment{ID: "2", Type: edulab.AssessmentTypePost}
+	delayed := edulab.Assessment{ID: "3", Type: edulab.AssessmentTypeDelayed}
+
+	tests := []struct {
+		assessment  edulab.Assessment
+		assessments []edulab.Assessment
+		want        edulab.AssessmentType
+	}{
+		{pre, []edulab.Assessment{pre}, edulab.AssessmentTypePost},
+		{pre, []edulab.Assessment{pre, post}, edulab.AssessmentTypeMid},
+		{post, []edulab.Assessment{pre, post}, edulab.AssessmentTypeDelayed},
+		{delayed, []edulab.Assessment{pre, post, delayed}, edulab.AssessmentTypeDelayed},
+	}
+
+	for _, tt := range tests {
+		if got := duplicateType(tt.assessment, tt.assessments); got != tt.want {
+			t.Errorf("duplicateType(%s, %v) = %s, want %s", tt.assessment.Type, tt.assessments, got, tt.want)
+		}
+	}
+}
+
+func TestCloneExperiment(t *testing.T) {
+	db := mock.NewDB()
+	db.CreateExperiment(&edulab.Experiment{ID: "1", PublicID: "E1", Name: "Fall"})
+	db.CreateAssessment(&edulab.Assessment{ID: "1", ExperimentID: "1", PublicID: "A1",
+		Type: edulab.AssessmentTypePre})
+	db.CreateQuestion(&edulab.Question{ID: "1", AssessmentID: "1", Text: "What causes seasons?",
+		Type: edulab.InputSingle, Anchor: "seasons"})
+	db.CreateQuestionChoice(&edulab.QuestionChoice{ID: "1", QuestionID: "1", Text: "Tilt", IsCorrect: true})
+	db.UpdateTranslation(edulab.Translation{QuestionID: "1", ChoiceID: "1", Lang: "pt-BR", Text: "Inclinação"})
+	db.CreateArm(&edulab.Arm{ID: "1", ExperimentID: "1", PublicID: "E1-1", Name: "Control", Control: true})
+	db.CreateCohort(&edulab.Cohort{ID: "1", ExperimentID: "1", ArmID: "1", PublicID: "C1", Name: "Section 1"})
+	db.CreateDemographic(&edulab.Demographic{ID: "1", ExperimentID: "1", Text: "Gender", Type: edulab.InputSingle})
+	db.CreateDemographicOption(&edulab.DemographicOption{ID: "1", DemographicID: "1", Text: "Female"})
+	db.CreateParticipant(&edulab.Participant{ID: "1", ExperimentID: "1", CohortID: "1", AccessToken: "T1"})
+	db.UpdateRandomization(edulab.Randomization{ExperimentID: "1", Method: edulab.RandomizationStratified,
+		DemographicID: "1", Seed: 3})
+
+	srv := &Server{DB: db, Random: rand.New(rand.NewSource(1))}
+
+	form := url.Values{"name": {"Spring"}}
+	req := httptest.NewRequest("POST", "/experiments/E1/clone", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res := serverTest(srv, req)
+	if res.Code != http.StatusSeeOther {
+		t.Fatalf("expected status %d, got %d", http.StatusSeeOther, res.Code)
+	}
+
+	experiments, _ := db.FindExperiments()
+	if len(experiments) != 2 {
+		t.Fatalf("expected 2 experiments, got %d", len(experiments))
+	}
+
+	clone := experiments[1]
+	if clone.Name != "Spring" || clone.PublicID == "E1" ||
+		res.Header().Get("Location") != "/experiments/"+clone.PublicID {
+		t.Fatalf("expected a new experiment, got %v", clone)
+	}
+
+	assessments, _ := db.FindAssessments(clone.ID)
+	if len(assessments) != 1 || assessments[0].PublicID == "A1" {
+		t.Fatalf("expected a copy of the assessment, got %v", assessments)
+	}
+
+	questions, _ := db.FindQuestions(assessments[0].ID)
+	choices, _ := db.FindQuestionChoices(assessments[0].ID)
+	if len(questions) != 1 || questions[0].Anchor != "seasons" || len(choices) != 1 || !choices[0].IsCorrect {
+		t.Errorf("expected copies of the questions and choices, got %v %v", questions, choices)
+	}
+
+	translations, _ := db.FindTranslations(assessments[0].ID)
+	if len(translations) != 1 || translations[0].QuestionID != questions[0].ID || translations[0].ChoiceID != choices[0].ID {
+		t.Errorf("expected a copy of the translation, got %v", translations)
+	}
+
+	arms, _ := db.FindArms(clone.ID)
+	cohorts, _ := db.FindCohorts(clone.ID)
+	if len(arms) != 1 || len(cohorts) != 1 || cohorts[0].ArmID != arms[0].ID {
+		t.Errorf("expected the cohort in the copied arm, got %v %v", arms, cohorts)
+	}
+
+	demographics, _ := db.FindDemographics(clone.ID)
+	options, _ := db.FindDemographicOptions(clone.ID)
+	if len(demographics) != 1 || len(options) != 1 {
+		t.Errorf("expected copies of the demographics, got %v %v", demographics, options)
+	}
+
+	randomization, _ := db.FindRandomization(clone.ID)
+	if randomization.Method != edulab.RandomizationStratified || randomization.DemographicID != demographics[0].ID {
+		t.Errorf("expected the randomization settings to be copied, got %v", randomization)
+	}
+
+	participants, _ := db.FindParticipants(clone.ID)
+	if len(participants) != 0 {
+		t.Errorf("expected no participants, got %d", len(participants))
+	}
+}
+
+func TestImportExperiment(t *testing.T) {
+	content := `public_id: E1
+name: Seasons
+assessments:
+  - public_id: A1
+    type: pre
+    questions:
+      - text: What causes the seasons?
+        type: single
+        choices:
+          - text: The tilt
+            is_correct: true
+cohorts:
+  - public_id: C1
+    name: Section 1
+force_delete: true
+bootstrap_config:
+  participants: 5
+  assessments:
+    - correct_probabilities: [0.5]
+`
+
+	db := &mock.DB{}
+	db.CreateExperiment(&edulab.Experiment{ID: "1", PublicID: "E1", Name: "Existing"})
+
+	srv := &Server{DB: db, Random: rand.New(rand.NewSource(1))}
+
+	upload := func(content string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		fw, _ := mw.CreateFormFile("file", "seasons.yaml")
+		fw.Write([]byte(content))
+		mw.Close()
+
+		req := httptest.NewRequest("POST", "/experiments/import", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return serverTest(srv, req)
+	}
+
+	res := upload(content)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected a preview with status %d, got %d", http.StatusOK, res.Code)
+	}
+
+	experiments, _ := db.FindExperiments()
+	if len(experiments) != 1 {
+		t.Fatalf("expected the preview not to create the experiment, got %d experiments", len(experiments))
+	}
+
+	res = upload("name: Seasons\nassessments: 3\n")
+	if res.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d for an invalid file, got %d", http.StatusUnprocessableEntity, res.Code)
+	}
+
+	form := url.Values{"confirm": {"1"}, "yaml": {content}}
+	req := httptest.NewRequest("POST", "/experiments/import", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res = serverTest(srv, req)
+	if res.Code != http.StatusSeeOther {
+		t.Fatalf("expected status %d, got %d", http.StatusSeeOther, res.Code)
+	}
+
+	experiments, _ = db.FindExperiments()
+	if len(experiments) != 2 || experiments[0].Name != "Existing" {
+		t.Fatalf("expected a new experiment next to the existing one, got %v", experiments)
+	}
+
+	imported := experiments[1]
+	if imported.PublicID == "E1" || res.Header().Get("Location") != "/experiments/"+imported.PublicID {
+		t.Errorf("expected the experiment to get a new public ID, got %s", imported.PublicID)
+	}
+
+	assessments, _ := db.FindAssessments(imported.ID)
+	if len(assessments) != 1 || assessments[0].PublicID == "A1" {
+		t.Errorf("expected the assessment to get a new public ID, got %v", assessments)
+	}
+
+	if participants, _ := db.FindParticipants(imported.ID); len(participants) != 0 {
+		t.Errorf("expected uploads not to simulate participants, got %d", len(participants))
+	}
+}
+
+func TestImportAssessment(t *testing.T) {
+	item := func(id, correct string) string {
+		return `<assessmentItem identifier="` + id + `" title="` + id + `">
+  <responseDeclaration identifier="RESPONSE" cardinality="single">
+    <correctResponse>` + correct + `</correctResponse>
+  </responseDeclaration>
+  <itemBody>
+    <choiceInteraction responseIdentifier="RESPONSE" maxChoices="1">
+      <prompt>What causes the seasons?</prompt>
+      <simpleChoice identifier="A">The tilt</simpleChoice>
+      <simpleChoice identifier="B">The distance</simpleChoice>
+    </choiceInteraction>
+  </itemBody>
+</assessmentItem>`
+	}
+
+	var pkg bytes.Buffer
+	zw := zip.NewWriter(&pkg)
+	for name, content := range map[string]string{
+		"imsmanifest.xml": `<manifest><resources>
+  <resource identifier="q1" type="imsqti_item_xmlv2p1" href="q1.xml"/>
+  <resource identifier="q2" type="imsqti_item_xmlv2p1" href="q2.xml"/>
+</resources></manifest>`,
+		"q1.xml": item("Q1", "<value>A</value>"),
+		"q2.xml": item("Q2", ""),
+	} {
+		f, _ := zw.Create(name)
+		f.Write([]byte(content))
+	}
+	zw.Close()
+
+	db := &mock.DB{}
+	experiment := &edulab.Experiment{PublicID: "E1", Name: "Seasons"}
+	db.CreateExperiment(experiment)
+
+	srv := &Server{DB: db, Random: rand.New(rand.NewSource(1))}
+
+	upload := func(content []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		mw.WriteField("type", "pre")
+		fw, _ := mw.CreateFormFile("file", "bank.zip")
+		fw.Write(content)
+		mw.Close()
+
+		req := httptest.NewRequest("POST", "/experiments/E1/assessments/import", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return serverTest(srv, req)
+	}
+
+	res := upload([]byte("not a zip"))
+	if res.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d for an invalid file, got %d", http.StatusUnprocessableEntity, res.Code)
+	}
+	srv.Template.(*mock.Template).PopPage()
+
+	res = upload(pkg.Bytes())
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected the skipped items with status %d, got %d", http.StatusOK, res.Code)
+	}
+
+	page := srv.Template.(*mock.Template).PopPage()
+	skipped := reflect.ValueOf(page.Content).FieldByName("Skipped").Interface().([]presenter.Skipped)
+	if len(skipped) != 1 || skipped[0].Item != "Q2" || skipped[0].Reason != "it has no correct response" {
+		t.Errorf("expected item Q2 to be skipped, got %v", skipped)
+	}
+
+	assessments, _ := db.FindAssessments(experiment.ID)
+	if len(assessments) != 1 || assessments[0].Type != edulab.AssessmentTypePre {
+		t.Fatalf("expected a pre-assessment to be created, got %v", assessments)
+	}
+
+	questions, _ := db.FindQuestions(assessments[0].ID)
+	if len(questions) != 1 || questions[0].Text != "What causes the seasons?" {
+		t.Errorf("expected the converted question, got %v", questions)
+	}
+}
+
+func TestImportResponses(t *testing.T) {
+	db := &mock.DB{}
+	db.CreateExperiment(&edulab.Experiment{PublicID: "E1", Name: "Habitable Zones"})
+	db.CreateAssessment(&edulab.Assessment{ExperimentID: "1", PublicID: "A1", Type: edulab.AssessmentTypePre})
+	db.CreateCohort(&edulab.Cohort{ExperimentID: "1", PublicID: "C1", Name: "Control"})
+	db.CreateCohort(&edulab.Cohort{ExperimentID: "1", PublicID: "C2", Name: "Intervention"})
+
+	for _, q := range []edulab.Question{
+		{AssessmentID: "1", Text: "What is a habitable zone?", Type: edulab.InputSingle},
+		{AssessmentID: "1", Text: "What determines habitability?", Type: edulab.InputMultiple},
+		{AssessmentID: "1", Text: "Why?", Type: edulab.InputText},
+	} {
+		db.CreateQuestion(&q)
+	}
+	for _, c := range []edulab.QuestionChoice{
+		{QuestionID: "1", Text: "Where liquid water can exist", IsCorrect: true},
+		{QuestionID: "1", Text: "Where life exists"},
+		{QuestionID: "2", Text: "Distance from the star", IsCorrect: true},
+		{QuestionID: "2", Text: "Size of the star", IsCorrect: true},
+		{QuestionID: "2", Text: "Distance from the galaxy"},
+	} {
+		db.CreateQuestionChoice(&c)
+	}
+
+	srv := &Server{DB: db, Random: rand.New(rand.NewSource(1))}
+
+	responses := "Student,Cohort,Q1,Q2,Q3\nann,c1,A,1;2,Because of water.\nbob,Intervention,B,Size of the star,\n"
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("file", "responses.csv")
+	fw.Write([]byte(responses))
+	mw.Close()
+
+	req := httptest.NewRequest("POST", "/experiments/E1/assessments/A1/responses", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	res := serverTest(srv, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected the responses to be checked with status %d, got %d", http.StatusOK, res.Code)
+	}
+
+	page := srv.Template.(*mock.Template).PopPage()
+	report := reflect.ValueOf(page.Content).FieldByName("Report").Interface().(*wizard.ResponseImport)
+	if report == nil || report.Responses != 2 || len(report.Unmatched) != 0 {
+		t.Fatalf("expected 2 responses to be matched, got %+v", report)
+	}
+
+	experiment, _ := db.FindExperiment("E1")
+	if participants, _ := db.FindParticipants(experiment.ID); len(participants) != 0 {
+		t.Fatalf("expected the upload to import nothing, got %d participants", len(participants))
+	}
+
+	check := url.Values{
+		"responses": {"Student,Cohort,Q1\nann,Evening,A\n"},
+		"column_0":  {"student"},
+		"column_1":  {"cohort"},
+		"column_2":  {"1"},
+	}
+	req = httptest.NewRequest("POST", "/experiments/E1/assessments/A1/responses?lang=pt-BR", strings.NewReader(check.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	serverTest(srv, req)
+
+	page = srv.Template.(*mock.Template).PopPage()
+	unmatched := reflect.ValueOf(page.Content).FieldByName("Unmatched").Interface().([]presenter.Unmatched)
+	if len(unmatched) != 1 || unmatched[0].Reason != "não corresponde a uma coorte" {
+		t.Errorf("expected the unmatched cohort with a translated reason, got %+v", unmatched)
+	}
+
+	form := url.Values{
+		"responses": {responses},
+		"column_0":  {"student"},
+		"column_1":  {"cohort"},
+		"column_2":  {"1"},
+		"column_3":  {""},
+		"column_4":  {"3"},
+		"action":    {"import"},
+	}
+
+	req = httptest.NewRequest("POST", "/experiments/E1/assessments/A1/responses", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res = serverTest(srv, req)
+	if res.Code != http.StatusSeeOther || res.Header().Get("Location") != "/experiments/E1/results" {
+		t.Fatalf("expected a redirect to the results, got %d %s", res.Code, res.Header().Get("Location"))
+	}
+
+	participants, _ := db.FindParticipants(experiment.ID)
+	if len(participants) != 2 {
+		t.Fatalf("expected 2 participants, got %d", len(participants))
+	}
+
+	assessment, _ := db.FindAssessment(experiment.ID, "A1")
+	participation, err := db.FindParticipation(experiment.ID, assessment.ID, participants[0].ID)
+	if err != nil {
+		t.Fatalf("failed to find participation: %v", err)
+	}
+	if got := string(participation.Answers); got != `{"1":["1"],"3":["Because of water."]}` {
+		t.Errorf("expected the mapped answers, got %s", got)
+	}
+
+	form.Set("column_2", "3")
+	req = httptest.NewRequest("POST", "/experiments/E1/assessments/A1/responses", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res = serverTest(srv, req)
+	if res.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d for a question mapped twice, got %d", http.StatusUnprocessableEntity, res.Code)
+	}
+}
+
+func TestDemographicEditor(t *testing.T) {
+	db := &mock.DB{}
+	db.CreateExperiment(&edulab.Experiment{PublicID: "E1", Name: "Earth's Seasons"})
+	db.CreateDemographic(&edulab.Demographic{ExperimentID: "1", Text: "Gender", Type: edulab.InputSingle})
+	db.CreateDemographicOption(&edulab.DemographicOption{DemographicID: "1", Text: "Female"})
+	db.CreateDemographicOption(&edulab.DemographicOption{DemographicID: "1", Text: "Male"})
+
+	srv := &Server{DB: db}
+
+	post := func(path string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return serverTest(srv, req)
+	}
+
+	res := post("/experiments/E1/demographics", url.Values{
+		"text":         {"Languages spoken"},
+		"type":         {"multiple"},
+		"option_ids[]": {"", "", ""},
+		"options[]":    {"Portuguese", "English", ""},
+		"positions[]":  {"2", "1", "3"},
+	})
+	if res.Code != http.StatusSeeOther {
+		t.Fatalf("expected the demographic to be created, got %d", res.Code)
+	}
+
+	res = post("/experiments/E1/demographics", url.Values{
+		"text": {"Why did you enroll?"},
+		"type": {"text"},
+	})
+	if res.Code != http.StatusSeeOther {
+		t.Fatalf("expected the text demographic to be created, got %d", res.Code)
+	}
+
+	res = post("/experiments/E1/demographics", url.Values{"text": {"Program"}, "type": {"single"}})
+	if res.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d for a demographic without options, got %d", http.StatusUnprocessableEntity, res.Code)
+	}
+
+	// Male is reworded, Female removed and Non-binary added
+	res = post("/experiments/E1/demographics/1", url.Values{
+		"text":         {"Gender identity"},
+		"type":         {"single"},
+		"option_ids[]": {"1", "2", ""},
+		"options[]":    {"", "Man", "Non-binary"},
+		"positions[]":  {"1", "2", "3"},
+	})
+	if res.Code != http.StatusSeeOther {
+		t.Fatalf("expected the demographic to be updated, got %d", res.Code)
+	}
+
+	res = post("/experiments/E1/demographics/3/move", url.Values{"direction": {"up"}})
+	if res.Code != http.StatusSeeOther {
+		t.Fatalf("expected the demographic to be moved, got %d", res.Code)
+	}
+
+	demographics, _ := db.FindDemographics("1")
+	var texts []string
+	for _, d := range demographics {
+		texts = append(texts, d.Text)
+	}
+	if want := []string{"Gender identity", "Why did you enroll?", "Languages spoken"}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("expected demographics %q, got %q", want, texts)
+	}
+
+	options, _ := db.FindDemographicOptions("1")
+	want := []edulab.DemographicOption{
+		{ID: "2", DemographicID: "1", Text: "Man", Position: 1},
+		{ID: "3", DemographicID: "2", Text: "English", Position: 1},
+		{ID: "4", DemographicID: "2", Text: "Portuguese", Position: 2},
+		{ID: "5", DemographicID: "1", Text: "Non-binary", Position: 2},
+	}
+	if !reflect.DeepEqual(options, want) {
+		t.Errorf("expected options %+v, got %+v", want, options)
+	}
+
+	db.UpdateRandomization(edulab.Randomization{ExperimentID: "1", Method: edulab.RandomizationStratified,
+		DemographicID: "1"})
+	res = post("/experiments/E1/demographics/1/delete", nil)
+	if res.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d for the stratification demographic, got %d", http.StatusUnprocessableEntity, res.Code)
+	}
+
+	// The strata of the randomization are the options of its demographic
+	for name, form := range map[string]url.Values{
+		"text":    {"text": {"Gender identity"}, "type": {"text"}},
+		"options": {"text": {"Gender identity"}, "type": {"single"}, "option_ids[]": {"5"}, "options[]": {"Non-binary"}},
+	} {
+		res = post("/experiments/E1/demographics/1", form)
+		if res.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status %d for the stratification demographic losing its %s, got %d",
+				http.StatusUnprocessableEntity, name, res.Code)
+		}
+	}
+
+	res = post("/experiments/E1/demographics/1", url.Values{
+		"text":         {"Gender"},
+		"type":         {"single"},
+		"option_ids[]": {"2", "5"},
+		"options[]":    {"Male", "Non-binary"},
+	})
+	if res.Code != http.StatusSeeOther {
+		t.Errorf("expected the stratification demographic to be reworded, got %d", res.Code)
+	}
+
+	// Answers refer to the options participants selected
+	db.UpdateRandomization(edulab.Randomization{ExperimentID: "1", Method: edulab.RandomizationSimple})
+	srv.Template = &mock.Template{}
+	db.CreateParticipation(&edulab.Participation{ExperimentID: "1", AssessmentID: "1", ParticipantID: "1",
+		Demographics: json.RawMessage(`{"1":["2"]}`)})
+	for name, form := range map[string]url.Values{
+		"removed":   {"text": {"Gender"}, "type": {"single"}, "option_ids[]": {"5"}, "options[]": {"Non-binary"}},
+		"reworded":  {"text": {"Gender"}, "type": {"single"}, "option_ids[]": {"2", "5"}, "options[]": {"Man", "Non-binary"}},
+		"text type": {"text": {"Gender"}, "type": {"text"}},
+	} {
+		res = post("/experiments/E1/demographics/1", form)
+		if res.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status %d for an answered option %s, got %d", http.StatusUnprocessableEntity, name, res.Code)
+		}
+		page := srv.Template.(*mock.Template).PopPage()
+		problems := reflect.ValueOf(page.Content).FieldByName("Problems").Interface().([]string)
+		if len(problems) != 1 || !strings.HasPrefix(problems[0], "Male was answered by participants") {
+			t.Errorf("expected the answered option to be reported, got %q", problems)
+		}
+	}
+
+	res = post("/experiments/E1/demographics/1", url.Values{
+		"text":         {"Gender"},
+		"type":         {"single"},
+		"option_ids[]": {"2", "5"},
+		"options[]":    {"Male", "Nonbinary"},
+	})
+	if res.Code != http.StatusSeeOther {
+		t.Errorf("expected an option without answers to be reworded, got %d", res.Code)
+	}
+
+	res = post("/experiments/E1/demographics/2/delete", nil)
+	if res.Code != http.StatusSeeOther {
+		t.Fatalf("expected the demographic to be deleted, got %d", res.Code)
+	}
+	if options, _ := db.FindDemographicOptions("1"); len(options) != 2 {
+		t.Errorf("expected the options of the deleted demographic to be deleted, got %+v", options)
+	}
+}
+
+func TestTranslations(t *testing.T) {
+	db := &mock.DB{}
+	db.CreateExperiment(&edulab.Experiment{PublicID: "E1", Name: "Earth's Seasons"})
+	db.CreateAssessment(&edulab.Assessment{ExperimentID: "1", PublicID: "A1", Type: edulab.AssessmentTypePre})
+	db.CreateCohort(&edulab.Cohort{ExperimentID: "1", PublicID: "C1", Name: "Section 1"})
+	db.CreateQuestion(&edulab.Question{AssessmentID: "1", Text: "What causes seasons?", Type: edulab.InputSingle})
+	db.CreateQuestionChoice(&edulab.QuestionChoice{QuestionID: "1", Text: "Tilt", IsCorrect: true})
+	db.CreateQuestionChoice(&edulab.QuestionChoice{QuestionID: "1", Text: "Distance"})
+	db.UpdateTranslation(edulab.Translation{QuestionID: "1", ChoiceID: "2", Lang: "pt-BR", Text: "Distância"})
+
+	srv := &Server{DB: db}
+
+	form := url.Values{
+		"text.pt-BR":     {" O que causa as estações? "},
+		"choice.pt-BR.1": {"Inclinação"},
+		"choice.pt-BR.2": {""},
+		"text.en":        {""},
+	}
+	req := httptest.NewRequest("POST", "/experiments/E1/assessments/A1/questions/1/translations",
+		strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res := serverTest(srv, req)
+	if res.Code != http.StatusFound {
+		t.Fatalf("expected status %d, got %d", http.StatusFound, res.Code)
+	}
+
+	translations, _ := db.FindTranslations("1")
+	want := []edulab.Translation{
+		{QuestionID: "1", Lang: "pt-BR", Text: "O que causa as estações?"},
+		{QuestionID: "1", ChoiceID: "1", Lang: "pt-BR", Text: "Inclinação"},
+	}
+	if !reflect.DeepEqual(translations, want) {
+		t.Errorf("expected translations %v, got %v", want, translations)
+	}
+
+	tests := []struct {
+		lang    string
+		text    string
+		choices []string
+	}{
+		{lang: "en", text: "What causes seasons?", choices: []string{"Tilt", "Distance"}},
+		{lang: "pt-BR", text: "O que causa as estações?", choices: []string{"Inclinação", "Distance"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/E1-C1-A1", nil)
+			req.AddCookie(&http.Cookie{Name: "lang", Value: tt.lang})
+
+			srv := &Server{DB: db}
+			res := serverTest(srv, req)
+			if res.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, res.Code)
+			}
+
+			page := srv.Template.(*mock.Template).PopPage()
+			questions := reflect.ValueOf(page.Content).FieldByName("Questions").Interface().([]presenter.Question)
+			if len(questions) != 1 || questions[0].Text != tt.text || questions[0].ID != "1" {
+				t.Fatalf("expected %q, got %v", tt.text, questions)
+			}
+
+			// Answers are given to the same choices in every language
+			for i, id := range []string{"1", "2"} {
+				if c := questions[0].Choices[i]; c.Text != tt.choices[i] || c.ID != id {
+					t.Errorf("expected choice %d to be %q, got %v", i+1, tt.choices[i], c)
+				}
+			}
+		})
+	}
+}
+
+func TestDemographicsLanguage(t *testing.T) {
+	db := &mock.DB{}
+	db.CreateExperiment(&edulab.Experiment{PublicID: "E1", Name: "Earth's Seasons"})
+	db.CreateAssessment(&edulab.Assessment{ExperimentID: "1", PublicID: "A1", Type: edulab.AssessmentTypePre})
+	db.CreateCohort(&edulab.Cohort{ExperimentID: "1", PublicID: "C1", Name: "Control"})
+	experiment, _ := db.FindExperiment("E1")
+	if err := wizard.Demographics(db, experiment); err != nil {
+		t.Fatalf("failed to create demographics: %v", err)
+	}
+	db.CreateDemographic(&edulab.Demographic{ExperimentID: "1", Text: "Program", Type: edulab.InputText, Position: 5})
+
+	tests := []struct {
+		lang   string
+		gender string
+		female string
+	}{
+		{lang: "en", gender: "Gender", female: "Female"},
+		{lang: "pt-BR", gender: "Gênero", female: "Feminino"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/E1-C1-A1", nil)
+			req.AddCookie(&http.Cookie{Name: "lang", Value: tt.lang})
+
+			srv := &Server{DB: db}
+			res := serverTest(srv, req)
+			if res.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, res.Code)
+			}
+
+			page := srv.Template.(*mock.Template).PopPage()
+			demographics := reflect.ValueOf(page.Content).FieldByName("Demographics").Interface().([]presenter.Demographic)
+			if len(demographics) != 5 {
+				t.Fatalf("expected 5 demographics, got %d", len(demographics))
+			}
+
+			gender := demographics[0]
+			if gender.Text != tt.gender || gender.Options[1].Text != tt.female {
+				t.Errorf("expected %q with %q, got %q with %q", tt.gender, tt.female, gender.Text, gender.Options[1].Text)
+			}
+			if program := demographics[4]; program.Text != "Program" {
+				t.Errorf("expected custom demographics as written, got %q", program.Text)
+			}
+		})
+	}
+
+	demographics, _ := db.FindDemographics(experiment.ID)
+	if demographics[0].Text != "Gender" {
+		t.Errorf("expected the message key to be stored, got %q", demographics[0].Text)
+	}
+}
+
 func TestIndex(t *testing.T) {
 	req, err := http.NewRequest("GET", "/", nil)
 	if err != nil {
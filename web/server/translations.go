@@ -0,0 +1,116 @@
+package server
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/web/presenter"
+)
+
+// showTranslations displays the texts of a question and of its choices in
+// every language, to be translated by the instructor.
+func (srv *Server) showTranslations(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, assessment edulab.Assessment, pid string) {
+
+	printer, page := srv.i18n(w, r)
+
+	question, choices, err := srv.findQuestion(assessment, pid)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	translations, err := srv.DB.FindTranslations(assessment.ID)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	var tr []presenter.Translations
+	for _, l := range locales {
+		tr = append(tr, presenter.NewTranslations(l.Code, l.Name, question, translations))
+	}
+
+	page.Title = printer.Sprintf("Translations")
+	page.Partials = []string{"translations"}
+	page.Content = struct {
+		Breadcrumbs  template.HTML
+		Experiment   edulab.Experiment
+		Assessment   edulab.Assessment
+		Question     edulab.Question
+		Choices      []edulab.QuestionChoice
+		Translations []presenter.Translations
+		Texts        interface{}
+	}{
+		Breadcrumbs:  presenter.AssessmentBreadcrumb(experiment, assessment, printer),
+		Experiment:   experiment,
+		Assessment:   assessment,
+		Question:     question,
+		Choices:      choices,
+		Translations: tr,
+		Texts: struct {
+			Title   string
+			Help    string
+			Text    string
+			Choices string
+			Submit  string
+		}{
+			Title:   printer.Sprintf("Translations"),
+			Help:    printer.Sprintf("Participants see the question in the language they chose for the website. Leave a text empty to show it as written. Answers in every language are counted as the same question and choices."),
+			Text:    printer.Sprintf("Text"),
+			Choices: printer.Sprintf("Choices"),
+			Submit:  printer.Sprintf("Update"),
+		},
+	}
+
+	srv.render(w, page)
+}
+
+// updateTranslations saves the texts of a question and of its choices in
+// every language. Empty texts remove their translation.
+func (srv *Server) updateTranslations(w http.ResponseWriter, r *http.Request,
+	experiment edulab.Experiment, assessment edulab.Assessment, pid string) {
+
+	err := r.ParseForm()
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	question, choices, err := srv.findQuestion(assessment, pid)
+	if err != nil {
+		srv.renderError(w, r, err)
+		return
+	}
+
+	for _, l := range locales {
+		translations := []edulab.Translation{{
+			QuestionID: question.ID,
+			Lang:       l.Code,
+			Text:       strings.TrimSpace(r.FormValue("text." + l.Code)),
+		}}
+
+		for _, c := range choices {
+			translations = append(translations, edulab.Translation{
+				QuestionID: question.ID,
+				ChoiceID:   c.ID,
+				Lang:       l.Code,
+				Text:       strings.TrimSpace(r.FormValue("choice." + l.Code + "." + c.ID)),
+			})
+		}
+
+		for _, t := range translations {
+			err = srv.DB.UpdateTranslation(t)
+			if err != nil {
+				srv.renderError(w, r, err)
+				return
+			}
+		}
+	}
+
+	uri := fmt.Sprintf("/experiments/%s/assessments/%s", experiment.PublicID, assessment.PublicID)
+	http.Redirect(w, r, uri, http.StatusFound)
+}
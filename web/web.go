@@ -12,6 +12,7 @@ type Language struct {
 
 type Page struct {
 	ID        string
+	Lang      string
 	Title     string
 	Header    string
 	Website   string
@@ -15,6 +15,7 @@ import (
 
 type BootstrapConfig struct {
 	Participants      int                `yaml:"participants"` // Total number of participants to create
+	Seed              int64              `yaml:"seed"`         // Seeds the answers and demographics of each participant
 	AssessmentConfigs []AssessmentConfig `yaml:"assessments"`  // Configuration for each assessment
 	DemographicConfig DemographicConfig  `yaml:"demographics"` // Configuration for demographics
 }
@@ -86,6 +87,10 @@ func bootstrapParticipants(db edulab.Database, config BootstrapConfig,
 	demographicConfig := config.DemographicConfig
 
 	for i := len(participants); i < config.Participants; i++ {
+		// Each participant draws from their own seed, so bootstrapping more
+		// participants later gives the same data as all at once
+		random := rand.New(rand.NewSource(participantSeed(config.Seed, i)))
+
 		cohort := cohorts[i%numCohorts] // Distribute participants evenly across cohorts
 		participant := edulab.Participant{
 			PublicID:     fmt.Sprintf("%s-P%d", experiment.PublicID, i+1),
@@ -103,7 +108,10 @@ func bootstrapParticipants(db edulab.Database, config BootstrapConfig,
 		demographicResponses := make(map[string]string)
 		for _, demographic := range demographics {
 			options := demographicOptions[demographic.ID]
-			selectedOption := weightedRandomChoice(options, demographicConfig.Probabilities,
+			if len(options) == 0 {
+				continue // Text demographics are left unanswered
+			}
+			selectedOption := weightedRandomChoice(random, options, demographicConfig.Probabilities,
 				demographicConfig.OutlierProbability)
 			demographicResponses[demographic.ID] = selectedOption.ID
 		}
@@ -121,7 +129,7 @@ func bootstrapParticipants(db edulab.Database, config BootstrapConfig,
 			correctProbability := cohortProb
 
 			// Generate answers with bias applied
-			answers, err := generateAnswers(db, assessment, correctProbability, assessmentConfig.BiasFactor)
+			answers, err := generateAnswers(db, random, assessment, correctProbability, assessmentConfig.BiasFactor)
 			if err != nil {
 				return errors.Wrapf(err, "could not generate answers for participant %s", participant.PublicID)
 			}
@@ -150,7 +158,7 @@ func bootstrapParticipants(db edulab.Database, config BootstrapConfig,
 	return nil
 }
 
-func generateAnswers(db edulab.Database, assessment edulab.Assessment,
+func generateAnswers(db edulab.Database, random *rand.Rand, assessment edulab.Assessment,
 	correctProbability float64, biasFactor float64) ([]byte, error) {
 
 	questions, err := db.FindQuestions(assessment.ID)
@@ -174,7 +182,7 @@ func generateAnswers(db edulab.Database, assessment edulab.Assessment,
 		}
 
 		// Add selected choices to answers map
-		selected := selectChoices(question, choices, correctProbability, biasFactor)
+		selected := selectChoices(random, question, choices, correctProbability, biasFactor)
 		sort.Strings(selected)
 		answers[question.ID] = selected
 	}
@@ -182,7 +190,7 @@ func generateAnswers(db edulab.Database, assessment edulab.Assessment,
 	return json.Marshal(answers)
 }
 
-func selectChoices(question edulab.Question, choices []edulab.QuestionChoice,
+func selectChoices(random *rand.Rand, question edulab.Question, choices []edulab.QuestionChoice,
 	correctProbability float64, biasFactor float64) []string {
 
 	var correctChoices, incorrectChoices, selectedChoices []string
@@ -201,32 +209,32 @@ func selectChoices(question edulab.Question, choices []edulab.QuestionChoice,
 	switch question.Type {
 	case "single":
 		// Single choice: Use correctProbability to pick correct vs. incorrect
-		if rand.Float64() < correctProbability {
+		if random.Float64() < correctProbability {
 			// Pick the correct choice
 			if len(correctChoices) > 0 {
 				selectedChoices = append(selectedChoices, correctChoices[0])
 			}
 		} else {
 			// Pick a "biased" incorrect choice using seeded random for consistency
-			if rand.Float64() < biasFactor && len(incorrectChoices) > 0 {
+			if random.Float64() < biasFactor && len(incorrectChoices) > 0 {
 				// Seeded random generator to consistently select the same preferred incorrect choice per question
 				selectedChoices = append(selectedChoices, incorrectChoices[seededRand.Intn(len(incorrectChoices))])
 			} else if len(incorrectChoices) > 0 {
-				// Use any random incorrect choice with the global random generator
-				selectedChoices = append(selectedChoices, incorrectChoices[rand.Intn(len(incorrectChoices))])
+				// Use any random incorrect choice
+				selectedChoices = append(selectedChoices, incorrectChoices[random.Intn(len(incorrectChoices))])
 			}
 		}
 
 	case "multiple":
 		// Multiple choice: Use correctProbability to pick all correct or a mix
-		if rand.Float64() < correctProbability {
+		if random.Float64() < correctProbability {
 			// Pick all correct choices
 			selectedChoices = append(selectedChoices, correctChoices...)
 		} else {
 			// Pick a random subset of correct and/or incorrect choices
-			numChoices := rand.Intn(len(choices) + 1) // Pick any number of choices from 0 to all
+			numChoices := random.Intn(len(choices) + 1) // Pick any number of choices from 0 to all
 			allChoices := append(correctChoices, incorrectChoices...)
-			rand.Shuffle(len(allChoices), func(i, j int) {
+			random.Shuffle(len(allChoices), func(i, j int) {
 				allChoices[i], allChoices[j] = allChoices[j], allChoices[i]
 			})
 			selectedChoices = append(selectedChoices, allChoices[:numChoices]...)
@@ -236,17 +244,16 @@ func selectChoices(question edulab.Question, choices []edulab.QuestionChoice,
 	return selectedChoices
 }
 
-func weightedRandomChoice(options []edulab.DemographicOption,
-	probabilities []float64, outlierProbability float64) edulab.DemographicOption {
+func weightedRandomChoice(random *rand.Rand, options []edulab.DemographicOption,
+	configured []float64, outlierProbability float64) edulab.DemographicOption {
 	if len(options) == 0 {
 		return edulab.DemographicOption{} // No options available
 	}
 
-	// Ensure probabilities match the number of options, default to 0 for extra options
-	if len(probabilities) < len(options) {
-		diff := len(options) - len(probabilities)
-		probabilities = append(probabilities, make([]float64, diff)...)
-	}
+	// Copy the probabilities, defaulting to 0 for extra options, so the
+	// configuration isn't normalized in place
+	probabilities := make([]float64, len(options))
+	copy(probabilities, configured)
 
 	// Normalize probabilities to sum to 1
 	total := 0.0
@@ -260,12 +267,12 @@ func weightedRandomChoice(options []edulab.DemographicOption,
 	}
 
 	// Outlier check: override with a random outlier if probability is met
-	if rand.Float64() < outlierProbability {
-		return options[rand.Intn(len(options))]
+	if random.Float64() < outlierProbability {
+		return options[random.Intn(len(options))]
 	}
 
 	// Weighted random selection
-	r := rand.Float64()
+	r := random.Float64()
 	cumulative := 0.0
 	for i, option := range options {
 		cumulative += probabilities[i]
@@ -278,6 +285,14 @@ func weightedRandomChoice(options []edulab.DemographicOption,
 	return options[0]
 }
 
+// participantSeed derives the seed of a bootstrapped participant from the
+// bootstrap seed.
+func participantSeed(seed int64, participant int) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%d", seed, participant)
+	return int64(h.Sum64() >> 1)
+}
+
 // Helper function to convert a string (question ID) to a consistent seed value
 func hashStringToSeed(s string) int64 {
 	h := fnv.New32a()
@@ -0,0 +1,84 @@
+package wizard
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/mock"
+)
+
+// bootstrapped creates an experiment with the given bootstrap configuration
+// and returns its participations.
+func bootstrapped(t *testing.T, configs ...BootstrapConfig) []edulab.Participation {
+	t.Helper()
+
+	db := &mock.DB{}
+	experiment := Experiment{
+		PublicID: "E1",
+		Name:     "Earth's Seasons",
+		Assessments: []Assessment{
+			{PublicID: "A1", Type: edulab.AssessmentTypePre, Questions: exportQuestions},
+			{PublicID: "A2", Type: edulab.AssessmentTypePost, Questions: exportQuestions},
+		},
+		Cohorts: []Cohort{
+			{PublicID: "C1", Name: "Control", Arm: "Control"},
+			{PublicID: "C2", Name: "Intervention", Arm: "Intervention"},
+		},
+	}
+
+	for _, config := range configs {
+		experiment.BootstrapConfig = config
+		if err := create(db, experiment); err != nil {
+			t.Fatalf("failed to bootstrap experiment: %v", err)
+		}
+	}
+
+	e, _ := db.FindExperiment("E1")
+	participations, err := db.FindParticipations(e.ID)
+	if err != nil {
+		t.Fatalf("failed to find participations: %v", err)
+	}
+	return participations
+}
+
+func TestBootstrapSeed(t *testing.T) {
+	config := BootstrapConfig{
+		Participants: 20,
+		Seed:         42,
+		AssessmentConfigs: []AssessmentConfig{
+			{CorrectProbabilities: []float64{0.3, 0.4}, BiasFactor: 0.5},
+			{CorrectProbabilities: []float64{0.6, 0.8}, BiasFactor: 0.3},
+		},
+		DemographicConfig: DemographicConfig{
+			Probabilities:      []float64{0.7, 0.2, 0.1},
+			OutlierProbability: 0.1,
+		},
+	}
+
+	first := bootstrapped(t, config)
+	if len(first) != 40 {
+		t.Fatalf("expected 40 participations, got %d", len(first))
+	}
+
+	if again := bootstrapped(t, config); !reflect.DeepEqual(again, first) {
+		t.Error("expected the same participations from the same seed")
+	}
+
+	half := config
+	half.Participants = 10
+	if resumed := bootstrapped(t, half, config); !reflect.DeepEqual(resumed, first) {
+		t.Error("expected bootstrapping in two imports to give the same participations")
+	}
+
+	if config.DemographicConfig.Probabilities[0] != 0.7 {
+		t.Errorf("expected the demographic probabilities to be left as configured, got %v",
+			config.DemographicConfig.Probabilities)
+	}
+
+	other := config
+	other.Seed = 7
+	if different := bootstrapped(t, other); reflect.DeepEqual(different, first) {
+		t.Error("expected other participations from another seed")
+	}
+}
@@ -0,0 +1,271 @@
+package wizard
+
+import (
+	"database/sql"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+)
+
+// cloneAttempts is how many times a clone is tried, drawing new public IDs
+// each time, before giving up.
+const cloneAttempts = 3
+
+// Clone copies the design of an experiment into a new one: its assessments,
+// questions, choices, arms, cohorts, crossover periods, demographics and
+// randomization settings. Participants and their responses are not copied.
+// The target sets the name and description of the new experiment, and its
+// public ID when one is given; the other public IDs are drawn from random, as
+// is a new randomization seed. The copy is made in a transaction, tried again
+// with new public IDs when one of the drawn IDs is already taken.
+func Clone(db edulab.Database, source edulab.Experiment, target *edulab.Experiment,
+	random *rand.Rand) error {
+
+	publicID := target.PublicID
+	if target.Name == "" {
+		target.Name = source.Name
+	}
+
+	var err error
+	for attempt := 1; attempt <= cloneAttempts; attempt++ {
+		target.ID = ""
+		target.PublicID = publicID
+		if target.PublicID == "" {
+			target.PublicID = PublicID(random, 2)
+		}
+		target.CreatedAt = time.Now()
+
+		err = db.Transaction(func(tx edulab.Database) error {
+			return clone(tx, source, target, random)
+		})
+		if !errors.Is(err, edulab.ErrPublicIDTaken) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// clone copies the design of an experiment into the target.
+func clone(db edulab.Database, source edulab.Experiment, target *edulab.Experiment,
+	random *rand.Rand) error {
+
+	if err := db.CreateExperiment(target); err != nil {
+		return errors.Wrap(err, "could not create experiment")
+	}
+
+	assessmentIDs, err := cloneAssessments(db, source, *target, random)
+	if err != nil {
+		return err
+	}
+
+	arms, err := db.FindArms(source.ID)
+	if err != nil {
+		return errors.Wrap(err, "could not find arms")
+	}
+
+	armIDs := make(map[string]string)
+	for _, a := range arms {
+		arm := edulab.Arm{
+			ExperimentID: target.ID,
+			PublicID:     PublicID(random, 3),
+			Name:         a.Name,
+			Description:  a.Description,
+			Control:      a.Control,
+		}
+		if err := db.CreateArm(&arm); err != nil {
+			return errors.Wrap(err, "could not create arm")
+		}
+		armIDs[a.ID] = arm.ID
+	}
+
+	cohorts, err := db.FindCohorts(source.ID)
+	if err != nil {
+		return errors.Wrap(err, "could not find cohorts")
+	}
+
+	cohortIDs := make(map[string]string)
+	for _, c := range cohorts {
+		cohort := edulab.Cohort{
+			ExperimentID: target.ID,
+			ArmID:        armIDs[c.ArmID],
+			PublicID:     PublicID(random, 3),
+			Name:         c.Name,
+			Description:  c.Description,
+		}
+		if err := db.CreateCohort(&cohort); err != nil {
+			return errors.Wrap(err, "could not create cohort")
+		}
+		cohortIDs[c.ID] = cohort.ID
+	}
+	periods, err := db.FindCohortPeriods(source.ID)
+	if err != nil {
+		return errors.Wrap(err, "could not find cohort periods")
+	}
+
+	for _, p := range periods {
+		period := edulab.CohortPeriod{
+			ExperimentID: target.ID,
+			CohortID:     cohortIDs[p.CohortID],
+			AssessmentID: assessmentIDs[p.AssessmentID],
+			ArmID:        armIDs[p.ArmID],
+		}
+		if err := db.UpdateCohortPeriod(period); err != nil {
+			return errors.Wrap(err, "could not create cohort period")
+		}
+	}
+
+	demographicIDs, err := cloneDemographics(db, source, *target)
+	if err != nil {
+		return err
+	}
+
+	randomization, err := db.FindRandomization(source.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not find randomization")
+	}
+
+	randomization.ExperimentID = target.ID
+	randomization.DemographicID = demographicIDs[randomization.DemographicID]
+	randomization.Seed = random.Int63()
+
+	if err := db.UpdateRandomization(randomization); err != nil {
+		return errors.Wrap(err, "could not create randomization")
+	}
+
+	return nil
+}
+
+// cloneAssessments copies the assessments of an experiment with their
+// questions and choices, returning the new assessment IDs by original ID.
+func cloneAssessments(db edulab.Database, source, target edulab.Experiment,
+	random *rand.Rand) (map[string]string, error) {
+
+	assessments, err := db.FindAssessments(source.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find assessments")
+	}
+
+	ids := make(map[string]string)
+	for _, a := range assessments {
+		assessment := edulab.Assessment{
+			ExperimentID: target.ID,
+			PublicID:     PublicID(random, 2),
+			Type:         a.Type,
+			Description:  a.Description,
+		}
+		if err := db.CreateAssessment(&assessment); err != nil {
+			return nil, errors.Wrap(err, "could not create assessment")
+		}
+		ids[a.ID] = assessment.ID
+
+		questions, err := db.FindQuestions(a.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find questions")
+		}
+
+		choices, err := db.FindQuestionChoices(a.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find question choices")
+		}
+
+		translations, err := db.FindTranslations(a.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find translations")
+		}
+
+		for _, q := range questions {
+			question := edulab.Question{
+				AssessmentID: assessment.ID,
+				Text:         q.Text,
+				Type:         q.Type,
+				Anchor:       q.Anchor,
+			}
+			if err := db.CreateQuestion(&question); err != nil {
+				return nil, errors.Wrap(err, "could not create question")
+			}
+
+			choiceIDs := map[string]string{"": ""}
+			for _, c := range choices {
+				if c.QuestionID != q.ID {
+					continue
+				}
+
+				choice := edulab.QuestionChoice{
+					QuestionID: question.ID,
+					Text:       c.Text,
+					IsCorrect:  c.IsCorrect,
+				}
+				if err := db.CreateQuestionChoice(&choice); err != nil {
+					return nil, errors.Wrap(err, "could not create question choice")
+				}
+				choiceIDs[c.ID] = choice.ID
+			}
+
+			for _, t := range translations {
+				if t.QuestionID != q.ID {
+					continue
+				}
+
+				t.QuestionID = question.ID
+				t.ChoiceID = choiceIDs[t.ChoiceID]
+				if err := db.UpdateTranslation(t); err != nil {
+					return nil, errors.Wrap(err, "could not create translation")
+				}
+			}
+		}
+	}
+
+	return ids, nil
+}
+
+// cloneDemographics copies the demographics of an experiment with their
+// options, returning the new demographic IDs by original ID.
+func cloneDemographics(db edulab.Database, source, target edulab.Experiment) (map[string]string, error) {
+	demographics, err := db.FindDemographics(source.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find demographics")
+	}
+
+	options, err := db.FindDemographicOptions(source.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find demographic options")
+	}
+
+	ids := make(map[string]string)
+	for _, d := range demographics {
+		demographic := edulab.Demographic{
+			ExperimentID: target.ID,
+			Text:         d.Text,
+			Type:         d.Type,
+			Position:     d.Position,
+		}
+		if err := db.CreateDemographic(&demographic); err != nil {
+			return nil, errors.Wrap(err, "could not create demographic")
+		}
+		ids[d.ID] = demographic.ID
+
+		for _, o := range options {
+			if o.DemographicID != d.ID {
+				continue
+			}
+
+			option := edulab.DemographicOption{
+				DemographicID: demographic.ID,
+				Text:          o.Text,
+				Position:      o.Position,
+			}
+			if err := db.CreateDemographicOption(&option); err != nil {
+				return nil, errors.Wrap(err, "could not create demographic option")
+			}
+		}
+	}
+
+	return ids, nil
+}
@@ -1,14 +1,20 @@
 package wizard
 
 import (
+	"github.com/pkg/errors"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 
 	"github.com/louisbranch/edulab"
 )
 
+// Demographics creates the default demographics of an experiment: gender,
+// age group, year of study and STEM major. Their texts are stored as message
+// keys and translated to the language of each participant when shown.
 func Demographics(db edulab.Database, experiment edulab.Experiment) error {
 
+	// The English messages are their own keys; printing them keeps the texts
+	// in the catalog extracted by gotext
 	printer := message.NewPrinter(language.English)
 
 	var (
@@ -77,12 +83,27 @@ func Demographics(db edulab.Database, experiment edulab.Experiment) error {
 
 	demographics_order := []string{gender, ageGroup, yearOfStudy, stemMajor}
 
+	var defaults []Demographic
 	for _, category := range demographics_order {
-		options := demographics[category]
+		defaults = append(defaults, Demographic{
+			Text:    category,
+			Type:    edulab.InputSingle,
+			Options: demographics[category],
+		})
+	}
+
+	return createDemographics(db, experiment, defaults)
+}
+
+// createDemographics creates the demographics of an experiment, positioned
+// in the given order.
+func createDemographics(db edulab.Database, experiment edulab.Experiment, demographics []Demographic) error {
+	for i, demographic := range demographics {
 		d := edulab.Demographic{
 			ExperimentID: experiment.ID,
-			Text:         category,
-			Type:         edulab.InputSingle,
+			Text:         demographic.Text,
+			Type:         demographic.Type,
+			Position:     i + 1,
 		}
 
 		err := db.CreateDemographic(&d)
@@ -90,10 +111,11 @@ func Demographics(db edulab.Database, experiment edulab.Experiment) error {
 			return err
 		}
 
-		for _, option := range options {
+		for j, option := range demographic.Options {
 			err := db.CreateDemographicOption(&edulab.DemographicOption{
 				DemographicID: d.ID,
 				Text:          option,
+				Position:      j + 1,
 			})
 			if err != nil {
 				return err
@@ -103,3 +125,35 @@ func Demographics(db edulab.Database, experiment edulab.Experiment) error {
 
 	return nil
 }
+
+// exportDemographics reads the demographics of an experiment with their
+// options, in order.
+func exportDemographics(db edulab.Database, experiment edulab.Experiment) ([]Demographic, error) {
+	demographics, err := db.FindDemographics(experiment.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find demographics")
+	}
+
+	options, err := db.FindDemographicOptions(experiment.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find demographic options")
+	}
+
+	exported := []Demographic{}
+	for _, d := range demographics {
+		demographic := Demographic{
+			Text: d.Text,
+			Type: d.Type,
+		}
+
+		for _, o := range options {
+			if o.DemographicID == d.ID {
+				demographic.Options = append(demographic.Options, o.Text)
+			}
+		}
+
+		exported = append(exported, demographic)
+	}
+
+	return exported, nil
+}
@@ -0,0 +1,374 @@
+package wizard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+)
+
+// giftAnswer is an answer of a GIFT question, before it is converted.
+type giftAnswer struct {
+	Correct  bool // marked with = instead of ~
+	Weighted bool // with a %weight% of partial credit
+	Weight   float64
+	Text     string
+	Feedback bool // followed by #feedback
+}
+
+// Reasons a GIFT file or one of its questions can't be imported.
+var (
+	msgNoGIFTQuestions  = "the file has no GIFT questions"
+	msgTitleNotClosed   = "its title isn't closed with ::"
+	msgAnswersNotClosed = "its answers aren't closed with }"
+	msgNumerical        = "numerical questions aren't supported"
+	msgMatching         = "matching questions aren't supported"
+	msgShortAnswer      = "short answer questions aren't supported"
+	msgAnswerStart      = "its answers don't start with = or ~"
+	msgWeightNotClosed  = "the weight of %q isn't closed with %s"
+	msgWeightNotNumber  = "the weight of %q isn't a number"
+)
+
+// ParseGIFT reads the questions of a question bank in the GIFT format of
+// Moodle, one question per paragraph. Multiple choice and true/false
+// questions become choice questions, with the choices worth any credit
+// correct, and essays open-ended questions. A missing word in the middle of a
+// question is shown as a blank. Feedback is left out. Other questions, such as
+// matching, numerical and short answer ones, are skipped, and the feedback and
+// partial credit that converted questions lost are reported with them.
+func ParseGIFT(r io.Reader) ([]Question, []Skipped, error) {
+	var questions []Question
+	var skipped []Skipped
+	items := 0
+
+	var block strings.Builder
+	start, n := 0, 0
+
+	flush := func() {
+		text := strings.TrimSpace(block.String())
+		block.Reset()
+		if text == "" {
+			return
+		}
+		items++
+
+		title, question, changes, err := parseGIFTQuestion(text)
+		if title == "" {
+			title = fmt.Sprintf("line %d", start)
+		}
+		if err != nil {
+			skipped = append(skipped, Skipped{Item: title, Reason: errorMessage(err)})
+			return
+		}
+		questions = append(questions, question)
+		for _, c := range changes {
+			skipped = append(skipped, Skipped{Item: title, Reason: c})
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10<<20)
+	for scanner.Scan() {
+		n++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "//"), strings.HasPrefix(trimmed, "$CATEGORY:"):
+		default:
+			if block.Len() == 0 {
+				start = n
+			}
+			block.WriteString(line)
+			block.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errors.Wrap(err, "could not read GIFT file")
+	}
+	flush()
+
+	if items == 0 {
+		return nil, nil, newMessage(msgNoGIFTQuestions)
+	}
+
+	return questions, skipped, nil
+}
+
+// parseGIFTQuestion converts a GIFT question, returning its title even when
+// it can't be converted, and the reasons for the parts of it that were left
+// out or changed.
+func parseGIFTQuestion(text string) (string, Question, []Message, error) {
+	var question Question
+	var changes []Message
+	var title string
+
+	if strings.HasPrefix(text, "::") {
+		end := giftIndex(text[2:], "::")
+		if end < 0 {
+			return title, question, nil, newMessage(msgTitleNotClosed)
+		}
+		title = strings.TrimSpace(giftUnescape(text[2 : end+2]))
+		text = strings.TrimSpace(text[end+4:])
+	}
+
+	format := "moodle"
+	if strings.HasPrefix(text, "[") {
+		if end := strings.Index(text, "]"); end > 0 {
+			switch f := text[1:end]; f {
+			case "html", "markdown", "plain", "moodle":
+				format = f
+				text = text[end+1:]
+			}
+		}
+	}
+
+	open := giftIndex(text, "{")
+	if open < 0 {
+		return title, question, nil, newMessage(msgNoAnswers)
+	}
+	closing := giftIndex(text[open:], "}")
+	if closing < 0 {
+		return title, question, nil, newMessage(msgAnswersNotClosed)
+	}
+	closing += open
+
+	stem := strings.TrimSpace(text[:open])
+	if after := strings.TrimSpace(text[closing+1:]); after != "" {
+		stem += " _____ " + after
+	}
+
+	question.Text = giftContent(format, stem)
+	if question.Text == "" {
+		return title, question, nil, newMessage(msgNoText)
+	}
+
+	body := text[open+1 : closing]
+	feedback := false
+	if i := giftIndex(body, "####"); i >= 0 {
+		feedback = strings.TrimSpace(body[i+4:]) != ""
+		body = body[:i]
+	}
+	body = strings.TrimSpace(body)
+
+	switch {
+	case body == "":
+		question.Type = edulab.InputText
+		if feedback {
+			changes = append(changes, newMessage(msgFeedbackLeftOut))
+		}
+		return title, question, changes, nil
+	case strings.HasPrefix(body, "#"):
+		return title, question, nil, newMessage(msgNumerical)
+	}
+
+	// True/false answers may be followed by feedback
+	value := body
+	if i := giftIndex(value, "#"); i >= 0 {
+		value = value[:i]
+	}
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "T", "TRUE":
+		question.Choices = []Choice{{Text: "True", IsCorrect: true}, {Text: "False"}}
+	case "F", "FALSE":
+		question.Choices = []Choice{{Text: "True"}, {Text: "False", IsCorrect: true}}
+	}
+	if question.Choices != nil {
+		question.Type = edulab.InputSingle
+		if feedback || strings.Trim(body[len(value):], "# \t\r\n") != "" {
+			changes = append(changes, newMessage(msgFeedbackLeftOut))
+		}
+		return title, question, changes, nil
+	}
+
+	answers, err := giftAnswers(body)
+	if err != nil {
+		return title, question, nil, err
+	}
+
+	wrong, weighted := false, false
+	best := 0.0
+	for _, a := range answers {
+		if giftIndex(a.Text, "->") >= 0 {
+			return title, question, nil, newMessage(msgMatching)
+		}
+		wrong = wrong || !a.Correct
+		weighted = weighted || a.Weighted
+		feedback = feedback || a.Feedback
+		best = max(best, a.Weight)
+	}
+	if !wrong && !weighted {
+		return title, question, nil, newMessage(msgShortAnswer)
+	}
+	if feedback {
+		changes = append(changes, newMessage(msgFeedbackLeftOut))
+	}
+
+	// Partial credit without a single right answer allows many choices
+	question.Type = edulab.InputSingle
+	if weighted {
+		question.Type = edulab.InputMultiple
+		for _, a := range answers {
+			if a.Correct && !a.Weighted {
+				question.Type = edulab.InputSingle
+			}
+		}
+	}
+
+	correct, unequal := 0, false
+	for i, a := range answers {
+		choice := Choice{Text: giftContent(format, a.Text)}
+		if choice.Text == "" {
+			return title, question, nil, newMessage(msgAnswerNoText, i+1)
+		}
+
+		if question.Type == edulab.InputSingle {
+			choice.IsCorrect = a.Correct
+			if !a.Correct && a.Weight > 0 {
+				changes = append(changes, partialCredit(i+1, a.Weight))
+			}
+		} else {
+			choice.IsCorrect = a.Weight > 0
+			// Rounded weights of the same credit, such as 33.33333, count as equal
+			unequal = unequal || choice.IsCorrect && math.Abs(a.Weight-best) > 0.01
+		}
+		if choice.IsCorrect {
+			correct++
+		}
+
+		question.Choices = append(question.Choices, choice)
+	}
+	if unequal {
+		changes = append(changes, newMessage(msgUnequalCredit))
+	}
+
+	switch {
+	case correct == 0:
+		return title, question, nil, newMessage(msgNoCorrectAnswer)
+	case question.Type == edulab.InputSingle && correct > 1:
+		return title, question, nil, newMessage(msgManyCorrect, correct)
+	}
+
+	return title, question, changes, nil
+}
+
+// giftAnswers splits the answers of a GIFT question, each starting with = or
+// ~, dropping their feedback.
+func giftAnswers(body string) ([]giftAnswer, error) {
+	var answers []giftAnswer
+	var current *giftAnswer
+	var text strings.Builder
+
+	end := func() error {
+		if current == nil {
+			if strings.TrimSpace(text.String()) != "" {
+				return newMessage(msgAnswerStart)
+			}
+			return nil
+		}
+
+		answer := text.String()
+		if i := giftIndex(answer, "#"); i >= 0 {
+			current.Feedback = strings.TrimSpace(answer[i+1:]) != ""
+			answer = answer[:i]
+		}
+		answer = strings.TrimSpace(answer)
+
+		if strings.HasPrefix(answer, "%") {
+			end := strings.Index(answer[1:], "%")
+			if end < 0 {
+				return newMessage(msgWeightNotClosed, answer, "%")
+			}
+			weight, err := strconv.ParseFloat(answer[1:end+1], 64)
+			if err != nil {
+				return newMessage(msgWeightNotNumber, answer)
+			}
+			current.Weighted = true
+			current.Weight = weight
+			answer = answer[end+2:]
+		}
+
+		current.Text = answer
+		answers = append(answers, *current)
+		return nil
+	}
+
+	escaped := false
+	for _, r := range body {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '=' || r == '~':
+			if err := end(); err != nil {
+				return nil, err
+			}
+			current = &giftAnswer{Correct: r == '='}
+			text.Reset()
+			continue
+		}
+		text.WriteRune(r)
+	}
+	if err := end(); err != nil {
+		return nil, err
+	}
+
+	return answers, nil
+}
+
+// giftContent converts a text of a GIFT question in one of its formats.
+func giftContent(format, text string) string {
+	switch format {
+	case "html":
+		return htmlText(giftUnescape(text), func(src string) string {
+			return src
+		})
+	case "markdown":
+		return strings.TrimSpace(giftUnescape(text))
+	}
+	return joinText(giftUnescape(text))
+}
+
+// giftIndex returns the index of the first occurrence of sep in s that isn't
+// escaped with a backslash, or -1.
+func giftIndex(s, sep string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sep) {
+			return i
+		}
+	}
+	return -1
+}
+
+// giftUnescape removes the backslashes escaping the special characters of
+// GIFT.
+func giftUnescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			switch s[i+1] {
+			case '~', '=', '#', '{', '}', ':', '\\':
+				i++
+			case 'n':
+				b.WriteByte('\n')
+				i++
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package wizard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/louisbranch/edulab"
+)
+
+const gift = `// Astronomy concept inventory
+$CATEGORY: $course$/Astronomy
+
+::Seasons:: What causes the seasons? {
+=The tilt of Earth's axis #Right
+~The distance from the Sun #Not quite
+####The axis is tilted.
+}
+
+::Planets:: Which are planets? {
+~%50%Mars
+~%-100%The Moon
+~%50%Venus
+}
+
+::Closest:: Which planet is closest to the Sun? {=Mercury ~%50%Venus ~Mars}
+
+::Giants:: Which are gas giants? {~%60%Jupiter ~%40%Saturn ~%-100%Mars}
+
+Earth orbits the Sun.{T#It takes a year.}
+
+The Moon is a {~star =satellite ~planet} of Earth.
+
+::Explain:: Explain why \{briefly\}. {}
+
+[html]<p>Which is <b>closer</b> to the Sun?</p>{=Mercury ~Mars}
+
+::Capital:: What is the capital of France? {=Paris =paris}
+
+::Match:: Match the planets. {
+=Mars -> red
+=Venus -> hot
+}
+
+::Orbit:: How many days in a year? {#365}
+`
+
+func TestParseGIFT(t *testing.T) {
+	questions, skipped, err := ParseGIFT(strings.NewReader(gift))
+	if err != nil {
+		t.Fatalf("failed to parse GIFT: %v", err)
+	}
+
+	want := []Question{
+		{
+			Text: "What causes the seasons?",
+			Type: edulab.InputSingle,
+			Choices: []Choice{
+				{Text: "The tilt of Earth's axis", IsCorrect: true},
+				{Text: "The distance from the Sun"},
+			},
+		},
+		{
+			Text: "Which are planets?",
+			Type: edulab.InputMultiple,
+			Choices: []Choice{
+				{Text: "Mars", IsCorrect: true},
+				{Text: "The Moon"},
+				{Text: "Venus", IsCorrect: true},
+			},
+		},
+		{
+			Text: "Which planet is closest to the Sun?",
+			Type: edulab.InputSingle,
+			Choices: []Choice{
+				{Text: "Mercury", IsCorrect: true},
+				{Text: "Venus"},
+				{Text: "Mars"},
+			},
+		},
+		{
+			Text: "Which are gas giants?",
+			Type: edulab.InputMultiple,
+			Choices: []Choice{
+				{Text: "Jupiter", IsCorrect: true},
+				{Text: "Saturn", IsCorrect: true},
+				{Text: "Mars"},
+			},
+		},
+		{
+			Text:    "Earth orbits the Sun.",
+			Type:    edulab.InputSingle,
+			Choices: []Choice{{Text: "True", IsCorrect: true}, {Text: "False"}},
+		},
+		{
+			Text: "The Moon is a _____ of Earth.",
+			Type: edulab.InputSingle,
+			Choices: []Choice{
+				{Text: "star"},
+				{Text: "satellite", IsCorrect: true},
+				{Text: "planet"},
+			},
+		},
+		{
+			Text: "Explain why {briefly}.",
+			Type: edulab.InputText,
+		},
+		{
+			Text:    "Which is closer to the Sun?",
+			Type:    edulab.InputSingle,
+			Choices: []Choice{{Text: "Mercury", IsCorrect: true}, {Text: "Mars"}},
+		},
+	}
+
+	if !reflect.DeepEqual(questions, want) {
+		t.Errorf("expected questions %+v, got %+v", want, questions)
+	}
+
+	wantSkipped := []string{
+		"Seasons: its feedback was left out",
+		"Closest: answer 2 gives 50% of the credit, but counts as wrong",
+		"Giants: its correct answers give different credit, but count the same",
+		"line 20: its feedback was left out",
+		"Capital: short answer questions aren't supported",
+		"Match: matching questions aren't supported",
+		"Orbit: numerical questions aren't supported",
+	}
+
+	if got := skippedItems(skipped); !reflect.DeepEqual(got, wantSkipped) {
+		t.Errorf("expected skipped %q, got %q", wantSkipped, got)
+	}
+}
@@ -0,0 +1,38 @@
+package wizard
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Message is a text shown to people, such as the reason an item of a question
+// bank was skipped, kept as its format and arguments so it is translated where
+// it is shown. Formats are declared as msg variables, which are extracted into
+// the translation catalog.
+type Message struct {
+	Format string
+	Args   []interface{}
+}
+
+// newMessage returns the message of a format and its arguments.
+func newMessage(format string, args ...interface{}) Message {
+	return Message{Format: format, Args: args}
+}
+
+func (m Message) String() string {
+	return fmt.Sprintf(m.Format, m.Args...)
+}
+
+func (m Message) Error() string {
+	return m.String()
+}
+
+// errorMessage returns the message of an error, or its text when it has none.
+func errorMessage(err error) Message {
+	var m Message
+	if errors.As(err, &m) {
+		return m
+	}
+	return newMessage("%s", err.Error())
+}
@@ -0,0 +1,364 @@
+package wizard
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+)
+
+// moodleQuiz is a question bank exported by Moodle.
+type moodleQuiz struct {
+	XMLName   xml.Name         `xml:"quiz"`
+	Questions []moodleQuestion `xml:"question"`
+}
+
+type moodleQuestion struct {
+	Type                     string         `xml:"type,attr"`
+	Category                 *moodleName    `xml:"category"`
+	Name                     *moodleName    `xml:"name"`
+	Text                     *moodleText    `xml:"questiontext"`
+	GeneralFeedback          *moodleText    `xml:"generalfeedback"`
+	DefaultGrade             string         `xml:"defaultgrade,omitempty"`
+	Single                   string         `xml:"single,omitempty"`
+	ShuffleAnswers           string         `xml:"shuffleanswers,omitempty"`
+	CorrectFeedback          *moodleText    `xml:"correctfeedback"`
+	PartiallyCorrectFeedback *moodleText    `xml:"partiallycorrectfeedback"`
+	IncorrectFeedback        *moodleText    `xml:"incorrectfeedback"`
+	AnswerNumbering          string         `xml:"answernumbering,omitempty"`
+	Answers                  []moodleAnswer `xml:"answer"`
+}
+
+type moodleName struct {
+	Text string `xml:"text"`
+}
+
+// moodleText is a text in one of the Moodle formats, with the files it
+// references.
+type moodleText struct {
+	Format string       `xml:"format,attr"`
+	Text   string       `xml:"text"`
+	Files  []moodleFile `xml:"file"`
+}
+
+type moodleFile struct {
+	Name     string `xml:"name,attr"`
+	Path     string `xml:"path,attr,omitempty"`
+	Encoding string `xml:"encoding,attr"`
+	Content  string `xml:",chardata"`
+}
+
+type moodleAnswer struct {
+	Fraction string       `xml:"fraction,attr"`
+	Format   string       `xml:"format,attr,omitempty"`
+	Text     string       `xml:"text"`
+	Files    []moodleFile `xml:"file"`
+	Feedback *moodleText  `xml:"feedback"`
+}
+
+// Reasons a Moodle XML file or one of its questions can't be imported.
+var (
+	msgNoMoodleQuestions = "the file has no Moodle questions"
+	msgUnsupportedType   = "%s questions aren't supported"
+	msgInvalidFraction   = "answer %d has an invalid fraction %q"
+)
+
+// ParseMoodleXML reads the questions of a Moodle XML question bank. Multiple
+// choice questions become single or multiple choice questions, true/false
+// ones single choice questions and essays open-ended questions. With partial
+// credit, the choices worth any credit are correct. Images attached to the
+// questions are embedded in their text, while feedback, which has no place in
+// an assessment, is left out. Other questions are skipped, and the feedback
+// and partial credit that converted questions lost are reported with them.
+func ParseMoodleXML(r io.Reader) ([]Question, []Skipped, error) {
+	var quiz moodleQuiz
+
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charsetReader
+	if err := decoder.Decode(&quiz); err != nil {
+		return nil, nil, errors.Wrap(err, "could not read Moodle XML file")
+	}
+
+	var questions []Question
+	var skipped []Skipped
+	items := 0
+
+	for _, mq := range quiz.Questions {
+		if mq.Type == "category" {
+			continue
+		}
+		items++
+
+		var name string
+		if mq.Name != nil {
+			name = strings.TrimSpace(mq.Name.Text)
+		}
+		if name == "" {
+			name = fmt.Sprintf("question %d", items)
+		}
+
+		question, changes, err := convertMoodleQuestion(mq)
+		if err != nil {
+			skipped = append(skipped, Skipped{Item: name, Reason: errorMessage(err)})
+			continue
+		}
+
+		questions = append(questions, question)
+		for _, c := range changes {
+			skipped = append(skipped, Skipped{Item: name, Reason: c})
+		}
+	}
+
+	if items == 0 {
+		return nil, nil, newMessage(msgNoMoodleQuestions)
+	}
+
+	return questions, skipped, nil
+}
+
+// convertMoodleQuestion converts a question of a Moodle XML question bank,
+// with the reasons for the parts of it that were left out or changed.
+func convertMoodleQuestion(mq moodleQuestion) (Question, []Message, error) {
+	var question Question
+	var changes []Message
+
+	switch mq.Type {
+	case "multichoice", "truefalse", "essay":
+	default:
+		return question, nil, newMessage(msgUnsupportedType, mq.Type)
+	}
+
+	if mq.Text != nil {
+		question.Text = moodleContent(mq.Text.Format, mq.Text.Text, mq.Text.Files)
+	}
+	if question.Text == "" {
+		return question, nil, newMessage(msgNoText)
+	}
+
+	feedback := []*moodleText{mq.GeneralFeedback, mq.CorrectFeedback,
+		mq.PartiallyCorrectFeedback, mq.IncorrectFeedback}
+	for _, a := range mq.Answers {
+		feedback = append(feedback, a.Feedback)
+	}
+	for _, f := range feedback {
+		if f != nil && moodleContent(f.Format, f.Text, f.Files) != "" {
+			changes = append(changes, newMessage(msgFeedbackLeftOut))
+			break
+		}
+	}
+
+	if mq.Type == "essay" {
+		question.Type = edulab.InputText
+		return question, changes, nil
+	}
+
+	fractions := make([]float64, len(mq.Answers))
+	best := 0.0
+	for i, a := range mq.Answers {
+		f, err := strconv.ParseFloat(strings.TrimSpace(a.Fraction), 64)
+		if a.Fraction != "" && err != nil {
+			return question, nil, newMessage(msgInvalidFraction, i+1, a.Fraction)
+		}
+		fractions[i] = f
+		if f > best {
+			best = f
+		}
+	}
+
+	question.Type = edulab.InputMultiple
+	if mq.Type == "truefalse" || mq.Single == "true" || mq.Single == "1" {
+		question.Type = edulab.InputSingle
+	}
+
+	correct, unequal := 0, false
+	for i, a := range mq.Answers {
+		choice := Choice{Text: moodleContent(a.Format, a.Text, a.Files)}
+		if mq.Type == "truefalse" {
+			switch strings.ToLower(choice.Text) {
+			case "true":
+				choice.Text = "True"
+			case "false":
+				choice.Text = "False"
+			}
+		}
+		if choice.Text == "" {
+			return question, nil, newMessage(msgAnswerNoText, i+1)
+		}
+
+		// Single choice questions give full marks to their best answers only
+		if question.Type == edulab.InputSingle {
+			choice.IsCorrect = best > 0 && fractions[i] == best
+			if fractions[i] > 0 && fractions[i] < best {
+				changes = append(changes, partialCredit(i+1, fractions[i]))
+			}
+		} else {
+			choice.IsCorrect = fractions[i] > 0
+			// Rounded fractions of the same credit, such as 33.33333, count as equal
+			unequal = unequal || choice.IsCorrect && math.Abs(fractions[i]-best) > 0.01
+		}
+		if choice.IsCorrect {
+			correct++
+		}
+
+		question.Choices = append(question.Choices, choice)
+	}
+	if unequal {
+		changes = append(changes, newMessage(msgUnequalCredit))
+	}
+
+	switch {
+	case len(question.Choices) == 0:
+		return question, nil, newMessage(msgNoAnswers)
+	case correct == 0:
+		return question, nil, newMessage(msgNoCorrectAnswer)
+	case question.Type == edulab.InputSingle && correct > 1:
+		return question, nil, newMessage(msgManyCorrect, correct)
+	}
+
+	return question, changes, nil
+}
+
+// moodleContent converts a text of a Moodle question bank, embedding the
+// images of its files.
+func moodleContent(format, text string, files []moodleFile) string {
+	switch format {
+	case "plain_text":
+		return joinText(text)
+	case "markdown":
+		return strings.TrimSpace(text)
+	}
+
+	return htmlText(text, func(src string) string {
+		name, ok := strings.CutPrefix(src, "@@PLUGINFILE@@/")
+		if !ok {
+			return src
+		}
+		if unescaped, err := url.PathUnescape(name); err == nil {
+			name = unescaped
+		}
+
+		for _, f := range files {
+			if f.Name != name || f.Encoding != "base64" {
+				continue
+			}
+			content, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(f.Content), ""))
+			if err != nil {
+				return ""
+			}
+			return dataURL(f.Name, content)
+		}
+
+		return ""
+	})
+}
+
+// ExportMoodleXML writes an assessment as a Moodle XML question bank, in a
+// category named after the experiment. The correct choices of a multiple
+// choice question share its full credit, while the wrong ones take it away.
+// Embedded images are attached to their questions.
+func ExportMoodleXML(db edulab.Database, experiment edulab.Experiment, a edulab.Assessment, w io.Writer) error {
+	assessment, err := exportAssessment(db, a)
+	if err != nil {
+		return err
+	}
+
+	// Slashes separate categories in Moodle, unless doubled
+	category := strings.ReplaceAll(experiment.Name, "/", "//")
+
+	quiz := moodleQuiz{
+		Questions: []moodleQuestion{{
+			Type:     "category",
+			Category: &moodleName{Text: fmt.Sprintf("$course$/%s/%s", category, a.Type)},
+		}},
+	}
+
+	for i, q := range assessment.Questions {
+		var files []moodleFile
+		images := 0
+		file := func(ext string, content []byte) string {
+			images++
+			name := fmt.Sprintf("question%d-%d%s", i+1, images, ext)
+			files = append(files, moodleFile{
+				Name:     name,
+				Path:     "/",
+				Encoding: "base64",
+				Content:  base64.StdEncoding.EncodeToString(content),
+			})
+			return "@@PLUGINFILE@@/" + name
+		}
+
+		mq := moodleQuestion{
+			Type:         "multichoice",
+			Name:         &moodleName{Text: QuestionTitle(q.Text)},
+			Text:         &moodleText{Format: "html", Text: xhtml(q.Text, false, file)},
+			DefaultGrade: "1",
+		}
+		mq.Text.Files = files
+
+		if q.Type == edulab.InputText {
+			mq.Type = "essay"
+			quiz.Questions = append(quiz.Questions, mq)
+			continue
+		}
+
+		correct := 0
+		for _, c := range q.Choices {
+			if c.IsCorrect {
+				correct++
+			}
+		}
+		wrong := len(q.Choices) - correct
+
+		mq.Single = "true"
+		mq.ShuffleAnswers = "false"
+		mq.AnswerNumbering = "abc"
+		if q.Type == edulab.InputMultiple {
+			mq.Single = "false"
+		}
+
+		for _, c := range q.Choices {
+			files = nil
+			answer := moodleAnswer{Format: "html", Text: xhtml(c.Text, true, file)}
+			answer.Files = files
+
+			switch {
+			case c.IsCorrect:
+				answer.Fraction = moodleFraction(100 / float64(correct))
+			case q.Type == edulab.InputMultiple:
+				answer.Fraction = moodleFraction(-100 / float64(wrong))
+			default:
+				answer.Fraction = "0"
+			}
+
+			mq.Answers = append(mq.Answers, answer)
+		}
+
+		quiz.Questions = append(quiz.Questions, mq)
+	}
+
+	content, err := xml.MarshalIndent(quiz, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not encode Moodle XML file")
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errors.Wrap(err, "could not write Moodle XML file")
+	}
+	_, err = w.Write(append(content, '\n'))
+	return errors.Wrap(err, "could not write Moodle XML file")
+}
+
+// moodleFraction formats a percentage of credit with the precision of the
+// grades Moodle accepts, such as 33.33333.
+func moodleFraction(f float64) string {
+	f = math.Round(f*1e5) / 1e5
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
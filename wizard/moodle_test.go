@@ -0,0 +1,193 @@
+package wizard
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/louisbranch/edulab"
+)
+
+const moodleXML = `<?xml version="1.0" encoding="UTF-8"?>
+<quiz>
+  <question type="category">
+    <category><text>$course$/Astronomy</text></category>
+  </question>
+  <question type="multichoice">
+    <name><text>Seasons</text></name>
+    <questiontext format="html">
+      <text><![CDATA[<p>What causes the seasons?</p><p><img src="@@PLUGINFILE@@/earth%20tilt.png" alt="Earth"></p>]]></text>
+      <file name="earth tilt.png" path="/" encoding="base64">UE5H</file>
+    </questiontext>
+    <generalfeedback format="html"><text>The axis is tilted.</text></generalfeedback>
+    <single>true</single>
+    <answer fraction="100" format="html">
+      <text><![CDATA[The tilt of Earth&rsquo;s axis]]></text>
+      <feedback format="html"><text>Right</text></feedback>
+    </answer>
+    <answer fraction="0" format="html"><text>The distance from the Sun</text></answer>
+  </question>
+  <question type="multichoice">
+    <name><text>Planets</text></name>
+    <questiontext format="plain_text"><text>Which are planets?</text></questiontext>
+    <single>false</single>
+    <answer fraction="50"><text>Mars</text></answer>
+    <answer fraction="-100"><text>The Moon</text></answer>
+    <answer fraction="50"><text>Venus</text></answer>
+  </question>
+  <question type="multichoice">
+    <name><text>Closest</text></name>
+    <questiontext format="plain_text"><text>Which planet is closest to the Sun?</text></questiontext>
+    <single>true</single>
+    <answer fraction="100"><text>Mercury</text></answer>
+    <answer fraction="50"><text>Venus</text></answer>
+    <answer fraction="0"><text>Mars</text></answer>
+  </question>
+  <question type="multichoice">
+    <name><text>Giants</text></name>
+    <questiontext format="plain_text"><text>Which are gas giants?</text></questiontext>
+    <single>false</single>
+    <answer fraction="60"><text>Jupiter</text></answer>
+    <answer fraction="40"><text>Saturn</text></answer>
+    <answer fraction="-100"><text>Mars</text></answer>
+  </question>
+  <question type="truefalse">
+    <name><text>Orbit</text></name>
+    <questiontext format="markdown"><text>Earth orbits the **Sun**.</text></questiontext>
+    <answer fraction="100"><text>true</text></answer>
+    <answer fraction="0"><text>false</text></answer>
+  </question>
+  <question type="essay">
+    <name><text>Explain</text></name>
+    <questiontext format="html"><text>Explain why.</text></questiontext>
+  </question>
+  <question type="matching">
+    <name><text>Match</text></name>
+    <questiontext format="html"><text>Match the planets.</text></questiontext>
+  </question>
+  <question type="multichoice">
+    <name><text>Moons</text></name>
+    <questiontext format="html"><text>How many moons?</text></questiontext>
+    <single>true</single>
+    <answer fraction="100"><text>One</text></answer>
+    <answer fraction="100"><text>1</text></answer>
+  </question>
+</quiz>`
+
+func TestParseMoodleXML(t *testing.T) {
+	questions, skipped, err := ParseMoodleXML(strings.NewReader(moodleXML))
+	if err != nil {
+		t.Fatalf("failed to parse Moodle XML: %v", err)
+	}
+
+	want := []Question{
+		{
+			Text: "What causes the seasons?\n\n![Earth](data:image/png;base64,UE5H)",
+			Type: edulab.InputSingle,
+			Choices: []Choice{
+				{Text: "The tilt of Earth’s axis", IsCorrect: true},
+				{Text: "The distance from the Sun"},
+			},
+		},
+		{
+			Text: "Which are planets?",
+			Type: edulab.InputMultiple,
+			Choices: []Choice{
+				{Text: "Mars", IsCorrect: true},
+				{Text: "The Moon"},
+				{Text: "Venus", IsCorrect: true},
+			},
+		},
+		{
+			Text: "Which planet is closest to the Sun?",
+			Type: edulab.InputSingle,
+			Choices: []Choice{
+				{Text: "Mercury", IsCorrect: true},
+				{Text: "Venus"},
+				{Text: "Mars"},
+			},
+		},
+		{
+			Text: "Which are gas giants?",
+			Type: edulab.InputMultiple,
+			Choices: []Choice{
+				{Text: "Jupiter", IsCorrect: true},
+				{Text: "Saturn", IsCorrect: true},
+				{Text: "Mars"},
+			},
+		},
+		{
+			Text:    "Earth orbits the **Sun**.",
+			Type:    edulab.InputSingle,
+			Choices: []Choice{{Text: "True", IsCorrect: true}, {Text: "False"}},
+		},
+		{
+			Text: "Explain why.",
+			Type: edulab.InputText,
+		},
+	}
+
+	if !reflect.DeepEqual(questions, want) {
+		t.Errorf("expected questions %+v, got %+v", want, questions)
+	}
+
+	wantSkipped := []string{
+		"Seasons: its feedback was left out",
+		"Closest: answer 2 gives 50% of the credit, but counts as wrong",
+		"Giants: its correct answers give different credit, but count the same",
+		"Match: matching questions aren't supported",
+		"Moons: it accepts 2 answers as correct, but only one can be chosen",
+	}
+
+	if got := skippedItems(skipped); !reflect.DeepEqual(got, wantSkipped) {
+		t.Errorf("expected skipped %q, got %q", wantSkipped, got)
+	}
+
+	_, _, err = ParseMoodleXML(strings.NewReader("<quiz></quiz>"))
+	if err == nil {
+		t.Error("expected an error for a file without questions")
+	}
+}
+
+func TestExportMoodleXML(t *testing.T) {
+	db, experiment, assessment := exportExperiment(t)
+
+	var out bytes.Buffer
+	if err := ExportMoodleXML(db, experiment, assessment, &out); err != nil {
+		t.Fatalf("failed to export Moodle XML: %v", err)
+	}
+
+	for _, s := range []string{
+		`<text>$course$/Earth&#39;s Seasons/pre</text>`,
+		`<answer fraction="50" format="html">`,
+		`<answer fraction="-100" format="html">`,
+		`<file name="question1-1.png" path="/" encoding="base64">UE5H</file>`,
+	} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("expected export to contain %s\n%s", s, out.String())
+		}
+	}
+
+	questions, skipped, err := ParseMoodleXML(&out)
+	if err != nil {
+		t.Fatalf("failed to parse exported Moodle XML: %v", err)
+	}
+
+	if len(skipped) > 0 {
+		t.Errorf("expected all questions to be converted, got %v", skipped)
+	}
+
+	if !reflect.DeepEqual(questions, exportQuestions) {
+		t.Errorf("expected questions %+v, got %+v", exportQuestions, questions)
+	}
+}
+
+func TestMoodleFraction(t *testing.T) {
+	tests := map[float64]string{100: "100", 50: "50", 100.0 / 3: "33.33333", -100.0 / 6: "-16.66667"}
+	for f, want := range tests {
+		if got := moodleFraction(f); got != want {
+			t.Errorf("expected %v to be %s, got %s", f, want, got)
+		}
+	}
+}
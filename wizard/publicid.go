@@ -0,0 +1,41 @@
+package wizard
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+var alphanum = []rune("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+// PublicID returns a random public ID with a group of characters for each
+// length, separated by dashes, such as AZG-7H0 for lengths 3 and 3.
+func PublicID(random *rand.Rand, lens ...int) string {
+	sum := 0
+	for _, l := range lens {
+		sum += l
+	}
+
+	b := make([]rune, sum)
+	for i := range b {
+		b[i] = alphanum[random.Intn(len(alphanum))]
+	}
+
+	pid := ""
+	for i, l := range lens {
+		pid += string(b[:l])
+		if i < len(lens)-1 {
+			pid += "-"
+		}
+		b = b[l:]
+	}
+
+	return pid
+}
+
+// AccessToken returns a random token identifying a participant across
+// assessments.
+func AccessToken(random *rand.Rand) string {
+	b := make([]byte, 8)
+	random.Read(b)
+	return fmt.Sprintf("%x", b)
+}
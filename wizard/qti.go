@@ -0,0 +1,607 @@
+package wizard
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+)
+
+// qtiManifest is the list of resources of an IMS content package.
+type qtiManifest struct {
+	XMLName       xml.Name      `xml:"manifest"`
+	Namespace     string        `xml:"xmlns,attr,omitempty"`
+	Identifier    string        `xml:"identifier,attr"`
+	Schema        string        `xml:"metadata>schema,omitempty"`
+	SchemaVersion string        `xml:"metadata>schemaversion,omitempty"`
+	Organizations struct{}      `xml:"organizations"`
+	Resources     []qtiResource `xml:"resources>resource"`
+}
+
+type qtiResource struct {
+	Identifier   string          `xml:"identifier,attr"`
+	Type         string          `xml:"type,attr"`
+	Href         string          `xml:"href,attr"`
+	Files        []qtiFile       `xml:"file"`
+	Dependencies []qtiDependency `xml:"dependency"`
+}
+
+type qtiFile struct {
+	Href string `xml:"href,attr"`
+}
+
+type qtiDependency struct {
+	IdentifierRef string `xml:"identifierref,attr"`
+}
+
+// qtiResponse declares the correct values of an interaction.
+type qtiResponse struct {
+	Identifier  string   `xml:"identifier,attr"`
+	Cardinality string   `xml:"cardinality,attr"`
+	BaseType    string   `xml:"baseType,attr,omitempty"`
+	Correct     []string `xml:"correctResponse>value"`
+}
+
+// qtiItem is an assessmentItem as it is exported, with its body already in
+// XHTML.
+type qtiItem struct {
+	XMLName       xml.Name    `xml:"assessmentItem"`
+	Namespace     string      `xml:"xmlns,attr"`
+	Identifier    string      `xml:"identifier,attr"`
+	Title         string      `xml:"title,attr"`
+	Adaptive      bool        `xml:"adaptive,attr"`
+	TimeDependent bool        `xml:"timeDependent,attr"`
+	Response      qtiResponse `xml:"responseDeclaration"`
+	Outcome       struct {
+		Identifier  string `xml:"identifier,attr"`
+		Cardinality string `xml:"cardinality,attr"`
+		BaseType    string `xml:"baseType,attr"`
+	} `xml:"outcomeDeclaration"`
+	Body       qtiBody        `xml:"itemBody"`
+	Processing *qtiProcessing `xml:"responseProcessing"`
+}
+
+type qtiBody struct {
+	Content string `xml:",innerxml"`
+}
+
+type qtiProcessing struct {
+	Template string `xml:"template,attr"`
+}
+
+// qtiTest is an assessmentTest giving the items of a package in order.
+type qtiTest struct {
+	XMLName    xml.Name `xml:"assessmentTest"`
+	Namespace  string   `xml:"xmlns,attr"`
+	Identifier string   `xml:"identifier,attr"`
+	Title      string   `xml:"title,attr"`
+	Part       struct {
+		Identifier     string `xml:"identifier,attr"`
+		NavigationMode string `xml:"navigationMode,attr"`
+		SubmissionMode string `xml:"submissionMode,attr"`
+		Section        struct {
+			Identifier string       `xml:"identifier,attr"`
+			Title      string       `xml:"title,attr"`
+			Visible    bool         `xml:"visible,attr"`
+			Items      []qtiItemRef `xml:"assessmentItemRef"`
+		} `xml:"assessmentSection"`
+	} `xml:"testPart"`
+}
+
+type qtiItemRef struct {
+	Identifier string `xml:"identifier,attr"`
+	Href       string `xml:"href,attr"`
+}
+
+// qtiInteraction is an interaction of an item body, with its prompt and
+// choices.
+type qtiInteraction struct {
+	Kind       string
+	Response   string
+	MaxChoices int
+	Prompt     strings.Builder
+	Choices    []*qtiChoice
+}
+
+type qtiChoice struct {
+	Identifier string
+	Text       strings.Builder
+}
+
+// qtiIgnored are the elements of an item body whose content isn't part of the
+// question.
+var qtiIgnored = map[string]bool{
+	"feedbackInline": true, "feedbackBlock": true, "modalFeedback": true,
+	"rubricBlock": true, "templateInline": true, "templateBlock": true,
+}
+
+// Reasons a QTI package or one of its items can't be imported.
+var (
+	msgNoManifest       = "the package has no imsmanifest.xml"
+	msgQTI12            = "QTI 1.2 packages aren't supported, export the questions as QTI 2.1"
+	msgNoQTIItems       = "the package has no QTI 2.1 items"
+	msgMissingFile      = "%s is missing from the package"
+	msgImageTooLarge    = "image %s is too large, so it was left out"
+	msgUnreadableItem   = "could not read the item: %s"
+	msgNotQTIItem       = "it is not a QTI 2.1 item"
+	msgNoInteraction    = "it has no interaction"
+	msgManyInteractions = "it has %d interactions, only items with one can be imported"
+	msgUnsupported      = "%s isn't supported"
+	msgChoiceNoText     = "choice %s has no text"
+	msgNoChoices        = "it has no choices"
+	msgNoCorrectResp    = "it has no correct response"
+)
+
+// maxImage is the size of the largest image embedded from a package, and
+// maxImages of all of them, so a small package can't expand into huge
+// questions.
+const (
+	maxImage  = 1 << 20
+	maxImages = 20 << 20
+)
+
+// ParseQTI reads the items of an IMS QTI 2.1 content package, as exported by
+// most learning management systems. Single and multiple choice items
+// (choiceInteraction) and open ended items (extendedTextInteraction) become
+// questions, in the order of the package manifest. Other items are skipped.
+// Images of the package are embedded in the text of the questions, unless they
+// are too large.
+func ParseQTI(r io.ReaderAt, size int64) ([]Question, []Skipped, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not open the package as a zip file")
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		files[path.Clean(f.Name)] = f
+	}
+
+	manifestFile, ok := files["imsmanifest.xml"]
+	if !ok {
+		return nil, nil, newMessage(msgNoManifest)
+	}
+
+	var manifest qtiManifest
+	if err := decodeZipXML(manifestFile, &manifest); err != nil {
+		return nil, nil, errors.Wrap(err, "could not read imsmanifest.xml")
+	}
+
+	var questions []Question
+	var skipped []Skipped
+	items := 0
+	budget := int64(maxImages)
+
+	for _, res := range manifest.Resources {
+		switch {
+		case strings.HasPrefix(res.Type, "imsqti_item_xmlv2"):
+		case strings.HasPrefix(res.Type, "imsqti_xmlv1"):
+			return nil, nil, newMessage(msgQTI12)
+		default:
+			continue
+		}
+		items++
+
+		name := path.Clean(res.Href)
+		f, ok := files[name]
+		if !ok {
+			skipped = append(skipped, Skipped{Item: res.Identifier, Reason: newMessage(msgMissingFile, res.Href)})
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "could not open %s", name)
+		}
+
+		var large []string
+		item, question, err := parseQTIItem(rc, func(src string) string {
+			url, ok := embedImage(files[path.Join(path.Dir(name), src)], &budget)
+			if !ok {
+				large = append(large, src)
+			}
+			return url
+		})
+		rc.Close()
+
+		if item == "" {
+			item = res.Identifier
+		}
+		if err != nil {
+			skipped = append(skipped, Skipped{Item: item, Reason: errorMessage(err)})
+			continue
+		}
+
+		questions = append(questions, question)
+		for _, src := range large {
+			skipped = append(skipped, Skipped{Item: item, Reason: newMessage(msgImageTooLarge, src)})
+		}
+	}
+
+	if items == 0 {
+		return nil, nil, newMessage(msgNoQTIItems)
+	}
+
+	return questions, skipped, nil
+}
+
+// parseQTIItem converts an assessmentItem into a question. The name of the
+// item is returned even when it can't be converted. Images are replaced by
+// the URL returned by image, when there is one.
+func parseQTIItem(r io.Reader, image func(src string) string) (string, Question, error) {
+	var question Question
+	var name string
+	isItem := false
+
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charsetReader
+
+	responses := make(map[string]qtiResponse)
+	var interactions []*qtiInteraction
+	var stem strings.Builder
+
+	var text *strings.Builder // where character data is written, if anywhere
+	var current *qtiInteraction
+	inBody := false
+
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return name, question, newMessage(msgUnreadableItem, err.Error())
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			local := t.Name.Local
+
+			switch {
+			case local == "assessmentItem":
+				isItem = true
+				name = attr(t, "title")
+				if name == "" {
+					name = attr(t, "identifier")
+				}
+			case !isItem:
+				return name, question, newMessage(msgNotQTIItem)
+			case local == "responseDeclaration":
+				var decl qtiResponse
+				if err := decoder.DecodeElement(&decl, &t); err != nil {
+					return name, question, newMessage(msgUnreadableItem, err.Error())
+				}
+				responses[decl.Identifier] = decl
+			case local == "itemBody":
+				inBody = true
+				text = &stem
+			case !inBody:
+			case qtiIgnored[local]:
+				if err := decoder.Skip(); err != nil {
+					return name, question, newMessage(msgUnreadableItem, err.Error())
+				}
+			case strings.HasSuffix(local, "Interaction"):
+				current = &qtiInteraction{Kind: local, Response: attr(t, "responseIdentifier")}
+				current.MaxChoices, _ = strconv.Atoi(attr(t, "maxChoices"))
+				interactions = append(interactions, current)
+				text = nil
+			case current != nil && local == "prompt":
+				text = &current.Prompt
+			case current != nil && local == "simpleChoice":
+				choice := &qtiChoice{Identifier: attr(t, "identifier")}
+				current.Choices = append(current.Choices, choice)
+				text = &choice.Text
+			case local == "img" && text != nil:
+				writeImage(text, t, image)
+			case textBlocks[local] && text != nil:
+				text.WriteString("\n")
+			}
+		case xml.EndElement:
+			local := t.Name.Local
+
+			switch {
+			case !inBody:
+			case local == "itemBody":
+				inBody = false
+				text = nil
+			case strings.HasSuffix(local, "Interaction") && current != nil && local == current.Kind:
+				current = nil
+				text = &stem
+			case current != nil && (local == "prompt" || local == "simpleChoice"):
+				text = nil
+			case textBlocks[local] && text != nil:
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			if text != nil {
+				writeText(text, t)
+			}
+		}
+	}
+
+	if !isItem {
+		return name, question, newMessage(msgNotQTIItem)
+	}
+
+	switch len(interactions) {
+	case 0:
+		return name, question, newMessage(msgNoInteraction)
+	case 1:
+	default:
+		return name, question, newMessage(msgManyInteractions, len(interactions))
+	}
+
+	in := interactions[0]
+	if in.Kind != "choiceInteraction" && in.Kind != "extendedTextInteraction" {
+		return name, question, newMessage(msgUnsupported, in.Kind)
+	}
+
+	question.Text = joinText(stem.String(), in.Prompt.String())
+	if question.Text == "" {
+		return name, question, newMessage(msgNoText)
+	}
+
+	if in.Kind == "extendedTextInteraction" {
+		question.Type = edulab.InputText
+		return name, question, nil
+	}
+
+	response := responses[in.Response]
+	correct := make(map[string]bool)
+	for _, v := range response.Correct {
+		correct[strings.TrimSpace(v)] = true
+	}
+
+	for _, c := range in.Choices {
+		choice := Choice{Text: joinText(c.Text.String()), IsCorrect: correct[c.Identifier]}
+		if choice.Text == "" {
+			return name, question, newMessage(msgChoiceNoText, c.Identifier)
+		}
+		question.Choices = append(question.Choices, choice)
+	}
+
+	if len(question.Choices) == 0 {
+		return name, question, newMessage(msgNoChoices)
+	}
+	if len(correct) == 0 {
+		return name, question, newMessage(msgNoCorrectResp)
+	}
+
+	question.Type = edulab.InputMultiple
+	if response.Cardinality == "single" || (response.Cardinality == "" && in.MaxChoices == 1) {
+		question.Type = edulab.InputSingle
+	}
+
+	return name, question, nil
+}
+
+// embedImage returns an image of a package as a data URL, so it is shown
+// without hosting the file. Images larger than maxImage, or than what is left
+// of the budget of all images, are left out and reported as not ok. The size
+// declared by the package is checked before reading, and again while reading,
+// as it can be forged.
+func embedImage(f *zip.File, budget *int64) (string, bool) {
+	if f == nil {
+		return "", true
+	}
+
+	limit := min(int64(maxImage), *budget)
+	if f.UncompressedSize64 > uint64(limit) {
+		return "", false
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return "", true
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return "", true
+	}
+	if int64(len(content)) > limit {
+		return "", false
+	}
+
+	*budget -= int64(len(content))
+	return dataURL(f.Name, content), true
+}
+
+// qtiNamespace is the namespace of the QTI 2.1 items and tests.
+const qtiNamespace = "http://www.imsglobal.org/xsd/imsqti_v2p1"
+
+// ExportQTI writes an assessment as an IMS QTI 2.1 content package, to be
+// given inside a learning management system: a test with one item per
+// question, in order, and the images embedded in them as files.
+func ExportQTI(db edulab.Database, experiment edulab.Experiment, a edulab.Assessment, w io.Writer) error {
+	assessment, err := exportAssessment(db, a)
+	if err != nil {
+		return err
+	}
+
+	type entry struct {
+		name    string
+		content []byte
+	}
+	var entries []entry
+
+	encode := func(name string, v interface{}) error {
+		content, err := xml.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return errors.Wrapf(err, "could not encode %s", name)
+		}
+		entries = append(entries, entry{name, append([]byte(xml.Header), content...)})
+		return nil
+	}
+
+	manifest := qtiManifest{
+		Namespace:     "http://www.imsglobal.org/xsd/imscp_v1p1",
+		Identifier:    "MANIFEST-" + experiment.PublicID + "-" + a.PublicID,
+		Schema:        "QTIv2.1 Package",
+		SchemaVersion: "1.0.0",
+	}
+
+	test := qtiTest{
+		Namespace:  qtiNamespace,
+		Identifier: "TEST-" + experiment.PublicID + "-" + a.PublicID,
+		Title:      fmt.Sprintf("%s - %s", experiment.Name, a.Type),
+	}
+	test.Part.Identifier = "part"
+	test.Part.NavigationMode = "linear"
+	test.Part.SubmissionMode = "individual"
+	test.Part.Section.Identifier = "section"
+	test.Part.Section.Title = test.Title
+	test.Part.Section.Visible = true
+
+	testResource := qtiResource{
+		Identifier: "test",
+		Type:       "imsqti_test_xmlv2p1",
+		Href:       "test.xml",
+		Files:      []qtiFile{{Href: "test.xml"}},
+	}
+
+	var resources []qtiResource
+	for i, q := range assessment.Questions {
+		id := fmt.Sprintf("item%d", i+1)
+		href := "items/" + id + ".xml"
+
+		resource := qtiResource{
+			Identifier: id,
+			Type:       "imsqti_item_xmlv2p1",
+			Href:       href,
+			Files:      []qtiFile{{Href: href}},
+		}
+
+		images := 0
+		file := func(ext string, content []byte) string {
+			images++
+			name := fmt.Sprintf("images/%s-%d%s", id, images, ext)
+			entries = append(entries, entry{name, content})
+			resource.Files = append(resource.Files, qtiFile{Href: name})
+			return "../" + name
+		}
+
+		if err := encode(href, qtiExportItem(id, q, file)); err != nil {
+			return err
+		}
+
+		resources = append(resources, resource)
+		testResource.Dependencies = append(testResource.Dependencies, qtiDependency{IdentifierRef: id})
+		test.Part.Section.Items = append(test.Part.Section.Items, qtiItemRef{Identifier: id, Href: href})
+	}
+
+	if err := encode("test.xml", test); err != nil {
+		return err
+	}
+
+	manifest.Resources = append([]qtiResource{testResource}, resources...)
+	if err := encode("imsmanifest.xml", manifest); err != nil {
+		return err
+	}
+
+	// The manifest goes first, as packages are usually laid out
+	archive := zip.NewWriter(w)
+	entries = append(entries[len(entries)-1:], entries[:len(entries)-1]...)
+	for _, e := range entries {
+		f, err := archive.Create(e.name)
+		if err != nil {
+			return errors.Wrap(err, "could not write package")
+		}
+		if _, err := f.Write(e.content); err != nil {
+			return errors.Wrap(err, "could not write package")
+		}
+	}
+
+	return errors.Wrap(archive.Close(), "could not write package")
+}
+
+// qtiExportItem converts a question into an assessmentItem. Choice questions
+// are scored by matching all their correct choices.
+func qtiExportItem(id string, q Question, file func(ext string, content []byte) string) qtiItem {
+	item := qtiItem{
+		Namespace:  qtiNamespace,
+		Identifier: id,
+		Title:      QuestionTitle(q.Text),
+		Response: qtiResponse{
+			Identifier:  "RESPONSE",
+			Cardinality: "single",
+			BaseType:    "identifier",
+		},
+	}
+	item.Outcome.Identifier = "SCORE"
+	item.Outcome.Cardinality = "single"
+	item.Outcome.BaseType = "float"
+
+	body := xhtml(q.Text, false, file)
+
+	if q.Type == edulab.InputText {
+		item.Response.BaseType = "string"
+		item.Body.Content = body + `<extendedTextInteraction responseIdentifier="RESPONSE" expectedLines="5"/>`
+		return item
+	}
+
+	maxChoices := 1
+	if q.Type == edulab.InputMultiple {
+		item.Response.Cardinality = "multiple"
+		maxChoices = 0
+	}
+
+	var choices strings.Builder
+	for i, c := range q.Choices {
+		identifier := fmt.Sprintf("choice%d", i+1)
+		if c.IsCorrect {
+			item.Response.Correct = append(item.Response.Correct, identifier)
+		}
+		fmt.Fprintf(&choices, `<simpleChoice identifier="%s">%s</simpleChoice>`,
+			identifier, xhtml(c.Text, true, file))
+	}
+
+	item.Body.Content = fmt.Sprintf(`%s<choiceInteraction responseIdentifier="RESPONSE" shuffle="false" maxChoices="%d">%s</choiceInteraction>`,
+		body, maxChoices, choices.String())
+	item.Processing = &qtiProcessing{
+		Template: "http://www.imsglobal.org/question/qti_v2p1/rptemplates/match_correct",
+	}
+
+	return item
+}
+
+// decodeZipXML decodes an XML file of a package.
+func decodeZipXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	decoder := xml.NewDecoder(rc)
+	decoder.CharsetReader = charsetReader
+	return decoder.Decode(v)
+}
+
+// charsetReader accepts the encodings declared by packages that are
+// compatible with UTF-8.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "utf-8", "us-ascii", "ascii":
+		return input, nil
+	}
+	return nil, errors.Errorf("unsupported encoding %s", charset)
+}
+
+// attr returns the value of an attribute of an element, by local name.
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,313 @@
+package wizard
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/mock"
+)
+
+const qtiManifestXML = `<?xml version="1.0" encoding="UTF-8"?>
+<manifest xmlns="http://www.imsglobal.org/xsd/imscp_v1p1" identifier="bank">
+  <resources>
+    <resource identifier="test" type="imsqti_test_xmlv2p1" href="test.xml"/>
+    <resource identifier="single" type="imsqti_item_xmlv2p1" href="items/single.xml"/>
+    <resource identifier="multiple" type="imsqti_item_xmlv2p1" href="items/multiple.xml"/>
+    <resource identifier="essay" type="imsqti_item_xmlv2p1" href="items/essay.xml"/>
+    <resource identifier="gap" type="imsqti_item_xmlv2p1" href="items/gap.xml"/>
+    <resource identifier="survey" type="imsqti_item_xmlv2p1" href="items/survey.xml"/>
+    <resource identifier="lost" type="imsqti_item_xmlv2p1" href="items/lost.xml"/>
+  </resources>
+</manifest>`
+
+var qtiItems = map[string]string{
+	"items/single.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<assessmentItem xmlns="http://www.imsglobal.org/xsd/imsqti_v2p1" identifier="single" title="Seasons">
+  <responseDeclaration identifier="RESPONSE" cardinality="single" baseType="identifier">
+    <correctResponse><value>A</value></correctResponse>
+  </responseDeclaration>
+  <itemBody>
+    <p>What causes the
+       <b>seasons</b>?</p>
+    <p><img src="../images/earth.png" alt="Earth"/></p>
+    <choiceInteraction responseIdentifier="RESPONSE" shuffle="false" maxChoices="1">
+      <simpleChoice identifier="A">The tilt of Earth's axis</simpleChoice>
+      <simpleChoice identifier="B">The distance from the Sun
+        <feedbackInline identifier="B" outcomeIdentifier="FEEDBACK">Not quite</feedbackInline></simpleChoice>
+    </choiceInteraction>
+  </itemBody>
+</assessmentItem>`,
+	"items/multiple.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<assessmentItem xmlns="http://www.imsglobal.org/xsd/imsqti_v2p1" identifier="multiple">
+  <responseDeclaration identifier="RESPONSE" cardinality="multiple" baseType="identifier">
+    <correctResponse><value>A</value><value>C</value></correctResponse>
+  </responseDeclaration>
+  <itemBody>
+    <choiceInteraction responseIdentifier="RESPONSE" maxChoices="0">
+      <prompt>Which are planets?</prompt>
+      <simpleChoice identifier="A">Mars</simpleChoice>
+      <simpleChoice identifier="B">The Moon</simpleChoice>
+      <simpleChoice identifier="C">Venus</simpleChoice>
+    </choiceInteraction>
+  </itemBody>
+</assessmentItem>`,
+	"items/essay.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<assessmentItem xmlns="http://www.imsglobal.org/xsd/imsqti_v2p1" identifier="essay" title="Essay">
+  <responseDeclaration identifier="RESPONSE" cardinality="single" baseType="string"/>
+  <itemBody>
+    <extendedTextInteraction responseIdentifier="RESPONSE">
+      <prompt>Explain why.</prompt>
+    </extendedTextInteraction>
+  </itemBody>
+</assessmentItem>`,
+	"items/gap.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<assessmentItem xmlns="http://www.imsglobal.org/xsd/imsqti_v2p1" identifier="gap" title="Gap">
+  <itemBody>
+    <p>Earth has <textEntryInteraction responseIdentifier="RESPONSE"/> moon.</p>
+  </itemBody>
+</assessmentItem>`,
+	"items/survey.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<assessmentItem xmlns="http://www.imsglobal.org/xsd/imsqti_v2p1" identifier="survey" title="Survey">
+  <responseDeclaration identifier="RESPONSE" cardinality="single" baseType="identifier"/>
+  <itemBody>
+    <choiceInteraction responseIdentifier="RESPONSE" maxChoices="1">
+      <prompt>Did you like the lecture?</prompt>
+      <simpleChoice identifier="Y">Yes</simpleChoice>
+      <simpleChoice identifier="N">No</simpleChoice>
+    </choiceInteraction>
+  </itemBody>
+</assessmentItem>`,
+	"images/earth.png": "PNG",
+}
+
+func qtiPackage(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to write package: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestParseQTI(t *testing.T) {
+	files := map[string]string{"imsmanifest.xml": qtiManifestXML}
+	for name, content := range qtiItems {
+		files[name] = content
+	}
+	pkg := qtiPackage(t, files)
+
+	questions, skipped, err := ParseQTI(bytes.NewReader(pkg), int64(len(pkg)))
+	if err != nil {
+		t.Fatalf("failed to parse package: %v", err)
+	}
+
+	want := []Question{
+		{
+			Text: "What causes the seasons?\n\n![Earth](data:image/png;base64,UE5H)",
+			Type: edulab.InputSingle,
+			Choices: []Choice{
+				{Text: "The tilt of Earth's axis", IsCorrect: true},
+				{Text: "The distance from the Sun"},
+			},
+		},
+		{
+			Text: "Which are planets?",
+			Type: edulab.InputMultiple,
+			Choices: []Choice{
+				{Text: "Mars", IsCorrect: true},
+				{Text: "The Moon"},
+				{Text: "Venus", IsCorrect: true},
+			},
+		},
+		{
+			Text: "Explain why.",
+			Type: edulab.InputText,
+		},
+	}
+
+	if !reflect.DeepEqual(questions, want) {
+		t.Errorf("expected questions %+v, got %+v", want, questions)
+	}
+
+	wantSkipped := []string{
+		"Gap: textEntryInteraction isn't supported",
+		"Survey: it has no correct response",
+		"lost: items/lost.xml is missing from the package",
+	}
+
+	if got := skippedItems(skipped); !reflect.DeepEqual(got, wantSkipped) {
+		t.Errorf("expected skipped %q, got %q", wantSkipped, got)
+	}
+
+	db := &mock.DB{}
+	assessment := &edulab.Assessment{ExperimentID: "1", PublicID: "Q1", Type: edulab.AssessmentTypePre}
+	if err := ImportQuestions(db, assessment, questions); err != nil {
+		t.Fatalf("failed to import questions: %v", err)
+	}
+
+	created, _ := db.FindQuestions(assessment.ID)
+	choices, _ := db.FindQuestionChoices(assessment.ID)
+	if len(created) != 3 || len(choices) != 5 {
+		t.Errorf("expected 3 questions and 5 choices, got %d and %d", len(created), len(choices))
+	}
+}
+
+func TestParseQTILargeImage(t *testing.T) {
+	pkg := qtiPackage(t, map[string]string{
+		"imsmanifest.xml":  qtiManifestXML,
+		"items/single.xml": qtiItems["items/single.xml"],
+		"images/earth.png": strings.Repeat("0", maxImage+1),
+	})
+
+	questions, skipped, err := ParseQTI(bytes.NewReader(pkg), int64(len(pkg)))
+	if err != nil {
+		t.Fatalf("failed to parse package: %v", err)
+	}
+
+	if want := "What causes the seasons?\n\n[Earth]"; len(questions) != 1 || questions[0].Text != want {
+		t.Errorf("expected the image to be left out of %q, got %+v", want, questions)
+	}
+	if got := skippedItems(skipped); len(got) == 0 || got[0] != "Seasons: image ../images/earth.png is too large, so it was left out" {
+		t.Errorf("expected the large image to be reported, got %q", got)
+	}
+}
+
+func TestParseQTIErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		err   string
+	}{
+		{
+			name:  "no manifest",
+			files: map[string]string{"items/single.xml": qtiItems["items/single.xml"]},
+			err:   "the package has no imsmanifest.xml",
+		},
+		{
+			name: "QTI 1.2",
+			files: map[string]string{"imsmanifest.xml": `<manifest><resources>
+				<resource identifier="bank" type="imsqti_xmlv1p2" href="bank.xml"/>
+				</resources></manifest>`},
+			err: "QTI 1.2 packages aren't supported",
+		},
+		{
+			name:  "no items",
+			files: map[string]string{"imsmanifest.xml": `<manifest><resources/></manifest>`},
+			err:   "the package has no QTI 2.1 items",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := qtiPackage(t, tt.files)
+			_, _, err := ParseQTI(bytes.NewReader(pkg), int64(len(pkg)))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+
+	_, _, err := ParseQTI(strings.NewReader("not a zip"), 9)
+	if err == nil {
+		t.Error("expected an error for a file that isn't a zip")
+	}
+}
+
+// exportQuestions are questions written to question banks and read back.
+var exportQuestions = []Question{
+	{
+		Text: "What causes the seasons & the tides?\n\n![Earth](data:image/png;base64,UE5H)",
+		Type: edulab.InputSingle,
+		Choices: []Choice{
+			{Text: "The tilt of Earth's axis", IsCorrect: true},
+			{Text: "The distance from the Sun"},
+		},
+	},
+	{
+		Text: "Which are planets?",
+		Type: edulab.InputMultiple,
+		Choices: []Choice{
+			{Text: "Mars", IsCorrect: true},
+			{Text: "![Moon](data:image/gif;base64,R0lG)"},
+			{Text: "Venus", IsCorrect: true},
+		},
+	},
+	{
+		Text: "Explain why.",
+		Type: edulab.InputText,
+	},
+}
+
+// exportExperiment creates an experiment with an assessment of the export
+// questions.
+func exportExperiment(t *testing.T) (*mock.DB, edulab.Experiment, edulab.Assessment) {
+	t.Helper()
+
+	db := &mock.DB{}
+	err := create(db, Experiment{
+		PublicID: "E1",
+		Name:     "Earth's Seasons",
+		Assessments: []Assessment{
+			{PublicID: "A1", Type: edulab.AssessmentTypePre, Questions: exportQuestions},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create experiment: %v", err)
+	}
+
+	experiment, _ := db.FindExperiment("E1")
+	assessment, _ := db.FindAssessment(experiment.ID, "A1")
+	return db, experiment, assessment
+}
+
+func TestExportQTI(t *testing.T) {
+	db, experiment, assessment := exportExperiment(t)
+
+	var out bytes.Buffer
+	if err := ExportQTI(db, experiment, assessment, &out); err != nil {
+		t.Fatalf("failed to export QTI package: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatalf("failed to open QTI package: %v", err)
+	}
+
+	var names []string
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+	}
+
+	want := []string{"imsmanifest.xml", "images/item1-1.png", "items/item1.xml",
+		"images/item2-1.gif", "items/item2.xml", "items/item3.xml", "test.xml"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected files %v, got %v", want, names)
+	}
+
+	questions, skipped, err := ParseQTI(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatalf("failed to parse exported package: %v", err)
+	}
+
+	if len(skipped) > 0 {
+		t.Errorf("expected all items to be converted, got %v", skipped)
+	}
+
+	if !reflect.DeepEqual(questions, exportQuestions) {
+		t.Errorf("expected questions %+v, got %+v", exportQuestions, questions)
+	}
+}
@@ -0,0 +1,308 @@
+package wizard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+
+	"github.com/louisbranch/edulab"
+)
+
+// Skipped is an item of a question bank that couldn't be converted into a
+// question, or the part of a converted one that was left out or changed, with
+// the reason why.
+type Skipped struct {
+	Item   string
+	Reason Message
+}
+
+func (s Skipped) String() string {
+	return fmt.Sprintf("%s: %s", s.Item, s.Reason)
+}
+
+// Reasons for the parts of a converted question that were left out or changed,
+// since assessments have no feedback and give every correct choice the same
+// credit, and for the questions of any format that couldn't be converted.
+var (
+	msgFeedbackLeftOut = "its feedback was left out"
+	msgUnequalCredit   = "its correct answers give different credit, but count the same"
+	msgPartialCredit   = "answer %d gives %s of the credit, but counts as wrong"
+	msgNoText          = "it has no text"
+	msgNoAnswers       = "it has no answers"
+	msgAnswerNoText    = "answer %d has no text"
+	msgNoCorrectAnswer = "it has no correct answer"
+	msgManyCorrect     = "it accepts %d answers as correct, but only one can be chosen"
+	msgUnknownFormat   = "unknown question bank format %q, use a QTI 2.1 package (.zip), Moodle XML (.xml) or GIFT (.gift, .txt)"
+)
+
+// partialCredit is the reason for an answer of a single choice question that
+// gives part of the credit, but counts as wrong.
+func partialCredit(answer int, credit float64) Message {
+	return newMessage(msgPartialCredit, answer, strconv.FormatFloat(credit, 'f', -1, 64)+"%")
+}
+
+// ParseQuestionBank reads a question bank in the format of the extension of
+// its file name: a QTI 2.1 package (.zip), Moodle XML (.xml) or GIFT (.gift
+// or .txt).
+func ParseQuestionBank(name string, content []byte) ([]Question, []Skipped, error) {
+	switch ext := strings.ToLower(path.Ext(name)); ext {
+	case ".zip":
+		return ParseQTI(bytes.NewReader(content), int64(len(content)))
+	case ".xml":
+		return ParseMoodleXML(bytes.NewReader(content))
+	case ".gift", ".txt":
+		return ParseGIFT(bytes.NewReader(content))
+	default:
+		return nil, nil, newMessage(msgUnknownFormat, ext)
+	}
+}
+
+// ImportQuestions creates an assessment with the questions read from a
+// question bank.
+func ImportQuestions(db edulab.Database, assessment *edulab.Assessment, questions []Question) error {
+	if !assessment.Type.Valid() {
+		return errors.Errorf("unknown assessment type %q", assessment.Type)
+	}
+
+	if err := db.CreateAssessment(assessment); err != nil {
+		return errors.Wrap(err, "could not create assessment")
+	}
+
+	return createQuestions(db, *assessment, questions)
+}
+
+// createQuestions creates the questions of an assessment with their choices.
+func createQuestions(db edulab.Database, assessment edulab.Assessment, questions []Question) error {
+	for _, q := range questions {
+		question := edulab.Question{
+			AssessmentID: assessment.ID,
+			Text:         q.Text,
+			Type:         q.Type,
+			Anchor:       q.Anchor,
+		}
+		if err := db.CreateQuestion(&question); err != nil {
+			return errors.Wrap(err, "could not create question")
+		}
+
+		if err := createTranslations(db, question.ID, "", q.Translations); err != nil {
+			return err
+		}
+
+		for _, choice := range q.Choices {
+			questionChoice := edulab.QuestionChoice{
+				QuestionID: question.ID,
+				Text:       choice.Text,
+				IsCorrect:  choice.IsCorrect,
+			}
+			if err := db.CreateQuestionChoice(&questionChoice); err != nil {
+				return errors.Wrap(err, "could not create question choice")
+			}
+
+			if err := createTranslations(db, question.ID, questionChoice.ID, choice.Translations); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// createTranslations creates the texts of a question, or of one of its
+// choices, in other languages.
+func createTranslations(db edulab.Database, questionID, choiceID string, translations map[string]string) error {
+	for lang, text := range translations {
+		t := edulab.Translation{
+			QuestionID: questionID,
+			ChoiceID:   choiceID,
+			Lang:       lang,
+			Text:       text,
+		}
+		if err := db.UpdateTranslation(t); err != nil {
+			return errors.Wrap(err, "could not create translation")
+		}
+	}
+
+	return nil
+}
+
+// textBlocks are the elements that start a new line in the text of a
+// question.
+var textBlocks = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true, "blockquote": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// htmlText converts the HTML of a question bank into the text of a question,
+// one paragraph per block. Images are replaced by the URL returned by image,
+// when there is one, or by their description.
+func htmlText(content string, image func(src string) string) string {
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var text strings.Builder
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			local := strings.ToLower(t.Name.Local)
+			switch {
+			case local == "img":
+				writeImage(&text, t, image)
+			case local == "script" || local == "style":
+				decoder.Skip()
+			case textBlocks[local]:
+				text.WriteString("\n")
+			}
+		case xml.EndElement:
+			if textBlocks[strings.ToLower(t.Name.Local)] {
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			writeText(&text, t)
+		}
+	}
+
+	return joinText(text.String())
+}
+
+// writeImage writes an image as Markdown.
+func writeImage(text io.Writer, img xml.StartElement, image func(src string) string) {
+	if src := image(attr(img, "src")); src != "" {
+		fmt.Fprintf(text, " ![%s](%s) ", attr(img, "alt"), src)
+	} else if alt := attr(img, "alt"); alt != "" {
+		fmt.Fprintf(text, " [%s] ", alt)
+	}
+}
+
+// dataURL returns an image as a data URL, so it is shown without hosting the
+// file. Only formats browsers display are embedded.
+func dataURL(name string, content []byte) string {
+	mediatype := mime.TypeByExtension(strings.ToLower(path.Ext(name)))
+	switch mediatype {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+	default:
+		return ""
+	}
+
+	return "data:" + mediatype + ";base64," + base64.StdEncoding.EncodeToString(content)
+}
+
+// writeText writes character data of a document. Its line breaks are spaces,
+// as new lines only start at blocks.
+func writeText(text *strings.Builder, data []byte) {
+	text.WriteString(strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		return r
+	}, string(data)))
+}
+
+// joinText joins parts of text, collapsing the spaces of each line and
+// dropping the empty ones. Lines are joined as paragraphs.
+func joinText(parts ...string) string {
+	var lines []string
+	for _, part := range parts {
+		for _, line := range strings.Split(part, "\n") {
+			if line = strings.Join(strings.Fields(line), " "); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+// markdownImage matches the images of a question text.
+var markdownImage = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+
+// imageExtensions are the file extensions of the images embedded in questions.
+var imageExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// xhtml converts the Markdown text of a question into XHTML for a question
+// bank. Inline text, as for choices, isn't wrapped in a paragraph unless it
+// has many. Embedded images are given to file, which returns where they are
+// found, with their extension.
+func xhtml(text string, inline bool, file func(ext string, content []byte) string) string {
+	text = markdownImage.ReplaceAllStringFunc(text, func(image string) string {
+		m := markdownImage.FindStringSubmatch(image)
+		if ext, content, ok := parseDataURL(m[2]); ok {
+			return "![" + m[1] + "](" + file(ext, content) + ")"
+		}
+		return image
+	})
+
+	var buf bytes.Buffer
+	md := goldmark.New(goldmark.WithRendererOptions(html.WithXHTML()))
+	if err := md.Convert([]byte(text), &buf); err != nil {
+		return ""
+	}
+
+	out := strings.TrimSpace(buf.String())
+	if inline && strings.Count(out, "<p>") == 1 && strings.HasPrefix(out, "<p>") && strings.HasSuffix(out, "</p>") {
+		out = strings.TrimSuffix(strings.TrimPrefix(out, "<p>"), "</p>")
+	}
+
+	return out
+}
+
+// parseDataURL returns the extension and content of an image embedded as a
+// data URL.
+func parseDataURL(src string) (string, []byte, bool) {
+	rest, ok := strings.CutPrefix(src, "data:")
+	if !ok {
+		return "", nil, false
+	}
+
+	mediatype, data, ok := strings.Cut(rest, ";base64,")
+	ext, known := imageExtensions[mediatype]
+	if !ok || !known {
+		return "", nil, false
+	}
+
+	content, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", nil, false
+	}
+
+	return ext, content, true
+}
+
+// markdownEmphasis removes the emphasis of Markdown text.
+var markdownEmphasis = strings.NewReplacer("**", "", "__", "", "`", "")
+
+// QuestionTitle shortens the text of a question into a title, without its
+// images and emphasis.
+func QuestionTitle(text string) string {
+	const max = 50
+
+	text = markdownEmphasis.Replace(markdownImage.ReplaceAllString(text, ""))
+	title := strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(title) <= max {
+		return title
+	}
+
+	return string([]rune(title)[:max-3]) + "..."
+}
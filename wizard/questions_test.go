@@ -0,0 +1,74 @@
+package wizard
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestXHTML(t *testing.T) {
+	tests := []struct {
+		text   string
+		inline bool
+		xhtml  string
+	}{
+		{
+			text:  "What causes the **seasons** & tides?",
+			xhtml: "<p>What causes the <strong>seasons</strong> &amp; tides?</p>",
+		},
+		{
+			text:   "The *tilt*",
+			inline: true,
+			xhtml:  "The <em>tilt</em>",
+		},
+		{
+			text:   "First\n\nSecond",
+			inline: true,
+			xhtml:  "<p>First</p>\n<p>Second</p>",
+		},
+		{
+			text:  "Look:\n\n![Earth](data:image/png;base64,UE5H) ![Moon](https://example.com/moon.png)",
+			xhtml: `<p>Look:</p>` + "\n" + `<p><img src="image-1.png" alt="Earth" /> <img src="https://example.com/moon.png" alt="Moon" /></p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			images := 0
+			got := xhtml(tt.text, tt.inline, func(ext string, content []byte) string {
+				images++
+				if string(content) != "PNG" {
+					t.Errorf("expected the image content, got %q", content)
+				}
+				return fmt.Sprintf("image-%d%s", images, ext)
+			})
+
+			if got != tt.xhtml {
+				t.Errorf("expected %q, got %q", tt.xhtml, got)
+			}
+		})
+	}
+}
+
+func TestQuestionTitle(t *testing.T) {
+	tests := map[string]string{
+		"What causes the **seasons**?":                                    "What causes the seasons?",
+		"![Earth](data:image/png;base64,UE5H)\n\nWhy?":                    "Why?",
+		"Which of these statements about the phases of the Moon is true?": "Which of these statements about the phases of t...",
+	}
+
+	for text, want := range tests {
+		if got := QuestionTitle(text); got != want {
+			t.Errorf("expected title %q, got %q", want, got)
+		}
+	}
+}
+
+// skippedItems returns the skipped items as text, to compare them with the
+// expected ones.
+func skippedItems(skipped []Skipped) []string {
+	var items []string
+	for _, s := range skipped {
+		items = append(items, s.String())
+	}
+	return items
+}
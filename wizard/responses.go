@@ -0,0 +1,545 @@
+package wizard
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/louisbranch/edulab"
+)
+
+// ResponseFile is a CSV file of responses collected outside of edulab, such
+// as a test on paper or a quiz in a learning management system.
+type ResponseFile struct {
+	Header []string
+	Rows   []ResponseRow
+}
+
+// ResponseRow is a row of a response file, with its line in the file.
+type ResponseRow struct {
+	Line   int
+	Values []string
+}
+
+// ResponseColumns maps the columns of a response file, by header, to the
+// student, their cohort and the questions of an assessment.
+type ResponseColumns struct {
+	Student   string         // identifies the student across imports
+	Cohort    string         // public ID or name of the cohort, optional with a single cohort
+	Questions map[string]int // question number, from 1, of each answer column
+}
+
+// Unmatched is a value of a response file that couldn't be matched to a
+// student, a cohort or a choice.
+type Unmatched struct {
+	Line   int
+	Column string
+	Value  string
+	Reason Message
+}
+
+func (u Unmatched) String() string {
+	return fmt.Sprintf("line %d, %s: %q %s", u.Line, u.Column, u.Value, u.Reason)
+}
+
+// ResponseImport summarizes an import of responses.
+type ResponseImport struct {
+	Responses    int // rows with answers
+	Participants int // new participants among them
+	Replaced     int // responses that replace an earlier import
+	Unmatched    []Unmatched
+}
+
+// Reasons a response file can't be imported, or one of its values matched.
+var (
+	msgUnreadableResponses = "could not read responses: %s"
+	msgEmptyResponses      = "the file is empty"
+	msgNoResponses         = "the file has no responses"
+	msgUnmatchedValues     = "%d values couldn't be matched, nothing was imported"
+	msgNoColumn            = "the file has no %q column"
+	msgNoStudentColumn     = "no column identifies the students"
+	msgNoCohortColumn      = "no column identifies the cohorts, which is required with more than one cohort"
+	msgNoQuestionColumns   = "no column is mapped to a question"
+	msgUnknownQuestion     = "column %q is mapped to question %d, but the assessment has %d questions"
+	msgSameQuestion        = "columns %q and %q are both mapped to question %d"
+	msgStudentMissing      = "is missing"
+	msgAlreadyResponded    = "already responded on line %d"
+	msgNoCohort            = "doesn't match a cohort"
+	msgOtherCohort         = "was imported in another cohort"
+	msgNoChoice            = "doesn't match a choice"
+)
+
+var (
+	questionHeader = regexp.MustCompile(`(?i)^\s*(?:q|question)?\s*(\d+)\s*$`)
+	studentHeaders = []string{"student", "student id", "student_id", "id", "username", "email"}
+	cohortHeaders  = []string{"cohort", "group", "section", "class"}
+)
+
+// ParseResponses reads a CSV file of responses with a header row. Files
+// separated by semicolons, as some spreadsheets write them, are accepted too.
+func ParseResponses(r io.Reader) (ResponseFile, error) {
+	var file ResponseFile
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return file, newMessage(msgUnreadableResponses, err.Error())
+	}
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	first, _ := bufio.NewReader(bytes.NewReader(content)).ReadString('\n')
+	if strings.Count(first, ";") > strings.Count(first, ",") {
+		reader.Comma = ';'
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return file, newMessage(msgUnreadableResponses, err.Error())
+		}
+
+		if file.Header == nil {
+			for _, h := range record {
+				file.Header = append(file.Header, strings.TrimSpace(h))
+			}
+			continue
+		}
+
+		empty := true
+		for _, v := range record {
+			empty = empty && strings.TrimSpace(v) == ""
+		}
+		if empty {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		file.Rows = append(file.Rows, ResponseRow{Line: line, Values: record})
+	}
+
+	switch {
+	case file.Header == nil:
+		return file, newMessage(msgEmptyResponses)
+	case len(file.Rows) == 0:
+		return file, newMessage(msgNoResponses)
+	}
+
+	return file, nil
+}
+
+// GuessColumns maps the columns of a response file by their headers: a
+// student column such as "Student ID", a cohort column such as "Cohort", and
+// answer columns named after a question, by number ("Q1", "Question 2", "3")
+// or by text.
+func (file ResponseFile) GuessColumns(questions []edulab.Question) ResponseColumns {
+	columns := ResponseColumns{Questions: make(map[string]int)}
+
+	texts := make(map[string]int)
+	for i, q := range questions {
+		texts[strings.ToLower(strings.TrimSpace(q.Text))] = i + 1
+	}
+
+	for _, h := range file.Header {
+		name := strings.ToLower(h)
+		switch {
+		case columns.Student == "" && contains(studentHeaders, name):
+			columns.Student = h
+		case columns.Cohort == "" && contains(cohortHeaders, name):
+			columns.Cohort = h
+		case texts[name] > 0:
+			columns.Questions[h] = texts[name]
+		default:
+			m := questionHeader.FindStringSubmatch(h)
+			if m == nil {
+				continue
+			}
+			if n, err := strconv.Atoi(m[1]); err == nil && n >= 1 && n <= len(questions) {
+				columns.Questions[h] = n
+			}
+		}
+	}
+
+	return columns
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// responseRow is a row of a response file matched to the experiment.
+type responseRow struct {
+	ExternalID  string
+	Cohort      edulab.Cohort
+	Participant *edulab.Participant
+	Answers     map[string][]string
+}
+
+// ImportResponses creates the participants of a response file and their
+// participations in an assessment, in the same form as the responses given
+// through a participation link, so results treat them alike. Students are
+// identified across imports by a hash of the student column, so importing a
+// file again replaces their answers. Choices are matched by text, ignoring case, by
+// number from 1 or by letter from A, and the choices of a multiple choice
+// question are separated by semicolons. Empty answers are left unanswered.
+//
+// Nothing is written when values are unmatched or on a dry run, which only
+// reports what the import would do, and nothing is kept when a write fails. The public IDs and access tokens of new
+// participants are drawn from random.
+func ImportResponses(db edulab.Database, experiment edulab.Experiment, assessment edulab.Assessment,
+	file ResponseFile, columns ResponseColumns, random *rand.Rand, dryRun bool) (ResponseImport, error) {
+
+	var report ResponseImport
+
+	rows, err := matchResponses(db, experiment, assessment, file, columns, &report)
+	if err != nil {
+		return report, err
+	}
+
+	for _, row := range rows {
+		if row.Participant == nil {
+			report.Participants++
+		}
+	}
+
+	if len(report.Unmatched) > 0 && !dryRun {
+		return report, newMessage(msgUnmatchedValues, len(report.Unmatched))
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	err = db.Transaction(func(tx edulab.Database) error {
+		for _, row := range rows {
+			answers, err := json.Marshal(row.Answers)
+			if err != nil {
+				return errors.Wrap(err, "could not encode answers")
+			}
+
+			participant := row.Participant
+			if participant == nil {
+				participant = &edulab.Participant{
+					PublicID:     PublicID(random, 3),
+					ExperimentID: experiment.ID,
+					CohortID:     row.Cohort.ID,
+					AccessToken:  AccessToken(random),
+					ExternalID:   row.ExternalID,
+				}
+				if err := tx.CreateParticipant(participant); err != nil {
+					return errors.Wrap(err, "could not create participant")
+				}
+			}
+
+			participation, err := tx.FindParticipation(experiment.ID, assessment.ID, participant.ID)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				participation = edulab.Participation{
+					ExperimentID:  experiment.ID,
+					AssessmentID:  assessment.ID,
+					ParticipantID: participant.ID,
+					Answers:       answers,
+				}
+				err = tx.CreateParticipation(&participation)
+			case err == nil:
+				participation.Answers = answers
+				err = tx.UpdateParticipation(participation)
+			}
+			if err != nil {
+				return errors.Wrapf(err, "could not save participation of participant %s", participant.PublicID)
+			}
+		}
+		return nil
+	})
+
+	return report, err
+}
+
+// matchResponses matches the rows of a response file to cohorts, existing
+// participants and choices, recording the values that don't match in the
+// report.
+func matchResponses(db edulab.Database, experiment edulab.Experiment, assessment edulab.Assessment,
+	file ResponseFile, columns ResponseColumns, report *ResponseImport) ([]responseRow, error) {
+
+	index := make(map[string]int)
+	for i, h := range file.Header {
+		if _, ok := index[h]; !ok {
+			index[h] = i
+		}
+	}
+
+	column := func(name string) (int, error) {
+		i, ok := index[name]
+		if !ok {
+			return 0, newMessage(msgNoColumn, name)
+		}
+		return i, nil
+	}
+
+	if columns.Student == "" {
+		return nil, newMessage(msgNoStudentColumn)
+	}
+	student, err := column(columns.Student)
+	if err != nil {
+		return nil, err
+	}
+
+	cohorts, err := db.FindCohorts(experiment.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find cohorts")
+	}
+
+	participants, err := db.FindParticipants(experiment.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find participants")
+	}
+
+	imported := make(map[string]edulab.Participant)
+	for _, p := range participants {
+		if p.ExternalID != "" {
+			imported[p.ExternalID] = p
+		}
+	}
+
+	cohort := -1
+	switch {
+	case columns.Cohort != "":
+		if cohort, err = column(columns.Cohort); err != nil {
+			return nil, err
+		}
+	case len(cohorts) != 1:
+		return nil, newMessage(msgNoCohortColumn)
+	}
+
+	questions, err := db.FindQuestions(assessment.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find questions")
+	}
+
+	choices, err := db.FindQuestionChoices(assessment.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find question choices")
+	}
+
+	if len(columns.Questions) == 0 {
+		return nil, newMessage(msgNoQuestionColumns)
+	}
+
+	// Answer columns by question, so problems are reported in a stable order
+	var answerColumns []string
+	for name := range columns.Questions {
+		answerColumns = append(answerColumns, name)
+	}
+	sort.Slice(answerColumns, func(i, j int) bool {
+		a, b := answerColumns[i], answerColumns[j]
+		if columns.Questions[a] != columns.Questions[b] {
+			return columns.Questions[a] < columns.Questions[b]
+		}
+		return a < b
+	})
+
+	mapped := make(map[int]string)
+	for _, name := range answerColumns {
+		n := columns.Questions[name]
+		if _, err := column(name); err != nil {
+			return nil, err
+		}
+		if n < 1 || n > len(questions) {
+			return nil, newMessage(msgUnknownQuestion, name, n, len(questions))
+		}
+		if other, ok := mapped[n]; ok {
+			a, b := other, name
+			if index[a] > index[b] {
+				a, b = b, a
+			}
+			return nil, newMessage(msgSameQuestion, a, b, n)
+		}
+		mapped[n] = name
+	}
+
+	// Values are matched in the order of the file
+	sort.Slice(answerColumns, func(i, j int) bool {
+		return index[answerColumns[i]] < index[answerColumns[j]]
+	})
+
+	unmatched := func(row ResponseRow, name, value string, reason Message) {
+		report.Unmatched = append(report.Unmatched, Unmatched{
+			Line: row.Line, Column: name, Value: value, Reason: reason,
+		})
+	}
+
+	value := func(row ResponseRow, i int) string {
+		if i < len(row.Values) {
+			return strings.TrimSpace(row.Values[i])
+		}
+		return ""
+	}
+
+	seen := make(map[string]int)
+	var rows []responseRow
+
+	for _, row := range file.Rows {
+		matched := responseRow{Answers: make(map[string][]string)}
+		ok := true
+
+		id := value(row, student)
+		switch line, found := seen[id]; {
+		case id == "":
+			unmatched(row, columns.Student, id, newMessage(msgStudentMissing))
+			ok = false
+		case found:
+			unmatched(row, columns.Student, id, newMessage(msgAlreadyResponded, line))
+			ok = false
+		default:
+			seen[id] = row.Line
+		}
+
+		if cohort < 0 {
+			matched.Cohort = cohorts[0]
+		} else {
+			name := value(row, cohort)
+			found := false
+			for _, c := range cohorts {
+				if strings.EqualFold(c.PublicID, name) || strings.EqualFold(c.Name, name) {
+					matched.Cohort, found = c, true
+					break
+				}
+			}
+			if !found {
+				unmatched(row, columns.Cohort, name, newMessage(msgNoCohort))
+				ok = false
+			}
+		}
+
+		if id != "" {
+			matched.ExternalID = externalID(id)
+			if participant, found := imported[matched.ExternalID]; found {
+				matched.Participant = &participant
+				if matched.Cohort.ID != "" && participant.CohortID != matched.Cohort.ID {
+					unmatched(row, columns.Student, id, newMessage(msgOtherCohort))
+					ok = false
+				}
+			}
+		}
+
+		for _, name := range answerColumns {
+			question := questions[columns.Questions[name]-1]
+			answer := value(row, index[name])
+			if answer == "" {
+				continue
+			}
+
+			if question.Type == edulab.InputText {
+				matched.Answers[question.ID] = []string{answer}
+				continue
+			}
+
+			var options []edulab.QuestionChoice
+			for _, c := range choices {
+				if c.QuestionID == question.ID {
+					options = append(options, c)
+				}
+			}
+
+			parts := []string{answer}
+			if question.Type == edulab.InputMultiple {
+				parts = strings.Split(answer, ";")
+			}
+
+			selected := make(map[string]bool)
+			for _, part := range parts {
+				part = strings.TrimSpace(part)
+				if part == "" {
+					continue
+				}
+				choice, found := matchChoice(options, part)
+				if !found {
+					unmatched(row, name, part, newMessage(msgNoChoice))
+					ok = false
+					continue
+				}
+				selected[choice.ID] = true
+			}
+
+			// Choices in the order they are shown, as a participation link sends them
+			for _, c := range options {
+				if selected[c.ID] {
+					matched.Answers[question.ID] = append(matched.Answers[question.ID], c.ID)
+				}
+			}
+		}
+
+		if !ok {
+			continue
+		}
+
+		if matched.Participant != nil {
+			_, err := db.FindParticipation(experiment.ID, assessment.ID, matched.Participant.ID)
+			switch {
+			case err == nil:
+				report.Replaced++
+			case !errors.Is(err, sql.ErrNoRows):
+				return nil, errors.Wrap(err, "could not find participation")
+			}
+		}
+
+		report.Responses++
+		rows = append(rows, matched)
+	}
+
+	return rows, nil
+}
+
+// externalID hashes the ID of a student, so participants are matched across
+// imports without keeping student IDs, which are often names or emails.
+func externalID(student string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(student)))
+}
+
+// matchChoice finds the choice of a value, by text, number or letter.
+func matchChoice(choices []edulab.QuestionChoice, value string) (edulab.QuestionChoice, bool) {
+	for _, c := range choices {
+		if strings.EqualFold(strings.TrimSpace(c.Text), value) {
+			return c, true
+		}
+	}
+
+	if n, err := strconv.Atoi(value); err == nil {
+		if n >= 1 && n <= len(choices) {
+			return choices[n-1], true
+		}
+		return edulab.QuestionChoice{}, false
+	}
+
+	if len(value) == 1 {
+		n := int(unicode.ToUpper(rune(value[0])) - 'A')
+		if n >= 0 && n < len(choices) {
+			return choices[n], true
+		}
+	}
+
+	return edulab.QuestionChoice{}, false
+}
@@ -0,0 +1,237 @@
+package wizard
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/mock"
+)
+
+// responsesExperiment creates an experiment with two cohorts and a
+// pre-assessment of the export questions.
+func responsesExperiment(t *testing.T) (*mock.DB, edulab.Experiment, edulab.Assessment) {
+	t.Helper()
+
+	db := &mock.DB{}
+	err := create(db, Experiment{
+		PublicID: "E1",
+		Name:     "Earth's Seasons",
+		Assessments: []Assessment{
+			{PublicID: "A1", Type: edulab.AssessmentTypePre, Questions: exportQuestions},
+		},
+		Cohorts: []Cohort{
+			{PublicID: "C1", Name: "Morning", Arm: "Control"},
+			{PublicID: "C2", Name: "Afternoon", Arm: "Treatment"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create experiment: %v", err)
+	}
+
+	experiment, _ := db.FindExperiment("E1")
+	assessment, _ := db.FindAssessment(experiment.ID, "A1")
+	return db, experiment, assessment
+}
+
+func TestParseResponses(t *testing.T) {
+	file, err := ParseResponses(strings.NewReader("\xef\xbb\xbfStudent; Q1\n\nann;\"The tilt; of course\"\n"))
+	if err != nil {
+		t.Fatalf("failed to parse responses: %v", err)
+	}
+
+	want := ResponseFile{
+		Header: []string{"Student", "Q1"},
+		Rows:   []ResponseRow{{Line: 3, Values: []string{"ann", "The tilt; of course"}}},
+	}
+	if !reflect.DeepEqual(file, want) {
+		t.Errorf("expected %+v, got %+v", want, file)
+	}
+
+	for input, msg := range map[string]string{
+		"":                   "the file is empty",
+		"Student,Q1\n":       "the file has no responses",
+		"Student,Q1\n\"ann,": "could not read responses",
+	} {
+		_, err := ParseResponses(strings.NewReader(input))
+		if err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("expected error %q for %q, got %v", msg, input, err)
+		}
+	}
+}
+
+func TestGuessColumns(t *testing.T) {
+	db, _, assessment := responsesExperiment(t)
+	questions, _ := db.FindQuestions(assessment.ID)
+
+	file := ResponseFile{Header: []string{"Email", "Section", "Submitted", "Q1", "which are planets?", "Question 3", "Q4"}}
+
+	want := ResponseColumns{
+		Student:   "Email",
+		Cohort:    "Section",
+		Questions: map[string]int{"Q1": 1, "which are planets?": 2, "Question 3": 3},
+	}
+	if got := file.GuessColumns(questions); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestImportResponses(t *testing.T) {
+	db, experiment, assessment := responsesExperiment(t)
+	random := rand.New(rand.NewSource(1))
+
+	columns := ResponseColumns{
+		Student:   "Student",
+		Cohort:    "Cohort",
+		Questions: map[string]int{"Q1": 1, "Q2": 2, "Q3": 3},
+	}
+
+	file, err := ParseResponses(strings.NewReader(`Student,Cohort,Q1,Q2,Q3
+ann,C1,the tilt of earth's axis,1;c,Because of the tilt.
+bob,Evening,B,D,
+ann,C1,A,,
+,C2,A,,
+`))
+	if err != nil {
+		t.Fatalf("failed to parse responses: %v", err)
+	}
+
+	report, err := ImportResponses(db, experiment, assessment, file, columns, random, true)
+	if err != nil {
+		t.Fatalf("failed to check responses: %v", err)
+	}
+
+	wantUnmatched := []Unmatched{
+		{Line: 3, Column: "Cohort", Value: "Evening", Reason: newMessage(msgNoCohort)},
+		{Line: 3, Column: "Q2", Value: "D", Reason: newMessage(msgNoChoice)},
+		{Line: 4, Column: "Student", Value: "ann", Reason: newMessage(msgAlreadyResponded, 2)},
+		{Line: 5, Column: "Student", Value: "", Reason: newMessage(msgStudentMissing)},
+	}
+	if !reflect.DeepEqual(report.Unmatched, wantUnmatched) {
+		t.Errorf("expected unmatched values %v, got %v", wantUnmatched, report.Unmatched)
+	}
+
+	_, err = ImportResponses(db, experiment, assessment, file, columns, random, false)
+	if err == nil || !strings.Contains(err.Error(), "4 values couldn't be matched") {
+		t.Errorf("expected the import to be refused, got %v", err)
+	}
+	if participants, _ := db.FindParticipants(experiment.ID); len(participants) != 0 {
+		t.Fatalf("expected no participants to be created, got %d", len(participants))
+	}
+
+	file, _ = ParseResponses(strings.NewReader(`Student,Cohort,Q1,Q2,Q3
+ann,C1,the tilt of earth's axis,1;c,Because of the tilt.
+bob,afternoon,B,,
+`))
+
+	report, err = ImportResponses(db, experiment, assessment, file, columns, random, true)
+	if err != nil {
+		t.Fatalf("failed to check responses: %v", err)
+	}
+	if want := (ResponseImport{Responses: 2, Participants: 2}); !reflect.DeepEqual(report, want) {
+		t.Errorf("expected dry run %+v, got %+v", want, report)
+	}
+	if participants, _ := db.FindParticipants(experiment.ID); len(participants) != 0 {
+		t.Fatalf("expected a dry run to create no participants, got %d", len(participants))
+	}
+
+	if _, err := ImportResponses(db, experiment, assessment, file, columns, random, false); err != nil {
+		t.Fatalf("failed to import responses: %v", err)
+	}
+
+	participants, _ := db.FindParticipants(experiment.ID)
+	cohorts, _ := db.FindCohorts(experiment.ID)
+	if len(participants) != 2 || participants[0].CohortID != cohorts[0].ID || participants[1].CohortID != cohorts[1].ID {
+		t.Fatalf("expected a participant in each cohort, got %+v", participants)
+	}
+	if p := participants[0]; p.ExternalID != externalID("ann") || p.ExternalID == "ann" || strings.Contains(p.AccessToken, "ann") {
+		t.Errorf("expected the student ID to be hashed apart from a random access token, got %+v", p)
+	}
+
+	questions, _ := db.FindQuestions(assessment.ID)
+	choices, _ := db.FindQuestionChoices(assessment.ID)
+	choice := func(question, n int) string {
+		var ids []string
+		for _, c := range choices {
+			if c.QuestionID == questions[question].ID {
+				ids = append(ids, c.ID)
+			}
+		}
+		return ids[n]
+	}
+
+	participation, err := db.FindParticipation(experiment.ID, assessment.ID, participants[0].ID)
+	if err != nil {
+		t.Fatalf("failed to find participation: %v", err)
+	}
+
+	var answers map[string][]string
+	json.Unmarshal(participation.Answers, &answers)
+	want := map[string][]string{
+		questions[0].ID: {choice(0, 0)},
+		questions[1].ID: {choice(1, 0), choice(1, 2)},
+		questions[2].ID: {"Because of the tilt."},
+	}
+	if !reflect.DeepEqual(answers, want) {
+		t.Errorf("expected answers %v, got %v", want, answers)
+	}
+
+	// Importing again replaces the answers of the same students
+	file, _ = ParseResponses(strings.NewReader("Student,Cohort,Q1\nbob,C2,1\n"))
+	report, err = ImportResponses(db, experiment, assessment, file, columns, random, false)
+	if err == nil || !strings.Contains(err.Error(), `no "Q2" column`) {
+		t.Errorf("expected an error for a missing column, got %v", err)
+	}
+
+	delete(columns.Questions, "Q2")
+	delete(columns.Questions, "Q3")
+	report, err = ImportResponses(db, experiment, assessment, file, columns, random, false)
+	if err != nil {
+		t.Fatalf("failed to import responses again: %v", err)
+	}
+	if want := (ResponseImport{Responses: 1, Replaced: 1}); !reflect.DeepEqual(report, want) {
+		t.Errorf("expected import %+v, got %+v", want, report)
+	}
+
+	participation, _ = db.FindParticipation(experiment.ID, assessment.ID, participants[1].ID)
+	if got := string(participation.Answers); got != `{"`+questions[0].ID+`":["`+choice(0, 0)+`"]}` {
+		t.Errorf("expected the answers to be replaced, got %s", got)
+	}
+	if participants, _ := db.FindParticipants(experiment.ID); len(participants) != 2 {
+		t.Errorf("expected the participants to be reused, got %d", len(participants))
+	}
+}
+
+func TestImportResponsesColumns(t *testing.T) {
+	db, experiment, assessment := responsesExperiment(t)
+	file := ResponseFile{
+		Header: []string{"Student", "Cohort", "Q1", "Q1 again"},
+		Rows:   []ResponseRow{{Line: 2, Values: []string{"ann", "C1", "A", "A"}}},
+	}
+
+	tests := []struct {
+		name    string
+		columns ResponseColumns
+		err     string
+	}{
+		{"no student", ResponseColumns{Questions: map[string]int{"Q1": 1}}, "no column identifies the students"},
+		{"no cohort", ResponseColumns{Student: "Student", Questions: map[string]int{"Q1": 1}}, "required with more than one cohort"},
+		{"no questions", ResponseColumns{Student: "Student", Cohort: "Cohort"}, "no column is mapped to a question"},
+		{"unknown question", ResponseColumns{Student: "Student", Cohort: "Cohort", Questions: map[string]int{"Q1": 4}},
+			"mapped to question 4, but the assessment has 3 questions"},
+		{"same question", ResponseColumns{Student: "Student", Cohort: "Cohort", Questions: map[string]int{"Q1": 1, "Q1 again": 1}},
+			`columns "Q1" and "Q1 again" are both mapped to question 1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportResponses(db, experiment, assessment, file, tt.columns, nil, true)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,535 @@
+package wizard
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/louisbranch/edulab"
+)
+
+// Problem is an error in an experiment file, at the line it was found when
+// known. Warnings don't stop the experiment from being created.
+type Problem struct {
+	Line    int
+	Message string
+	Warning bool
+}
+
+func (p Problem) Error() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	}
+	return p.Message
+}
+
+// Problems are all the errors found in an experiment file.
+type Problems []Problem
+
+func (ps Problems) Error() string {
+	msgs := make([]string, len(ps))
+	for i, p := range ps {
+		msgs[i] = p.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Errors returns the problems that aren't warnings.
+func (ps Problems) Errors() Problems {
+	var errs Problems
+	for _, p := range ps {
+		if !p.Warning {
+			errs = append(errs, p)
+		}
+	}
+	return errs
+}
+
+var yamlLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// Parse decodes and validates an experiment file. Unknown fields are errors,
+// and problems are reported at the line they were found. Warnings are left
+// out; Check reports them too.
+func Parse(r io.Reader) (Experiment, error) {
+	experiment, problems, err := parse(r)
+	if err != nil {
+		return experiment, err
+	}
+
+	if errs := problems.Errors(); len(errs) > 0 {
+		return experiment, errs
+	}
+
+	return experiment, nil
+}
+
+// Check returns the errors and warnings found in an experiment file.
+func Check(r io.Reader) (Problems, error) {
+	_, problems, err := parse(r)
+
+	var decoding Problems
+	if errors.As(err, &decoding) {
+		return decoding, nil
+	}
+
+	return problems, err
+}
+
+// parse decodes an experiment file and returns the problems found validating
+// it. Files that can't be decoded are reported as an error of Problems.
+func parse(r io.Reader) (Experiment, Problems, error) {
+	var experiment Experiment
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return experiment, nil, errors.Wrap(err, "could not read YAML file")
+	}
+
+	// The document tree locates the problems found after decoding
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return experiment, nil, decodeProblems(err)
+	}
+	if len(root.Content) == 0 {
+		return experiment, nil, Problems{{Message: "the file is empty"}}
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&experiment); err != nil {
+		return experiment, nil, decodeProblems(err)
+	}
+
+	problems := validate(experiment, func(path ...interface{}) int {
+		return lineOf(root.Content[0], path...)
+	})
+
+	return experiment, problems, nil
+}
+
+// decodeProblems splits the errors of the YAML decoder by line.
+func decodeProblems(err error) Problems {
+	var msgs []string
+	if typeErr, ok := err.(*yaml.TypeError); ok {
+		msgs = typeErr.Errors
+	} else {
+		msgs = []string{err.Error()}
+	}
+
+	problems := make(Problems, len(msgs))
+	for i, msg := range msgs {
+		problems[i] = Problem{Message: msg}
+
+		m := yamlLine.FindStringSubmatch(msg)
+		if m != nil {
+			problems[i].Line, _ = strconv.Atoi(m[1])
+			problems[i].Message = m[2]
+		}
+	}
+
+	return problems
+}
+
+// lineOf returns the line of the node at a path of mapping keys and sequence
+// indexes, or of its closest ancestor when the path doesn't exist. Mapping
+// values are found at the line of their key.
+func lineOf(node *yaml.Node, path ...interface{}) int {
+	line := node.Line
+
+	for _, p := range path {
+		var next *yaml.Node
+
+		switch key := p.(type) {
+		case string:
+			if node.Kind != yaml.MappingNode {
+				break
+			}
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == key {
+					next = node.Content[i+1]
+					line = node.Content[i].Line
+				}
+			}
+		case int:
+			if node.Kind == yaml.SequenceNode && key < len(node.Content) {
+				next = node.Content[key]
+				line = next.Line
+			}
+		}
+
+		if next == nil {
+			break
+		}
+		node = next
+	}
+
+	return line
+}
+
+// Validate checks that an experiment can be created: required fields are
+// set, public IDs are unique, references between assessments, arms and
+// cohorts exist, questions have correct choices, demographics have options
+// unless they are text and the bootstrap configuration matches the design.
+// Pre- and post-assessments that don't ask the same items are warnings.
+func Validate(experiment Experiment) Problems {
+	return validate(experiment, func(path ...interface{}) int {
+		return 0
+	})
+}
+
+func validate(experiment Experiment, line func(path ...interface{}) int) Problems {
+	var problems Problems
+	add := func(path []interface{}, format string, args ...interface{}) {
+		problems = append(problems, Problem{
+			Line:    line(path...),
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	at := func(path ...interface{}) []interface{} {
+		return path
+	}
+
+	if strings.TrimSpace(experiment.Name) == "" {
+		add(at("name"), "the experiment has no name")
+	}
+
+	assessments := make(map[string]bool)
+	for i, a := range experiment.Assessments {
+		name := fmt.Sprintf("assessment %d", i+1)
+		if a.PublicID == "" {
+			add(at("assessments", i), "%s has no public_id", name)
+		} else {
+			name = fmt.Sprintf("assessment %s", a.PublicID)
+			if assessments[a.PublicID] {
+				add(at("assessments", i, "public_id"), "%s is declared more than once", name)
+			}
+			if strings.Contains(a.PublicID, "-") {
+				add(at("assessments", i, "public_id"), "%s can't have a dash in its public_id", name)
+			}
+			assessments[a.PublicID] = true
+		}
+
+		if !a.Type.Valid() {
+			add(at("assessments", i, "type"), "%s has an unknown type %q", name, a.Type)
+		}
+
+		for j, q := range a.Questions {
+			path := at("assessments", i, "questions", j)
+			question := fmt.Sprintf("question %d of %s", j+1, name)
+			if strings.TrimSpace(q.Text) == "" {
+				add(path, "%s has no text", question)
+			}
+			for _, lang := range emptyTranslations(q.Translations) {
+				add(at("assessments", i, "questions", j, "translations"), "%s has no %s text", question, lang)
+			}
+
+			correct := 0
+			for k, c := range q.Choices {
+				if strings.TrimSpace(c.Text) == "" {
+					add(at("assessments", i, "questions", j, "choices", k), "choice %d of %s has no text", k+1, question)
+				}
+				for _, lang := range emptyTranslations(c.Translations) {
+					add(at("assessments", i, "questions", j, "choices", k), "choice %d of %s has no %s text", k+1, question, lang)
+				}
+				if c.IsCorrect {
+					correct++
+				}
+			}
+
+			switch q.Type {
+			case edulab.InputSingle, edulab.InputMultiple:
+				if len(q.Choices) == 0 {
+					add(path, "%s has no choices", question)
+				} else if correct == 0 {
+					add(at("assessments", i, "questions", j, "choices"), "%s has no correct choice", question)
+				} else if correct > 1 && q.Type == edulab.InputSingle {
+					add(at("assessments", i, "questions", j, "choices"),
+						"%s is single choice but has %d correct choices", question, correct)
+				}
+			case edulab.InputText:
+			default:
+				add(at("assessments", i, "questions", j, "type"), "%s has an unknown type %q", question, q.Type)
+			}
+		}
+	}
+
+	problems = append(problems, validateItems(experiment, line)...)
+
+	// Without arms, each cohort names its own
+	arms := make(map[string]bool)
+	if len(experiment.Arms) == 0 {
+		for _, c := range experiment.Cohorts {
+			arms[cohortArm(c, nil)] = true
+		}
+	}
+	for i, a := range experiment.Arms {
+		if strings.TrimSpace(a.Name) == "" {
+			add(at("arms", i), "arm %d has no name", i+1)
+		}
+		arms[a.Name] = true
+		if a.PublicID != "" {
+			arms[a.PublicID] = true
+		}
+	}
+
+	cohorts := make(map[string]bool)
+	for i, c := range experiment.Cohorts {
+		name := fmt.Sprintf("cohort %d", i+1)
+		if c.PublicID == "" {
+			add(at("cohorts", i), "%s has no public_id", name)
+		} else {
+			name = fmt.Sprintf("cohort %s", c.PublicID)
+			if cohorts[c.PublicID] {
+				add(at("cohorts", i, "public_id"), "%s is declared more than once", name)
+			}
+			if strings.Contains(c.PublicID, "-") {
+				add(at("cohorts", i, "public_id"), "%s can't have a dash in its public_id", name)
+			}
+			cohorts[c.PublicID] = true
+		}
+
+		if strings.TrimSpace(c.Name) == "" {
+			add(at("cohorts", i), "%s has no name", name)
+		}
+
+		if !arms[cohortArm(c, experiment.Arms)] {
+			add(at("cohorts", i, "arm"), "%s has an unknown arm %q", name, c.Arm)
+		}
+
+		for j, p := range c.Crossover {
+			if !assessments[p.After] {
+				add(at("cohorts", i, "crossover", j, "after"),
+					"%s crosses over after an unknown assessment %q", name, p.After)
+			}
+			if !arms[p.Arm] {
+				add(at("cohorts", i, "crossover", j, "arm"),
+					"%s crosses over to an unknown arm %q", name, p.Arm)
+			}
+		}
+	}
+
+	for i, d := range experiment.Demographics {
+		name := fmt.Sprintf("demographic %d", i+1)
+		if strings.TrimSpace(d.Text) == "" {
+			add(at("demographics", i), "%s has no text", name)
+		}
+
+		for j, o := range d.Options {
+			if strings.TrimSpace(o) == "" {
+				add(at("demographics", i, "options", j), "option %d of %s has no text", j+1, name)
+			}
+		}
+
+		switch d.Type {
+		case edulab.InputSingle, edulab.InputMultiple:
+			if len(d.Options) == 0 {
+				add(at("demographics", i), "%s has no options", name)
+			}
+		case edulab.InputText:
+			if len(d.Options) > 0 {
+				add(at("demographics", i, "options"), "%s is text but has options", name)
+			}
+		default:
+			add(at("demographics", i, "type"), "%s has an unknown type %q", name, d.Type)
+		}
+	}
+
+	problems = append(problems, validateBootstrap(experiment, line)...)
+
+	return problems
+}
+
+// emptyTranslations returns the languages of translations without text, in
+// order.
+func emptyTranslations(translations map[string]string) []string {
+	var langs []string
+	for lang, text := range translations {
+		if strings.TrimSpace(text) == "" {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// validateItems warns about questions of the pre- and post-assessments that
+// can't be compared, matched as in the results: by anchor first, then
+// questions without an anchor by text with any other question, anchored or
+// not. Experiments edited after they started may no longer match, so these
+// don't stop them from being created.
+func validateItems(experiment Experiment, line func(path ...interface{}) int) Problems {
+	type item struct {
+		assessment, question int
+	}
+
+	// questions of an assessment type by anchor, by text of any question
+	// and by text of unanchored questions
+	type lookup struct {
+		anchors, texts, unanchored map[string]item
+	}
+
+	items := make(map[edulab.AssessmentType]*lookup)
+	for _, t := range []edulab.AssessmentType{edulab.AssessmentTypePre, edulab.AssessmentTypePost} {
+		items[t] = &lookup{
+			anchors:    make(map[string]item),
+			texts:      make(map[string]item),
+			unanchored: make(map[string]item),
+		}
+	}
+
+	first := func(m map[string]item, key string, it item) {
+		if _, ok := m[key]; !ok {
+			m[key] = it
+		}
+	}
+
+	for i, a := range experiment.Assessments {
+		l := items[a.Type]
+		if l == nil {
+			continue
+		}
+		for j, q := range a.Questions {
+			first(l.texts, q.Text, item{i, j})
+			if q.Anchor != "" {
+				first(l.anchors, q.Anchor, item{i, j})
+			} else {
+				first(l.unanchored, q.Text, item{i, j})
+			}
+		}
+	}
+
+	find := func(l *lookup, q Question) (item, bool) {
+		if q.Anchor == "" {
+			it, ok := l.texts[q.Text]
+			return it, ok
+		}
+		if it, ok := l.anchors[q.Anchor]; ok {
+			return it, true
+		}
+		it, ok := l.unanchored[q.Text]
+		return it, ok
+	}
+
+	pre, post := items[edulab.AssessmentTypePre], items[edulab.AssessmentTypePost]
+	if len(pre.texts) == 0 || len(post.texts) == 0 {
+		return nil
+	}
+
+	var problems Problems
+	for i, a := range experiment.Assessments {
+		var others *lookup
+		var missing string
+		switch a.Type {
+		case edulab.AssessmentTypePre:
+			others, missing = post, "post-assessment"
+		case edulab.AssessmentTypePost:
+			others, missing = pre, "pre-assessment"
+		default:
+			continue
+		}
+
+		for j, q := range a.Questions {
+			path := []interface{}{"assessments", i, "questions", j}
+			question := fmt.Sprintf("question %d of assessment %s", j+1, a.PublicID)
+
+			match, ok := find(others, q)
+			if !ok {
+				problems = append(problems, Problem{
+					Line:    line(path...),
+					Message: fmt.Sprintf("%s has no matching question in the %s", question, missing),
+					Warning: true,
+				})
+				continue
+			}
+
+			other := experiment.Assessments[match.assessment].Questions[match.question]
+			if a.Type == edulab.AssessmentTypePost && other.Type != q.Type {
+				problems = append(problems, Problem{
+					Line: line(append(path, "type")...),
+					Message: fmt.Sprintf("%s is %s but its match in assessment %s is %s", question, q.Type,
+						experiment.Assessments[match.assessment].PublicID, other.Type),
+					Warning: true,
+				})
+			}
+		}
+	}
+
+	return problems
+}
+
+// validateBootstrap checks the configuration of simulated participants
+// against the assessments and cohorts they are created for.
+func validateBootstrap(experiment Experiment, line func(path ...interface{}) int) Problems {
+	config := experiment.BootstrapConfig
+	if config.Participants == 0 {
+		return nil
+	}
+
+	var problems Problems
+	add := func(path []interface{}, format string, args ...interface{}) {
+		problems = append(problems, Problem{
+			Line:    line(append([]interface{}{"bootstrap_config"}, path...)...),
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	probability := func(p float64) bool {
+		return p >= 0 && p <= 1
+	}
+
+	if config.Participants < 0 {
+		add([]interface{}{"participants"}, "bootstrap_config can't have a negative number of participants")
+	}
+
+	if len(config.AssessmentConfigs) != len(experiment.Assessments) {
+		add([]interface{}{"assessments"}, "bootstrap_config has %d assessments, but the experiment has %d",
+			len(config.AssessmentConfigs), len(experiment.Assessments))
+	}
+
+	for i, a := range config.AssessmentConfigs {
+		path := []interface{}{"assessments", i}
+		if len(a.CorrectProbabilities) != len(experiment.Cohorts) {
+			add(append(path, "correct_probabilities"),
+				"bootstrap_config assessment %d has %d correct_probabilities, one per cohort is needed (%d)",
+				i+1, len(a.CorrectProbabilities), len(experiment.Cohorts))
+		}
+		for _, p := range a.CorrectProbabilities {
+			if !probability(p) {
+				add(append(path, "correct_probabilities"),
+					"bootstrap_config assessment %d has a correct probability of %g, outside 0 to 1", i+1, p)
+			}
+		}
+		if !probability(a.BiasFactor) {
+			add(append(path, "bias_factor"),
+				"bootstrap_config assessment %d has a bias_factor of %g, outside 0 to 1", i+1, a.BiasFactor)
+		}
+	}
+
+	demographics := config.DemographicConfig
+	sum := 0.0
+	for _, p := range demographics.Probabilities {
+		if !probability(p) {
+			add([]interface{}{"demographics", "probabilities"},
+				"bootstrap_config demographics has a probability of %g, outside 0 to 1", p)
+		}
+		sum += p
+	}
+	if len(demographics.Probabilities) > 0 && math.Abs(sum-1) > 1e-9 {
+		add([]interface{}{"demographics", "probabilities"},
+			"bootstrap_config demographics probabilities sum to %g instead of 1", sum)
+	}
+	if !probability(demographics.OutlierProbability) {
+		add([]interface{}{"demographics", "outlier_probability"},
+			"bootstrap_config demographics has an outlier_probability of %g, outside 0 to 1",
+			demographics.OutlierProbability)
+	}
+
+	return problems
+}
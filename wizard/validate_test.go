@@ -0,0 +1,304 @@
+package wizard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		problems Problems
+	}{
+		{
+			name: "valid",
+			yaml: `
+name: Seasons
+assessments:
+  - public_id: A1
+    type: pre
+    questions:
+      - text: Why?
+        type: text
+cohorts:
+  - public_id: C1
+    name: Section 1
+`,
+		},
+		{
+			name:     "empty",
+			yaml:     "",
+			problems: Problems{{Message: "the file is empty"}},
+		},
+		{
+			name: "syntax",
+			yaml: "name: Seasons\nassessments: [\n",
+			problems: Problems{
+				{Line: 2, Message: "did not find expected node content"},
+			},
+		},
+		{
+			name: "types",
+			yaml: "name: Seasons\nassessments:\n  - public_id: A1\n    questions: 3\ncohorts: no\n",
+			problems: Problems{
+				{Line: 4, Message: "cannot unmarshal !!int `3` into []wizard.Question"},
+				{Line: 5, Message: "cannot unmarshal !!str `no` into []wizard.Cohort"},
+			},
+		},
+		{
+			name: "semantics",
+			yaml: `
+assessments:
+  - public_id: A1
+    type: later
+  - public_id: A1
+    type: post
+    questions:
+      - text: Why?
+        type: single
+arms:
+  - name: Lecture
+cohorts:
+  - public_id: C-1
+    name: Section 1
+    arm: Workshop
+    crossover:
+      - after: A9
+        arm: Lecture
+`,
+			problems: Problems{
+				{Line: 2, Message: "the experiment has no name"},
+				{Line: 4, Message: `assessment A1 has an unknown type "later"`},
+				{Line: 5, Message: "assessment A1 is declared more than once"},
+				{Line: 8, Message: "question 1 of assessment A1 has no choices"},
+				{Line: 13, Message: "cohort C-1 can't have a dash in its public_id"},
+				{Line: 15, Message: `cohort C-1 has an unknown arm "Workshop"`},
+				{Line: 17, Message: `cohort C-1 crosses over after an unknown assessment "A9"`},
+			},
+		},
+		{
+			name: "unknown fields",
+			yaml: "name: Seasons\nassessments:\n  - public_id: A1\n    type: pre\n    question: []\n",
+			problems: Problems{
+				{Line: 5, Message: "field question not found in type wizard.Assessment"},
+			},
+		},
+		{
+			name: "choices",
+			yaml: `
+name: Seasons
+assessments:
+  - public_id: A1
+    type: pre
+    questions:
+      - text: Why?
+        type: single
+        choices:
+          - text: Tilt
+            is_correct: true
+          - text: Distance
+            is_correct: true
+      - text: How?
+        type: multiple
+        choices:
+          - text: Orbit
+`,
+			problems: Problems{
+				{Line: 9, Message: "question 1 of assessment A1 is single choice but has 2 correct choices"},
+				{Line: 16, Message: "question 2 of assessment A1 has no correct choice"},
+			},
+		},
+		{
+			name: "translations",
+			yaml: `
+name: Seasons
+assessments:
+  - public_id: A1
+    type: pre
+    questions:
+      - text: Why?
+        type: single
+        translations:
+          pt-BR: ""
+        choices:
+          - text: Tilt
+            is_correct: true
+            translations:
+              pt-BR: " "
+              en: Tilt
+`,
+			problems: Problems{
+				{Line: 9, Message: "question 1 of assessment A1 has no pt-BR text"},
+				{Line: 12, Message: "choice 1 of question 1 of assessment A1 has no pt-BR text"},
+			},
+		},
+		{
+			name: "items",
+			yaml: `
+name: Seasons
+assessments:
+  - public_id: A1
+    type: pre
+    questions:
+      - text: Why?
+        type: text
+      - text: When?
+        type: text
+  - public_id: A2
+    type: post
+    questions:
+      - text: Why is that?
+        anchor: why
+        type: text
+      - text: When?
+        type: single
+        choices:
+          - text: Summer
+            is_correct: true
+`,
+			problems: Problems{
+				{Line: 7, Message: "question 1 of assessment A1 has no matching question in the post-assessment", Warning: true},
+				{Line: 14, Message: "question 1 of assessment A2 has no matching question in the pre-assessment", Warning: true},
+				{Line: 18, Message: "question 2 of assessment A2 is single but its match in assessment A1 is text", Warning: true},
+			},
+		},
+		{
+			name: "items anchored on one side",
+			yaml: `
+name: Seasons
+assessments:
+  - public_id: A1
+    type: pre
+    questions:
+      - text: Why?
+        anchor: why
+        type: text
+  - public_id: A2
+    type: post
+    questions:
+      - text: Why?
+        type: text
+`,
+		},
+		{
+			name: "demographics",
+			yaml: `
+name: Seasons
+cohorts:
+  - public_id: C1
+    name: Section 1
+demographics:
+  - text: Program
+    type: single
+  - text: Why did you enroll?
+    type: text
+    options: [Curiosity]
+  - text: Languages
+    type: checkbox
+    options: [English, ""]
+`,
+			problems: Problems{
+				{Line: 7, Message: "demographic 1 has no options"},
+				{Line: 11, Message: "demographic 2 is text but has options"},
+				{Line: 14, Message: "option 2 of demographic 3 has no text"},
+				{Line: 13, Message: `demographic 3 has an unknown type "checkbox"`},
+			},
+		},
+		{
+			name: "bootstrap",
+			yaml: `
+name: Seasons
+assessments:
+  - public_id: A1
+    type: pre
+cohorts:
+  - public_id: C1
+    name: Section 1
+  - public_id: C2
+    name: Section 2
+bootstrap_config:
+  participants: 10
+  assessments:
+    - correct_probabilities: [0.5, 1.5]
+      bias_factor: -1
+    - correct_probabilities: [0.5]
+  demographics:
+    probabilities: [0.5, 0.3]
+    outlier_probability: 2
+`,
+			problems: Problems{
+				{Line: 13, Message: "bootstrap_config has 2 assessments, but the experiment has 1"},
+				{Line: 14, Message: "bootstrap_config assessment 1 has a correct probability of 1.5, outside 0 to 1"},
+				{Line: 15, Message: "bootstrap_config assessment 1 has a bias_factor of -1, outside 0 to 1"},
+				{Line: 16, Message: "bootstrap_config assessment 2 has 1 correct_probabilities, one per cohort is needed (2)"},
+				{Line: 18, Message: "bootstrap_config demographics probabilities sum to 0.8 instead of 1"},
+				{Line: 19, Message: "bootstrap_config demographics has an outlier_probability of 2, outside 0 to 1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := Check(strings.NewReader(tt.yaml))
+			if err != nil {
+				t.Fatalf("expected problems, got %v", err)
+			}
+
+			if !reflect.DeepEqual(problems, tt.problems) {
+				t.Errorf("expected %q, got %q", tt.problems, problems)
+			}
+
+			// Warnings don't stop the file from being parsed
+			_, err = Parse(strings.NewReader(tt.yaml))
+			if errs := tt.problems.Errors(); len(errs) == 0 && err != nil {
+				t.Errorf("expected only warnings, got %v", err)
+			} else if len(errs) > 0 && !reflect.DeepEqual(err, errs) {
+				t.Errorf("expected errors %q, got %v", errs, err)
+			}
+		})
+	}
+}
+
+func TestRename(t *testing.T) {
+	experiment := Experiment{
+		PublicID:    "E1",
+		Name:        "Seasons",
+		Assessments: []Assessment{{PublicID: "A1", Type: "pre"}, {PublicID: "A2", Type: "post"}},
+		Arms:        []Arm{{PublicID: "L", Name: "Lecture"}, {Name: "Workshop"}},
+		Cohorts: []Cohort{
+			{PublicID: "C1", Name: "Section 1", Arm: "L",
+				Crossover: []Period{{After: "A1", Arm: "Workshop"}}},
+			{PublicID: "C2", Name: "Section 2", Arm: "Workshop",
+				Crossover: []Period{{After: "A1", Arm: "L"}}},
+		},
+	}
+
+	n := 0
+	experiment.Rename(func() string {
+		n++
+		return strings.Repeat("X", n)
+	})
+
+	want := Experiment{
+		PublicID:    "X",
+		Name:        "Seasons",
+		Assessments: []Assessment{{PublicID: "XX", Type: "pre"}, {PublicID: "XXX", Type: "post"}},
+		Arms:        []Arm{{PublicID: "X-1", Name: "Lecture"}, {PublicID: "X-2", Name: "Workshop"}},
+		Cohorts: []Cohort{
+			{PublicID: "XXXX", Name: "Section 1", Arm: "X-1",
+				Crossover: []Period{{After: "XX", Arm: "Workshop"}}},
+			{PublicID: "XXXXX", Name: "Section 2", Arm: "Workshop",
+				Crossover: []Period{{After: "XX", Arm: "X-1"}}},
+		},
+	}
+
+	if !reflect.DeepEqual(experiment, want) {
+		t.Errorf("expected %+v, got %+v", want, experiment)
+	}
+
+	if problems := Validate(experiment); len(problems) > 0 {
+		t.Errorf("expected references to be kept, got %v", problems)
+	}
+}
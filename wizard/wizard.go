@@ -9,30 +9,69 @@ type Experiment struct {
 	Name            string          `yaml:"name"`
 	Description     string          `yaml:"description"`
 	Assessments     []Assessment    `yaml:"assessments"`
+	Arms            []Arm           `yaml:"arms,omitempty"`
 	Cohorts         []Cohort        `yaml:"cohorts"`
+	Demographics    []Demographic   `yaml:"demographics"` // The default ones when left out, none when empty
 	BootstrapConfig BootstrapConfig `yaml:"bootstrap_config,omitempty"`
 	ForceDelete     bool            `yaml:"force_delete,omitempty"`
 }
 
 type Assessment struct {
-	PublicID  string                `yaml:"public_id"`
-	Type      edulab.AssessmentType `yaml:"type"`
-	Questions []Question            `yaml:"questions"`
+	PublicID    string                `yaml:"public_id"`
+	Type        edulab.AssessmentType `yaml:"type"`
+	Description string                `yaml:"description,omitempty"`
+	Questions   []Question            `yaml:"questions,omitempty"`
 }
 
+// Question is an item of an assessment. Questions with the same anchor are
+// compared across assessments; without one, they are matched by text.
+// Translations are the texts in other languages, by language code, such as
+// pt-BR.
 type Question struct {
-	Text    string           `yaml:"text"`
-	Type    edulab.InputType `yaml:"type"`
-	Choices []Choice         `yaml:"choices"`
+	Text         string            `yaml:"text"`
+	Type         edulab.InputType  `yaml:"type"`
+	Anchor       string            `yaml:"anchor,omitempty"`
+	Translations map[string]string `yaml:"translations,omitempty"`
+	Choices      []Choice          `yaml:"choices,omitempty"`
 }
 
 type Choice struct {
-	Text      string `yaml:"text"`
-	IsCorrect bool   `yaml:"is_correct"`
+	Text         string            `yaml:"text"`
+	IsCorrect    bool              `yaml:"is_correct"`
+	Translations map[string]string `yaml:"translations,omitempty"`
 }
 
-type Cohort struct {
-	PublicID    string `yaml:"public_id"`
+// Arm is a treatment condition. When no arm is marked as control, the first
+// one is.
+type Arm struct {
+	PublicID    string `yaml:"public_id,omitempty"`
 	Name        string `yaml:"name"`
-	Description string `yaml:"description"`
+	Description string `yaml:"description,omitempty"`
+	Control     bool   `yaml:"control,omitempty"`
+}
+
+// Cohort is a group of participants. Its arm refers to the name or public ID
+// of an arm. Without arms, cohorts with the same arm name share an arm and
+// the others get an arm of their own.
+type Cohort struct {
+	PublicID    string   `yaml:"public_id"`
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Arm         string   `yaml:"arm,omitempty"`
+	Crossover   []Period `yaml:"crossover,omitempty"`
+}
+
+// Period switches a cohort to another arm after an assessment, referred to by
+// its public ID, in crossover designs.
+type Period struct {
+	After string `yaml:"after"`
+	Arm   string `yaml:"arm"`
+}
+
+// Demographic is a question asked to participants before their first
+// assessment. Text demographics have no options.
+type Demographic struct {
+	Text    string           `yaml:"text"`
+	Type    edulab.InputType `yaml:"type"`
+	Options []string         `yaml:"options,omitempty"`
 }
@@ -2,6 +2,8 @@ package wizard
 
 import (
 	"database/sql"
+	"fmt"
+	"io"
 	"io/fs"
 	"log"
 	"os"
@@ -52,13 +54,221 @@ func loadYAML(filename string) (Experiment, error) {
 	}
 	defer file.Close()
 
-	var experiment Experiment
-	decoder := yaml.NewDecoder(file)
-	if err := decoder.Decode(&experiment); err != nil {
-		return Experiment{}, errors.Wrap(err, "could not decode YAML file")
+	return Parse(file)
+}
+
+// ExportYAML writes an experiment in the format read by ImportYAML. Only its
+// design is exported, not its participants.
+func ExportYAML(db edulab.Database, publicID string, w io.Writer) error {
+	experiment, err := db.FindExperiment(publicID)
+	if err != nil {
+		return errors.Wrap(err, "could not find experiment")
+	}
+
+	experimentData, err := export(db, experiment)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(experimentData); err != nil {
+		return errors.Wrap(err, "could not encode YAML file")
+	}
+
+	return errors.Wrap(encoder.Close(), "could not encode YAML file")
+}
+
+// export is the inverse of create, referring to arms and assessments by their
+// public IDs.
+func export(db edulab.Database, experiment edulab.Experiment) (Experiment, error) {
+	experimentData := Experiment{
+		PublicID:    experiment.PublicID,
+		Name:        experiment.Name,
+		Description: experiment.Description,
+	}
+
+	assessments, err := db.FindAssessments(experiment.ID)
+	if err != nil {
+		return Experiment{}, errors.Wrap(err, "could not find assessments")
+	}
+
+	assessmentPIDs := make(map[string]string)
+	for _, a := range assessments {
+		assessmentPIDs[a.ID] = a.PublicID
+
+		assessment, err := exportAssessment(db, a)
+		if err != nil {
+			return Experiment{}, err
+		}
+
+		experimentData.Assessments = append(experimentData.Assessments, assessment)
+	}
+
+	arms, err := db.FindArms(experiment.ID)
+	if err != nil {
+		return Experiment{}, errors.Wrap(err, "could not find arms")
+	}
+
+	armPIDs := make(map[string]string)
+	for _, a := range arms {
+		armPIDs[a.ID] = a.PublicID
+
+		experimentData.Arms = append(experimentData.Arms, Arm{
+			PublicID:    a.PublicID,
+			Name:        a.Name,
+			Description: a.Description,
+			Control:     a.Control,
+		})
+	}
+
+	cohorts, err := db.FindCohorts(experiment.ID)
+	if err != nil {
+		return Experiment{}, errors.Wrap(err, "could not find cohorts")
+	}
+
+	periods, err := db.FindCohortPeriods(experiment.ID)
+	if err != nil {
+		return Experiment{}, errors.Wrap(err, "could not find cohort periods")
+	}
+
+	for _, c := range cohorts {
+		cohort := Cohort{
+			PublicID:    c.PublicID,
+			Name:        c.Name,
+			Description: c.Description,
+			Arm:         armPIDs[c.ArmID],
+		}
+
+		// Periods follow the order of the assessments
+		for _, a := range assessments {
+			for _, p := range periods {
+				if p.CohortID == c.ID && p.AssessmentID == a.ID {
+					cohort.Crossover = append(cohort.Crossover, Period{
+						After: assessmentPIDs[p.AssessmentID],
+						Arm:   armPIDs[p.ArmID],
+					})
+				}
+			}
+		}
+
+		experimentData.Cohorts = append(experimentData.Cohorts, cohort)
 	}
 
-	return experiment, nil
+	// Exported even when empty, so an experiment without demographics isn't
+	// imported with the default ones
+	experimentData.Demographics, err = exportDemographics(db, experiment)
+	if err != nil {
+		return Experiment{}, err
+	}
+
+	return experimentData, nil
+}
+
+// exportAssessment reads an assessment with its questions and choices.
+func exportAssessment(db edulab.Database, a edulab.Assessment) (Assessment, error) {
+	questions, err := db.FindQuestions(a.ID)
+	if err != nil {
+		return Assessment{}, errors.Wrap(err, "could not find questions")
+	}
+
+	choices, err := db.FindQuestionChoices(a.ID)
+	if err != nil {
+		return Assessment{}, errors.Wrap(err, "could not find question choices")
+	}
+
+	translations, err := db.FindTranslations(a.ID)
+	if err != nil {
+		return Assessment{}, errors.Wrap(err, "could not find translations")
+	}
+
+	assessment := Assessment{
+		PublicID:    a.PublicID,
+		Type:        a.Type,
+		Description: a.Description,
+	}
+
+	for _, q := range questions {
+		question := Question{
+			Text:         q.Text,
+			Type:         q.Type,
+			Anchor:       q.Anchor,
+			Translations: exportTranslations(translations, q.ID, ""),
+		}
+
+		for _, c := range choices {
+			if c.QuestionID == q.ID {
+				question.Choices = append(question.Choices, Choice{
+					Text:         c.Text,
+					IsCorrect:    c.IsCorrect,
+					Translations: exportTranslations(translations, q.ID, c.ID),
+				})
+			}
+		}
+
+		assessment.Questions = append(assessment.Questions, question)
+	}
+
+	return assessment, nil
+}
+
+// exportTranslations returns the texts of a question, or of one of its
+// choices, by language code.
+func exportTranslations(translations []edulab.Translation, questionID, choiceID string) map[string]string {
+	var texts map[string]string
+	for _, t := range translations {
+		if t.QuestionID != questionID || t.ChoiceID != choiceID {
+			continue
+		}
+		if texts == nil {
+			texts = make(map[string]string)
+		}
+		texts[t.Lang] = t.Text
+	}
+	return texts
+}
+
+// Import creates an experiment read from a YAML file, as ImportYAML does for
+// each file of a directory.
+func Import(db edulab.Database, experimentData Experiment) error {
+	return create(db, experimentData)
+}
+
+// Rename gives the experiment, its assessments, arms and cohorts new public
+// IDs drawn from publicID, keeping the references between them, so the same
+// file can be imported more than once.
+func (e *Experiment) Rename(publicID func() string) {
+	e.PublicID = publicID()
+
+	assessments := make(map[string]string)
+	for i, a := range e.Assessments {
+		e.Assessments[i].PublicID = publicID()
+		assessments[a.PublicID] = e.Assessments[i].PublicID
+	}
+
+	arms := make(map[string]string)
+	for i, a := range e.Arms {
+		e.Arms[i].PublicID = fmt.Sprintf("%s-%d", e.PublicID, i+1)
+		if a.PublicID != "" {
+			arms[a.PublicID] = e.Arms[i].PublicID
+		}
+	}
+
+	for i, c := range e.Cohorts {
+		e.Cohorts[i].PublicID = publicID()
+		if pid, ok := arms[c.Arm]; ok {
+			e.Cohorts[i].Arm = pid
+		}
+
+		for j, p := range c.Crossover {
+			if pid, ok := assessments[p.After]; ok {
+				e.Cohorts[i].Crossover[j].After = pid
+			}
+			if pid, ok := arms[p.Arm]; ok {
+				e.Cohorts[i].Crossover[j].Arm = pid
+			}
+		}
+	}
 }
 
 func create(db edulab.Database, experimentData Experiment) error {
@@ -95,44 +305,45 @@ func create(db edulab.Database, experimentData Experiment) error {
 		return errors.Wrap(err, "could not create experiment")
 	}
 
+	assessmentIDs := make(map[string]string)
 	for _, a := range experimentData.Assessments {
+		if !a.Type.Valid() {
+			return errors.Errorf("assessment %s has an unknown type %q", a.PublicID, a.Type)
+		}
+
 		assessment := edulab.Assessment{
 			PublicID:     a.PublicID,
 			ExperimentID: experiment.ID,
 			Type:         a.Type,
+			Description:  a.Description,
 		}
 		if err := db.CreateAssessment(&assessment); err != nil {
 			return errors.Wrap(err, "could not create assessment")
 		}
+		assessmentIDs[a.PublicID] = assessment.ID
 
-		for _, q := range a.Questions {
-			question := edulab.Question{
-				AssessmentID: assessment.ID,
-				Text:         q.Text,
-				Type:         q.Type,
-			}
-			if err := db.CreateQuestion(&question); err != nil {
-				return errors.Wrap(err, "could not create question")
-			}
-
-			for _, choice := range q.Choices {
-				questionChoice := edulab.QuestionChoice{
-					QuestionID: question.ID,
-					Text:       choice.Text,
-					IsCorrect:  choice.IsCorrect,
-				}
-				if err := db.CreateQuestionChoice(&questionChoice); err != nil {
-					return errors.Wrap(err, "could not create question choice")
-				}
-			}
+		if err := createQuestions(db, assessment, a.Questions); err != nil {
+			return err
 		}
 	}
 
+	// Import arms
+	armIDs, err := createArms(db, experiment, experimentData)
+	if err != nil {
+		return err
+	}
+
 	// Import cohorts
 	for _, c := range experimentData.Cohorts {
+		armID, ok := armIDs[cohortArm(c, experimentData.Arms)]
+		if !ok {
+			return errors.Errorf("cohort %s has an unknown arm %q", c.Name, c.Arm)
+		}
+
 		cohort := edulab.Cohort{
 			PublicID:     c.PublicID,
 			ExperimentID: experiment.ID,
+			ArmID:        armID,
 			Name:         c.Name,
 			Description:  c.Description,
 		}
@@ -140,10 +351,36 @@ func create(db edulab.Database, experimentData Experiment) error {
 		if err := db.CreateCohort(&cohort); err != nil {
 			return errors.Wrap(err, "could not create cohort")
 		}
+
+		for _, p := range c.Crossover {
+			assessmentID, ok := assessmentIDs[p.After]
+			if !ok {
+				return errors.Errorf("cohort %s crosses over after an unknown assessment %q", c.Name, p.After)
+			}
+
+			armID, ok := armIDs[p.Arm]
+			if !ok {
+				return errors.Errorf("cohort %s crosses over to an unknown arm %q", c.Name, p.Arm)
+			}
+
+			period := edulab.CohortPeriod{
+				ExperimentID: experiment.ID,
+				CohortID:     cohort.ID,
+				AssessmentID: assessmentID,
+				ArmID:        armID,
+			}
+			if err := db.UpdateCohortPeriod(period); err != nil {
+				return errors.Wrap(err, "could not create cohort period")
+			}
+		}
 	}
 
-	// Import demographics
-	err = Demographics(db, experiment)
+	// Import demographics, or the default ones when left out
+	if experimentData.Demographics == nil {
+		err = Demographics(db, experiment)
+	} else {
+		err = createDemographics(db, experiment, experimentData.Demographics)
+	}
 	if err != nil {
 		return errors.Wrap(err, "could not create demographics")
 	}
@@ -160,3 +397,63 @@ func create(db edulab.Database, experimentData Experiment) error {
 
 	return nil
 }
+
+// createArms creates the arms of an experiment and returns their IDs by name
+// and public ID. Without arms, they are derived from the cohorts.
+func createArms(db edulab.Database, experiment edulab.Experiment,
+	experimentData Experiment) (map[string]string, error) {
+
+	arms := experimentData.Arms
+	if len(arms) == 0 {
+		seen := make(map[string]bool)
+		for _, c := range experimentData.Cohorts {
+			name := cohortArm(c, nil)
+			if !seen[name] {
+				seen[name] = true
+				arms = append(arms, Arm{Name: name})
+			}
+		}
+	}
+
+	control := -1
+	for i, a := range arms {
+		if a.Control && control == -1 {
+			control = i
+		}
+	}
+	if control == -1 {
+		control = 0
+	}
+
+	ids := make(map[string]string)
+	for i, a := range arms {
+		arm := edulab.Arm{
+			ExperimentID: experiment.ID,
+			PublicID:     a.PublicID,
+			Name:         a.Name,
+			Description:  a.Description,
+			Control:      i == control,
+		}
+		if arm.PublicID == "" {
+			arm.PublicID = fmt.Sprintf("%s-%d", experiment.PublicID, i+1)
+		}
+
+		if err := db.CreateArm(&arm); err != nil {
+			return nil, errors.Wrap(err, "could not create arm")
+		}
+
+		ids[arm.Name] = arm.ID
+		ids[arm.PublicID] = arm.ID
+	}
+
+	return ids, nil
+}
+
+// cohortArm returns the name of the arm a cohort belongs to. Cohorts without
+// an arm have one named after them, unless arms were declared.
+func cohortArm(c Cohort, arms []Arm) string {
+	if c.Arm == "" && len(arms) == 0 {
+		return c.Name
+	}
+	return c.Arm
+}
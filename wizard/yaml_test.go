@@ -0,0 +1,182 @@
+package wizard
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/louisbranch/edulab"
+	"github.com/louisbranch/edulab/mock"
+)
+
+func TestExportYAML(t *testing.T) {
+	experiment := Experiment{
+		PublicID:    "E1",
+		Name:        "Earth's Seasons",
+		Description: "Lecture and workshop.\nTwo sections.\n",
+		Assessments: []Assessment{
+			{
+				PublicID:    "A1",
+				Type:        edulab.AssessmentTypePre,
+				Description: "Before the lecture",
+				Questions: []Question{
+					{
+						Text:         "What causes the seasons?",
+						Type:         edulab.InputSingle,
+						Anchor:       "seasons",
+						Translations: map[string]string{"pt-BR": "O que causa as estações?"},
+						Choices: []Choice{
+							{
+								Text:         "The tilt of Earth's axis",
+								IsCorrect:    true,
+								Translations: map[string]string{"pt-BR": "A inclinação do eixo da Terra"},
+							},
+							{Text: "The distance from the Sun"},
+						},
+					},
+					{
+						Text: "Explain why.",
+						Type: edulab.InputText,
+					},
+				},
+			},
+			{
+				PublicID: "A2",
+				Type:     edulab.AssessmentTypeMid,
+				Questions: []Question{
+					{
+						Text:   "What makes the seasons change?",
+						Type:   edulab.InputMultiple,
+						Anchor: "seasons",
+						Choices: []Choice{
+							{Text: "The tilt of Earth's axis", IsCorrect: true},
+							{Text: "The distance from the Sun", IsCorrect: true},
+						},
+					},
+				},
+			},
+			{
+				PublicID: "A3",
+				Type:     edulab.AssessmentTypePost,
+			},
+		},
+		Arms: []Arm{
+			{PublicID: "L", Name: "Lecture", Description: "Traditional lecture", Control: true},
+			{PublicID: "W", Name: "Workshop"},
+		},
+		Cohorts: []Cohort{
+			{
+				PublicID:    "C1",
+				Name:        "Section 1",
+				Description: "Monday",
+				Arm:         "L",
+				Crossover:   []Period{{After: "A2", Arm: "W"}},
+			},
+			{
+				PublicID:  "C2",
+				Name:      "Section 2",
+				Arm:       "W",
+				Crossover: []Period{{After: "A2", Arm: "L"}},
+			},
+		},
+		Demographics: []Demographic{
+			{Text: "Program", Type: edulab.InputSingle, Options: []string{"Physics", "Geography"}},
+			{Text: "Languages spoken", Type: edulab.InputMultiple, Options: []string{"English", "Portuguese"}},
+			{Text: "Why did you enroll?", Type: edulab.InputText},
+		},
+	}
+
+	db := &mock.DB{}
+	if err := create(db, experiment); err != nil {
+		t.Fatalf("failed to create experiment: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := ExportYAML(db, "E1", &out); err != nil {
+		t.Fatalf("failed to export experiment: %v", err)
+	}
+
+	exported, err := Parse(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("failed to decode exported experiment: %v\n%s", err, out.String())
+	}
+
+	if !reflect.DeepEqual(exported, experiment) {
+		t.Errorf("expected %+v, got %+v", experiment, exported)
+	}
+
+	// Importing the export into another database exports the same file
+	other := &mock.DB{}
+	if err := create(other, exported); err != nil {
+		t.Fatalf("failed to import exported experiment: %v", err)
+	}
+
+	var again bytes.Buffer
+	if err := ExportYAML(other, "E1", &again); err != nil {
+		t.Fatalf("failed to export imported experiment: %v", err)
+	}
+
+	if again.String() != out.String() {
+		t.Errorf("expected the same export, got\n%s\nand\n%s", out.String(), again.String())
+	}
+
+	// Experiments without demographics stay without them
+	experiment.PublicID = "E2"
+	experiment.Demographics = []Demographic{}
+	if err := create(db, experiment); err != nil {
+		t.Fatalf("failed to create experiment: %v", err)
+	}
+
+	var none bytes.Buffer
+	if err := ExportYAML(db, "E2", &none); err != nil {
+		t.Fatalf("failed to export experiment: %v", err)
+	}
+	if !strings.Contains(none.String(), "\ndemographics: []\n") {
+		t.Errorf("expected no demographics, got\n%s", none.String())
+	}
+}
+
+func TestExportYAMLExperiments(t *testing.T) {
+	for _, path := range []string{"../experiments/earth_seasons.yaml", "../experiments/habitability.yaml"} {
+		experiment, err := loadYAML(path)
+		if err != nil {
+			t.Fatalf("failed to load %s: %v", path, err)
+		}
+		experiment.BootstrapConfig = BootstrapConfig{}
+
+		db := &mock.DB{}
+		if err := create(db, experiment); err != nil {
+			t.Fatalf("failed to create %s: %v", path, err)
+		}
+
+		var out bytes.Buffer
+		if err := ExportYAML(db, experiment.PublicID, &out); err != nil {
+			t.Fatalf("failed to export %s: %v", path, err)
+		}
+
+		exported, err := Parse(&out)
+		if err != nil {
+			t.Fatalf("failed to decode export of %s: %v", path, err)
+		}
+
+		if !reflect.DeepEqual(exported.Assessments, experiment.Assessments) {
+			t.Errorf("expected the assessments of %s to round-trip", path)
+		}
+
+		if len(exported.Demographics) != 4 {
+			t.Errorf("expected the 4 default demographics of %s, got %+v", path, exported.Demographics)
+		}
+
+		if len(exported.Cohorts) != len(experiment.Cohorts) {
+			t.Fatalf("expected %d cohorts, got %d", len(experiment.Cohorts), len(exported.Cohorts))
+		}
+
+		for i, c := range exported.Cohorts {
+			want := experiment.Cohorts[i]
+			if c.PublicID != want.PublicID || c.Name != want.Name || c.Description != want.Description {
+				t.Errorf("expected cohort %+v, got %+v", want, c)
+			}
+		}
+	}
+}